@@ -0,0 +1,136 @@
+package buildinfo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrIncompatibleVersion is returned if the remote version is incompatible.
+var ErrIncompatibleVersion = errors.New("incompatible remote version")
+
+// CheckFunc checks if the remote build info is compatible with the local.
+// Returns an error if the versions are incompatible.
+type CheckFunc func(local, remote *BuildInfo) error
+
+// NewLocalBuildInfo builds the BuildInfo from srpc.GetBuildInfo.
+func NewLocalBuildInfo() *BuildInfo {
+	info := srpc.GetBuildInfo()
+	return &BuildInfo{
+		Name:            info.Name,
+		Version:         info.Version,
+		ProtocolVersion: srpc.ProtocolVersion,
+	}
+}
+
+// CheckCompatible is the default CheckFunc.
+//
+// Rejects if the protocol versions or the major versions differ.
+// If the major version is zero, the minor version must also match.
+// Empty or unset fields are not checked.
+func CheckCompatible(local, remote *BuildInfo) error {
+	localProto, remoteProto := local.GetProtocolVersion(), remote.GetProtocolVersion()
+	if localProto != 0 && remoteProto != 0 && localProto != remoteProto {
+		return errors.Wrapf(
+			ErrIncompatibleVersion,
+			"protocol version %d != %d",
+			remoteProto, localProto,
+		)
+	}
+
+	localVer, remoteVer := local.GetVersion(), remote.GetVersion()
+	if localVer == "" || remoteVer == "" {
+		return nil
+	}
+	localMajor, localMinor := parseMajorMinor(localVer)
+	remoteMajor, remoteMinor := parseMajorMinor(remoteVer)
+	if localMajor != remoteMajor || (localMajor == "0" && localMinor != remoteMinor) {
+		return errors.Wrapf(
+			ErrIncompatibleVersion,
+			"version %s is not compatible with %s",
+			remoteVer, localVer,
+		)
+	}
+	return nil
+}
+
+// parseMajorMinor parses the major and minor components of a version string.
+func parseMajorMinor(version string) (major, minor string) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	major = parts[0]
+	if len(parts) > 1 {
+		minor = parts[1]
+	}
+	return major, minor
+}
+
+// Server implements the BuildInfoService server.
+type Server struct {
+	// check checks the remote build info, if set.
+	check CheckFunc
+}
+
+// NewServer constructs a new BuildInfoService server.
+//
+// If check is set, rejects remotes which fail the check with an error.
+func NewServer(check CheckFunc) *Server {
+	return &Server{check: check}
+}
+
+// Register registers the server with the mux.
+func (s *Server) Register(mux srpc.Mux) error {
+	return SRPCRegisterBuildInfoService(mux, s)
+}
+
+// ExchangeBuildInfo sends the local build info and returns the remote's.
+func (s *Server) ExchangeBuildInfo(ctx context.Context, remote *BuildInfo) (*BuildInfo, error) {
+	local := NewLocalBuildInfo()
+	if s.check != nil {
+		if err := s.check(local, remote); err != nil {
+			return nil, err
+		}
+	}
+	return local, nil
+}
+
+// ExchangeBuildInfo exchanges build info with the remote.
+//
+// If check is nil, uses CheckCompatible.
+// Returns the remote build info and an error if incompatible.
+func ExchangeBuildInfo(ctx context.Context, client srpc.Client, check CheckFunc) (*BuildInfo, error) {
+	if check == nil {
+		check = CheckCompatible
+	}
+	local := NewLocalBuildInfo()
+	remote, err := NewSRPCBuildInfoServiceClient(client).ExchangeBuildInfo(ctx, local)
+	if err != nil {
+		return nil, err
+	}
+	return remote, check(local, remote)
+}
+
+// LogIncompatible exchanges build info with the remote and logs a warning if
+// the remote version is incompatible.
+//
+// If check is nil, uses CheckCompatible.
+// Returns an error only if the call failed.
+func LogIncompatible(ctx context.Context, le *logrus.Entry, client srpc.Client, check CheckFunc) (*BuildInfo, error) {
+	remote, err := ExchangeBuildInfo(ctx, client, check)
+	if err != nil && remote == nil {
+		return nil, err
+	}
+	if err != nil {
+		le.
+			WithError(err).
+			WithField("remote-name", remote.GetName()).
+			WithField("remote-version", remote.GetVersion()).
+			Warn("remote version is incompatible")
+	}
+	return remote, nil
+}
+
+// _ is a type assertion
+var _ SRPCBuildInfoServiceServer = ((*Server)(nil))
@@ -0,0 +1,178 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1-devel
+// 	protoc        v3.19.3
+// source: github.com/aperturerobotics/starpc/buildinfo/buildinfo.proto
+
+package buildinfo
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// BuildInfo contains build and version information for a program.
+type BuildInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name is the name of the program or module.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Version is the semantic version of the program.
+	// Optional: if empty, the version is not checked.
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// ProtocolVersion is the starpc protocol version.
+	// Optional: if zero, the protocol version is not checked.
+	ProtocolVersion uint32 `protobuf:"varint,3,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+}
+
+func (x *BuildInfo) Reset() {
+	*x = BuildInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildInfo) ProtoMessage() {}
+
+func (x *BuildInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildInfo.ProtoReflect.Descriptor instead.
+func (*BuildInfo) Descriptor() ([]byte, []int) {
+	return file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BuildInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BuildInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *BuildInfo) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+var File_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto protoreflect.FileDescriptor
+
+var file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDesc = []byte{
+	0x0a, 0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x70, 0x65,
+	0x72, 0x74, 0x75, 0x72, 0x65, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x69, 0x63, 0x73, 0x2f, 0x73, 0x74,
+	0x61, 0x72, 0x70, 0x63, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x69, 0x6e, 0x66, 0x6f, 0x2f, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x69, 0x6e, 0x66, 0x6f, 0x22, 0x64, 0x0a, 0x09, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x32,
+	0x53, 0x0a, 0x10, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x3f, 0x0a, 0x11, 0x45, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x14, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x14,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x49, 0x6e, 0x66, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDescOnce sync.Once
+	file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDescData = file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDesc
+)
+
+func file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDescGZIP() []byte {
+	file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDescOnce.Do(func() {
+		file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDescData)
+	})
+	return file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDescData
+}
+
+var file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_goTypes = []interface{}{
+	(*BuildInfo)(nil), // 0: buildinfo.BuildInfo
+}
+var file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_depIdxs = []int32{
+	0, // 0: buildinfo.BuildInfoService.ExchangeBuildInfo:input_type -> buildinfo.BuildInfo
+	0, // 1: buildinfo.BuildInfoService.ExchangeBuildInfo:output_type -> buildinfo.BuildInfo
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_init() }
+func file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_init() {
+	if File_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_goTypes,
+		DependencyIndexes: file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_depIdxs,
+		MessageInfos:      file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_msgTypes,
+	}.Build()
+	File_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto = out.File
+	file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_rawDesc = nil
+	file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_goTypes = nil
+	file_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto_depIdxs = nil
+}
@@ -0,0 +1,120 @@
+// Code generated by protoc-gen-srpc. DO NOT EDIT.
+// protoc-gen-srpc version: v0.0.0-20220611014014-aa9dc5523865
+// source: github.com/aperturerobotics/starpc/buildinfo/buildinfo.proto
+
+package buildinfo
+
+import (
+	context "context"
+
+	srpc "github.com/aperturerobotics/starpc/srpc"
+)
+
+type SRPCBuildInfoServiceClient interface {
+	SRPCClient() srpc.Client
+
+	ExchangeBuildInfo(ctx context.Context, in *BuildInfo, opts ...srpc.CallOption) (*BuildInfo, error)
+}
+
+type srpcBuildInfoServiceClient struct {
+	cc srpc.Client
+}
+
+func NewSRPCBuildInfoServiceClient(cc srpc.Client) SRPCBuildInfoServiceClient {
+	return &srpcBuildInfoServiceClient{cc}
+}
+
+func (c *srpcBuildInfoServiceClient) SRPCClient() srpc.Client { return c.cc }
+
+func (c *srpcBuildInfoServiceClient) ExchangeBuildInfo(ctx context.Context, in *BuildInfo, opts ...srpc.CallOption) (*BuildInfo, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	out := new(BuildInfo)
+	err := c.cc.Invoke(ctx, SRPCBuildInfoServiceServiceID, SRPCBuildInfoServiceExchangeBuildInfoMethodID, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type SRPCBuildInfoServiceServer interface {
+	ExchangeBuildInfo(context.Context, *BuildInfo) (*BuildInfo, error)
+}
+
+type SRPCBuildInfoServiceUnimplementedServer struct{}
+
+func (s *SRPCBuildInfoServiceUnimplementedServer) ExchangeBuildInfo(context.Context, *BuildInfo) (*BuildInfo, error) {
+	return nil, srpc.ErrUnimplemented
+}
+
+const SRPCBuildInfoServiceServiceID = "buildinfo.BuildInfoService"
+
+const (
+	SRPCBuildInfoServiceExchangeBuildInfoMethodID = "ExchangeBuildInfo"
+)
+
+// SRPCBuildInfoServiceService contains the references to the methods of the service.
+var SRPCBuildInfoServiceService = struct {
+	ExchangeBuildInfoMethod srpc.MethodRef
+}{
+	ExchangeBuildInfoMethod: srpc.MethodRef{ServiceID: SRPCBuildInfoServiceServiceID, MethodID: SRPCBuildInfoServiceExchangeBuildInfoMethodID},
+}
+
+type SRPCBuildInfoServiceHandler struct {
+	impl SRPCBuildInfoServiceServer
+}
+
+func (SRPCBuildInfoServiceHandler) GetServiceID() string { return SRPCBuildInfoServiceServiceID }
+
+func (SRPCBuildInfoServiceHandler) GetMethodIDs() []string {
+	return []string{
+		"ExchangeBuildInfo",
+	}
+}
+
+func (d *SRPCBuildInfoServiceHandler) InvokeMethod(
+	serviceID, methodID string,
+	strm srpc.Stream,
+) (bool, error) {
+	if serviceID != "" && serviceID != d.GetServiceID() {
+		return false, nil
+	}
+
+	switch methodID {
+	case "ExchangeBuildInfo":
+		return true, d.InvokeMethod_ExchangeBuildInfo(d.impl, strm)
+	default:
+		return false, nil
+	}
+}
+
+func (SRPCBuildInfoServiceHandler) InvokeMethod_ExchangeBuildInfo(impl SRPCBuildInfoServiceServer, strm srpc.Stream) error {
+	req := new(BuildInfo)
+	if err := strm.MsgRecv(req); err != nil {
+		return err
+	}
+	out, err := impl.ExchangeBuildInfo(strm.Context(), req)
+	if err != nil {
+		return err
+	}
+	return strm.MsgSend(out)
+}
+
+func SRPCRegisterBuildInfoService(mux srpc.Mux, impl SRPCBuildInfoServiceServer) error {
+	return mux.Register(&SRPCBuildInfoServiceHandler{impl: impl})
+}
+
+type SRPCBuildInfoService_ExchangeBuildInfoStream interface {
+	srpc.Stream
+	SendAndClose(*BuildInfo) error
+}
+
+type srpcBuildInfoService_ExchangeBuildInfoStream struct {
+	srpc.Stream
+}
+
+func (x *srpcBuildInfoService_ExchangeBuildInfoStream) SendAndClose(m *BuildInfo) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
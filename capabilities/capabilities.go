@@ -0,0 +1,166 @@
+package capabilities
+
+import (
+	"context"
+	"time"
+
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// Feature names advertised by this implementation.
+const (
+	// FeatureMetadata indicates the peer reads the call metadata.
+	FeatureMetadata = "metadata"
+	// FeatureErrorDetails indicates the peer sends or reads error details.
+	FeatureErrorDetails = "error-details"
+	// FeatureProgress indicates the peer sends or reads call progress.
+	FeatureProgress = "progress"
+	// FeatureLoadReport indicates the peer sends or reads load reports.
+	FeatureLoadReport = "load-report"
+	// FeatureHeartbeat indicates the peer responds to heartbeat Ping packets.
+	FeatureHeartbeat = "heartbeat"
+	// FeatureSignals indicates the peer accepts out-of-band Signal packets.
+	FeatureSignals = "signals"
+)
+
+// localFeatures contains the features supported by this implementation.
+var localFeatures = []string{
+	FeatureMetadata,
+	FeatureErrorDetails,
+	FeatureProgress,
+	FeatureLoadReport,
+	FeatureHeartbeat,
+	FeatureSignals,
+}
+
+// NewLocalCapabilities builds the Capabilities of this implementation.
+func NewLocalCapabilities() *Capabilities {
+	return &Capabilities{
+		ProtocolVersion: srpc.ProtocolVersion,
+		Features:        append([]string(nil), localFeatures...),
+		Compression:     srpc.CompressorNames(),
+		Codecs:          srpc.CodecNames(),
+	}
+}
+
+// HasFeature checks if the feature is in the list of features.
+func (c *Capabilities) HasFeature(feature string) bool {
+	return containsString(c.GetFeatures(), feature)
+}
+
+// HasCompression checks if the compression algorithm is in the list.
+func (c *Capabilities) HasCompression(algorithm string) bool {
+	return containsString(c.GetCompression(), algorithm)
+}
+
+// HasCodec checks if the message codec is in the list.
+func (c *Capabilities) HasCodec(codec string) bool {
+	return containsString(c.GetCodecs(), codec)
+}
+
+// containsString checks if the list contains the value.
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Server implements the CapabilitiesService server.
+type Server struct{}
+
+// NewServer constructs a new CapabilitiesService server.
+func NewServer() *Server {
+	return &Server{}
+}
+
+// Register registers the server with the mux.
+func (s *Server) Register(mux srpc.Mux) error {
+	return SRPCRegisterCapabilitiesService(mux, s)
+}
+
+// ExchangeCapabilities returns the local capabilities.
+func (s *Server) ExchangeCapabilities(ctx context.Context, remote *Capabilities) (*Capabilities, error) {
+	return NewLocalCapabilities(), nil
+}
+
+// ExchangeCapabilities exchanges the capabilities with the remote.
+//
+// If the remote does not implement the CapabilitiesService, returns empty
+// capabilities: the remote supports no optional features.
+func ExchangeCapabilities(ctx context.Context, client srpc.Client) (*Capabilities, error) {
+	remote, err := NewSRPCCapabilitiesServiceClient(client).ExchangeCapabilities(ctx, NewLocalCapabilities())
+	if err != nil {
+		if err.Error() == srpc.ErrUnimplemented.Error() {
+			return &Capabilities{}, nil
+		}
+		return nil, err
+	}
+	return remote, nil
+}
+
+// Client is a srpc.Client which only uses the features supported by the remote.
+//
+// Call metadata is dropped if the remote does not support FeatureMetadata, and
+// compression is disabled if the remote does not support the algorithm.
+type Client struct {
+	srpc.Client
+	// remote contains the remote capabilities
+	remote *Capabilities
+}
+
+// Discover exchanges the capabilities with the remote and returns a Client
+// caching the remote capabilities.
+//
+// Call once after connecting to the remote.
+func Discover(ctx context.Context, client srpc.Client) (*Client, error) {
+	remote, err := ExchangeCapabilities(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(client, remote), nil
+}
+
+// NewClient constructs a Client with the remote capabilities.
+func NewClient(client srpc.Client, remote *Capabilities) *Client {
+	return &Client{Client: client, remote: remote}
+}
+
+// GetRemoteCapabilities returns the cached remote capabilities.
+func (c *Client) GetRemoteCapabilities() *Capabilities {
+	return c.remote
+}
+
+// Invoke executes a unary RPC with the remote.
+func (c *Client) Invoke(ctx context.Context, service, method string, in, out srpc.Message) error {
+	return c.Client.Invoke(c.filterContext(ctx), service, method, in, out)
+}
+
+// NewStream starts a streaming RPC with the remote.
+func (c *Client) NewStream(ctx context.Context, service, method string, firstMsg srpc.Message) (srpc.Stream, error) {
+	return c.Client.NewStream(c.filterContext(ctx), service, method, firstMsg)
+}
+
+// Ping calls the built-in ping service with the remote.
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	return c.Client.Ping(c.filterContext(ctx))
+}
+
+// filterContext removes the features the remote does not support from ctx.
+func (c *Client) filterContext(ctx context.Context) context.Context {
+	if !c.remote.HasFeature(FeatureMetadata) && len(srpc.MetadataFromOutgoingContext(ctx)) != 0 {
+		ctx = srpc.WithoutOutgoingMetadata(ctx)
+	}
+	if comp := srpc.CompressionFromContext(ctx); comp != "" && !c.remote.HasCompression(comp) {
+		ctx = srpc.WithCallOptions(ctx, srpc.WithCompression(""))
+	}
+	return ctx
+}
+
+// _ is a type assertion
+var (
+	_ SRPCCapabilitiesServiceServer = ((*Server)(nil))
+	_ srpc.Client                   = ((*Client)(nil))
+)
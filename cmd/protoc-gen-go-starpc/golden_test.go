@@ -0,0 +1,172 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/buildinfo"
+	"github.com/aperturerobotics/starpc/capabilities"
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/examples/chat"
+	"github.com/aperturerobotics/starpc/examples/fileupload"
+	"github.com/aperturerobotics/starpc/examples/tunnel"
+	"github.com/aperturerobotics/starpc/srpc/operations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// updateGolden rewrites the golden files with the generated output.
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// goldenVersionRe matches the generator version line which depends on the build.
+var goldenVersionRe = regexp.MustCompile(`(?m)^// protoc-gen-srpc version: .*$`)
+
+// newCorpusFile builds a file with a nested package, all streaming kinds,
+// empty and nested messages and well-known types.
+func newCorpusFile() *descriptorpb.FileDescriptorProto {
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	method := func(name, in, out string, clientStreaming, serverStreaming bool) *descriptorpb.MethodDescriptorProto {
+		return &descriptorpb.MethodDescriptorProto{
+			Name:            proto.String(name),
+			InputType:       proto.String(in),
+			OutputType:      proto.String(out),
+			ClientStreaming: proto.Bool(clientStreaming),
+			ServerStreaming: proto.Bool(serverStreaming),
+		}
+	}
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("corpus/nested/v1/corpus.proto"),
+		Package: proto.String("corpus.nested.v1"),
+		Syntax:  proto.String("proto3"),
+		Dependency: []string{
+			emptypb.File_google_protobuf_empty_proto.Path(),
+			timestamppb.File_google_protobuf_timestamp_proto.Path(),
+		},
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/corpus/nested/v1;nestedv1")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Nothing"),
+		}, {
+			Name: proto.String("Event"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("at"),
+				JsonName: proto.String("at"),
+				Number:   proto.Int32(1),
+				Label:    optional,
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+				TypeName: proto.String(".google.protobuf.Timestamp"),
+			}},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Detail"),
+				Field: []*descriptorpb.FieldDescriptorProto{{
+					Name:     proto.String("body"),
+					JsonName: proto.String("body"),
+					Number:   proto.Int32(1),
+					Label:    optional,
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				}},
+			}},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Corpus"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				method("Unary", ".corpus.nested.v1.Event", ".google.protobuf.Empty", false, false),
+				method("Empty", ".corpus.nested.v1.Nothing", ".corpus.nested.v1.Nothing", false, false),
+				method("Nested", ".corpus.nested.v1.Event.Detail", ".corpus.nested.v1.Event.Detail", false, false),
+				method("ServerStream", ".google.protobuf.Empty", ".corpus.nested.v1.Event", false, true),
+				method("ClientStream", ".corpus.nested.v1.Event", ".google.protobuf.Empty", true, false),
+				method("BidiStream", ".corpus.nested.v1.Event", ".corpus.nested.v1.Event", true, true),
+			},
+		}, {
+			Name: proto.String("Second"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				method("Ping", ".google.protobuf.Empty", ".google.protobuf.Empty", false, false),
+			},
+		}},
+	}
+}
+
+// addGoldenFile adds the file and its dependencies to the request.
+//
+// Files without a go_package are mapped to the package in this module.
+func addGoldenFile(req *pluginpb.CodeGeneratorRequest, seen map[string]bool, fd protoreflect.FileDescriptor) {
+	if seen[fd.Path()] {
+		return
+	}
+	seen[fd.Path()] = true
+	imports := fd.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		addGoldenFile(req, seen, imports.Get(i).FileDescriptor)
+	}
+	fdp := protodesc.ToFileDescriptorProto(fd)
+	if fdp.GetOptions().GetGoPackage() == "" {
+		req.Parameter = proto.String(req.GetParameter() + ",M" + fd.Path() + "=" + path.Dir(fd.Path()))
+	}
+	req.ProtoFile = append(req.ProtoFile, fdp)
+}
+
+func TestGolden(t *testing.T) {
+	corpusFile, err := protodesc.NewFile(newCorpusFile(), protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := &pluginpb.CodeGeneratorRequest{Parameter: proto.String("paths=source_relative")}
+	seen := make(map[string]bool)
+	for _, fd := range []protoreflect.FileDescriptor{
+		echo.File_github_com_aperturerobotics_starpc_echo_echo_proto,
+		operations.File_github_com_aperturerobotics_starpc_srpc_operations_operations_proto,
+		capabilities.File_github_com_aperturerobotics_starpc_capabilities_capabilities_proto,
+		buildinfo.File_github_com_aperturerobotics_starpc_buildinfo_buildinfo_proto,
+		chat.File_github_com_aperturerobotics_starpc_examples_chat_chat_proto,
+		fileupload.File_github_com_aperturerobotics_starpc_examples_fileupload_fileupload_proto,
+		tunnel.File_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto,
+		corpusFile,
+	} {
+		addGoldenFile(req, seen, fd)
+		req.FileToGenerate = append(req.FileToGenerate, fd.Path())
+	}
+	plugin, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := generate(plugin, &generatorOptions{json: true}); err != nil {
+		t.Fatal(err.Error())
+	}
+	resp := plugin.Response()
+	if resp.Error != nil {
+		t.Fatal(resp.GetError())
+	}
+
+	for _, f := range resp.GetFile() {
+		content := goldenVersionRe.ReplaceAllString(f.GetContent(), "// protoc-gen-srpc version: (golden)")
+		name := strings.TrimPrefix(f.GetName(), "github.com/aperturerobotics/starpc/")
+		goldenPath := filepath.Join("testdata", filepath.FromSlash(name)+".golden")
+		if *updateGolden {
+			if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+				t.Fatal(err.Error())
+			}
+			if err := os.WriteFile(goldenPath, []byte(content), 0o644); err != nil {
+				t.Fatal(err.Error())
+			}
+			continue
+		}
+		expected, err := os.ReadFile(goldenPath)
+		if err != nil {
+			t.Fatalf("%s: %v: run make gengolden to create it", f.GetName(), err)
+		}
+		if string(expected) != content {
+			t.Errorf("%s: generated output differs from %s: run make gengolden if intended", f.GetName(), goldenPath)
+		}
+	}
+}
@@ -5,34 +5,147 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"text/template"
 
+	starpc "github.com/aperturerobotics/starpc/srpc"
 	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
 const SRPCPackage = "github.com/aperturerobotics/starpc/srpc"
 
+// deprecationComment is the comment emitted for deprecated methods.
+const deprecationComment = "// Deprecated: Do not use."
+
+// generatorOptions contains options parsed from the plugin parameters.
+type generatorOptions struct {
+	// json generates MarshalJSON and UnmarshalJSON for request and response types.
+	json bool
+	// serviceID is the template for the service ids.
+	// if nil, uses the full proto name.
+	serviceID *template.Template
+}
+
+// serviceIDData is the data passed to the service_id template.
+type serviceIDData struct {
+	// FullName is the full proto name of the service, e.g. echo.Echoer.
+	FullName string
+	// Package is the proto package of the service, e.g. echo.
+	Package string
+	// Name is the short name of the service, e.g. Echoer.
+	Name string
+	// GoName is the Go name of the service.
+	GoName string
+}
+
+// setServiceID parses the service_id plugin parameter.
+//
+// Accepts full (the default), short or a text/template with serviceIDData.
+func (o *generatorOptions) setServiceID(value string) error {
+	switch value {
+	case "", "full":
+		o.serviceID = nil
+		return nil
+	case "short":
+		value = "{{.Name}}"
+	default:
+		if !strings.Contains(value, "{{") {
+			return fmt.Errorf("service_id: expected full, short or a template: %q", value)
+		}
+	}
+	tmpl, err := template.New("service_id").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return fmt.Errorf("service_id: %w", err)
+	}
+	o.serviceID = tmpl
+	return nil
+}
+
+// getServiceID returns the service id for the service.
+func (o *generatorOptions) getServiceID(service *protogen.Service) (string, error) {
+	if o.serviceID == nil {
+		return string(service.Desc.FullName()), nil
+	}
+	var sb strings.Builder
+	err := o.serviceID.Execute(&sb, &serviceIDData{
+		FullName: string(service.Desc.FullName()),
+		Package:  string(service.Desc.ParentFile().Package()),
+		Name:     string(service.Desc.Name()),
+		GoName:   service.GoName,
+	})
+	if err != nil {
+		return "", fmt.Errorf("service_id: %s: %w", service.Desc.FullName(), err)
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("service_id: %s: template produced an empty id", service.Desc.FullName())
+	}
+	return sb.String(), nil
+}
+
 func main() {
-	opts := protogen.Options{}
+	var flags flag.FlagSet
+	genOpts := &generatorOptions{}
+	flags.BoolVar(&genOpts.json, "json", false, "generate MarshalJSON and UnmarshalJSON for request and response types")
+	flags.Func("service_id", "service id naming: full, short or a template", genOpts.setServiceID)
+
+	opts := protogen.Options{ParamFunc: flags.Set}
 	opts.Run(func(plugin *protogen.Plugin) error {
-		for _, f := range plugin.Files {
-			if !f.Generate || len(f.Services) == 0 {
-				continue
+		return generate(plugin, genOpts)
+	})
+}
+
+// generate generates the files for the files to generate of the plugin.
+func generate(plugin *protogen.Plugin, genOpts *generatorOptions) error {
+	// resolve the service ids up front to report errors and collisions.
+	serviceIDs := make(map[protoreflect.FullName]string)
+	seenIDs := make(map[string]protoreflect.FullName)
+	for _, f := range plugin.Files {
+		if !f.Generate {
+			continue
+		}
+		for _, service := range f.Services {
+			serviceID, err := genOpts.getServiceID(service)
+			if err != nil {
+				return err
 			}
-			generatePluginFile(plugin, f)
+			if other, ok := seenIDs[serviceID]; ok {
+				return fmt.Errorf("service_id: %s and %s have the same id %q", other, service.Desc.FullName(), serviceID)
+			}
+			seenIDs[serviceID] = service.Desc.FullName()
+			serviceIDs[service.Desc.FullName()] = serviceID
 		}
-		plugin.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
-		return nil
-	})
+	}
+
+	var jsonMsgs map[string][]*protogen.Message
+	if genOpts.json {
+		jsonMsgs = collectJSONMessages(plugin)
+	}
+	for _, f := range plugin.Files {
+		if !f.Generate {
+			continue
+		}
+		if len(f.Services) != 0 {
+			generatePluginFile(plugin, f, serviceIDs)
+		}
+		if msgs := jsonMsgs[f.Desc.Path()]; len(msgs) != 0 {
+			generateJSONFile(plugin, f, msgs)
+		}
+	}
+	plugin.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	return nil
 }
 
-func generatePluginFile(plugin *protogen.Plugin, file *protogen.File) {
+func generatePluginFile(plugin *protogen.Plugin, file *protogen.File, serviceIDs map[protoreflect.FullName]string) {
 	gf := plugin.NewGeneratedFile(file.GeneratedFilenamePrefix+"_srpc.pb.go", file.GoImportPath)
-	s := &srpc{gf, file}
+	s := &srpc{GeneratedFile: gf, file: file, serviceIDs: serviceIDs}
 
 	s.P("// Code generated by protoc-gen-srpc. DO NOT EDIT.")
 	if bi, ok := debug.ReadBuildInfo(); ok {
@@ -46,11 +159,50 @@ func generatePluginFile(plugin *protogen.Plugin, file *protogen.File) {
 	for _, service := range file.Services {
 		s.generateService(service)
 	}
+
+	generateIterFile(plugin, file)
+}
+
+// generateIterFile generates the range-over-func methods for the stream clients.
+//
+// The methods are generated to a separate file with the go1.23 build tag.
+func generateIterFile(plugin *protogen.Plugin, file *protogen.File) {
+	var methods []*protogen.Method
+	for _, service := range file.Services {
+		for _, method := range service.Methods {
+			if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+				methods = append(methods, method)
+			}
+		}
+	}
+	if len(methods) == 0 {
+		return
+	}
+
+	gf := plugin.NewGeneratedFile(file.GeneratedFilenamePrefix+"_srpc_iter.pb.go", file.GoImportPath)
+	s := &srpc{GeneratedFile: gf, file: file}
+
+	s.P("// Code generated by protoc-gen-srpc. DO NOT EDIT.")
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		s.P("// protoc-gen-srpc version: ", bi.Main.Version)
+	}
+	s.P("// source: ", file.Desc.Path())
+	s.P()
+	s.P("//go:build go1.23")
+	s.P()
+	s.P("package ", file.GoPackageName)
+	s.P()
+
+	for _, method := range methods {
+		s.generateClientIterMethods(method)
+	}
 }
 
 type srpc struct {
 	*protogen.GeneratedFile
 	file *protogen.File
+	// serviceIDs contains the service ids by full name.
+	serviceIDs map[protoreflect.FullName]string
 }
 
 func (s *srpc) Ident(path, ident string) string {
@@ -58,13 +210,18 @@ func (s *srpc) Ident(path, ident string) string {
 }
 
 // GetServiceID returns the service id for the srpc.
+//
+// Defaults to the full proto name unless set with the service_id parameter.
 func (s *srpc) GetServiceID(p *protogen.Service) (service string) {
+	if id, ok := s.serviceIDs[p.Desc.FullName()]; ok {
+		return id
+	}
 	return string(p.Desc.FullName())
 }
 
 // GetServiceAndMethodID returns the service and method for the srpc.
 func (s *srpc) GetServiceAndMethodID(p *protogen.Method) (service, method string) {
-	return string(p.Parent.Desc.FullName()), string(p.Desc.Name())
+	return s.GetServiceID(p.Parent), string(p.Desc.Name())
 }
 
 /*
@@ -73,10 +230,84 @@ func (s *srpc) ServiceMethodString(method *protogen.Method) string {
 }
 */
 
+// IsMethodDeprecated checks if the method has the deprecated option set.
+func (s *srpc) IsMethodDeprecated(method *protogen.Method) bool {
+	opts, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
+	return ok && opts.GetDeprecated()
+}
+
+// GetDeprecatedMethods returns the list of deprecated methods for the service.
+func (s *srpc) GetDeprecatedMethods(service *protogen.Service) []*protogen.Method {
+	var methods []*protogen.Method
+	for _, method := range service.Methods {
+		if s.IsMethodDeprecated(method) {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// IsMethodRawRequest checks if the method has the raw_request option set.
+func (s *srpc) IsMethodRawRequest(method *protogen.Method) bool {
+	opts, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
+	if !ok || opts == nil {
+		return false
+	}
+	raw, _ := proto.GetExtension(opts, starpc.E_RawRequest).(bool)
+	return raw
+}
+
+// IsWellKnownType checks if the message is a google.protobuf well-known type.
+//
+// The well-known types do not implement srpc.Message.
+func (s *srpc) IsWellKnownType(msg *protogen.Message) bool {
+	return msg.Desc.ParentFile().Package() == "google.protobuf"
+}
+
+// MsgRef returns the expression of the message type as a srpc.Message.
+//
+// Wraps the well-known types with srpc.NewProtoMessage.
+func (s *srpc) MsgRef(msg *protogen.Message, expr string) string {
+	if s.IsWellKnownType(msg) {
+		return s.Ident(SRPCPackage, "NewProtoMessage") + "(" + expr + ")"
+	}
+	return expr
+}
+
+// ServerInputRef returns the expression of the server input type as a srpc.Message.
+func (s *srpc) ServerInputRef(method *protogen.Method, expr string) string {
+	if s.IsMethodRawRequest(method) {
+		return expr
+	}
+	return s.MsgRef(method.Input, expr)
+}
+
+// GetMethodCacheTTL returns the cache_ttl_ms option of a unary method.
+// Returns zero if not set or if the method is streaming.
+func (s *srpc) GetMethodCacheTTL(method *protogen.Method) uint32 {
+	if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+		return 0
+	}
+	opts, ok := method.Desc.Options().(*descriptorpb.MethodOptions)
+	if !ok || opts == nil {
+		return 0
+	}
+	ttl, _ := proto.GetExtension(opts, starpc.E_CacheTtlMs).(uint32)
+	return ttl
+}
+
 func (s *srpc) InputType(method *protogen.Method) string {
 	return s.QualifiedGoIdent(method.Input.GoIdent)
 }
 
+// ServerInputType returns the request type passed to the server handler.
+func (s *srpc) ServerInputType(method *protogen.Method) string {
+	if s.IsMethodRawRequest(method) {
+		return s.Ident(SRPCPackage, "RawMessage")
+	}
+	return s.InputType(method)
+}
+
 func (s *srpc) OutputType(method *protogen.Method) string {
 	return s.QualifiedGoIdent(method.Output.GoIdent)
 }
@@ -146,6 +377,9 @@ func (s *srpc) generateService(service *protogen.Service) {
 	s.P("SRPCClient() ", s.Ident(SRPCPackage, "Client"))
 	s.P()
 	for _, method := range service.Methods {
+		if s.IsMethodDeprecated(method) {
+			s.P(deprecationComment)
+		}
 		s.P(s.generateClientSignature(method))
 	}
 	s.P("}")
@@ -169,6 +403,9 @@ func (s *srpc) generateService(service *protogen.Service) {
 	for _, method := range service.Methods {
 		s.generateClientMethod(method)
 	}
+	for _, method := range service.Methods {
+		s.generatePaginateMethod(method)
+	}
 
 	// Server interface
 	s.P("type ", s.ServerIface(service), " interface {")
@@ -189,6 +426,10 @@ func (s *srpc) generateService(service *protogen.Service) {
 	// Service ID constant
 	serviceID := s.GetServiceID(service)
 	s.P("const ", s.ServerServiceID(service), " = ", strconv.Quote(serviceID))
+	s.P()
+
+	// Method ID constants and references
+	s.generateMethodRefs(service)
 
 	// Handler implementation.
 	s.P("type ", s.ServerHandler(service), " struct{")
@@ -207,6 +448,38 @@ func (s *srpc) generateService(service *protogen.Service) {
 	s.P("}")
 	s.P()
 
+	// Deprecated methods list, only if any methods are deprecated.
+	if deprecatedMethods := s.GetDeprecatedMethods(service); len(deprecatedMethods) != 0 {
+		s.P("func (", s.ServerHandler(service), ") GetDeprecatedMethodIDs() []string {")
+		s.P("return []string{")
+		for _, method := range deprecatedMethods {
+			_, methodID := s.GetServiceAndMethodID(method)
+			s.P(strconv.Quote(methodID), ",")
+		}
+		s.P("}")
+		s.P("}")
+		s.P()
+	}
+
+	// Cached methods, only if any methods set cache_ttl_ms.
+	var cachedMethods []*protogen.Method
+	for _, method := range service.Methods {
+		if s.GetMethodCacheTTL(method) != 0 {
+			cachedMethods = append(cachedMethods, method)
+		}
+	}
+	if len(cachedMethods) != 0 {
+		s.P("func (", s.ServerHandler(service), ") GetMethodCacheTTLs() map[string]", s.Ident("time", "Duration"), " {")
+		s.P("return map[string]", s.Ident("time", "Duration"), "{")
+		for _, method := range cachedMethods {
+			_, methodID := s.GetServiceAndMethodID(method)
+			s.P(strconv.Quote(methodID), ": ", s.GetMethodCacheTTL(method), " * ", s.Ident("time", "Millisecond"), ",")
+		}
+		s.P("}")
+		s.P("}")
+		s.P()
+	}
+
 	// InvokeMethod function.
 	s.P("func (d *", s.ServerHandler(service), ") InvokeMethod(")
 	s.P("serviceID, methodID string,")
@@ -229,7 +502,7 @@ func (s *srpc) generateService(service *protogen.Service) {
 
 	// InvokeMethod_Echo function.
 	for _, method := range service.Methods {
-		inType := s.InputType(method)
+		inType := s.ServerInputType(method)
 		// outType := s.OutputType(method)
 		// _, methodID := s.GetServiceAndMethodID(method)
 		s.P()
@@ -244,7 +517,7 @@ func (s *srpc) generateService(service *protogen.Service) {
 			s.P("return impl.", method.GoName, "(clientStrm)")
 		} else {
 			s.P("req := new(", inType, ")")
-			s.P("if err := strm.MsgRecv(req); err != nil { return err }")
+			s.P("if err := strm.MsgRecv(", s.ServerInputRef(method, "req"), "); err != nil { return err }")
 
 			if method.Desc.IsStreamingServer() {
 				// non-streaming client, streaming server
@@ -254,7 +527,7 @@ func (s *srpc) generateService(service *protogen.Service) {
 				// non-streaming client, non-streaming server
 				s.P("out, err := impl.", method.GoName, "(strm.Context(), req)")
 				s.P("if err != nil { return err }")
-				s.P("return strm.MsgSend(out)")
+				s.P("return strm.MsgSend(", s.MsgRef(method.Output, "out"), ")")
 			}
 		}
 
@@ -296,6 +569,38 @@ func (s *srpc) generateService(service *protogen.Service) {
 // client methods
 //
 
+// MethodIDConst returns the name of the method id constant.
+func (s *srpc) MethodIDConst(method *protogen.Method) string {
+	return "SRPC" + method.Parent.GoName + method.GoName + "MethodID"
+}
+
+// generateMethodRefs generates the method id constants and the struct of the
+// typed method references of the service.
+func (s *srpc) generateMethodRefs(service *protogen.Service) {
+	if len(service.Methods) == 0 {
+		return
+	}
+	methodRef := s.Ident(SRPCPackage, "MethodRef")
+	s.P("const (")
+	for _, method := range service.Methods {
+		_, methodID := s.GetServiceAndMethodID(method)
+		s.P(s.MethodIDConst(method), " = ", strconv.Quote(methodID))
+	}
+	s.P(")")
+	s.P()
+	s.P("// SRPC", service.GoName, "Service contains the references to the methods of the service.")
+	s.P("var SRPC", service.GoName, "Service = struct {")
+	for _, method := range service.Methods {
+		s.P(method.GoName, "Method ", methodRef)
+	}
+	s.P("}{")
+	for _, method := range service.Methods {
+		s.P(method.GoName, "Method: ", methodRef, "{ServiceID: ", s.ServerServiceID(service), ", MethodID: ", s.MethodIDConst(method), "},")
+	}
+	s.P("}")
+	s.P()
+}
+
 func (s *srpc) generateClientSignature(method *protogen.Method) string {
 	reqArg := ", in *" + s.InputType(method)
 	if method.Desc.IsStreamingClient() {
@@ -305,7 +610,10 @@ func (s *srpc) generateClientSignature(method *protogen.Method) string {
 	if method.Desc.IsStreamingServer() || method.Desc.IsStreamingClient() {
 		respName = s.ClientStreamIface(method)
 	}
-	return fmt.Sprintf("%s(ctx %s%s) (%s, error)", method.GoName, s.Ident("context", "Context"), reqArg, respName)
+	return fmt.Sprintf(
+		"%s(ctx %s%s, opts ...%s) (%s, error)",
+		method.GoName, s.Ident("context", "Context"), reqArg, s.Ident(SRPCPackage, "CallOption"), respName,
+	)
 }
 
 func (s *srpc) generateClientMethod(p *protogen.Method) {
@@ -313,13 +621,16 @@ func (s *srpc) generateClientMethod(p *protogen.Method) {
 	outType := s.OutputType(p)
 	inType := s.InputType(p)
 
-	service, method := s.GetServiceAndMethodID(p)
-	serviceQuote, methodQuote := strconv.Quote(service), strconv.Quote(method)
+	serviceID, methodID := s.ServerServiceID(p.Parent), s.MethodIDConst(p)
 
+	if s.IsMethodDeprecated(p) {
+		s.P(deprecationComment)
+	}
 	s.P("func (c *", recvType, ") ", s.generateClientSignature(p), "{")
+	s.P("ctx = ", s.Ident(SRPCPackage, "WithCallOptions"), "(ctx, opts...)")
 	if !p.Desc.IsStreamingServer() && !p.Desc.IsStreamingClient() {
 		s.P("out := new(", outType, ")")
-		s.P("err := c.cc.Invoke(ctx, ", serviceQuote, ", ", methodQuote, ", ", "in, out)")
+		s.P("err := c.cc.Invoke(ctx, ", serviceID, ", ", methodID, ", ", s.MsgRef(p.Input, "in"), ", ", s.MsgRef(p.Output, "out"), ")")
 		s.P("if err != nil { return nil, err }")
 		s.P("return out, nil")
 		s.P("}")
@@ -329,10 +640,10 @@ func (s *srpc) generateClientMethod(p *protogen.Method) {
 
 	firstMsgRef := "nil"
 	if !p.Desc.IsStreamingClient() {
-		firstMsgRef = "in"
+		firstMsgRef = s.MsgRef(p.Input, "in")
 	}
 
-	s.P("stream, err := c.cc.NewStream(ctx, ", serviceQuote, ", ", methodQuote, ", ", firstMsgRef, ")")
+	s.P("stream, err := c.cc.NewStream(ctx, ", serviceID, ", ", methodID, ", ", firstMsgRef, ")")
 	s.P("if err != nil { return nil, err }")
 	s.P("strm := &", s.ClientStreamImpl(p), "{stream}")
 	if !p.Desc.IsStreamingClient() {
@@ -345,16 +656,27 @@ func (s *srpc) generateClientMethod(p *protogen.Method) {
 	genSend := p.Desc.IsStreamingClient()
 	genRecv := p.Desc.IsStreamingServer()
 	genCloseAndRecv := !p.Desc.IsStreamingServer()
+	outRef := s.MsgRef(p.Output, "m")
 
 	// Stream auxiliary types and methods.
 	s.P("type ", s.ClientStreamIface(p), " interface {")
 	s.P(s.Ident(SRPCPackage, "Stream"))
 	if genSend {
 		s.P("Send(*", inType, ") error")
+		s.P("SendCtx(", s.Ident("context", "Context"), ", *", inType, ") error")
+	}
+	if genSend {
+		s.P(s.Ident(SRPCPackage, "StreamSendIter"), "[*", inType, "]")
+	}
+	if genRecv {
+		s.P(s.Ident(SRPCPackage, "StreamRecvIter"), "[*", outType, "]")
 	}
 	if genRecv {
 		s.P("Recv() (*", outType, ", error)")
 		s.P("RecvTo(*", outType, ") error")
+		s.P("RecvTimeout(", s.Ident("time", "Duration"), ") (*", outType, ", error)")
+		s.P("TryRecv() (*", outType, ", bool, error)")
+		s.P("RecvBatch(max int) ([]*", outType, ", error)")
 	}
 	if genCloseAndRecv {
 		s.P("CloseAndRecv() (*", outType, ", error)")
@@ -369,20 +691,49 @@ func (s *srpc) generateClientMethod(p *protogen.Method) {
 
 	if genSend {
 		s.P("func (x *", s.ClientStreamImpl(p), ") Send(m *", inType, ") error {")
-		s.P("return x.MsgSend(m)")
+		s.P("return x.MsgSend(", s.MsgRef(p.Input, "m"), ")")
+		s.P("}")
+		s.P()
+
+		s.P("func (x *", s.ClientStreamImpl(p), ") SendCtx(ctx ", s.Ident("context", "Context"), ", m *", inType, ") error {")
+		s.P("return ", s.Ident(SRPCPackage, "MsgSendCtx"), "(ctx, x.Stream, ", s.MsgRef(p.Input, "m"), ")")
 		s.P("}")
 		s.P()
 	}
 	if genRecv {
 		s.P("func (x *", s.ClientStreamImpl(p), ") Recv() (*", outType, ", error) {")
 		s.P("m := new(", outType, ")")
-		s.P("if err := x.MsgRecv(m); err != nil { return nil, err }")
+		s.P("if err := x.MsgRecv(", outRef, "); err != nil { return nil, err }")
 		s.P("return m, nil")
 		s.P("}")
 		s.P()
 
 		s.P("func (x *", s.ClientStreamImpl(p), ") RecvTo(m *", outType, ") error {")
-		s.P("return x.MsgRecv(m)")
+		s.P("return x.MsgRecv(", outRef, ")")
+		s.P("}")
+		s.P()
+
+		s.P("func (x *", s.ClientStreamImpl(p), ") RecvTimeout(d ", s.Ident("time", "Duration"), ") (*", outType, ", error) {")
+		s.P("m := new(", outType, ")")
+		s.P("if err := ", s.Ident(SRPCPackage, "MsgRecvTimeout"), "(x.Stream, ", outRef, ", d); err != nil { return nil, err }")
+		s.P("return m, nil")
+		s.P("}")
+		s.P()
+
+		s.P("func (x *", s.ClientStreamImpl(p), ") TryRecv() (*", outType, ", bool, error) {")
+		s.P("m := new(", outType, ")")
+		s.P("ok, err := ", s.Ident(SRPCPackage, "MsgTryRecv"), "(x.Stream, ", outRef, ")")
+		s.P("if err != nil || !ok { return nil, ok, err }")
+		s.P("return m, true, nil")
+		s.P("}")
+		s.P()
+
+		s.P("func (x *", s.ClientStreamImpl(p), ") RecvBatch(max int) ([]*", outType, ", error) {")
+		recvBatch := "RecvBatch"
+		if s.IsWellKnownType(p.Output) {
+			recvBatch = "RecvProtoBatch"
+		}
+		s.P("return ", s.Ident(SRPCPackage, recvBatch), "(x.Stream, max, func() *", outType, " { return new(", outType, ") })")
 		s.P("}")
 		s.P()
 	}
@@ -390,17 +741,132 @@ func (s *srpc) generateClientMethod(p *protogen.Method) {
 		s.P("func (x *", s.ClientStreamImpl(p), ") CloseAndRecv() (*", outType, ", error) {")
 		s.P("if err := x.CloseSend(); err != nil { return nil, err }")
 		s.P("m := new(", outType, ")")
-		s.P("if err := x.MsgRecv(m); err != nil { return nil, err }")
+		s.P("if err := x.MsgRecv(", outRef, "); err != nil { return nil, err }")
 		s.P("return m, nil")
 		s.P("}")
 		s.P()
 
 		s.P("func (x *", s.ClientStreamImpl(p), ") CloseAndMsgRecv(m *", outType, ") error {")
 		s.P("if err := x.CloseSend(); err != nil { return err }")
-		s.P("return x.MsgRecv(m)")
+		s.P("return x.MsgRecv(", outRef, ")")
+		s.P("}")
+		s.P()
+	}
+}
+
+// generateClientIterMethods generates the range-over-func methods for a stream client.
+func (s *srpc) generateClientIterMethods(p *protogen.Method) {
+	if p.Desc.IsStreamingClient() {
+		inType := s.InputType(p)
+		s.P("func (x *", s.ClientStreamImpl(p), ") SendAll(seq ", s.Ident("iter", "Seq"), "[*", inType, "]) error {")
+		s.P("return ", s.Ident(SRPCPackage, "SendAll"), "[*", inType, "](x, seq)")
 		s.P("}")
 		s.P()
 	}
+	if p.Desc.IsStreamingServer() {
+		outType := s.OutputType(p)
+		s.P("func (x *", s.ClientStreamImpl(p), ") All() ", s.Ident("iter", "Seq2"), "[*", outType, ", error] {")
+		s.P("return ", s.Ident(SRPCPackage, "RecvAll"), "[*", outType, "](x)")
+		s.P("}")
+		s.P()
+	}
+}
+
+//
+// pagination
+//
+
+// GetPageItemsField returns the items field if the method follows the paged
+// list conventions: the request has a string page_token field and the response
+// has a string next_page_token field and exactly one repeated field.
+func (s *srpc) GetPageItemsField(method *protogen.Method) *protogen.Field {
+	if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+		return nil
+	}
+	pageToken := method.Input.Desc.Fields().ByName("page_token")
+	if pageToken == nil || pageToken.Kind() != protoreflect.StringKind || pageToken.IsList() {
+		return nil
+	}
+	nextPageToken := method.Output.Desc.Fields().ByName("next_page_token")
+	if nextPageToken == nil || nextPageToken.Kind() != protoreflect.StringKind || nextPageToken.IsList() {
+		return nil
+	}
+	var items *protogen.Field
+	for _, field := range method.Output.Fields {
+		if !field.Desc.IsList() {
+			continue
+		}
+		if items != nil {
+			return nil
+		}
+		items = field
+	}
+	return items
+}
+
+// FieldElemType returns the Go type of an element of a repeated field.
+func (s *srpc) FieldElemType(field *protogen.Field) string {
+	switch field.Desc.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return "*" + s.QualifiedGoIdent(field.Message.GoIdent)
+	case protoreflect.EnumKind:
+		return s.QualifiedGoIdent(field.Enum.GoIdent)
+	case protoreflect.BoolKind:
+		return "bool"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int32"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "uint32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "int64"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "uint64"
+	case protoreflect.FloatKind:
+		return "float32"
+	case protoreflect.DoubleKind:
+		return "float64"
+	case protoreflect.StringKind:
+		return "string"
+	case protoreflect.BytesKind:
+		return "[]byte"
+	default:
+		return "interface{}"
+	}
+}
+
+// generatePaginateMethod generates a typed Paginate wrapper for paged list methods.
+func (s *srpc) generatePaginateMethod(method *protogen.Method) {
+	items := s.GetPageItemsField(method)
+	if items == nil {
+		return
+	}
+	inType, outType := s.InputType(method), s.OutputType(method)
+	itemType := s.FieldElemType(items)
+	funcName := "SRPC" + method.Parent.GoName + "Paginate" + method.GoName
+
+	s.P("// ", funcName, " iterates over the ", items.GoName, " of the ", method.GoName, " pages.")
+	s.P("// Sets the page token on req before each call. The opts apply to each call.")
+	if s.IsMethodDeprecated(method) {
+		s.P("//")
+		s.P(deprecationComment)
+	}
+	s.P(
+		"func ", funcName, "(ctx ", s.Ident("context", "Context"),
+		", client ", s.ClientIface(method.Parent), ", req *", inType,
+		", opts ...", s.Ident(SRPCPackage, "CallOption"), ") *",
+		s.Ident(SRPCPackage, "Paginator"), "[", itemType, "] {",
+	)
+	s.P("return ", s.Ident(SRPCPackage, "Paginate"), "(")
+	s.P("ctx,")
+	s.P("req,")
+	s.P("func(req *", inType, ", pageToken string) { req.PageToken = pageToken },")
+	s.P("func(ctx ", s.Ident("context", "Context"), ", req *", inType, ") (*", outType, ", error) {")
+	s.P("return client.", method.GoName, "(ctx, req, opts...)")
+	s.P("},")
+	s.P("func(resp *", outType, ") []", itemType, " { return resp.Get", items.GoName, "() },")
+	s.P(")")
+	s.P("}")
+	s.P()
 }
 
 //
@@ -415,7 +881,7 @@ func (s *srpc) generateServerSignature(method *protogen.Method) string {
 		ret = "(*" + s.OutputType(method) + ", error)"
 	}
 	if !method.Desc.IsStreamingClient() {
-		reqArgs = append(reqArgs, "*"+s.InputType(method))
+		reqArgs = append(reqArgs, "*"+s.ServerInputType(method))
 	}
 	if method.Desc.IsStreamingServer() || method.Desc.IsStreamingClient() {
 		reqArgs = append(reqArgs, s.ServerStreamIface(method))
@@ -474,7 +940,7 @@ func (s *srpc) generateServerMethod(method *protogen.Method) {
 		s.P("SendAndClose(*", s.OutputType(method), ") error")
 	}
 	if genRecv {
-		s.P("Recv() (*", s.InputType(method), ", error)")
+		s.P("Recv() (*", s.ServerInputType(method), ", error)")
 	}
 	s.P("}")
 	s.P()
@@ -486,30 +952,100 @@ func (s *srpc) generateServerMethod(method *protogen.Method) {
 
 	if genSend {
 		s.P("func (x *", s.ServerStreamImpl(method), ") Send(m *", s.OutputType(method), ") error {")
-		s.P("return x.MsgSend(m)")
+		s.P("return x.MsgSend(", s.MsgRef(method.Output, "m"), ")")
 		s.P("}")
 		s.P()
 	}
 
 	if genSendAndClose {
 		s.P("func (x *", s.ServerStreamImpl(method), ") SendAndClose(m *", s.OutputType(method), ") error {")
-		s.P("if err := x.MsgSend(m); err != nil { return err }")
+		s.P("if err := x.MsgSend(", s.MsgRef(method.Output, "m"), "); err != nil { return err }")
 		s.P("return x.CloseSend()")
 		s.P("}")
 		s.P()
 	}
 
 	if genRecv {
-		s.P("func (x *", s.ServerStreamImpl(method), ") Recv() (*", s.InputType(method), ", error) {")
-		s.P("m := new(", s.InputType(method), ")")
-		s.P("if err := x.MsgRecv(m); err != nil { return nil, err }")
+		s.P("func (x *", s.ServerStreamImpl(method), ") Recv() (*", s.ServerInputType(method), ", error) {")
+		s.P("m := new(", s.ServerInputType(method), ")")
+		s.P("if err := x.MsgRecv(", s.ServerInputRef(method, "m"), "); err != nil { return nil, err }")
 		s.P("return m, nil")
 		s.P("}")
 		s.P()
 
-		s.P("func (x *", s.ServerStreamImpl(method), ") RecvTo(m *", s.InputType(method), ") error {")
-		s.P("return x.MsgRecv(m)")
+		s.P("func (x *", s.ServerStreamImpl(method), ") RecvTo(m *", s.ServerInputType(method), ") error {")
+		s.P("return x.MsgRecv(", s.ServerInputRef(method, "m"), ")")
 		s.P("}")
 		s.P()
 	}
 }
+
+//
+// json methods
+//
+
+// collectJSONMessages collects the request and response types of the services
+// which are defined in the generated files, keyed by the defining file path.
+//
+// The methods are generated only in the file which defines the message: a
+// message used by services in multiple files is only generated once.
+func collectJSONMessages(plugin *protogen.Plugin) map[string][]*protogen.Message {
+	msgs := make(map[string][]*protogen.Message)
+	seen := make(map[protogen.GoIdent]struct{})
+	for _, f := range plugin.Files {
+		if !f.Generate {
+			continue
+		}
+		for _, service := range f.Services {
+			for _, method := range service.Methods {
+				for _, msg := range []*protogen.Message{method.Input, method.Output} {
+					path := msg.Location.SourceFile
+					if def, ok := plugin.FilesByPath[path]; !ok || !def.Generate {
+						continue
+					}
+					if _, ok := seen[msg.GoIdent]; ok {
+						continue
+					}
+					seen[msg.GoIdent] = struct{}{}
+					msgs[path] = append(msgs[path], msg)
+				}
+			}
+		}
+	}
+	return msgs
+}
+
+// generateJSONFile generates MarshalJSON and UnmarshalJSON for the messages
+// defined in the file.
+func generateJSONFile(plugin *protogen.Plugin, file *protogen.File, msgs []*protogen.Message) {
+	gf := plugin.NewGeneratedFile(file.GeneratedFilenamePrefix+"_srpc_json.pb.go", file.GoImportPath)
+	s := &srpc{GeneratedFile: gf, file: file}
+
+	s.P("// Code generated by protoc-gen-srpc. DO NOT EDIT.")
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		s.P("// protoc-gen-srpc version: ", bi.Main.Version)
+	}
+	s.P("// source: ", file.Desc.Path())
+	s.P()
+	s.P("package ", file.GoPackageName)
+	s.P()
+
+	for _, msg := range msgs {
+		s.generateMessageJSONMethods(msg)
+	}
+}
+
+// generateMessageJSONMethods generates MarshalJSON and UnmarshalJSON for the message.
+func (s *srpc) generateMessageJSONMethods(msg *protogen.Message) {
+	typeName := s.QualifiedGoIdent(msg.GoIdent)
+	s.P("// MarshalJSON marshals the ", typeName, " to the canonical protojson format.")
+	s.P("func (x *", typeName, ") MarshalJSON() ([]byte, error) {")
+	s.P("return ", s.Ident(SRPCPackage, "MarshalProtoJSON"), "(x)")
+	s.P("}")
+	s.P()
+	s.P("// UnmarshalJSON unmarshals the ", typeName, " from the protojson format.")
+	s.P("func (x *", typeName, ") UnmarshalJSON(data []byte) error {")
+	s.P("return ", s.Ident(SRPCPackage, "UnmarshalProtoJSON"), "(data, x)")
+	s.P("}")
+	s.P()
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	starpc "github.com/aperturerobotics/starpc/srpc"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// newTestPlugin constructs a plugin generating a file with a paged list
+// method and a method with the raw_request option.
+func newTestPlugin(t *testing.T) *protogen.Plugin {
+	rawOpts := &descriptorpb.MethodOptions{}
+	proto.SetExtension(rawOpts, starpc.E_RawRequest, true)
+	stringField := func(name string, num int32, label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(num),
+			Label:    label.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		}
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	testFile := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test/test.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{starpc.File_github_com_aperturerobotics_starpc_srpc_options_proto.Path()},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name:  proto.String("ListRequest"),
+			Field: []*descriptorpb.FieldDescriptorProto{stringField("page_token", 1, optional)},
+		}, {
+			Name: proto.String("ListResponse"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				stringField("names", 1, descriptorpb.FieldDescriptorProto_LABEL_REPEATED),
+				stringField("next_page_token", 2, optional),
+			},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Names"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("List"),
+				InputType:  proto.String(".test.ListRequest"),
+				OutputType: proto.String(".test.ListResponse"),
+			}, {
+				Name:       proto.String("Raw"),
+				InputType:  proto.String(".test.ListRequest"),
+				OutputType: proto.String(".test.ListResponse"),
+				Options:    rawOpts,
+			}},
+		}},
+	}
+	optionsFile := protodesc.ToFileDescriptorProto(starpc.File_github_com_aperturerobotics_starpc_srpc_options_proto)
+	optionsFile.Options = &descriptorpb.FileOptions{GoPackage: proto.String(SRPCPackage)}
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{testFile.GetName()},
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+			optionsFile,
+			testFile,
+		},
+	}
+	plugin, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return plugin
+}
+
+func TestGeneratePluginFile(t *testing.T) {
+	plugin := newTestPlugin(t)
+	generatePluginFile(plugin, plugin.FilesByPath["test/test.proto"], nil)
+	resp := plugin.Response()
+	if resp.Error != nil {
+		t.Fatal(resp.GetError())
+	}
+	if len(resp.GetFile()) != 1 {
+		t.Fatalf("expected 1 generated file got %d", len(resp.GetFile()))
+	}
+	content := resp.GetFile()[0].GetContent()
+	for _, expected := range []string{
+		// the raw_request handler receives a RawMessage
+		"Raw(context.Context, *srpc.RawMessage) (*ListResponse, error)",
+		// the client still sends the request type
+		"Raw(ctx context.Context, in *ListRequest, opts ...srpc.CallOption) (*ListResponse, error)",
+		// the paged list method has a Paginate wrapper
+		"func SRPCNamesPaginateList(ctx context.Context, client SRPCNamesClient, req *ListRequest, opts ...srpc.CallOption) *srpc.Paginator[string] {",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Fatalf("expected generated code to contain %q:\n%s", expected, content)
+		}
+	}
+}
+
+func TestGenerateServiceID(t *testing.T) {
+	for _, tc := range []struct {
+		value, expected string
+	}{
+		{"full", `"test.Names"`},
+		{"short", `"Names"`},
+		{"legacy/{{.Package}}/{{.Name}}", `"legacy/test/Names"`},
+	} {
+		genOpts := &generatorOptions{}
+		if err := genOpts.setServiceID(tc.value); err != nil {
+			t.Fatal(err.Error())
+		}
+		plugin := newTestPlugin(t)
+		if err := generate(plugin, genOpts); err != nil {
+			t.Fatal(err.Error())
+		}
+		content := plugin.Response().GetFile()[0].GetContent()
+		for _, expected := range []string{
+			// the mux and the client use the same id
+			"const SRPCNamesServiceID = " + tc.expected,
+			"c.cc.Invoke(ctx, SRPCNamesServiceID, SRPCNamesListMethodID, in, out)",
+		} {
+			if !strings.Contains(content, expected) {
+				t.Fatalf("%s: expected generated code to contain %q:\n%s", tc.value, expected, content)
+			}
+		}
+	}
+
+	genOpts := &generatorOptions{}
+	if err := genOpts.setServiceID("legacy"); err == nil {
+		t.Fatal("expected error for invalid service_id")
+	}
+	if err := genOpts.setServiceID("{{.Missing}}"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := generate(newTestPlugin(t), genOpts); err == nil {
+		t.Fatal("expected error for unknown template field")
+	}
+}
@@ -0,0 +1,184 @@
+//go:build !starpc_core
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aperturerobotics/starpc/capabilities"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// checkConfig contains the options for the checks.
+type checkConfig struct {
+	// pings is the number of pings to measure the round trip time.
+	pings int
+	// maxProbeSize is the largest message size to probe.
+	maxProbeSize int
+	// idle is the duration to leave the connection idle.
+	idle time.Duration
+}
+
+// checkResult is the result of a check.
+type checkResult struct {
+	// name is the name of the check.
+	name string
+	// err is the error if the check failed.
+	err error
+	// detail describes the result.
+	detail string
+}
+
+// check is a protocol check against the remote.
+type check struct {
+	// name is the name of the check.
+	name string
+	// run runs the check returning the detail.
+	run func(ctx context.Context, client srpc.Client, conf *checkConfig) (string, error)
+}
+
+// checks are the checks in the order they run.
+var checks = []check{
+	{name: "handshake", run: checkHandshake},
+	{name: "ping", run: checkPing},
+	{name: "max-message-size", run: checkMaxMessageSize},
+	{name: "keepalive", run: checkKeepAlive},
+	{name: "clean-close", run: checkCleanClose},
+}
+
+// runChecks runs the checks against the client.
+func runChecks(ctx context.Context, client srpc.Client, conf *checkConfig) []*checkResult {
+	results := make([]*checkResult, 0, len(checks))
+	for _, c := range checks {
+		detail, err := c.run(ctx, client, conf)
+		results = append(results, &checkResult{name: c.name, err: err, detail: detail})
+	}
+	return results
+}
+
+// checkHandshake exchanges the capabilities with the remote.
+func checkHandshake(ctx context.Context, client srpc.Client, conf *checkConfig) (string, error) {
+	remote, err := capabilities.ExchangeCapabilities(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	if remote.GetProtocolVersion() == 0 {
+		return "capability exchange not supported by the remote", nil
+	}
+	if remote.GetProtocolVersion() != srpc.ProtocolVersion {
+		return "", errors.Errorf("protocol version mismatch: local %d remote %d", srpc.ProtocolVersion, remote.GetProtocolVersion())
+	}
+	return fmt.Sprintf(
+		"protocol version %d, features [%s], compression [%s]",
+		remote.GetProtocolVersion(),
+		strings.Join(remote.GetFeatures(), ", "),
+		strings.Join(remote.GetCompression(), ", "),
+	), nil
+}
+
+// checkPing measures the round trip time with the built-in ping service.
+func checkPing(ctx context.Context, client srpc.Client, conf *checkConfig) (string, error) {
+	var minRTT, maxRTT, total time.Duration
+	for i := 0; i < conf.pings; i++ {
+		rtt, err := client.Ping(ctx)
+		if err != nil {
+			return "", err
+		}
+		if i == 0 || rtt < minRTT {
+			minRTT = rtt
+		}
+		if rtt > maxRTT {
+			maxRTT = rtt
+		}
+		total += rtt
+	}
+	if conf.pings == 0 {
+		return "skipped", nil
+	}
+	return fmt.Sprintf("rtt min %v avg %v max %v", minRTT, total/time.Duration(conf.pings), maxRTT), nil
+}
+
+// checkMaxMessageSize finds the largest message echoed by the ping service.
+//
+// Doubles the size from 1KiB up to the max probe size.
+func checkMaxMessageSize(ctx context.Context, client srpc.Client, conf *checkConfig) (string, error) {
+	var largest int
+	for size := 1024; size <= conf.maxProbeSize; size *= 2 {
+		payload := bytes.Repeat([]byte{0xa5}, size)
+		out := srpc.NewRawMessage(nil)
+		err := client.Invoke(ctx, srpc.PingServiceID, srpc.PingMethodID, srpc.NewRawMessage(payload), out)
+		if err == nil && !bytes.Equal(out.GetData(), payload) {
+			err = errors.Errorf("echoed %d bytes did not match", len(out.GetData()))
+		}
+		if err != nil {
+			if largest == 0 {
+				return "", errors.Wrapf(err, "%d bytes", size)
+			}
+			return fmt.Sprintf("%d bytes (%d bytes failed: %v)", largest, size, err), nil
+		}
+		largest = size
+	}
+	return fmt.Sprintf("at least %d bytes", largest), nil
+}
+
+// checkKeepAlive checks the connection is usable after being idle.
+func checkKeepAlive(ctx context.Context, client srpc.Client, conf *checkConfig) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-time.After(conf.idle):
+	}
+	rtt, err := client.Ping(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "ping after %v idle", conf.idle)
+	}
+	return fmt.Sprintf("ping after %v idle: rtt %v", conf.idle, rtt), nil
+}
+
+// checkCleanClose checks the remote completes a stream after CloseSend.
+func checkCleanClose(ctx context.Context, client srpc.Client, conf *checkConfig) (string, error) {
+	strm, err := client.NewStream(ctx, srpc.PingServiceID, srpc.PingMethodID, srpc.NewRawMessage([]byte("doctor")))
+	if err != nil {
+		return "", err
+	}
+	defer strm.Close()
+	if err := strm.CloseSend(); err != nil {
+		return "", err
+	}
+	out := srpc.NewRawMessage(nil)
+	if err := strm.MsgRecv(out); err != nil {
+		return "", err
+	}
+	if string(out.GetData()) != "doctor" {
+		return "", errors.Errorf("unexpected response %q", out.GetData())
+	}
+	if err := strm.MsgRecv(out); err != io.EOF {
+		return "", errors.Errorf("expected EOF after the response got %v", err)
+	}
+	return "stream completed with EOF", nil
+}
+
+// writeSummary writes the results and returns if all checks passed.
+func writeSummary(w io.Writer, results []*checkResult) bool {
+	healthy := true
+	for _, result := range results {
+		if result.err != nil {
+			healthy = false
+			fmt.Fprintf(w, "FAIL %s: %v\n", result.name, result.err)
+		} else {
+			fmt.Fprintf(w, "PASS %s: %s\n", result.name, result.detail)
+		}
+	}
+	if healthy {
+		fmt.Fprintln(w, "healthy")
+	} else {
+		fmt.Fprintln(w, "unhealthy")
+	}
+	return healthy
+}
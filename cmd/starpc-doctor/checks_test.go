@@ -0,0 +1,63 @@
+//go:build !starpc_core
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/capabilities"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestRunChecks(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := capabilities.NewServer().Register(mux); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux)))
+
+	conf := &checkConfig{pings: 3, maxProbeSize: 64 * 1024, idle: time.Millisecond}
+	results := runChecks(context.Background(), client, conf)
+	if len(results) != len(checks) {
+		t.Fatalf("expected %d results got %d", len(checks), len(results))
+	}
+	for _, result := range results {
+		if result.err != nil {
+			t.Fatalf("check %s failed: %v", result.name, result.err)
+		}
+	}
+	if !strings.HasPrefix(results[0].detail, "protocol version") {
+		t.Fatalf("unexpected handshake result: %q", results[0].detail)
+	}
+	if results[2].detail != "at least 65536 bytes" {
+		t.Fatalf("unexpected max message size result: %q", results[2].detail)
+	}
+
+	var summary bytes.Buffer
+	if !writeSummary(&summary, results) || !strings.HasSuffix(summary.String(), "healthy\n") {
+		t.Fatalf("unexpected summary: %s", summary.String())
+	}
+}
+
+func TestRunChecks_Unhealthy(t *testing.T) {
+	// expect the checks to fail if the remote rejects the calls
+	server := srpc.NewServer(srpc.NewMux(), srpc.WithInterceptors(
+		func(ctx context.Context, serviceID, methodID string, strm srpc.Stream, next srpc.Invoker) (bool, error) {
+			return true, srpc.ErrUnavailable
+		},
+	))
+	client := srpc.NewClient(srpc.NewServerPipe(server))
+
+	conf := &checkConfig{pings: 1, maxProbeSize: 1024, idle: time.Millisecond}
+	var summary bytes.Buffer
+	if writeSummary(&summary, runChecks(context.Background(), client, conf)) {
+		t.Fatalf("expected unhealthy summary: %s", summary.String())
+	}
+	if !strings.Contains(summary.String(), "FAIL ping: ") || !strings.HasSuffix(summary.String(), "unhealthy\n") {
+		t.Fatalf("unexpected summary: %s", summary.String())
+	}
+}
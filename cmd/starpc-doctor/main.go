@@ -0,0 +1,55 @@
+//go:build !starpc_core
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func main() {
+	addr := flag.String("addr", "", "tcp address of the endpoint")
+	http2URL := flag.String("http2", "", "url of the endpoint served with the HTTP/2 transport")
+	pings := flag.Int("pings", 5, "number of pings to measure the round trip time")
+	maxProbeSize := flag.Int("max-probe-size", 16*1024*1024, "largest message size to probe in bytes")
+	idle := flag.Duration("idle", 5*time.Second, "duration to leave the connection idle before the keepalive check")
+	timeout := flag.Duration("timeout", time.Minute, "timeout for all checks")
+	flag.Parse()
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), *timeout)
+	defer ctxCancel()
+
+	var client srpc.Client
+	switch {
+	case *addr != "":
+		var err error
+		var conn net.Conn
+		client, conn, err = srpc.Dial(ctx, *addr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "dial %s: %v\n", *addr, err)
+			os.Exit(1)
+		}
+		defer conn.Close()
+	case *http2URL != "":
+		client = srpc.NewClient(srpc.NewHTTP2OpenStream(http.DefaultClient, *http2URL))
+	default:
+		fmt.Fprintln(os.Stderr, "usage: starpc-doctor -addr host:port | -http2 url")
+		os.Exit(2)
+	}
+
+	conf := &checkConfig{
+		pings:        *pings,
+		maxProbeSize: *maxProbeSize,
+		idle:         *idle,
+	}
+	if !writeSummary(os.Stdout, runChecks(ctx, client, conf)) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,59 @@
+//go:build go1.23 && !starpc_core
+
+package e2e
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/pkg/errors"
+)
+
+func TestE2E_ServerStreamAll(t *testing.T) {
+	ctx := context.Background()
+	RunE2E(t, func(client echo.SRPCEchoerClient) error {
+		req := &echo.EchoMsg{Body: "hello world"}
+		strm, err := client.EchoServerStream(ctx, req)
+		if err != nil {
+			return err
+		}
+		var count int
+		for msg, err := range strm.All() {
+			if err != nil {
+				return err
+			}
+			if msg.GetBody() != req.GetBody() {
+				return errors.Errorf("expected %q got %q", req.GetBody(), msg.GetBody())
+			}
+			count++
+		}
+		if count != 5 {
+			return errors.Errorf("expected 5 messages got %d", count)
+		}
+		return nil
+	})
+}
+
+func TestE2E_ClientStreamSendAll(t *testing.T) {
+	ctx := context.Background()
+	RunE2E(t, func(client echo.SRPCEchoerClient) error {
+		strm, err := client.EchoClientStream(ctx)
+		if err != nil {
+			return err
+		}
+		req := &echo.EchoMsg{Body: "hello world"}
+		if err := strm.SendAll(slices.Values([]*echo.EchoMsg{req})); err != nil {
+			return err
+		}
+		out, err := strm.CloseAndRecv()
+		if err != nil {
+			return err
+		}
+		if out.GetBody() != req.GetBody() {
+			return errors.Errorf("expected %q got %q", req.GetBody(), out.GetBody())
+		}
+		return nil
+	})
+}
@@ -1,47 +1,90 @@
+//go:build !starpc_core
+
 package e2e
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/aperturerobotics/starpc/buildinfo"
+	"github.com/aperturerobotics/starpc/capabilities"
+	"github.com/aperturerobotics/starpc/e2e/mock"
 	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/examples/chat"
+	"github.com/aperturerobotics/starpc/examples/fileupload"
+	"github.com/aperturerobotics/starpc/examples/tunnel"
 	"github.com/aperturerobotics/starpc/rpcstream"
 	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p/p2p/muxer/mplex"
 	mp "github.com/libp2p/go-mplex"
 	"github.com/pkg/errors"
+	"go.uber.org/goleak"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"nhooyr.io/websocket"
 )
 
-// RunE2E runs an end to end test with a callback.
-func RunE2E(t *testing.T, cb func(client echo.SRPCEchoerClient) error) {
-	// construct the server
-	mux := srpc.NewMux()
-	echoServer := echo.NewEchoServer(mux)
-	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
-		t.Fatal(err.Error())
-	}
-	server := srpc.NewServer(mux)
+// e2eTransport connects a client to a server over a transport.
+type e2eTransport struct {
+	// name is the transport name used for the subtest.
+	name string
+	// connect connects a client to the server.
+	// any resources are released with t.Cleanup.
+	connect func(t *testing.T, ctx context.Context, server *srpc.Server) (srpc.Client, error)
+}
+
+// e2eTransports is the list of transports to run the e2e tests with.
+var e2eTransports = []e2eTransport{
+	{name: "pipe", connect: connectPipe},
+	{name: "mplex-pipe", connect: connectMplexPipe},
+	{name: "tcp", connect: connectTCP},
+	{name: "unix", connect: connectUnix},
+	{name: "websocket", connect: connectWebSocket},
+}
 
-	// note: also possible to do without mplex:
-	// openStream := srpc.NewServerPipe(server)
-	// client := srpc.NewClient(openStream)
+// connectPipe connects with an in-memory pipe for each stream.
+func connectPipe(t *testing.T, ctx context.Context, server *srpc.Server) (srpc.Client, error) {
+	return srpc.NewClient(srpc.NewServerPipe(server)), nil
+}
 
-	// construct the client
+// connectMplexPipe connects with mplex over an in-memory pipe.
+func connectMplexPipe(t *testing.T, ctx context.Context, server *srpc.Server) (srpc.Client, error) {
 	clientPipe, serverPipe := net.Pipe()
+	t.Cleanup(func() {
+		_ = clientPipe.Close()
+		_ = serverPipe.Close()
+	})
 
 	// outbound=true
 	clientMp, err := mp.NewMultiplex(clientPipe, true, nil)
 	if err != nil {
-		t.Fatal(err.Error())
+		return nil, err
 	}
 	client := srpc.NewClientWithMuxedConn(mplex.NewMuxedConn(clientMp))
 
-	ctx := context.Background()
 	// outbound=false
-	serverMp, _ := mp.NewMultiplex(serverPipe, false, nil)
+	serverMp, err := mp.NewMultiplex(serverPipe, false, nil)
+	if err != nil {
+		return nil, err
+	}
 	go func() {
 		_ = server.AcceptMuxedConn(ctx, mplex.NewMuxedConn(serverMp))
 	}()
@@ -49,14 +92,105 @@ func RunE2E(t *testing.T, cb func(client echo.SRPCEchoerClient) error) {
 	// TODO: requires a moment for the listener to start: not sure why.
 	// the packets /should/ be buffered in the pipe.
 	<-time.After(time.Millisecond * 100)
+	return client, nil
+}
+
+// connectListener accepts connections from the listener and dials it.
+func connectListener(t *testing.T, ctx context.Context, server *srpc.Server, lis net.Listener) (srpc.Client, error) {
+	t.Cleanup(func() {
+		_ = lis.Close()
+	})
+	go func() {
+		_ = srpc.AcceptMuxedListener(ctx, lis, server)
+	}()
+
+	var dialer net.Dialer
+	nc, err := dialer.DialContext(ctx, lis.Addr().Network(), lis.Addr().String())
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() {
+		_ = nc.Close()
+	})
+	return srpc.NewClientWithConn(nc, true)
+}
+
+// connectTCP connects with mplex over a local TCP connection.
+func connectTCP(t *testing.T, ctx context.Context, server *srpc.Server) (srpc.Client, error) {
+	lis, err := srpc.Listen("127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	return connectListener(t, ctx, server, lis)
+}
+
+// connectUnix connects with mplex over a unix socket.
+func connectUnix(t *testing.T, ctx context.Context, server *srpc.Server) (srpc.Client, error) {
+	lis, err := net.Listen("unix", filepath.Join(t.TempDir(), "srpc.sock"))
+	if err != nil {
+		return nil, err
+	}
+	return connectListener(t, ctx, server, lis)
+}
+
+// connectWebSocket connects with mplex over a WebSocket.
+func connectWebSocket(t *testing.T, ctx context.Context, server *srpc.Server) (srpc.Client, error) {
+	httpServer, err := srpc.NewHTTPServer(server.GetMux(), "/srpc")
+	if err != nil {
+		return nil, err
+	}
+	hs := httptest.NewServer(httpServer)
+	t.Cleanup(hs.Close)
 
-	// construct the client rpc interface
-	clientEcho := echo.NewSRPCEchoerClient(client)
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(hs.URL, "http")+"/srpc", nil)
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() {
+		_ = conn.Close(websocket.StatusNormalClosure, "test complete")
+	})
+	wsConn, err := srpc.NewWebSocketConn(ctx, conn, false)
+	if err != nil {
+		return nil, err
+	}
+	return srpc.NewClient(wsConn.GetOpenStreamFunc()), nil
+}
 
-	// call
-	if err := cb(clientEcho); err != nil {
+// RunE2E runs an end to end test with a callback.
+//
+// Runs the callback as a subtest with each of the e2eTransports.
+func RunE2E(t *testing.T, cb func(client echo.SRPCEchoerClient) error) {
+	// construct the server
+	mux := srpc.NewMux()
+	echoServer := echo.NewEchoServer(mux)
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
 		t.Fatal(err.Error())
 	}
+	if err := buildinfo.NewServer(buildinfo.CheckCompatible).Register(mux); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux)
+
+	for _, transport := range e2eTransports {
+		transport := transport
+		t.Run(transport.name, func(t *testing.T) {
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+
+			client, err := transport.connect(t, ctx, server)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			// construct the client rpc interface
+			clientEcho := echo.NewSRPCEchoerClient(client)
+
+			// call
+			if err := cb(clientEcho); err != nil {
+				t.Fatal(err.Error())
+			}
+		})
+	}
 }
 
 func TestE2E_Unary(t *testing.T) {
@@ -67,15 +201,125 @@ func TestE2E_Unary(t *testing.T) {
 			Body: bodyTxt,
 		})
 		if err != nil {
-			t.Fatal(err.Error())
+			return err
 		}
 		if out.GetBody() != bodyTxt {
-			t.Fatalf("expected %q got %q", bodyTxt, out.GetBody())
+			return errors.Errorf("expected %q got %q", bodyTxt, out.GetBody())
+		}
+		return nil
+	})
+}
+
+func TestE2E_RawResponse(t *testing.T) {
+	RunE2E(t, func(client echo.SRPCEchoerClient) error {
+		req := &echo.EchoMsg{Body: "hello world"}
+		var raw []byte
+		ctx := srpc.WithCallOptions(context.Background(), srpc.WithRawResponse(&raw))
+		err := client.SRPCClient().Invoke(ctx, echo.SRPCEchoerServiceID, "Echo", req, nil)
+		if err != nil {
+			return err
+		}
+		out := &echo.EchoMsg{}
+		if err := out.UnmarshalVT(raw); err != nil {
+			return err
+		}
+		if out.GetBody() != req.GetBody() {
+			return errors.Errorf("expected %q got %q", req.GetBody(), out.GetBody())
+		}
+		return nil
+	})
+}
+
+func TestE2E_ClientTrace(t *testing.T) {
+	RunE2E(t, func(client echo.SRPCEchoerClient) error {
+		var mtx sync.Mutex
+		var phases []string
+		addPhase := func(phase string) {
+			mtx.Lock()
+			phases = append(phases, phase)
+			mtx.Unlock()
+		}
+		ctx := srpc.WithClientTrace(context.Background(), &srpc.ClientTrace{
+			ConnectStart: func(service, method string) { addPhase("connect-start") },
+			ConnectDone:  func(err error) { addPhase("connect-done") },
+			StreamOpened: func() { addPhase("stream-opened") },
+			FirstByte:    func() { addPhase("first-byte") },
+			Complete:     func(err error) { addPhase("complete") },
+		})
+		if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello world"}); err != nil {
+			return err
+		}
+		mtx.Lock()
+		got := strings.Join(phases, ",")
+		mtx.Unlock()
+		expected := "connect-start,connect-done,stream-opened,first-byte,complete"
+		if got != expected {
+			return errors.Errorf("expected phases %q got %q", expected, got)
 		}
 		return nil
 	})
 }
 
+// connInfoEchoServer returns the server ConnInfo in the Echo response.
+type connInfoEchoServer struct {
+	*echo.EchoServer
+}
+
+// Echo returns the transport and muxer in the message body.
+func (s *connInfoEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	info := srpc.ConnInfoFromContext(ctx)
+	if info == nil {
+		return nil, errors.New("expected conn info in context")
+	}
+	return &echo.EchoMsg{Body: info.Transport + "/" + info.Muxer}, nil
+}
+
+func TestE2E_TCP(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &connInfoEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux)
+
+	tcpOpts := []srpc.TCPOption{
+		srpc.WithTCPNoDelay(true),
+		srpc.WithTCPKeepAlive(time.Second * 30),
+		srpc.WithTCPReadBufferSize(64 * 1024),
+		srpc.WithTCPWriteBufferSize(64 * 1024),
+	}
+	lis, err := srpc.Listen("127.0.0.1:0", tcpOpts...)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer lis.Close()
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	go func() {
+		_ = srpc.AcceptMuxedListener(ctx, lis, server)
+	}()
+
+	client, conn, err := srpc.Dial(ctx, lis.Addr().String(), tcpOpts...)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer conn.Close()
+
+	clientInfo := srpc.GetConnInfo(client)
+	if clientInfo.GetTLSVersion() != 0 || clientInfo.Transport != "tcp" || clientInfo.Muxer != srpc.MuxerMplex {
+		t.Fatalf("unexpected client conn info: %#v", clientInfo)
+	}
+
+	out, err := echo.NewSRPCEchoerClient(client).Echo(ctx, &echo.EchoMsg{Body: "hello world"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if expected := "tcp/" + srpc.MuxerMplex; out.GetBody() != expected {
+		t.Fatalf("expected server conn info %q got %q", expected, out.GetBody())
+	}
+}
+
 // CheckServerStream checks the server stream portion of the Echo test.
 func CheckServerStream(t *testing.T, out echo.SRPCEchoer_EchoServerStreamClient, req *echo.EchoMsg) error {
 	// expect to rx 5, then close
@@ -112,12 +356,48 @@ func TestE2E_ServerStream(t *testing.T) {
 		}
 		out, err := client.EchoServerStream(ctx, req)
 		if err != nil {
-			t.Fatal(err.Error())
+			return err
 		}
 		return CheckServerStream(t, out, req)
 	})
 }
 
+func TestE2E_Select(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	RunE2E(t, func(client echo.SRPCEchoerClient) error {
+		var streams []srpc.StreamRecv[*echo.EchoMsg]
+		for _, body := range []string{"hello from stream 0", "hello from stream 1"} {
+			strm, err := client.EchoServerStream(ctx, &echo.EchoMsg{Body: body})
+			if err != nil {
+				return err
+			}
+			streams = append(streams, strm)
+		}
+
+		// expect 5 messages and io.EOF from each stream
+		counts := make([]int, len(streams))
+		for res := range srpc.Select(ctx, streams...) {
+			if res.Err == io.EOF {
+				continue
+			}
+			if res.Err != nil {
+				return res.Err
+			}
+			if expected := "hello from stream " + strconv.Itoa(res.Index); res.Msg.GetBody() != expected {
+				return errors.Errorf("expected %q got %q", expected, res.Msg.GetBody())
+			}
+			counts[res.Index]++
+		}
+		for i, count := range counts {
+			if count != 5 {
+				return errors.Errorf("expected 5 messages from stream %d got %d", i, count)
+			}
+		}
+		return nil
+	})
+}
+
 // CheckClientStream checks the server stream portion of the Echo test.
 func CheckClientStream(t *testing.T, out echo.SRPCEchoer_EchoClientStreamClient, req *echo.EchoMsg) error {
 	// send request
@@ -146,7 +426,7 @@ func TestE2E_ClientStream(t *testing.T) {
 		}
 		out, err := client.EchoClientStream(ctx)
 		if err != nil {
-			t.Fatal(err.Error())
+			return err
 		}
 		return CheckClientStream(t, out, req)
 	})
@@ -157,32 +437,176 @@ func TestE2E_BidiStream(t *testing.T) {
 	RunE2E(t, func(client echo.SRPCEchoerClient) error {
 		strm, err := client.EchoBidiStream(ctx)
 		if err != nil {
-			t.Fatal(err.Error())
+			return err
 		}
 		clientExpected := "hello from client"
 		if err := strm.MsgSend(&echo.EchoMsg{Body: clientExpected}); err != nil {
-			t.Fatal(err.Error())
+			return err
 		}
 		msg, err := strm.Recv()
 		if err != nil {
-			t.Fatal(err.Error())
+			return err
 		}
 		expected := "hello from server"
 		if msg.GetBody() != expected {
-			t.Fatalf("expected %q got %q", expected, msg.GetBody())
+			return errors.Errorf("expected %q got %q", expected, msg.GetBody())
 		}
 		msg, err = strm.Recv()
 		if err != nil {
-			t.Fatal(err.Error())
+			return err
 		}
 		if msg.GetBody() != clientExpected {
-			t.Fatalf("expected %q got %q", clientExpected, msg.GetBody())
+			return errors.Errorf("expected %q got %q", clientExpected, msg.GetBody())
 		}
 		// expect no error closing
 		return strm.Close()
 	})
 }
 
+func TestE2E_RecvTimeout(t *testing.T) {
+	ctx := context.Background()
+	RunE2E(t, func(client echo.SRPCEchoerClient) error {
+		strm, err := client.EchoBidiStream(ctx)
+		if err != nil {
+			return err
+		}
+		defer strm.Close()
+
+		// wait for the initial message from the server
+		msg, err := strm.RecvTimeout(time.Second * 5)
+		if err != nil {
+			return err
+		}
+		if expected := "hello from server"; msg.GetBody() != expected {
+			return errors.Errorf("expected %q got %q", expected, msg.GetBody())
+		}
+
+		// expect no more messages to be available
+		if _, ok, err := strm.TryRecv(); err != nil || ok {
+			return errors.Errorf("expected no message available: ok=%v err=%v", ok, err)
+		}
+		if _, err := strm.RecvTimeout(time.Millisecond * 50); err != srpc.ErrRecvTimeout {
+			return errors.Errorf("expected %v got %v", srpc.ErrRecvTimeout, err)
+		}
+
+		// send a message and poll for the echo
+		clientExpected := "hello from client"
+		if err := strm.Send(&echo.EchoMsg{Body: clientExpected}); err != nil {
+			return err
+		}
+		deadline := time.Now().Add(time.Second * 5)
+		for {
+			msg, ok, err := strm.TryRecv()
+			if err != nil {
+				return err
+			}
+			if ok {
+				if msg.GetBody() != clientExpected {
+					return errors.Errorf("expected %q got %q", clientExpected, msg.GetBody())
+				}
+				return nil
+			}
+			if time.Now().After(deadline) {
+				return errors.New("timed out polling for message")
+			}
+			<-time.After(time.Millisecond * 10)
+		}
+	})
+}
+
+func TestE2E_RecvBatch(t *testing.T) {
+	ctx := context.Background()
+	RunE2E(t, func(client echo.SRPCEchoerClient) error {
+		strm, err := client.EchoBidiStream(ctx)
+		if err != nil {
+			return err
+		}
+		defer strm.Close()
+
+		// wait for the initial message from the server
+		msgs, err := strm.RecvBatch(10)
+		if err != nil {
+			return err
+		}
+		if len(msgs) != 1 || msgs[0].GetBody() != "hello from server" {
+			return errors.Errorf("unexpected initial batch %v", msgs)
+		}
+
+		// expect the echoed messages in order in batches of at most 2
+		expected := []string{"one", "two", "three"}
+		for _, body := range expected {
+			if err := strm.Send(&echo.EchoMsg{Body: body}); err != nil {
+				return err
+			}
+		}
+		var bodies []string
+		for len(bodies) < len(expected) {
+			msgs, err := strm.RecvBatch(2)
+			if err != nil {
+				return err
+			}
+			if len(msgs) == 0 || len(msgs) > 2 {
+				return errors.Errorf("unexpected batch size %d", len(msgs))
+			}
+			for _, msg := range msgs {
+				bodies = append(bodies, msg.GetBody())
+			}
+		}
+		if strings.Join(bodies, ",") != strings.Join(expected, ",") {
+			return errors.Errorf("expected %v got %v", expected, bodies)
+		}
+		return nil
+	})
+}
+
+// trailerEchoServer fails the server stream after sending the message.
+type trailerEchoServer struct {
+	*echo.EchoServer
+}
+
+func (s *trailerEchoServer) EchoServerStream(msg *echo.EchoMsg, strm echo.SRPCEchoer_EchoServerStreamStream) error {
+	if err := strm.Send(msg); err != nil {
+		return err
+	}
+	return errors.New("final error")
+}
+
+func TestE2E_CloseOrdering(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &trailerEchoServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux)
+
+	for _, transport := range e2eTransports {
+		transport := transport
+		t.Run(transport.name, func(t *testing.T) {
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+			client, err := transport.connect(t, ctx, server)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			clientEcho := echo.NewSRPCEchoerClient(client)
+
+			// expect the final error after the message, never EOF
+			for i := 0; i < 20; i++ {
+				strm, err := clientEcho.EchoServerStream(ctx, &echo.EchoMsg{Body: "hello"})
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				if msg, err := strm.Recv(); err != nil || msg.GetBody() != "hello" {
+					t.Fatalf("expected message got %v: %v", msg, err)
+				}
+				if _, err := strm.Recv(); err == nil || err.Error() != "final error" {
+					t.Fatalf("expected final error got %v", err)
+				}
+				_ = strm.Close()
+			}
+		})
+	}
+}
+
 func TestE2E_RpcStream(t *testing.T) {
 	ctx := context.Background()
 	RunE2E(t, func(client echo.SRPCEchoerClient) error {
@@ -204,3 +628,1321 @@ func TestE2E_RpcStream(t *testing.T) {
 		return nil
 	})
 }
+
+func TestE2E_NestedClient(t *testing.T) {
+	ctx := context.Background()
+	RunE2E(t, func(client echo.SRPCEchoerClient) error {
+		proxiedSvc, release := rpcstream.NewNestedClient(func(ctx context.Context) (rpcstream.RpcStream, error) {
+			return client.RpcStream(ctx)
+		}, "test", echo.NewSRPCEchoerClient)
+
+		resp, err := proxiedSvc.Echo(ctx, &echo.EchoMsg{Body: "hello world"})
+		if err != nil {
+			release()
+			return err
+		}
+		if resp.GetBody() != "hello world" {
+			release()
+			return errors.Errorf("response body incorrect: %q", resp.GetBody())
+		}
+
+		release()
+		if _, err := proxiedSvc.Echo(ctx, &echo.EchoMsg{Body: "hello world"}); err != rpcstream.ErrNestedClientReleased {
+			return errors.Errorf("expected %v got %v", rpcstream.ErrNestedClientReleased, err)
+		}
+		return nil
+	})
+}
+
+func TestE2E_RpcStreamConnCache(t *testing.T) {
+	ctx := context.Background()
+	RunE2E(t, func(client echo.SRPCEchoerClient) error {
+		var dials int32
+		cache := rpcstream.NewConnCache(func(ctx context.Context) (rpcstream.RpcStream, error) {
+			atomic.AddInt32(&dials, 1)
+			return client.RpcStream(ctx)
+		}, time.Millisecond*100)
+		defer cache.Close()
+		proxiedSvc := echo.NewSRPCEchoerClient(cache.NewClient("test"))
+
+		// calls share a single rpc stream
+		for i := 0; i < 3; i++ {
+			resp, err := proxiedSvc.Echo(ctx, &echo.EchoMsg{Body: "hello world"})
+			if err != nil {
+				return err
+			}
+			if resp.GetBody() != "hello world" {
+				return errors.Errorf("response body incorrect: %q", resp.GetBody())
+			}
+		}
+		if n := atomic.LoadInt32(&dials); n != 1 {
+			return errors.Errorf("expected 1 rpc stream got %d", n)
+		}
+
+		// idle rpc stream is closed and reopened
+		<-time.After(time.Millisecond * 300)
+		if _, err := proxiedSvc.Echo(ctx, &echo.EchoMsg{Body: "hello world"}); err != nil {
+			return err
+		}
+		if n := atomic.LoadInt32(&dials); n != 2 {
+			return errors.Errorf("expected 2 rpc streams got %d", n)
+		}
+		return nil
+	})
+}
+
+func TestE2E_BuildInfo(t *testing.T) {
+	ctx := context.Background()
+	srpc.SetBuildInfo(&srpc.BuildInfo{Name: "e2e", Version: "v1.2.3"})
+	defer srpc.SetBuildInfo(nil)
+	RunE2E(t, func(client echo.SRPCEchoerClient) error {
+		remote, err := buildinfo.ExchangeBuildInfo(ctx, client.SRPCClient(), nil)
+		if err != nil {
+			return err
+		}
+		if remote.GetName() != "e2e" || remote.GetVersion() != "v1.2.3" {
+			return errors.Errorf("unexpected remote build info: %v", remote.String())
+		}
+		if remote.GetProtocolVersion() != srpc.ProtocolVersion {
+			return errors.Errorf("unexpected protocol version: %d", remote.GetProtocolVersion())
+		}
+
+		// expect an incompatible major version to be rejected
+		err = buildinfo.CheckCompatible(remote, &buildinfo.BuildInfo{Version: "v2.0.0"})
+		if !errors.Is(err, buildinfo.ErrIncompatibleVersion) {
+			return errors.Errorf("expected incompatible version error: %v", err)
+		}
+		return nil
+	})
+}
+
+// metadataEchoServer returns the incoming metadata value in the Echo response.
+type metadataEchoServer struct {
+	*echo.EchoServer
+}
+
+// Echo returns the metadata value in the message body.
+func (s *metadataEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	md := srpc.MetadataFromIncomingContext(ctx)
+	return &echo.EchoMsg{Body: md.Get(msg.GetBody())}, nil
+}
+
+// deprecatedHandler marks methods of a Handler as deprecated.
+type deprecatedHandler struct {
+	srpc.Handler
+	// methodIDs contains the deprecated method ids.
+	methodIDs []string
+}
+
+// GetDeprecatedMethodIDs returns the list of deprecated methods for the service.
+func (h *deprecatedHandler) GetDeprecatedMethodIDs() []string {
+	return h.methodIDs
+}
+
+// deprecatedMux registers handlers with deprecated methods.
+type deprecatedMux struct {
+	srpc.Mux
+	// methodIDs contains the deprecated method ids.
+	methodIDs []string
+}
+
+// Register registers the handler with the deprecated methods.
+func (m *deprecatedMux) Register(handler srpc.Handler) error {
+	return m.Mux.Register(&deprecatedHandler{Handler: handler, methodIDs: m.methodIDs})
+}
+
+func TestE2E_Deprecation(t *testing.T) {
+	var handlerCalls int32
+	mux := srpc.NewMux(srpc.WithDeprecationHandler(func(serviceID, methodID string) {
+		atomic.AddInt32(&handlerCalls, 1)
+	}))
+	dmux := &deprecatedMux{Mux: mux, methodIDs: []string{"Echo"}}
+	if err := echo.SRPCRegisterEchoer(dmux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	var md srpc.Metadata
+	ctx := srpc.WithCallOptions(context.Background(), srpc.WithResponseMetadata(func(resp srpc.Metadata) {
+		md = resp
+	}))
+	for i := 0; i < 2; i++ {
+		md = nil
+		if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil {
+			t.Fatal(err.Error())
+		}
+		if warning := md.Get(srpc.DeprecatedMetadataKey); warning != "echo.Echoer/Echo is deprecated" {
+			t.Fatalf("unexpected deprecation warning: %q", warning)
+		}
+	}
+
+	// not deprecated
+	md = nil
+	strm, err := client.EchoServerStream(ctx, &echo.EchoMsg{Body: "hello"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := CheckServerStream(t, strm, &echo.EchoMsg{Body: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(md) != 0 {
+		t.Fatalf("unexpected response metadata: %v", md)
+	}
+
+	var methods []srpc.MethodInfo
+	for _, svc := range mux.Services() {
+		if svc.ServiceID == echo.SRPCEchoerServiceID {
+			methods = svc.Methods
+		}
+	}
+	for _, method := range methods {
+		expected := method.MethodID == "Echo"
+		if method.Deprecated != expected {
+			t.Fatalf("method %s: expected deprecated %v", method.MethodID, expected)
+		}
+		if expected && method.DeprecatedCalls != 2 {
+			t.Fatalf("expected 2 deprecated calls got %d", method.DeprecatedCalls)
+		}
+	}
+	if calls := atomic.LoadInt32(&handlerCalls); calls != 2 {
+		t.Fatalf("expected 2 deprecation handler calls got %d", calls)
+	}
+}
+
+func TestE2E_Channelz(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	cz := srpc.NewChannelz()
+	client, err := connectTCP(t, ctx, srpc.NewServer(mux, srpc.WithChannelz(cz)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := echo.NewSRPCEchoerClient(client).Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// expect the connection to be listed with the completed stream
+	conns := cz.GetConns()
+	if len(conns) != 1 {
+		t.Fatalf("expected 1 conn got %v", conns)
+	}
+	if conn := conns[0]; conn.Transport != "tcp" || conn.RemoteAddr == "" || conn.TotalStreams != 1 {
+		t.Fatalf("unexpected conn %v", conn)
+	}
+}
+
+func TestE2E_ConnRotator(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	mux := srpc.NewMux()
+	echoServer := echo.NewEchoServer(mux)
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	lis, err := srpc.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer lis.Close()
+	go func() {
+		_ = srpc.AcceptMuxedListener(ctx, lis, srpc.NewServer(mux))
+	}()
+
+	var connsMtx sync.Mutex
+	var conns []network.MuxedConn
+	dial := srpc.NewTCPMuxedConnDialer(lis.Addr().String())
+	rotator := srpc.NewConnRotator(func(ctx context.Context) (network.MuxedConn, error) {
+		mconn, err := dial(ctx)
+		if err == nil {
+			connsMtx.Lock()
+			conns = append(conns, mconn)
+			connsMtx.Unlock()
+		}
+		return mconn, err
+	}, time.Millisecond*100)
+	defer rotator.Close()
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(rotator.OpenStream))
+
+	getConns := func() []network.MuxedConn {
+		connsMtx.Lock()
+		defer connsMtx.Unlock()
+		return append([]network.MuxedConn(nil), conns...)
+	}
+	msg := &echo.EchoMsg{Body: "hello world"}
+
+	// expect calls to share the connection until it expires
+	for i := 0; i < 3; i++ {
+		if _, err := client.Echo(ctx, msg); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if n := len(getConns()); n != 1 {
+		t.Fatalf("expected 1 dial got %d", n)
+	}
+
+	// expect a new connection after the max age and the old one to be closed
+	<-time.After(time.Millisecond * 150)
+	if _, err := client.Echo(ctx, msg); err != nil {
+		t.Fatal(err.Error())
+	}
+	dialed := getConns()
+	if len(dialed) != 2 {
+		t.Fatalf("expected 2 dials got %d", len(dialed))
+	}
+	for i := 0; i < 10 && !dialed[0].IsClosed(); i++ {
+		<-time.After(time.Millisecond * 10)
+	}
+	if !dialed[0].IsClosed() {
+		t.Fatal("expected expired connection to be closed")
+	}
+	if dialed[1].IsClosed() {
+		t.Fatal("expected current connection to be open")
+	}
+
+	rotator.Close()
+	if _, err := client.Echo(ctx, msg); err != srpc.ErrClientClosed {
+		t.Fatalf("expected %v got %v", srpc.ErrClientClosed, err)
+	}
+}
+
+func TestE2E_StreamPlacement(t *testing.T) {
+	ctx := context.Background()
+
+	// construct a lightly and a heavily loaded server
+	var clients []srpc.Client
+	for _, cpu := range []float64{0.1, 0.9} {
+		cpu := cpu
+		mux := srpc.NewMux()
+		if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+			t.Fatal(err.Error())
+		}
+		server := srpc.NewServer(mux, srpc.WithLoadReporter(func() *srpc.LoadReport {
+			return &srpc.LoadReport{CpuUtilization: cpu}
+		}))
+		client := srpc.NewClient(srpc.NewServerPipe(server))
+		if _, err := echo.NewSRPCEchoerClient(client).Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil {
+			t.Fatal(err.Error())
+		}
+		clients = append(clients, client)
+	}
+
+	// expect streams to be spread evenly regardless of the unary load
+	client := echo.NewSRPCEchoerClient(srpc.NewLeastLoadedClient(clients...))
+	var strms []echo.SRPCEchoer_EchoBidiStreamClient
+	for i := 0; i < 4; i++ {
+		strm, err := client.EchoBidiStream(ctx)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		strms = append(strms, strm)
+	}
+	for i, c := range clients {
+		if n := srpc.GetActiveStreams(c); n != 2 {
+			t.Fatalf("expected 2 streams on client %d got %d", i, n)
+		}
+	}
+
+	// expect the streams to be released when complete
+	for _, strm := range strms {
+		if _, err := strm.Recv(); err != nil {
+			t.Fatal(err.Error())
+		}
+		if err := strm.CloseSend(); err != nil {
+			t.Fatal(err.Error())
+		}
+		if _, err := strm.Recv(); err != io.EOF {
+			t.Fatalf("expected eof got %v", err)
+		}
+	}
+	for i := 0; i < 10 && srpc.GetActiveStreams(client.SRPCClient()) != 0; i++ {
+		<-time.After(time.Millisecond * 10)
+	}
+	if n := srpc.GetActiveStreams(client.SRPCClient()); n != 0 {
+		t.Fatalf("expected 0 active streams got %d", n)
+	}
+}
+
+func TestE2E_TracedMuxedConn(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var opened, accepted, localResets, remoteResets, closed int32
+	trace := &srpc.MuxedConnTrace{
+		StreamOpened: func(latency time.Duration, err error) {
+			if err == nil {
+				atomic.AddInt32(&opened, 1)
+			}
+		},
+		StreamAccepted: func(active int, err error) {
+			if err == nil {
+				atomic.AddInt32(&accepted, 1)
+			}
+		},
+		StreamReset: func(remote bool) {
+			if remote {
+				atomic.AddInt32(&remoteResets, 1)
+			} else {
+				atomic.AddInt32(&localResets, 1)
+			}
+		},
+		StreamClosed: func(lifetime time.Duration) {
+			atomic.AddInt32(&closed, 1)
+		},
+	}
+
+	clientPipe, serverPipe := net.Pipe()
+	defer clientPipe.Close()
+	defer serverPipe.Close()
+	clientMc, err := srpc.NewMuxedConn(clientPipe, true)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	serverMc, err := srpc.NewMuxedConn(serverPipe, false)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	clientConn := srpc.NewTracedMuxedConn(clientMc, trace)
+	serverConn := srpc.NewTracedMuxedConn(serverMc, trace)
+	go func() {
+		_ = srpc.NewServer(mux).AcceptMuxedConn(ctx, serverConn)
+	}()
+
+	client := echo.NewSRPCEchoerClient(srpc.NewClientWithMuxedConn(clientConn))
+	for i := 0; i < 3; i++ {
+		if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if n := atomic.LoadInt32(&opened); n != 3 {
+		t.Fatalf("expected 3 streams opened got %d", n)
+	}
+	if n := atomic.LoadInt32(&accepted); n != 3 {
+		t.Fatalf("expected 3 streams accepted got %d", n)
+	}
+
+	// expect a reset to be traced on both ends
+	strm, err := clientConn.OpenStream(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := strm.Reset(); err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 0; i < 50 && atomic.LoadInt32(&remoteResets) == 0; i++ {
+		<-time.After(time.Millisecond * 10)
+	}
+	if n := atomic.LoadInt32(&localResets); n != 1 {
+		t.Fatalf("expected 1 local reset got %d", n)
+	}
+	if n := atomic.LoadInt32(&remoteResets); n != 1 {
+		t.Fatalf("expected 1 remote reset got %d", n)
+	}
+	for i := 0; i < 50 && serverConn.ActiveStreams() != 0; i++ {
+		<-time.After(time.Millisecond * 10)
+	}
+	if n := serverConn.ActiveStreams(); n != 0 {
+		t.Fatalf("expected 0 active server streams got %d", n)
+	}
+	if atomic.LoadInt32(&closed) < 5 {
+		t.Fatalf("expected at least 5 streams closed got %d", atomic.LoadInt32(&closed))
+	}
+}
+
+func TestE2E_ServerRun(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux)
+	msg := &echo.EchoMsg{Body: "hello world"}
+	baseGoroutines := runtime.NumGoroutine()
+
+	// serve a tcp and a unix listener
+	tcpLis, err := srpc.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	unixLis, err := net.Listen("unix", filepath.Join(t.TempDir(), "srpc.sock"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Run(ctx, tcpLis, unixLis)
+	}()
+
+	for _, lis := range []net.Listener{tcpLis, unixLis} {
+		var dialer net.Dialer
+		nc, err := dialer.DialContext(ctx, lis.Addr().Network(), lis.Addr().String())
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		client, err := srpc.NewClientWithConn(nc, true)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if _, err := echo.NewSRPCEchoerClient(client).Echo(ctx, msg); err != nil {
+			t.Fatal(err.Error())
+		}
+		_ = nc.Close()
+	}
+
+	// expect Run to return nil after joining all goroutines when canceled
+	ctxCancel()
+	if err := <-errCh; err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 0; i < 50 && runtime.NumGoroutine() > baseGoroutines; i++ {
+		<-time.After(time.Millisecond * 10)
+	}
+	if n := runtime.NumGoroutine(); n > baseGoroutines {
+		t.Fatalf("expected %d goroutines after Run got %d", baseGoroutines, n)
+	}
+
+	// expect Run to return the error if a listener fails
+	lis, err := srpc.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	go func() {
+		errCh <- server.Run(context.Background(), lis)
+	}()
+	_ = lis.Close()
+	if err := <-errCh; err == nil {
+		t.Fatal("expected error from closed listener")
+	}
+}
+
+// releaseEchoServer blocks Echo until released.
+type releaseEchoServer struct {
+	*echo.EchoServer
+	started chan struct{}
+	release chan struct{}
+}
+
+// Echo waits for the release.
+func (s *releaseEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	s.started <- struct{}{}
+	<-s.release
+	return msg, nil
+}
+
+func TestE2E_ServerRunShutdown(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &releaseEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		started:    make(chan struct{}, 1),
+		release:    make(chan struct{}),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux)
+	lis, err := srpc.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx := context.Background()
+	runErrCh := make(chan error, 1)
+	go func() {
+		runErrCh <- server.Run(ctx, lis)
+	}()
+
+	client, nc, err := srpc.Dial(ctx, lis.Addr().String())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer nc.Close()
+	callErrCh := make(chan error, 1)
+	go func() {
+		_, err := echo.NewSRPCEchoerClient(client).Echo(ctx, &echo.EchoMsg{Body: "hello"})
+		callErrCh <- err
+	}()
+	<-echoServer.started
+
+	// expect Run to serve the open call until it completes
+	shutdownErrCh := make(chan error, 1)
+	go func() {
+		shutdownErrCh <- server.Shutdown(ctx)
+	}()
+	select {
+	case err := <-runErrCh:
+		t.Fatalf("expected Run to wait for the open call got %v", err)
+	case <-time.After(time.Millisecond * 50):
+	}
+	close(echoServer.release)
+	if err := <-callErrCh; err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := <-shutdownErrCh; err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := <-runErrCh; err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+// slowStopEchoServer blocks Echo until canceled and returns after a delay.
+type slowStopEchoServer struct {
+	*echo.EchoServer
+	started chan struct{}
+	exited  int32
+}
+
+// Echo waits for the context to be canceled.
+func (s *slowStopEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	s.started <- struct{}{}
+	<-ctx.Done()
+	<-time.After(time.Millisecond * 50)
+	atomic.StoreInt32(&s.exited, 1)
+	return nil, context.Canceled
+}
+
+func TestE2E_GoroutineLeaks(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	mux := srpc.NewMux()
+	echoServer := &slowStopEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		started:    make(chan struct{}, 1),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux)
+	msg := &echo.EchoMsg{Body: "hello world"}
+
+	clientPipe, serverPipe := net.Pipe()
+	clientConn, err := srpc.NewMuxedConn(clientPipe, true)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer clientConn.Close()
+	serverConn, err := srpc.NewMuxedConn(serverPipe, false)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	acceptDone := make(chan error, 1)
+	go func() {
+		acceptDone <- server.AcceptMuxedConn(context.Background(), serverConn)
+	}()
+
+	client := echo.NewSRPCEchoerClient(srpc.NewClientWithMuxedConn(clientConn))
+	callDone := make(chan error, 1)
+	go func() {
+		_, err := client.Echo(context.Background(), msg)
+		callDone <- err
+	}()
+	<-echoServer.started
+
+	// expect closing the connection to join the handler goroutine
+	_ = serverConn.Close()
+	<-acceptDone
+	if atomic.LoadInt32(&echoServer.exited) == 0 {
+		t.Fatal("expected handler to exit before AcceptMuxedConn returned")
+	}
+	if err := <-callDone; err == nil {
+		t.Fatal("expected error from closed connection")
+	}
+}
+
+func TestE2E_Capabilities(t *testing.T) {
+	ctx := srpc.AppendToOutgoingContext(context.Background(), "x-request-id", "req-1")
+	msg := &echo.EchoMsg{Body: "x-request-id"}
+	for _, withCapabilities := range []bool{true, false} {
+		mux := srpc.NewMux()
+		echoServer := &metadataEchoServer{EchoServer: echo.NewEchoServer(mux)}
+		if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+			t.Fatal(err.Error())
+		}
+		if withCapabilities {
+			if err := capabilities.NewServer().Register(mux); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+
+		client, err := capabilities.Discover(ctx, srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		remote := client.GetRemoteCapabilities()
+		if remote.HasFeature(capabilities.FeatureMetadata) != withCapabilities {
+			t.Fatalf("unexpected remote capabilities: %v", remote.String())
+		}
+		if withCapabilities && remote.GetProtocolVersion() != srpc.ProtocolVersion {
+			t.Fatalf("unexpected protocol version: %d", remote.GetProtocolVersion())
+		}
+
+		// expect the metadata to be sent only if the remote supports it
+		resp, err := echo.NewSRPCEchoerClient(client).Echo(ctx, msg)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		expected := ""
+		if withCapabilities {
+			expected = "req-1"
+		}
+		if resp.GetBody() != expected {
+			t.Fatalf("expected metadata %q got %q", expected, resp.GetBody())
+		}
+	}
+}
+
+func TestE2E_HTTP2(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &connInfoEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	ts := httptest.NewUnstartedServer(srpc.NewHTTP2Handler(srpc.NewServer(mux)))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewHTTP2OpenStream(ts.Client(), ts.URL)))
+	resp, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != srpc.TransportHTTP2+"/" {
+		t.Fatalf("expected http2 transport got %q", resp.GetBody())
+	}
+
+	// expect streaming calls to use a single HTTP/2 stream
+	strm, err := client.EchoBidiStream(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	// the server sends a message first
+	if _, err := strm.Recv(); err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 0; i < 3; i++ {
+		body := "msg-" + strconv.Itoa(i)
+		if err := strm.Send(&echo.EchoMsg{Body: body}); err != nil {
+			t.Fatal(err.Error())
+		}
+		out, err := strm.Recv()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if out.GetBody() != body {
+			t.Fatalf("expected %q got %q", body, out.GetBody())
+		}
+	}
+	if err := strm.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// expect HTTP/1 requests to be rejected
+	h1TLSConf := ts.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+	h1TLSConf.NextProtos = []string{"http/1.1"}
+	h1Client := &http.Client{Transport: &http.Transport{TLSClientConfig: h1TLSConf}}
+	h1Resp, err := h1Client.Post(ts.URL, "application/octet-stream", nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	_ = h1Resp.Body.Close()
+	if h1Resp.StatusCode != http.StatusHTTPVersionNotSupported {
+		t.Fatalf("expected HTTP/1 request to be rejected got %s", h1Resp.Status)
+	}
+}
+
+// floodEchoServer sends messages on EchoServerStream until the call ends.
+type floodEchoServer struct {
+	*echo.EchoServer
+}
+
+// EchoServerStream sends the message until the call is canceled.
+func (s *floodEchoServer) EchoServerStream(msg *echo.EchoMsg, strm echo.SRPCEchoer_EchoServerStreamStream) error {
+	for {
+		if err := strm.Send(msg); err != nil {
+			return err
+		}
+	}
+}
+
+func TestE2E_HTTP2ConcurrentSend(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &floodEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	// the server pings the idle client while the handler is sending
+	params := srpc.HeartbeatParams{Interval: time.Millisecond, Timeout: time.Second}
+	ts := httptest.NewUnstartedServer(srpc.NewHTTP2Handler(srpc.NewServer(mux, srpc.WithServerHeartbeat(params))))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	ctx := srpc.WithCallOptions(context.Background(), srpc.WithClientHeartbeat(params))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewHTTP2OpenStream(ts.Client(), ts.URL)))
+	for i := 0; i < 10; i++ {
+		// cancel while the handler is sending
+		callCtx, callCtxCancel := context.WithCancel(ctx)
+		strm, err := client.EchoServerStream(callCtx, &echo.EchoMsg{Body: "hello"})
+		if err != nil {
+			callCtxCancel()
+			t.Fatal(err.Error())
+		}
+		for j := 0; j < 20; j++ {
+			if _, err := strm.Recv(); err != nil {
+				callCtxCancel()
+				t.Fatal(err.Error())
+			}
+			<-time.After(time.Millisecond / 4)
+		}
+		callCtxCancel()
+		_ = strm.Close()
+	}
+}
+
+func TestE2E_ExampleChat(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer ctxCancel()
+
+	mux := srpc.NewMux()
+	if err := chat.NewRoom().Register(mux); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := chat.NewSRPCChatClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	alice, err := chat.JoinRoom(ctx, client, "alice")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer alice.Close()
+	bob, err := chat.JoinRoom(ctx, client, "bob")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer bob.Close()
+
+	// expect alice to see bob join
+	msg, err := alice.Recv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !msg.GetJoined() || msg.GetSender() != "bob" {
+		t.Fatalf("expected bob to join got %v", msg.String())
+	}
+
+	// expect both members to receive the message with the sender set
+	if err := bob.Send(&chat.ChatMsg{Sender: "mallory", Text: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, strm := range []chat.SRPCChat_JoinClient{alice, bob} {
+		msg, err := strm.Recv()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if msg.GetSender() != "bob" || msg.GetText() != "hello" {
+			t.Fatalf("unexpected message: %v", msg.String())
+		}
+	}
+
+	// expect a member without a name to be rejected: the stream ends
+	strm, err := client.Join(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer strm.Close()
+	if err := strm.Send(&chat.ChatMsg{}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if msg, err := strm.Recv(); err == nil {
+		t.Fatalf("expected error got %v", msg.String())
+	}
+}
+
+func TestE2E_ExampleFileUpload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	mux := srpc.NewMux()
+	if err := fileupload.NewServer(dir).Register(mux); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := fileupload.NewSRPCFileStoreClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	data := make([]byte, fileupload.ChunkSize*3+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	var progress []uint64
+	var progressMtx sync.Mutex
+	res, err := fileupload.Upload(ctx, client, "../data.bin", bytes.NewReader(data), func(received uint64) {
+		progressMtx.Lock()
+		progress = append(progress, received)
+		progressMtx.Unlock()
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	sum := sha256.Sum256(data)
+	if res.GetName() != "data.bin" || res.GetSize() != uint64(len(data)) || res.GetSha256() != hex.EncodeToString(sum[:]) {
+		t.Fatalf("unexpected result: %v", res.String())
+	}
+
+	// expect the file to be stored in the directory
+	stored, err := os.ReadFile(filepath.Join(dir, "data.bin"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !bytes.Equal(stored, data) {
+		t.Fatal("stored file does not match the upload")
+	}
+
+	// expect progress for every chunk
+	progressMtx.Lock()
+	defer progressMtx.Unlock()
+	if len(progress) != 4 || progress[len(progress)-1] != uint64(len(data)) {
+		t.Fatalf("unexpected progress: %v", progress)
+	}
+}
+
+func TestE2E_ExampleTunnel(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer ctxCancel()
+
+	// target echoes back everything it receives
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer target.Close()
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	targetID := "tcp:" + target.Addr().String()
+
+	mux := srpc.NewMux()
+	if err := tunnel.NewServer(rpcstream.NewRawDialAllowlist(targetID)).Register(mux); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := tunnel.NewSRPCTunnelClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- tunnel.Forward(ctx, lis, client, targetID)
+	}()
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("hello tunnel")); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := conn.(*net.TCPConn).CloseWrite(); err != nil {
+		t.Fatal(err.Error())
+	}
+	out, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(out) != "hello tunnel" {
+		t.Fatalf("expected echo got %q", string(out))
+	}
+
+	// expect targets not in the allowlist to be denied
+	if _, err := tunnel.Dial(ctx, client, "tcp:127.0.0.1:1"); err == nil || !strings.Contains(err.Error(), rpcstream.ErrRawDialDenied.Error()) {
+		t.Fatalf("expected %v got %v", rpcstream.ErrRawDialDenied, err)
+	}
+
+	ctxCancel()
+	if err := <-errCh; err != context.Canceled {
+		t.Fatalf("expected %v got %v", context.Canceled, err)
+	}
+}
+
+func TestE2E_WellKnownTypes(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := mock.NewMockServer().Register(mux); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux)
+
+	for _, transport := range e2eTransports {
+		transport := transport
+		t.Run(transport.name, func(t *testing.T) {
+			ctx, ctxCancel := context.WithCancel(context.Background())
+			defer ctxCancel()
+			client, err := transport.connect(t, ctx, server)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			clientMock := mock.NewSRPCMockClient(client)
+
+			if _, err := clientMock.DoNothing(ctx, &emptypb.Empty{}); err != nil {
+				t.Fatal(err.Error())
+			}
+			ts := timestamppb.New(time.Unix(1700000000, 123456789))
+			if resp, err := clientMock.EchoTimestamp(ctx, ts); err != nil || !proto.Equal(resp, ts) {
+				t.Fatalf("expected %v got %v: %v", ts, resp, err)
+			}
+			dur := durationpb.New(time.Minute + time.Millisecond)
+			if resp, err := clientMock.EchoDuration(ctx, dur); err != nil || resp.AsDuration() != dur.AsDuration() {
+				t.Fatalf("expected %v got %v: %v", dur, resp, err)
+			}
+
+			// expect the durations to round trip over a stream
+			strm, err := clientMock.EchoDurations(ctx)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			for i := 1; i <= 3; i++ {
+				if err := strm.Send(durationpb.New(time.Duration(i) * time.Second)); err != nil {
+					t.Fatal(err.Error())
+				}
+			}
+			if err := strm.CloseSend(); err != nil {
+				t.Fatal(err.Error())
+			}
+			var durs []*durationpb.Duration
+			for len(durs) < 3 {
+				batch, err := strm.RecvBatch(3)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				durs = append(durs, batch...)
+			}
+			for i, resp := range durs {
+				if resp.AsDuration() != time.Duration(i+1)*time.Second {
+					t.Fatalf("expected %v got %v", time.Duration(i+1)*time.Second, resp.AsDuration())
+				}
+			}
+			if _, err := strm.Recv(); err != io.EOF {
+				t.Fatalf("expected EOF got %v", err)
+			}
+		})
+	}
+}
+
+func TestE2E_DialResolved(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux)
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	lis, err := srpc.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer lis.Close()
+	go func() {
+		_ = srpc.AcceptMuxedListener(ctx, lis, server)
+	}()
+	target := "dns:///" + lis.Addr().String()
+
+	// expect Dial to resolve the target
+	client, nc, err := srpc.Dial(ctx, target)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer nc.Close()
+	if _, err := echo.NewSRPCEchoerClient(client).Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// expect DialResolved to balance across the resolved addresses
+	resolved, err := srpc.DialResolved(ctx, target, srpc.NewLeastLoadedClient)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer resolved.Close()
+	if addrs := resolved.GetAddrs(); len(addrs) != 1 || addrs[0] != lis.Addr().String() {
+		t.Fatalf("unexpected addrs %v", addrs)
+	}
+	if resp, err := echo.NewSRPCEchoerClient(resolved).Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil || resp.GetBody() != "hello" {
+		t.Fatalf("expected response got %v: %v", resp, err)
+	}
+}
+
+// trackingListener records the accepted connections.
+type trackingListener struct {
+	net.Listener
+	mtx   sync.Mutex
+	conns []net.Conn
+}
+
+func (l *trackingListener) Accept() (net.Conn, error) {
+	nc, err := l.Listener.Accept()
+	if err == nil {
+		l.mtx.Lock()
+		l.conns = append(l.conns, nc)
+		l.mtx.Unlock()
+	}
+	return nc, err
+}
+
+// closeConns closes the accepted connections returning the count.
+func (l *trackingListener) closeConns() int {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	n := len(l.conns)
+	for _, nc := range l.conns {
+		_ = nc.Close()
+	}
+	l.conns = nil
+	return n
+}
+
+func TestE2E_ReconnectingClient(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	baseLis, err := srpc.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer baseLis.Close()
+	lis := &trackingListener{Listener: baseLis}
+	go func() {
+		_ = srpc.AcceptMuxedListener(ctx, lis, srpc.NewServer(mux))
+	}()
+
+	reconnecting := srpc.NewReconnectingClient(
+		srpc.NewTCPMuxedConnDialer(baseLis.Addr().String()),
+		&srpc.ReconnectPolicy{InitialBackoff: time.Millisecond * 10},
+	)
+	defer reconnecting.Close()
+	client := echo.NewSRPCEchoerClient(reconnecting)
+	msg := &echo.EchoMsg{Body: "hello"}
+
+	// expect new calls to wait for the connection
+	if _, err := client.Echo(ctx, msg); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// expect the in-flight stream to fail with ErrConnReset
+	strm, err := client.EchoBidiStream(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if n := lis.closeConns(); n != 1 {
+		t.Fatalf("expected 1 connection got %d", n)
+	}
+	if _, err := strm.Recv(); err != srpc.ErrConnReset {
+		t.Fatalf("expected %v got %v", srpc.ErrConnReset, err)
+	}
+
+	// expect the next call to use a new connection
+	if resp, err := client.Echo(ctx, msg); err != nil || resp.GetBody() != "hello" {
+		t.Fatalf("expected response got %v: %v", resp, err)
+	}
+	if !reconnecting.IsConnected() {
+		t.Fatal("expected client to be connected")
+	}
+
+	reconnecting.Close()
+	if _, err := client.Echo(ctx, msg); err != srpc.ErrClientClosed {
+		t.Fatalf("expected %v got %v", srpc.ErrClientClosed, err)
+	}
+}
+
+func TestE2E_ClientPool(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	lis, err := srpc.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer lis.Close()
+	go func() {
+		_ = srpc.AcceptMuxedListener(ctx, lis, srpc.NewServer(mux))
+	}()
+
+	var connsMtx sync.Mutex
+	var conns []network.MuxedConn
+	dial := srpc.NewTCPMuxedConnDialer(lis.Addr().String())
+	pool := srpc.NewClientPool(func(ctx context.Context) (network.MuxedConn, error) {
+		mconn, err := dial(ctx)
+		if err == nil {
+			connsMtx.Lock()
+			conns = append(conns, mconn)
+			connsMtx.Unlock()
+		}
+		return mconn, err
+	}, &srpc.ClientPoolConfig{Size: 2, IdleTimeout: time.Millisecond * 50})
+	defer pool.Close()
+	client := echo.NewSRPCEchoerClient(pool)
+
+	// expect the open streams to be spread across the connections up to the size
+	var strms []echo.SRPCEchoer_EchoBidiStreamClient
+	for i := 0; i < 4; i++ {
+		strm, err := client.EchoBidiStream(ctx)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if _, err := strm.Recv(); err != nil {
+			t.Fatal(err.Error())
+		}
+		strms = append(strms, strm)
+	}
+	if n := pool.GetConnCount(); n != 2 {
+		t.Fatalf("expected 2 connections got %d", n)
+	}
+
+	// expect the connections to be closed after the idle timeout
+	for _, strm := range strms {
+		if err := strm.CloseSend(); err != nil {
+			t.Fatal(err.Error())
+		}
+		if _, err := strm.Recv(); err != io.EOF {
+			t.Fatalf("expected EOF got %v", err)
+		}
+		_ = strm.Close()
+	}
+	for i := 0; i < 20 && pool.GetConnCount() != 0; i++ {
+		<-time.After(time.Millisecond * 10)
+	}
+	if n := pool.GetConnCount(); n != 0 {
+		t.Fatalf("expected idle connections to be closed, got %d", n)
+	}
+	connsMtx.Lock()
+	for _, mconn := range conns {
+		if !mconn.IsClosed() {
+			t.Fatal("expected idle connection to be closed")
+		}
+	}
+	connsMtx.Unlock()
+
+	// expect a new connection to be dialed on demand
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if n := pool.GetConnCount(); n != 1 {
+		t.Fatalf("expected 1 connection got %d", n)
+	}
+
+	pool.Close()
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != srpc.ErrClientClosed {
+		t.Fatalf("expected %v got %v", srpc.ErrClientClosed, err)
+	}
+}
+
+// connEventHandler records the closed connection events.
+type connEventHandler struct {
+	closed chan *srpc.ConnEvent
+}
+
+func (h *connEventHandler) HandleRPCBegin(ctx context.Context, begin *srpc.RPCBegin) {}
+
+func (h *connEventHandler) HandleRPCEnd(ctx context.Context, stats *srpc.StreamStats) {}
+
+func (h *connEventHandler) HandleConn(ctx context.Context, ev *srpc.ConnEvent) {
+	if ev.Closed {
+		h.closed <- ev
+	}
+}
+
+func TestE2E_CloseReason(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	handler := &connEventHandler{closed: make(chan *srpc.ConnEvent, 1)}
+	cz := srpc.NewChannelz()
+	server := srpc.NewServer(mux, srpc.WithStatsHandler(handler), srpc.WithChannelz(cz))
+
+	// accept starts a server conn over a pipe returning the client side
+	accept := func(ctx context.Context) net.Conn {
+		clientPipe, serverPipe := net.Pipe()
+		serverMp, err := mp.NewMultiplex(serverPipe, false, nil)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		go func() {
+			_ = server.AcceptMuxedConn(ctx, mplex.NewMuxedConn(serverMp))
+		}()
+		return clientPipe
+	}
+	expectReason := func(expected srpc.CloseReason) {
+		select {
+		case ev := <-handler.closed:
+			if ev.Reason != expected {
+				t.Fatalf("expected reason %v got %v (%v)", expected, ev.Reason, ev.Err)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("expected close with reason %v", expected)
+		}
+	}
+
+	// the client closes the connection
+	clientPipe := accept(context.Background())
+	clientMp, err := mp.NewMultiplex(clientPipe, true, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClientWithMuxedConn(mplex.NewMuxedConn(clientMp)))
+	if _, err := client.Echo(context.Background(), &echo.EchoMsg{Body: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	_ = clientMp.Close()
+	expectReason(srpc.CloseReasonClientClose)
+
+	// the server stops serving the connection
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	clientPipe = accept(ctx)
+	ctxCancel()
+	expectReason(srpc.CloseReasonServerClose)
+	_ = clientPipe.Close()
+
+	// the client opens the same mplex stream twice
+	clientPipe = accept(context.Background())
+	go func() {
+		_, _ = clientPipe.Write([]byte{0, 0, 0, 0})
+	}()
+	expectReason(srpc.CloseReasonProtocolError)
+	_ = clientPipe.Close()
+
+	reasons := cz.GetCloseReasons()
+	for _, reason := range []srpc.CloseReason{srpc.CloseReasonClientClose, srpc.CloseReasonServerClose, srpc.CloseReasonProtocolError} {
+		if reasons[reason] != 1 {
+			t.Fatalf("expected 1 close with reason %v got %v", reason, reasons)
+		}
+	}
+}
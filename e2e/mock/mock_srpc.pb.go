@@ -0,0 +1,335 @@
+// Code generated by protoc-gen-srpc. DO NOT EDIT.
+// protoc-gen-srpc version: v0.0.0-20220611014014-aa9dc5523865
+// source: github.com/aperturerobotics/starpc/e2e/mock/mock.proto
+
+package mock
+
+import (
+	context "context"
+	time "time"
+
+	srpc "github.com/aperturerobotics/starpc/srpc"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type SRPCMockClient interface {
+	SRPCClient() srpc.Client
+
+	DoNothing(ctx context.Context, in *emptypb.Empty, opts ...srpc.CallOption) (*emptypb.Empty, error)
+	EchoTimestamp(ctx context.Context, in *timestamppb.Timestamp, opts ...srpc.CallOption) (*timestamppb.Timestamp, error)
+	EchoDuration(ctx context.Context, in *durationpb.Duration, opts ...srpc.CallOption) (*durationpb.Duration, error)
+	EchoDurations(ctx context.Context, opts ...srpc.CallOption) (SRPCMock_EchoDurationsClient, error)
+}
+
+type srpcMockClient struct {
+	cc srpc.Client
+}
+
+func NewSRPCMockClient(cc srpc.Client) SRPCMockClient {
+	return &srpcMockClient{cc}
+}
+
+func (c *srpcMockClient) SRPCClient() srpc.Client { return c.cc }
+
+func (c *srpcMockClient) DoNothing(ctx context.Context, in *emptypb.Empty, opts ...srpc.CallOption) (*emptypb.Empty, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, SRPCMockServiceID, SRPCMockDoNothingMethodID, srpc.NewProtoMessage(in), srpc.NewProtoMessage(out))
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *srpcMockClient) EchoTimestamp(ctx context.Context, in *timestamppb.Timestamp, opts ...srpc.CallOption) (*timestamppb.Timestamp, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	out := new(timestamppb.Timestamp)
+	err := c.cc.Invoke(ctx, SRPCMockServiceID, SRPCMockEchoTimestampMethodID, srpc.NewProtoMessage(in), srpc.NewProtoMessage(out))
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *srpcMockClient) EchoDuration(ctx context.Context, in *durationpb.Duration, opts ...srpc.CallOption) (*durationpb.Duration, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	out := new(durationpb.Duration)
+	err := c.cc.Invoke(ctx, SRPCMockServiceID, SRPCMockEchoDurationMethodID, srpc.NewProtoMessage(in), srpc.NewProtoMessage(out))
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *srpcMockClient) EchoDurations(ctx context.Context, opts ...srpc.CallOption) (SRPCMock_EchoDurationsClient, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	stream, err := c.cc.NewStream(ctx, SRPCMockServiceID, SRPCMockEchoDurationsMethodID, nil)
+	if err != nil {
+		return nil, err
+	}
+	strm := &srpcMock_EchoDurationsClient{stream}
+	return strm, nil
+}
+
+type SRPCMock_EchoDurationsClient interface {
+	srpc.Stream
+	Send(*durationpb.Duration) error
+	SendCtx(context.Context, *durationpb.Duration) error
+	srpc.StreamSendIter[*durationpb.Duration]
+	srpc.StreamRecvIter[*durationpb.Duration]
+	Recv() (*durationpb.Duration, error)
+	RecvTo(*durationpb.Duration) error
+	RecvTimeout(time.Duration) (*durationpb.Duration, error)
+	TryRecv() (*durationpb.Duration, bool, error)
+	RecvBatch(max int) ([]*durationpb.Duration, error)
+}
+
+type srpcMock_EchoDurationsClient struct {
+	srpc.Stream
+}
+
+func (x *srpcMock_EchoDurationsClient) Send(m *durationpb.Duration) error {
+	return x.MsgSend(srpc.NewProtoMessage(m))
+}
+
+func (x *srpcMock_EchoDurationsClient) SendCtx(ctx context.Context, m *durationpb.Duration) error {
+	return srpc.MsgSendCtx(ctx, x.Stream, srpc.NewProtoMessage(m))
+}
+
+func (x *srpcMock_EchoDurationsClient) Recv() (*durationpb.Duration, error) {
+	m := new(durationpb.Duration)
+	if err := x.MsgRecv(srpc.NewProtoMessage(m)); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcMock_EchoDurationsClient) RecvTo(m *durationpb.Duration) error {
+	return x.MsgRecv(srpc.NewProtoMessage(m))
+}
+
+func (x *srpcMock_EchoDurationsClient) RecvTimeout(d time.Duration) (*durationpb.Duration, error) {
+	m := new(durationpb.Duration)
+	if err := srpc.MsgRecvTimeout(x.Stream, srpc.NewProtoMessage(m), d); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcMock_EchoDurationsClient) TryRecv() (*durationpb.Duration, bool, error) {
+	m := new(durationpb.Duration)
+	ok, err := srpc.MsgTryRecv(x.Stream, srpc.NewProtoMessage(m))
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return m, true, nil
+}
+
+func (x *srpcMock_EchoDurationsClient) RecvBatch(max int) ([]*durationpb.Duration, error) {
+	return srpc.RecvProtoBatch(x.Stream, max, func() *durationpb.Duration { return new(durationpb.Duration) })
+}
+
+type SRPCMockServer interface {
+	DoNothing(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	EchoTimestamp(context.Context, *timestamppb.Timestamp) (*timestamppb.Timestamp, error)
+	EchoDuration(context.Context, *durationpb.Duration) (*durationpb.Duration, error)
+	EchoDurations(SRPCMock_EchoDurationsStream) error
+}
+
+type SRPCMockUnimplementedServer struct{}
+
+func (s *SRPCMockUnimplementedServer) DoNothing(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, srpc.ErrUnimplemented
+}
+
+func (s *SRPCMockUnimplementedServer) EchoTimestamp(context.Context, *timestamppb.Timestamp) (*timestamppb.Timestamp, error) {
+	return nil, srpc.ErrUnimplemented
+}
+
+func (s *SRPCMockUnimplementedServer) EchoDuration(context.Context, *durationpb.Duration) (*durationpb.Duration, error) {
+	return nil, srpc.ErrUnimplemented
+}
+
+func (s *SRPCMockUnimplementedServer) EchoDurations(SRPCMock_EchoDurationsStream) error {
+	return srpc.ErrUnimplemented
+}
+
+const SRPCMockServiceID = "e2e.mock.Mock"
+
+const (
+	SRPCMockDoNothingMethodID     = "DoNothing"
+	SRPCMockEchoTimestampMethodID = "EchoTimestamp"
+	SRPCMockEchoDurationMethodID  = "EchoDuration"
+	SRPCMockEchoDurationsMethodID = "EchoDurations"
+)
+
+// SRPCMockService contains the references to the methods of the service.
+var SRPCMockService = struct {
+	DoNothingMethod     srpc.MethodRef
+	EchoTimestampMethod srpc.MethodRef
+	EchoDurationMethod  srpc.MethodRef
+	EchoDurationsMethod srpc.MethodRef
+}{
+	DoNothingMethod:     srpc.MethodRef{ServiceID: SRPCMockServiceID, MethodID: SRPCMockDoNothingMethodID},
+	EchoTimestampMethod: srpc.MethodRef{ServiceID: SRPCMockServiceID, MethodID: SRPCMockEchoTimestampMethodID},
+	EchoDurationMethod:  srpc.MethodRef{ServiceID: SRPCMockServiceID, MethodID: SRPCMockEchoDurationMethodID},
+	EchoDurationsMethod: srpc.MethodRef{ServiceID: SRPCMockServiceID, MethodID: SRPCMockEchoDurationsMethodID},
+}
+
+type SRPCMockHandler struct {
+	impl SRPCMockServer
+}
+
+func (SRPCMockHandler) GetServiceID() string { return SRPCMockServiceID }
+
+func (SRPCMockHandler) GetMethodIDs() []string {
+	return []string{
+		"DoNothing",
+		"EchoTimestamp",
+		"EchoDuration",
+		"EchoDurations",
+	}
+}
+
+func (d *SRPCMockHandler) InvokeMethod(
+	serviceID, methodID string,
+	strm srpc.Stream,
+) (bool, error) {
+	if serviceID != "" && serviceID != d.GetServiceID() {
+		return false, nil
+	}
+
+	switch methodID {
+	case "DoNothing":
+		return true, d.InvokeMethod_DoNothing(d.impl, strm)
+	case "EchoTimestamp":
+		return true, d.InvokeMethod_EchoTimestamp(d.impl, strm)
+	case "EchoDuration":
+		return true, d.InvokeMethod_EchoDuration(d.impl, strm)
+	case "EchoDurations":
+		return true, d.InvokeMethod_EchoDurations(d.impl, strm)
+	default:
+		return false, nil
+	}
+}
+
+func (SRPCMockHandler) InvokeMethod_DoNothing(impl SRPCMockServer, strm srpc.Stream) error {
+	req := new(emptypb.Empty)
+	if err := strm.MsgRecv(srpc.NewProtoMessage(req)); err != nil {
+		return err
+	}
+	out, err := impl.DoNothing(strm.Context(), req)
+	if err != nil {
+		return err
+	}
+	return strm.MsgSend(srpc.NewProtoMessage(out))
+}
+
+func (SRPCMockHandler) InvokeMethod_EchoTimestamp(impl SRPCMockServer, strm srpc.Stream) error {
+	req := new(timestamppb.Timestamp)
+	if err := strm.MsgRecv(srpc.NewProtoMessage(req)); err != nil {
+		return err
+	}
+	out, err := impl.EchoTimestamp(strm.Context(), req)
+	if err != nil {
+		return err
+	}
+	return strm.MsgSend(srpc.NewProtoMessage(out))
+}
+
+func (SRPCMockHandler) InvokeMethod_EchoDuration(impl SRPCMockServer, strm srpc.Stream) error {
+	req := new(durationpb.Duration)
+	if err := strm.MsgRecv(srpc.NewProtoMessage(req)); err != nil {
+		return err
+	}
+	out, err := impl.EchoDuration(strm.Context(), req)
+	if err != nil {
+		return err
+	}
+	return strm.MsgSend(srpc.NewProtoMessage(out))
+}
+
+func (SRPCMockHandler) InvokeMethod_EchoDurations(impl SRPCMockServer, strm srpc.Stream) error {
+	clientStrm := &srpcMock_EchoDurationsStream{strm}
+	return impl.EchoDurations(clientStrm)
+}
+
+func SRPCRegisterMock(mux srpc.Mux, impl SRPCMockServer) error {
+	return mux.Register(&SRPCMockHandler{impl: impl})
+}
+
+type SRPCMock_DoNothingStream interface {
+	srpc.Stream
+	SendAndClose(*emptypb.Empty) error
+}
+
+type srpcMock_DoNothingStream struct {
+	srpc.Stream
+}
+
+func (x *srpcMock_DoNothingStream) SendAndClose(m *emptypb.Empty) error {
+	if err := x.MsgSend(srpc.NewProtoMessage(m)); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type SRPCMock_EchoTimestampStream interface {
+	srpc.Stream
+	SendAndClose(*timestamppb.Timestamp) error
+}
+
+type srpcMock_EchoTimestampStream struct {
+	srpc.Stream
+}
+
+func (x *srpcMock_EchoTimestampStream) SendAndClose(m *timestamppb.Timestamp) error {
+	if err := x.MsgSend(srpc.NewProtoMessage(m)); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type SRPCMock_EchoDurationStream interface {
+	srpc.Stream
+	SendAndClose(*durationpb.Duration) error
+}
+
+type srpcMock_EchoDurationStream struct {
+	srpc.Stream
+}
+
+func (x *srpcMock_EchoDurationStream) SendAndClose(m *durationpb.Duration) error {
+	if err := x.MsgSend(srpc.NewProtoMessage(m)); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type SRPCMock_EchoDurationsStream interface {
+	srpc.Stream
+	Send(*durationpb.Duration) error
+	Recv() (*durationpb.Duration, error)
+}
+
+type srpcMock_EchoDurationsStream struct {
+	srpc.Stream
+}
+
+func (x *srpcMock_EchoDurationsStream) Send(m *durationpb.Duration) error {
+	return x.MsgSend(srpc.NewProtoMessage(m))
+}
+
+func (x *srpcMock_EchoDurationsStream) Recv() (*durationpb.Duration, error) {
+	m := new(durationpb.Duration)
+	if err := x.MsgRecv(srpc.NewProtoMessage(m)); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcMock_EchoDurationsStream) RecvTo(m *durationpb.Duration) error {
+	return x.MsgRecv(srpc.NewProtoMessage(m))
+}
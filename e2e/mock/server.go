@@ -0,0 +1,60 @@
+package mock
+
+import (
+	context "context"
+	"errors"
+	"io"
+
+	srpc "github.com/aperturerobotics/starpc/srpc"
+	"google.golang.org/protobuf/proto"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MockServer implements the Mock server.
+type MockServer struct{}
+
+// NewMockServer constructs a new MockServer.
+func NewMockServer() *MockServer {
+	return &MockServer{}
+}
+
+// Register registers the Mock server with the Mux.
+func (s *MockServer) Register(mux srpc.Mux) error {
+	return SRPCRegisterMock(mux, s)
+}
+
+// DoNothing implements SRPCMockServer
+func (*MockServer) DoNothing(ctx context.Context, msg *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+// EchoTimestamp implements SRPCMockServer
+func (*MockServer) EchoTimestamp(ctx context.Context, msg *timestamppb.Timestamp) (*timestamppb.Timestamp, error) {
+	return proto.Clone(msg).(*timestamppb.Timestamp), nil
+}
+
+// EchoDuration implements SRPCMockServer
+func (*MockServer) EchoDuration(ctx context.Context, msg *durationpb.Duration) (*durationpb.Duration, error) {
+	return proto.Clone(msg).(*durationpb.Duration), nil
+}
+
+// EchoDurations implements SRPCMockServer
+func (*MockServer) EchoDurations(strm SRPCMock_EchoDurationsStream) error {
+	for {
+		msg, err := strm.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err := strm.Send(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// _ is a type assertion
+var _ SRPCMockServer = ((*MockServer)(nil))
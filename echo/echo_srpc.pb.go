@@ -6,6 +6,7 @@ package echo
 
 import (
 	context "context"
+	time "time"
 
 	rpcstream "github.com/aperturerobotics/starpc/rpcstream"
 	srpc "github.com/aperturerobotics/starpc/srpc"
@@ -14,11 +15,11 @@ import (
 type SRPCEchoerClient interface {
 	SRPCClient() srpc.Client
 
-	Echo(ctx context.Context, in *EchoMsg) (*EchoMsg, error)
-	EchoServerStream(ctx context.Context, in *EchoMsg) (SRPCEchoer_EchoServerStreamClient, error)
-	EchoClientStream(ctx context.Context) (SRPCEchoer_EchoClientStreamClient, error)
-	EchoBidiStream(ctx context.Context) (SRPCEchoer_EchoBidiStreamClient, error)
-	RpcStream(ctx context.Context) (SRPCEchoer_RpcStreamClient, error)
+	Echo(ctx context.Context, in *EchoMsg, opts ...srpc.CallOption) (*EchoMsg, error)
+	EchoServerStream(ctx context.Context, in *EchoMsg, opts ...srpc.CallOption) (SRPCEchoer_EchoServerStreamClient, error)
+	EchoClientStream(ctx context.Context, opts ...srpc.CallOption) (SRPCEchoer_EchoClientStreamClient, error)
+	EchoBidiStream(ctx context.Context, opts ...srpc.CallOption) (SRPCEchoer_EchoBidiStreamClient, error)
+	RpcStream(ctx context.Context, opts ...srpc.CallOption) (SRPCEchoer_RpcStreamClient, error)
 }
 
 type srpcEchoerClient struct {
@@ -31,17 +32,19 @@ func NewSRPCEchoerClient(cc srpc.Client) SRPCEchoerClient {
 
 func (c *srpcEchoerClient) SRPCClient() srpc.Client { return c.cc }
 
-func (c *srpcEchoerClient) Echo(ctx context.Context, in *EchoMsg) (*EchoMsg, error) {
+func (c *srpcEchoerClient) Echo(ctx context.Context, in *EchoMsg, opts ...srpc.CallOption) (*EchoMsg, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
 	out := new(EchoMsg)
-	err := c.cc.Invoke(ctx, "echo.Echoer", "Echo", in, out)
+	err := c.cc.Invoke(ctx, SRPCEchoerServiceID, SRPCEchoerEchoMethodID, in, out)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *srpcEchoerClient) EchoServerStream(ctx context.Context, in *EchoMsg) (SRPCEchoer_EchoServerStreamClient, error) {
-	stream, err := c.cc.NewStream(ctx, "echo.Echoer", "EchoServerStream", in)
+func (c *srpcEchoerClient) EchoServerStream(ctx context.Context, in *EchoMsg, opts ...srpc.CallOption) (SRPCEchoer_EchoServerStreamClient, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	stream, err := c.cc.NewStream(ctx, SRPCEchoerServiceID, SRPCEchoerEchoServerStreamMethodID, in)
 	if err != nil {
 		return nil, err
 	}
@@ -54,8 +57,12 @@ func (c *srpcEchoerClient) EchoServerStream(ctx context.Context, in *EchoMsg) (S
 
 type SRPCEchoer_EchoServerStreamClient interface {
 	srpc.Stream
+	srpc.StreamRecvIter[*EchoMsg]
 	Recv() (*EchoMsg, error)
 	RecvTo(*EchoMsg) error
+	RecvTimeout(time.Duration) (*EchoMsg, error)
+	TryRecv() (*EchoMsg, bool, error)
+	RecvBatch(max int) ([]*EchoMsg, error)
 }
 
 type srpcEchoer_EchoServerStreamClient struct {
@@ -74,8 +81,30 @@ func (x *srpcEchoer_EchoServerStreamClient) RecvTo(m *EchoMsg) error {
 	return x.MsgRecv(m)
 }
 
-func (c *srpcEchoerClient) EchoClientStream(ctx context.Context) (SRPCEchoer_EchoClientStreamClient, error) {
-	stream, err := c.cc.NewStream(ctx, "echo.Echoer", "EchoClientStream", nil)
+func (x *srpcEchoer_EchoServerStreamClient) RecvTimeout(d time.Duration) (*EchoMsg, error) {
+	m := new(EchoMsg)
+	if err := srpc.MsgRecvTimeout(x.Stream, m, d); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcEchoer_EchoServerStreamClient) TryRecv() (*EchoMsg, bool, error) {
+	m := new(EchoMsg)
+	ok, err := srpc.MsgTryRecv(x.Stream, m)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return m, true, nil
+}
+
+func (x *srpcEchoer_EchoServerStreamClient) RecvBatch(max int) ([]*EchoMsg, error) {
+	return srpc.RecvBatch(x.Stream, max, func() *EchoMsg { return new(EchoMsg) })
+}
+
+func (c *srpcEchoerClient) EchoClientStream(ctx context.Context, opts ...srpc.CallOption) (SRPCEchoer_EchoClientStreamClient, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	stream, err := c.cc.NewStream(ctx, SRPCEchoerServiceID, SRPCEchoerEchoClientStreamMethodID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +115,8 @@ func (c *srpcEchoerClient) EchoClientStream(ctx context.Context) (SRPCEchoer_Ech
 type SRPCEchoer_EchoClientStreamClient interface {
 	srpc.Stream
 	Send(*EchoMsg) error
+	SendCtx(context.Context, *EchoMsg) error
+	srpc.StreamSendIter[*EchoMsg]
 	CloseAndRecv() (*EchoMsg, error)
 }
 
@@ -97,6 +128,10 @@ func (x *srpcEchoer_EchoClientStreamClient) Send(m *EchoMsg) error {
 	return x.MsgSend(m)
 }
 
+func (x *srpcEchoer_EchoClientStreamClient) SendCtx(ctx context.Context, m *EchoMsg) error {
+	return srpc.MsgSendCtx(ctx, x.Stream, m)
+}
+
 func (x *srpcEchoer_EchoClientStreamClient) CloseAndRecv() (*EchoMsg, error) {
 	if err := x.CloseSend(); err != nil {
 		return nil, err
@@ -115,8 +150,9 @@ func (x *srpcEchoer_EchoClientStreamClient) CloseAndMsgRecv(m *EchoMsg) error {
 	return x.MsgRecv(m)
 }
 
-func (c *srpcEchoerClient) EchoBidiStream(ctx context.Context) (SRPCEchoer_EchoBidiStreamClient, error) {
-	stream, err := c.cc.NewStream(ctx, "echo.Echoer", "EchoBidiStream", nil)
+func (c *srpcEchoerClient) EchoBidiStream(ctx context.Context, opts ...srpc.CallOption) (SRPCEchoer_EchoBidiStreamClient, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	stream, err := c.cc.NewStream(ctx, SRPCEchoerServiceID, SRPCEchoerEchoBidiStreamMethodID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -127,8 +163,14 @@ func (c *srpcEchoerClient) EchoBidiStream(ctx context.Context) (SRPCEchoer_EchoB
 type SRPCEchoer_EchoBidiStreamClient interface {
 	srpc.Stream
 	Send(*EchoMsg) error
+	SendCtx(context.Context, *EchoMsg) error
+	srpc.StreamSendIter[*EchoMsg]
+	srpc.StreamRecvIter[*EchoMsg]
 	Recv() (*EchoMsg, error)
 	RecvTo(*EchoMsg) error
+	RecvTimeout(time.Duration) (*EchoMsg, error)
+	TryRecv() (*EchoMsg, bool, error)
+	RecvBatch(max int) ([]*EchoMsg, error)
 }
 
 type srpcEchoer_EchoBidiStreamClient struct {
@@ -139,6 +181,10 @@ func (x *srpcEchoer_EchoBidiStreamClient) Send(m *EchoMsg) error {
 	return x.MsgSend(m)
 }
 
+func (x *srpcEchoer_EchoBidiStreamClient) SendCtx(ctx context.Context, m *EchoMsg) error {
+	return srpc.MsgSendCtx(ctx, x.Stream, m)
+}
+
 func (x *srpcEchoer_EchoBidiStreamClient) Recv() (*EchoMsg, error) {
 	m := new(EchoMsg)
 	if err := x.MsgRecv(m); err != nil {
@@ -151,8 +197,30 @@ func (x *srpcEchoer_EchoBidiStreamClient) RecvTo(m *EchoMsg) error {
 	return x.MsgRecv(m)
 }
 
-func (c *srpcEchoerClient) RpcStream(ctx context.Context) (SRPCEchoer_RpcStreamClient, error) {
-	stream, err := c.cc.NewStream(ctx, "echo.Echoer", "RpcStream", nil)
+func (x *srpcEchoer_EchoBidiStreamClient) RecvTimeout(d time.Duration) (*EchoMsg, error) {
+	m := new(EchoMsg)
+	if err := srpc.MsgRecvTimeout(x.Stream, m, d); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcEchoer_EchoBidiStreamClient) TryRecv() (*EchoMsg, bool, error) {
+	m := new(EchoMsg)
+	ok, err := srpc.MsgTryRecv(x.Stream, m)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return m, true, nil
+}
+
+func (x *srpcEchoer_EchoBidiStreamClient) RecvBatch(max int) ([]*EchoMsg, error) {
+	return srpc.RecvBatch(x.Stream, max, func() *EchoMsg { return new(EchoMsg) })
+}
+
+func (c *srpcEchoerClient) RpcStream(ctx context.Context, opts ...srpc.CallOption) (SRPCEchoer_RpcStreamClient, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	stream, err := c.cc.NewStream(ctx, SRPCEchoerServiceID, SRPCEchoerRpcStreamMethodID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -163,8 +231,14 @@ func (c *srpcEchoerClient) RpcStream(ctx context.Context) (SRPCEchoer_RpcStreamC
 type SRPCEchoer_RpcStreamClient interface {
 	srpc.Stream
 	Send(*rpcstream.RpcStreamPacket) error
+	SendCtx(context.Context, *rpcstream.RpcStreamPacket) error
+	srpc.StreamSendIter[*rpcstream.RpcStreamPacket]
+	srpc.StreamRecvIter[*rpcstream.RpcStreamPacket]
 	Recv() (*rpcstream.RpcStreamPacket, error)
 	RecvTo(*rpcstream.RpcStreamPacket) error
+	RecvTimeout(time.Duration) (*rpcstream.RpcStreamPacket, error)
+	TryRecv() (*rpcstream.RpcStreamPacket, bool, error)
+	RecvBatch(max int) ([]*rpcstream.RpcStreamPacket, error)
 }
 
 type srpcEchoer_RpcStreamClient struct {
@@ -175,6 +249,10 @@ func (x *srpcEchoer_RpcStreamClient) Send(m *rpcstream.RpcStreamPacket) error {
 	return x.MsgSend(m)
 }
 
+func (x *srpcEchoer_RpcStreamClient) SendCtx(ctx context.Context, m *rpcstream.RpcStreamPacket) error {
+	return srpc.MsgSendCtx(ctx, x.Stream, m)
+}
+
 func (x *srpcEchoer_RpcStreamClient) Recv() (*rpcstream.RpcStreamPacket, error) {
 	m := new(rpcstream.RpcStreamPacket)
 	if err := x.MsgRecv(m); err != nil {
@@ -187,6 +265,27 @@ func (x *srpcEchoer_RpcStreamClient) RecvTo(m *rpcstream.RpcStreamPacket) error
 	return x.MsgRecv(m)
 }
 
+func (x *srpcEchoer_RpcStreamClient) RecvTimeout(d time.Duration) (*rpcstream.RpcStreamPacket, error) {
+	m := new(rpcstream.RpcStreamPacket)
+	if err := srpc.MsgRecvTimeout(x.Stream, m, d); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcEchoer_RpcStreamClient) TryRecv() (*rpcstream.RpcStreamPacket, bool, error) {
+	m := new(rpcstream.RpcStreamPacket)
+	ok, err := srpc.MsgTryRecv(x.Stream, m)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return m, true, nil
+}
+
+func (x *srpcEchoer_RpcStreamClient) RecvBatch(max int) ([]*rpcstream.RpcStreamPacket, error) {
+	return srpc.RecvBatch(x.Stream, max, func() *rpcstream.RpcStreamPacket { return new(rpcstream.RpcStreamPacket) })
+}
+
 type SRPCEchoerServer interface {
 	Echo(context.Context, *EchoMsg) (*EchoMsg, error)
 	EchoServerStream(*EchoMsg, SRPCEchoer_EchoServerStreamStream) error
@@ -219,6 +318,29 @@ func (s *SRPCEchoerUnimplementedServer) RpcStream(SRPCEchoer_RpcStreamStream) er
 
 const SRPCEchoerServiceID = "echo.Echoer"
 
+const (
+	SRPCEchoerEchoMethodID             = "Echo"
+	SRPCEchoerEchoServerStreamMethodID = "EchoServerStream"
+	SRPCEchoerEchoClientStreamMethodID = "EchoClientStream"
+	SRPCEchoerEchoBidiStreamMethodID   = "EchoBidiStream"
+	SRPCEchoerRpcStreamMethodID        = "RpcStream"
+)
+
+// SRPCEchoerService contains the references to the methods of the service.
+var SRPCEchoerService = struct {
+	EchoMethod             srpc.MethodRef
+	EchoServerStreamMethod srpc.MethodRef
+	EchoClientStreamMethod srpc.MethodRef
+	EchoBidiStreamMethod   srpc.MethodRef
+	RpcStreamMethod        srpc.MethodRef
+}{
+	EchoMethod:             srpc.MethodRef{ServiceID: SRPCEchoerServiceID, MethodID: SRPCEchoerEchoMethodID},
+	EchoServerStreamMethod: srpc.MethodRef{ServiceID: SRPCEchoerServiceID, MethodID: SRPCEchoerEchoServerStreamMethodID},
+	EchoClientStreamMethod: srpc.MethodRef{ServiceID: SRPCEchoerServiceID, MethodID: SRPCEchoerEchoClientStreamMethodID},
+	EchoBidiStreamMethod:   srpc.MethodRef{ServiceID: SRPCEchoerServiceID, MethodID: SRPCEchoerEchoBidiStreamMethodID},
+	RpcStreamMethod:        srpc.MethodRef{ServiceID: SRPCEchoerServiceID, MethodID: SRPCEchoerRpcStreamMethodID},
+}
+
 type SRPCEchoerHandler struct {
 	impl SRPCEchoerServer
 }
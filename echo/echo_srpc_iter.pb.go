@@ -0,0 +1,38 @@
+// Code generated by protoc-gen-srpc. DO NOT EDIT.
+// protoc-gen-srpc version: v0.0.0-20220611014014-aa9dc5523865
+// source: github.com/aperturerobotics/starpc/echo/echo.proto
+
+//go:build go1.23
+
+package echo
+
+import (
+	iter "iter"
+
+	rpcstream "github.com/aperturerobotics/starpc/rpcstream"
+	srpc "github.com/aperturerobotics/starpc/srpc"
+)
+
+func (x *srpcEchoer_EchoServerStreamClient) All() iter.Seq2[*EchoMsg, error] {
+	return srpc.RecvAll[*EchoMsg](x)
+}
+
+func (x *srpcEchoer_EchoClientStreamClient) SendAll(seq iter.Seq[*EchoMsg]) error {
+	return srpc.SendAll[*EchoMsg](x, seq)
+}
+
+func (x *srpcEchoer_EchoBidiStreamClient) SendAll(seq iter.Seq[*EchoMsg]) error {
+	return srpc.SendAll[*EchoMsg](x, seq)
+}
+
+func (x *srpcEchoer_EchoBidiStreamClient) All() iter.Seq2[*EchoMsg, error] {
+	return srpc.RecvAll[*EchoMsg](x)
+}
+
+func (x *srpcEchoer_RpcStreamClient) SendAll(seq iter.Seq[*rpcstream.RpcStreamPacket]) error {
+	return srpc.SendAll[*rpcstream.RpcStreamPacket](x, seq)
+}
+
+func (x *srpcEchoer_RpcStreamClient) All() iter.Seq2[*rpcstream.RpcStreamPacket, error] {
+	return srpc.RecvAll[*rpcstream.RpcStreamPacket](x)
+}
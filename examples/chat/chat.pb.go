@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1-devel
+// 	protoc        v3.19.3
+// source: github.com/aperturerobotics/starpc/examples/chat/chat.proto
+
+package chat
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ChatMsg is a message in the chat room.
+type ChatMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Sender is the name of the sender.
+	Sender string `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	// Text is the message text.
+	Text string `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	// Joined indicates the sender joined the room.
+	// Sent by the server.
+	Joined bool `protobuf:"varint,3,opt,name=joined,proto3" json:"joined,omitempty"`
+}
+
+func (x *ChatMsg) Reset() {
+	*x = ChatMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChatMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMsg) ProtoMessage() {}
+
+func (x *ChatMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMsg.ProtoReflect.Descriptor instead.
+func (*ChatMsg) Descriptor() ([]byte, []int) {
+	return file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ChatMsg) GetSender() string {
+	if x != nil {
+		return x.Sender
+	}
+	return ""
+}
+
+func (x *ChatMsg) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ChatMsg) GetJoined() bool {
+	if x != nil {
+		return x.Joined
+	}
+	return false
+}
+
+var File_github_com_aperturerobotics_starpc_examples_chat_chat_proto protoreflect.FileDescriptor
+
+var file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDesc = []byte{
+	0x0a, 0x3b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x70, 0x65,
+	0x72, 0x74, 0x75, 0x72, 0x65, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x69, 0x63, 0x73, 0x2f, 0x73, 0x74,
+	0x61, 0x72, 0x70, 0x63, 0x2f, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x2f, 0x63, 0x68,
+	0x61, 0x74, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x63,
+	0x68, 0x61, 0x74, 0x22, 0x4d, 0x0a, 0x07, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6a, 0x6f,
+	0x69, 0x6e, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6a, 0x6f, 0x69, 0x6e,
+	0x65, 0x64, 0x32, 0x30, 0x0a, 0x04, 0x43, 0x68, 0x61, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x4a, 0x6f,
+	0x69, 0x6e, 0x12, 0x0d, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73,
+	0x67, 0x1a, 0x0d, 0x2e, 0x63, 0x68, 0x61, 0x74, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67,
+	0x28, 0x01, 0x30, 0x01, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDescOnce sync.Once
+	file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDescData = file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDesc
+)
+
+func file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDescGZIP() []byte {
+	file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDescOnce.Do(func() {
+		file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDescData)
+	})
+	return file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDescData
+}
+
+var file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_goTypes = []interface{}{
+	(*ChatMsg)(nil), // 0: chat.ChatMsg
+}
+var file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_depIdxs = []int32{
+	0, // 0: chat.Chat.Join:input_type -> chat.ChatMsg
+	0, // 1: chat.Chat.Join:output_type -> chat.ChatMsg
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_init() }
+func file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_init() {
+	if File_github_com_aperturerobotics_starpc_examples_chat_chat_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_goTypes,
+		DependencyIndexes: file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_depIdxs,
+		MessageInfos:      file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_msgTypes,
+	}.Build()
+	File_github_com_aperturerobotics_starpc_examples_chat_chat_proto = out.File
+	file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_rawDesc = nil
+	file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_goTypes = nil
+	file_github_com_aperturerobotics_starpc_examples_chat_chat_proto_depIdxs = nil
+}
@@ -0,0 +1,182 @@
+// Code generated by protoc-gen-srpc. DO NOT EDIT.
+// protoc-gen-srpc version: v0.0.0-20220611014014-aa9dc5523865
+// source: github.com/aperturerobotics/starpc/examples/chat/chat.proto
+
+package chat
+
+import (
+	context "context"
+	time "time"
+
+	srpc "github.com/aperturerobotics/starpc/srpc"
+)
+
+type SRPCChatClient interface {
+	SRPCClient() srpc.Client
+
+	Join(ctx context.Context, opts ...srpc.CallOption) (SRPCChat_JoinClient, error)
+}
+
+type srpcChatClient struct {
+	cc srpc.Client
+}
+
+func NewSRPCChatClient(cc srpc.Client) SRPCChatClient {
+	return &srpcChatClient{cc}
+}
+
+func (c *srpcChatClient) SRPCClient() srpc.Client { return c.cc }
+
+func (c *srpcChatClient) Join(ctx context.Context, opts ...srpc.CallOption) (SRPCChat_JoinClient, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	stream, err := c.cc.NewStream(ctx, SRPCChatServiceID, SRPCChatJoinMethodID, nil)
+	if err != nil {
+		return nil, err
+	}
+	strm := &srpcChat_JoinClient{stream}
+	return strm, nil
+}
+
+type SRPCChat_JoinClient interface {
+	srpc.Stream
+	Send(*ChatMsg) error
+	SendCtx(context.Context, *ChatMsg) error
+	srpc.StreamSendIter[*ChatMsg]
+	srpc.StreamRecvIter[*ChatMsg]
+	Recv() (*ChatMsg, error)
+	RecvTo(*ChatMsg) error
+	RecvTimeout(time.Duration) (*ChatMsg, error)
+	TryRecv() (*ChatMsg, bool, error)
+	RecvBatch(max int) ([]*ChatMsg, error)
+}
+
+type srpcChat_JoinClient struct {
+	srpc.Stream
+}
+
+func (x *srpcChat_JoinClient) Send(m *ChatMsg) error {
+	return x.MsgSend(m)
+}
+
+func (x *srpcChat_JoinClient) SendCtx(ctx context.Context, m *ChatMsg) error {
+	return srpc.MsgSendCtx(ctx, x.Stream, m)
+}
+
+func (x *srpcChat_JoinClient) Recv() (*ChatMsg, error) {
+	m := new(ChatMsg)
+	if err := x.MsgRecv(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcChat_JoinClient) RecvTo(m *ChatMsg) error {
+	return x.MsgRecv(m)
+}
+
+func (x *srpcChat_JoinClient) RecvTimeout(d time.Duration) (*ChatMsg, error) {
+	m := new(ChatMsg)
+	if err := srpc.MsgRecvTimeout(x.Stream, m, d); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcChat_JoinClient) TryRecv() (*ChatMsg, bool, error) {
+	m := new(ChatMsg)
+	ok, err := srpc.MsgTryRecv(x.Stream, m)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return m, true, nil
+}
+
+func (x *srpcChat_JoinClient) RecvBatch(max int) ([]*ChatMsg, error) {
+	return srpc.RecvBatch(x.Stream, max, func() *ChatMsg { return new(ChatMsg) })
+}
+
+type SRPCChatServer interface {
+	Join(SRPCChat_JoinStream) error
+}
+
+type SRPCChatUnimplementedServer struct{}
+
+func (s *SRPCChatUnimplementedServer) Join(SRPCChat_JoinStream) error {
+	return srpc.ErrUnimplemented
+}
+
+const SRPCChatServiceID = "chat.Chat"
+
+const (
+	SRPCChatJoinMethodID = "Join"
+)
+
+// SRPCChatService contains the references to the methods of the service.
+var SRPCChatService = struct {
+	JoinMethod srpc.MethodRef
+}{
+	JoinMethod: srpc.MethodRef{ServiceID: SRPCChatServiceID, MethodID: SRPCChatJoinMethodID},
+}
+
+type SRPCChatHandler struct {
+	impl SRPCChatServer
+}
+
+func (SRPCChatHandler) GetServiceID() string { return SRPCChatServiceID }
+
+func (SRPCChatHandler) GetMethodIDs() []string {
+	return []string{
+		"Join",
+	}
+}
+
+func (d *SRPCChatHandler) InvokeMethod(
+	serviceID, methodID string,
+	strm srpc.Stream,
+) (bool, error) {
+	if serviceID != "" && serviceID != d.GetServiceID() {
+		return false, nil
+	}
+
+	switch methodID {
+	case "Join":
+		return true, d.InvokeMethod_Join(d.impl, strm)
+	default:
+		return false, nil
+	}
+}
+
+func (SRPCChatHandler) InvokeMethod_Join(impl SRPCChatServer, strm srpc.Stream) error {
+	clientStrm := &srpcChat_JoinStream{strm}
+	return impl.Join(clientStrm)
+}
+
+func SRPCRegisterChat(mux srpc.Mux, impl SRPCChatServer) error {
+	return mux.Register(&SRPCChatHandler{impl: impl})
+}
+
+type SRPCChat_JoinStream interface {
+	srpc.Stream
+	Send(*ChatMsg) error
+	Recv() (*ChatMsg, error)
+}
+
+type srpcChat_JoinStream struct {
+	srpc.Stream
+}
+
+func (x *srpcChat_JoinStream) Send(m *ChatMsg) error {
+	return x.MsgSend(m)
+}
+
+func (x *srpcChat_JoinStream) Recv() (*ChatMsg, error) {
+	m := new(ChatMsg)
+	if err := x.MsgRecv(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcChat_JoinStream) RecvTo(m *ChatMsg) error {
+	return x.MsgRecv(m)
+}
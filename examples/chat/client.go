@@ -0,0 +1,30 @@
+package chat
+
+import (
+	"context"
+)
+
+// JoinRoom joins the chat room with the name.
+//
+// Waits until the member joined: messages received with the returned stream
+// were sent after joining. Close the stream to leave the room.
+func JoinRoom(ctx context.Context, client SRPCChatClient, name string) (SRPCChat_JoinClient, error) {
+	strm, err := client.Join(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := strm.Send(&ChatMsg{Sender: name}); err != nil {
+		_ = strm.Close()
+		return nil, err
+	}
+	for {
+		msg, err := strm.Recv()
+		if err != nil {
+			_ = strm.Close()
+			return nil, err
+		}
+		if msg.GetJoined() && msg.GetSender() == name {
+			return strm, nil
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package chat
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	srpc "github.com/aperturerobotics/starpc/srpc"
+)
+
+// memberQueueSize is the number of messages queued for each member.
+const memberQueueSize = 32
+
+// ErrEmptyName is returned if the member name is empty.
+var ErrEmptyName = errors.New("member name cannot be empty")
+
+// Room implements the Chat server with a single chat room.
+type Room struct {
+	// mtx guards members
+	mtx sync.Mutex
+	// members contains the message queues of the members.
+	members map[chan *ChatMsg]struct{}
+}
+
+// NewRoom constructs a new empty chat Room.
+func NewRoom() *Room {
+	return &Room{members: make(map[chan *ChatMsg]struct{})}
+}
+
+// Register registers the Room with the Mux.
+func (r *Room) Register(mux srpc.Mux) error {
+	return SRPCRegisterChat(mux, r)
+}
+
+// Join joins the chat room until the member closes the stream.
+func (r *Room) Join(strm SRPCChat_JoinStream) error {
+	join, err := strm.Recv()
+	if err != nil {
+		return err
+	}
+	name := join.GetSender()
+	if name == "" {
+		return ErrEmptyName
+	}
+
+	queue := make(chan *ChatMsg, memberQueueSize)
+	r.mtx.Lock()
+	r.members[queue] = struct{}{}
+	r.mtx.Unlock()
+	defer func() {
+		r.mtx.Lock()
+		delete(r.members, queue)
+		r.mtx.Unlock()
+	}()
+
+	sendErr := make(chan error, 1)
+	go func() {
+		for {
+			select {
+			case <-strm.Context().Done():
+				sendErr <- strm.Context().Err()
+				return
+			case msg := <-queue:
+				if err := strm.Send(msg); err != nil {
+					sendErr <- err
+					return
+				}
+			}
+		}
+	}()
+
+	r.broadcast(&ChatMsg{Sender: name, Joined: true})
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := strm.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			r.broadcast(&ChatMsg{Sender: name, Text: msg.GetText()})
+		}
+	}()
+
+	select {
+	case err := <-sendErr:
+		return err
+	case err := <-recvErr:
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+}
+
+// broadcast queues the message for all members.
+//
+// Members with a full queue miss the message.
+func (r *Room) broadcast(msg *ChatMsg) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for queue := range r.members {
+		select {
+		case queue <- msg:
+		default:
+		}
+	}
+}
+
+// _ is a type assertion
+var _ SRPCChatServer = ((*Room)(nil))
@@ -0,0 +1,217 @@
+//go:build !starpc_core
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/aperturerobotics/starpc/examples/chat"
+	"github.com/aperturerobotics/starpc/examples/fileupload"
+	"github.com/aperturerobotics/starpc/examples/tunnel"
+	"github.com/aperturerobotics/starpc/rpcstream"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+const usage = `usage: demo <command> [flags]
+
+commands:
+  serve   serve the examples over tcp and websocket
+  chat    join the chat room, sending lines read from stdin
+  upload  upload a file to the file store
+  tunnel  forward a local tcp port through the tunnel
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, ctxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer ctxCancel()
+
+	var err error
+	args := os.Args[2:]
+	switch os.Args[1] {
+	case "serve":
+		err = runServe(ctx, args)
+	case "chat":
+		err = runChat(ctx, args)
+	case "upload":
+		err = runUpload(ctx, args)
+	case "tunnel":
+		err = runTunnel(ctx, args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// runServe serves the examples over tcp and websocket.
+func runServe(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:5050", "tcp address to listen on")
+	wsAddr := fs.String("ws-addr", "127.0.0.1:5051", "http address to serve the websocket on, empty to disable")
+	dir := fs.String("dir", ".", "directory to store uploaded files in")
+	allow := fs.String("allow-dial", "", "comma-separated tunnel targets to allow, i.e. tcp:127.0.0.1:22")
+	_ = fs.Parse(args)
+
+	mux := srpc.NewMux()
+	if err := chat.NewRoom().Register(mux); err != nil {
+		return err
+	}
+	if err := fileupload.NewServer(*dir).Register(mux); err != nil {
+		return err
+	}
+	var targets []string
+	if *allow != "" {
+		targets = strings.Split(*allow, ",")
+	}
+	if err := tunnel.NewServer(rpcstream.NewRawDialAllowlist(targets...)).Register(mux); err != nil {
+		return err
+	}
+
+	if *wsAddr != "" {
+		// the websocket is the backend for browser clients
+		handler, err := srpc.NewHTTPServer(mux, "/demo")
+		if err != nil {
+			return err
+		}
+		httpServer := &http.Server{Addr: *wsAddr, Handler: handler}
+		go func() {
+			<-ctx.Done()
+			_ = httpServer.Close()
+		}()
+		go func() {
+			if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				fmt.Fprintln(os.Stderr, err.Error())
+			}
+		}()
+		fmt.Printf("serving websocket on ws://%s/demo\n", *wsAddr)
+	}
+
+	lis, err := srpc.Listen(*addr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("serving tcp on %s\n", lis.Addr().String())
+	return srpc.NewServer(mux).Run(ctx, lis)
+}
+
+// runChat joins the chat room.
+func runChat(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("chat", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:5050", "tcp address of the demo server")
+	name := fs.String("name", "", "name to join the room with")
+	_ = fs.Parse(args)
+	if *name == "" {
+		return errors.New("chat: -name is required")
+	}
+
+	client, conn, err := srpc.Dial(ctx, *addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	strm, err := chat.JoinRoom(ctx, chat.NewSRPCChatClient(client), *name)
+	if err != nil {
+		return err
+	}
+	defer strm.Close()
+
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if err := strm.Send(&chat.ChatMsg{Text: scanner.Text()}); err != nil {
+				return
+			}
+		}
+		_ = strm.CloseSend()
+	}()
+	for {
+		msg, err := strm.Recv()
+		if err != nil {
+			return err
+		}
+		if msg.GetJoined() {
+			fmt.Printf("* %s joined\n", msg.GetSender())
+		} else {
+			fmt.Printf("<%s> %s\n", msg.GetSender(), msg.GetText())
+		}
+	}
+}
+
+// runUpload uploads a file.
+func runUpload(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("upload", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:5050", "tcp address of the demo server")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: demo upload [flags] <file>")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var total int64
+	if fi, err := f.Stat(); err == nil {
+		total = fi.Size()
+	}
+
+	client, conn, err := srpc.Dial(ctx, *addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	res, err := fileupload.Upload(ctx, fileupload.NewSRPCFileStoreClient(client), fs.Arg(0), f, func(received uint64) {
+		fmt.Printf("\ruploaded %d/%d bytes", received, total)
+	})
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("stored %s: %d bytes, sha256 %s\n", res.GetName(), res.GetSize(), res.GetSha256())
+	return nil
+}
+
+// runTunnel forwards a local port through the tunnel.
+func runTunnel(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("tunnel", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:5050", "tcp address of the demo server")
+	listen := fs.String("listen", "127.0.0.1:5052", "local address to accept connections on")
+	target := fs.String("target", "", "target to dial from the server, i.e. tcp:127.0.0.1:22")
+	_ = fs.Parse(args)
+	if *target == "" {
+		return errors.New("tunnel: -target is required")
+	}
+
+	client, conn, err := srpc.Dial(ctx, *addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	lis, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("forwarding %s to %s\n", lis.Addr().String(), *target)
+	return tunnel.Forward(ctx, lis, tunnel.NewSRPCTunnelClient(client), *target)
+}
@@ -0,0 +1,62 @@
+package fileupload
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	srpc "github.com/aperturerobotics/starpc/srpc"
+)
+
+// ChunkSize is the size of the chunks sent by Upload.
+const ChunkSize = 32 * 1024
+
+// Upload uploads the contents of r as a file with the name.
+//
+// onProgress is called with the number of bytes stored by the server and may
+// be nil. It is called from the packet read loop and must not block.
+func Upload(
+	ctx context.Context,
+	client SRPCFileStoreClient,
+	name string,
+	r io.Reader,
+	onProgress func(received uint64),
+) (*UploadResult, error) {
+	if onProgress != nil {
+		ctx = srpc.WithCallOptions(ctx, srpc.WithProgress(func(progress srpc.Metadata) {
+			received, err := strconv.ParseUint(progress.Get(ProgressKey), 10, 64)
+			if err == nil {
+				onProgress(received)
+			}
+		}))
+	}
+
+	strm, err := client.Upload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer strm.Close()
+
+	if err := strm.Send(&UploadChunk{Name: name}); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, ChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n != 0 {
+			// copy the data: the chunk may be queued by the writer
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			if err := strm.Send(&UploadChunk{Data: data}); err != nil {
+				return nil, err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return strm.CloseAndRecv()
+}
@@ -0,0 +1,164 @@
+// Code generated by protoc-gen-srpc. DO NOT EDIT.
+// protoc-gen-srpc version: v0.0.0-20220611014014-aa9dc5523865
+// source: github.com/aperturerobotics/starpc/examples/fileupload/fileupload.proto
+
+package fileupload
+
+import (
+	context "context"
+
+	srpc "github.com/aperturerobotics/starpc/srpc"
+)
+
+type SRPCFileStoreClient interface {
+	SRPCClient() srpc.Client
+
+	Upload(ctx context.Context, opts ...srpc.CallOption) (SRPCFileStore_UploadClient, error)
+}
+
+type srpcFileStoreClient struct {
+	cc srpc.Client
+}
+
+func NewSRPCFileStoreClient(cc srpc.Client) SRPCFileStoreClient {
+	return &srpcFileStoreClient{cc}
+}
+
+func (c *srpcFileStoreClient) SRPCClient() srpc.Client { return c.cc }
+
+func (c *srpcFileStoreClient) Upload(ctx context.Context, opts ...srpc.CallOption) (SRPCFileStore_UploadClient, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	stream, err := c.cc.NewStream(ctx, SRPCFileStoreServiceID, SRPCFileStoreUploadMethodID, nil)
+	if err != nil {
+		return nil, err
+	}
+	strm := &srpcFileStore_UploadClient{stream}
+	return strm, nil
+}
+
+type SRPCFileStore_UploadClient interface {
+	srpc.Stream
+	Send(*UploadChunk) error
+	SendCtx(context.Context, *UploadChunk) error
+	srpc.StreamSendIter[*UploadChunk]
+	CloseAndRecv() (*UploadResult, error)
+}
+
+type srpcFileStore_UploadClient struct {
+	srpc.Stream
+}
+
+func (x *srpcFileStore_UploadClient) Send(m *UploadChunk) error {
+	return x.MsgSend(m)
+}
+
+func (x *srpcFileStore_UploadClient) SendCtx(ctx context.Context, m *UploadChunk) error {
+	return srpc.MsgSendCtx(ctx, x.Stream, m)
+}
+
+func (x *srpcFileStore_UploadClient) CloseAndRecv() (*UploadResult, error) {
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadResult)
+	if err := x.MsgRecv(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcFileStore_UploadClient) CloseAndMsgRecv(m *UploadResult) error {
+	if err := x.CloseSend(); err != nil {
+		return err
+	}
+	return x.MsgRecv(m)
+}
+
+type SRPCFileStoreServer interface {
+	Upload(SRPCFileStore_UploadStream) error
+}
+
+type SRPCFileStoreUnimplementedServer struct{}
+
+func (s *SRPCFileStoreUnimplementedServer) Upload(SRPCFileStore_UploadStream) error {
+	return srpc.ErrUnimplemented
+}
+
+const SRPCFileStoreServiceID = "fileupload.FileStore"
+
+const (
+	SRPCFileStoreUploadMethodID = "Upload"
+)
+
+// SRPCFileStoreService contains the references to the methods of the service.
+var SRPCFileStoreService = struct {
+	UploadMethod srpc.MethodRef
+}{
+	UploadMethod: srpc.MethodRef{ServiceID: SRPCFileStoreServiceID, MethodID: SRPCFileStoreUploadMethodID},
+}
+
+type SRPCFileStoreHandler struct {
+	impl SRPCFileStoreServer
+}
+
+func (SRPCFileStoreHandler) GetServiceID() string { return SRPCFileStoreServiceID }
+
+func (SRPCFileStoreHandler) GetMethodIDs() []string {
+	return []string{
+		"Upload",
+	}
+}
+
+func (d *SRPCFileStoreHandler) InvokeMethod(
+	serviceID, methodID string,
+	strm srpc.Stream,
+) (bool, error) {
+	if serviceID != "" && serviceID != d.GetServiceID() {
+		return false, nil
+	}
+
+	switch methodID {
+	case "Upload":
+		return true, d.InvokeMethod_Upload(d.impl, strm)
+	default:
+		return false, nil
+	}
+}
+
+func (SRPCFileStoreHandler) InvokeMethod_Upload(impl SRPCFileStoreServer, strm srpc.Stream) error {
+	clientStrm := &srpcFileStore_UploadStream{strm}
+	return impl.Upload(clientStrm)
+}
+
+func SRPCRegisterFileStore(mux srpc.Mux, impl SRPCFileStoreServer) error {
+	return mux.Register(&SRPCFileStoreHandler{impl: impl})
+}
+
+type SRPCFileStore_UploadStream interface {
+	srpc.Stream
+	SendAndClose(*UploadResult) error
+	Recv() (*UploadChunk, error)
+}
+
+type srpcFileStore_UploadStream struct {
+	srpc.Stream
+}
+
+func (x *srpcFileStore_UploadStream) SendAndClose(m *UploadResult) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+func (x *srpcFileStore_UploadStream) Recv() (*UploadChunk, error) {
+	m := new(UploadChunk)
+	if err := x.MsgRecv(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcFileStore_UploadStream) RecvTo(m *UploadChunk) error {
+	return x.MsgRecv(m)
+}
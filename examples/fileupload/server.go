@@ -0,0 +1,89 @@
+package fileupload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	srpc "github.com/aperturerobotics/starpc/srpc"
+)
+
+// ProgressKey is the progress metadata key with the number of bytes received.
+const ProgressKey = "received"
+
+// ErrInvalidName is returned if the file name is empty or invalid.
+var ErrInvalidName = errors.New("invalid file name")
+
+// Server implements the FileStore server storing files in a directory.
+type Server struct {
+	// dir is the directory to store files in
+	dir string
+}
+
+// NewServer constructs a new FileStore server storing files in dir.
+func NewServer(dir string) *Server {
+	return &Server{dir: dir}
+}
+
+// Register registers the Server with the Mux.
+func (s *Server) Register(mux srpc.Mux) error {
+	return SRPCRegisterFileStore(mux, s)
+}
+
+// Upload stores the uploaded file, sending progress after each chunk.
+func (s *Server) Upload(strm SRPCFileStore_UploadStream) error {
+	ctx := strm.Context()
+	chunk, err := strm.Recv()
+	if err != nil {
+		return err
+	}
+	name := filepath.Base(chunk.GetName())
+	if chunk.GetName() == "" || name == "." || name == ".." || name == string(filepath.Separator) {
+		return ErrInvalidName
+	}
+
+	f, err := os.Create(filepath.Join(s.dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	w := io.MultiWriter(f, hash)
+	var size uint64
+	for {
+		if data := chunk.GetData(); len(data) != 0 {
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+			size += uint64(len(data))
+			progress := srpc.Metadata{ProgressKey: strconv.FormatUint(size, 10)}
+			if err := srpc.SendProgress(ctx, progress); err != nil {
+				return err
+			}
+		}
+		chunk, err = strm.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return strm.SendAndClose(&UploadResult{
+		Name:   name,
+		Size:   size,
+		Sha256: hex.EncodeToString(hash.Sum(nil)),
+	})
+}
+
+// _ is a type assertion
+var _ SRPCFileStoreServer = ((*Server)(nil))
@@ -0,0 +1,105 @@
+package tunnel
+
+import (
+	"context"
+	"io"
+	"net"
+
+	"github.com/aperturerobotics/starpc/rpcstream"
+	srpc "github.com/aperturerobotics/starpc/srpc"
+)
+
+// Server implements the Tunnel server dialing targets allowed by a policy.
+type Server struct {
+	// getter dials the targets
+	getter rpcstream.RpcRawGetter
+}
+
+// NewServer constructs a new Tunnel server.
+//
+// Every dial is checked with policy, which must not be nil.
+func NewServer(policy rpcstream.RawDialPolicy) *Server {
+	return &Server{getter: rpcstream.NewRawDialGetter(policy)}
+}
+
+// Register registers the Server with the Mux.
+func (s *Server) Register(mux srpc.Mux) error {
+	return SRPCRegisterTunnel(mux, s)
+}
+
+// Dial proxies the stream to the target in the component ID.
+func (s *Server) Dial(strm SRPCTunnel_DialStream) error {
+	return rpcstream.HandleRawRpcStream(strm, s.getter)
+}
+
+// Conn is a tunnel opened with Dial.
+type Conn struct {
+	*rpcstream.RpcStreamReadWriter
+	// strm is the underlying rpc stream
+	strm rpcstream.RpcStream
+}
+
+// CloseWrite closes the write side of the tunnel.
+//
+// The remote connection receives EOF while data can still be read.
+func (c *Conn) CloseWrite() error {
+	return c.strm.CloseSend()
+}
+
+// Dial opens a tunnel to the target formatted like tcp:host:port.
+func Dial(ctx context.Context, client SRPCTunnelClient, target string) (*Conn, error) {
+	var strm rpcstream.RpcStream
+	rw, err := rpcstream.OpenRawRpcStream(ctx, func(ctx context.Context) (rpcstream.RpcStream, error) {
+		var err error
+		strm, err = client.Dial(ctx)
+		return strm, err
+	}, target)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{RpcStreamReadWriter: rw, strm: strm}, nil
+}
+
+// Forward accepts connections from the listener and tunnels each to target.
+//
+// Returns when the listener is closed or ctx is canceled.
+func Forward(ctx context.Context, lis net.Listener, client SRPCTunnelClient, target string) error {
+	ctx, ctxCancel := context.WithCancel(ctx)
+	defer ctxCancel()
+	go func() {
+		<-ctx.Done()
+		_ = lis.Close()
+	}()
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		go forwardConn(ctx, conn, client, target)
+	}
+}
+
+// forwardConn tunnels a single connection to the target.
+func forwardConn(ctx context.Context, conn net.Conn, client SRPCTunnelClient, target string) {
+	defer conn.Close()
+	rwc, err := Dial(ctx, client, target)
+	if err != nil {
+		return
+	}
+	defer rwc.Close()
+
+	go func() {
+		if _, err := io.Copy(rwc, conn); err == nil {
+			_ = rwc.CloseWrite()
+		} else {
+			_ = rwc.Close()
+		}
+	}()
+	_, _ = io.Copy(conn, rwc)
+}
+
+// _ is a type assertion
+var _ SRPCTunnelServer = ((*Server)(nil))
@@ -0,0 +1,78 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1-devel
+// 	protoc        v3.19.3
+// source: github.com/aperturerobotics/starpc/examples/tunnel/tunnel.proto
+
+package tunnel
+
+import (
+	reflect "reflect"
+
+	rpcstream "github.com/aperturerobotics/starpc/rpcstream"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto protoreflect.FileDescriptor
+
+var file_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto_rawDesc = []byte{
+	0x0a, 0x3f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x70, 0x65,
+	0x72, 0x74, 0x75, 0x72, 0x65, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x69, 0x63, 0x73, 0x2f, 0x73, 0x74,
+	0x61, 0x72, 0x70, 0x63, 0x2f, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x2f, 0x74, 0x75,
+	0x6e, 0x6e, 0x65, 0x6c, 0x2f, 0x74, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x06, 0x74, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x1a, 0x3c, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x70, 0x65, 0x72, 0x74, 0x75, 0x72, 0x65, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x69, 0x63, 0x73, 0x2f, 0x73, 0x74, 0x61, 0x72, 0x70, 0x63, 0x2f, 0x72, 0x70,
+	0x63, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2f, 0x72, 0x70, 0x63, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0x4c, 0x0a, 0x06, 0x54, 0x75, 0x6e, 0x6e, 0x65,
+	0x6c, 0x12, 0x42, 0x0a, 0x04, 0x44, 0x69, 0x61, 0x6c, 0x12, 0x1a, 0x2e, 0x72, 0x70, 0x63, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x52, 0x70, 0x63, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x50,
+	0x61, 0x63, 0x6b, 0x65, 0x74, 0x1a, 0x1a, 0x2e, 0x72, 0x70, 0x63, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x2e, 0x52, 0x70, 0x63, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x50, 0x61, 0x63, 0x6b, 0x65,
+	0x74, 0x28, 0x01, 0x30, 0x01, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var file_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto_goTypes = []interface{}{
+	(*rpcstream.RpcStreamPacket)(nil), // 0: rpcstream.RpcStreamPacket
+}
+var file_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto_depIdxs = []int32{
+	0, // 0: tunnel.Tunnel.Dial:input_type -> rpcstream.RpcStreamPacket
+	0, // 1: tunnel.Tunnel.Dial:output_type -> rpcstream.RpcStreamPacket
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto_init() }
+func file_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto_init() {
+	if File_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto_goTypes,
+		DependencyIndexes: file_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto_depIdxs,
+	}.Build()
+	File_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto = out.File
+	file_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto_rawDesc = nil
+	file_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto_goTypes = nil
+	file_github_com_aperturerobotics_starpc_examples_tunnel_tunnel_proto_depIdxs = nil
+}
@@ -0,0 +1,183 @@
+// Code generated by protoc-gen-srpc. DO NOT EDIT.
+// protoc-gen-srpc version: v0.0.0-20220611014014-aa9dc5523865
+// source: github.com/aperturerobotics/starpc/examples/tunnel/tunnel.proto
+
+package tunnel
+
+import (
+	context "context"
+	time "time"
+
+	rpcstream "github.com/aperturerobotics/starpc/rpcstream"
+	srpc "github.com/aperturerobotics/starpc/srpc"
+)
+
+type SRPCTunnelClient interface {
+	SRPCClient() srpc.Client
+
+	Dial(ctx context.Context, opts ...srpc.CallOption) (SRPCTunnel_DialClient, error)
+}
+
+type srpcTunnelClient struct {
+	cc srpc.Client
+}
+
+func NewSRPCTunnelClient(cc srpc.Client) SRPCTunnelClient {
+	return &srpcTunnelClient{cc}
+}
+
+func (c *srpcTunnelClient) SRPCClient() srpc.Client { return c.cc }
+
+func (c *srpcTunnelClient) Dial(ctx context.Context, opts ...srpc.CallOption) (SRPCTunnel_DialClient, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	stream, err := c.cc.NewStream(ctx, SRPCTunnelServiceID, SRPCTunnelDialMethodID, nil)
+	if err != nil {
+		return nil, err
+	}
+	strm := &srpcTunnel_DialClient{stream}
+	return strm, nil
+}
+
+type SRPCTunnel_DialClient interface {
+	srpc.Stream
+	Send(*rpcstream.RpcStreamPacket) error
+	SendCtx(context.Context, *rpcstream.RpcStreamPacket) error
+	srpc.StreamSendIter[*rpcstream.RpcStreamPacket]
+	srpc.StreamRecvIter[*rpcstream.RpcStreamPacket]
+	Recv() (*rpcstream.RpcStreamPacket, error)
+	RecvTo(*rpcstream.RpcStreamPacket) error
+	RecvTimeout(time.Duration) (*rpcstream.RpcStreamPacket, error)
+	TryRecv() (*rpcstream.RpcStreamPacket, bool, error)
+	RecvBatch(max int) ([]*rpcstream.RpcStreamPacket, error)
+}
+
+type srpcTunnel_DialClient struct {
+	srpc.Stream
+}
+
+func (x *srpcTunnel_DialClient) Send(m *rpcstream.RpcStreamPacket) error {
+	return x.MsgSend(m)
+}
+
+func (x *srpcTunnel_DialClient) SendCtx(ctx context.Context, m *rpcstream.RpcStreamPacket) error {
+	return srpc.MsgSendCtx(ctx, x.Stream, m)
+}
+
+func (x *srpcTunnel_DialClient) Recv() (*rpcstream.RpcStreamPacket, error) {
+	m := new(rpcstream.RpcStreamPacket)
+	if err := x.MsgRecv(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcTunnel_DialClient) RecvTo(m *rpcstream.RpcStreamPacket) error {
+	return x.MsgRecv(m)
+}
+
+func (x *srpcTunnel_DialClient) RecvTimeout(d time.Duration) (*rpcstream.RpcStreamPacket, error) {
+	m := new(rpcstream.RpcStreamPacket)
+	if err := srpc.MsgRecvTimeout(x.Stream, m, d); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcTunnel_DialClient) TryRecv() (*rpcstream.RpcStreamPacket, bool, error) {
+	m := new(rpcstream.RpcStreamPacket)
+	ok, err := srpc.MsgTryRecv(x.Stream, m)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return m, true, nil
+}
+
+func (x *srpcTunnel_DialClient) RecvBatch(max int) ([]*rpcstream.RpcStreamPacket, error) {
+	return srpc.RecvBatch(x.Stream, max, func() *rpcstream.RpcStreamPacket { return new(rpcstream.RpcStreamPacket) })
+}
+
+type SRPCTunnelServer interface {
+	Dial(SRPCTunnel_DialStream) error
+}
+
+type SRPCTunnelUnimplementedServer struct{}
+
+func (s *SRPCTunnelUnimplementedServer) Dial(SRPCTunnel_DialStream) error {
+	return srpc.ErrUnimplemented
+}
+
+const SRPCTunnelServiceID = "tunnel.Tunnel"
+
+const (
+	SRPCTunnelDialMethodID = "Dial"
+)
+
+// SRPCTunnelService contains the references to the methods of the service.
+var SRPCTunnelService = struct {
+	DialMethod srpc.MethodRef
+}{
+	DialMethod: srpc.MethodRef{ServiceID: SRPCTunnelServiceID, MethodID: SRPCTunnelDialMethodID},
+}
+
+type SRPCTunnelHandler struct {
+	impl SRPCTunnelServer
+}
+
+func (SRPCTunnelHandler) GetServiceID() string { return SRPCTunnelServiceID }
+
+func (SRPCTunnelHandler) GetMethodIDs() []string {
+	return []string{
+		"Dial",
+	}
+}
+
+func (d *SRPCTunnelHandler) InvokeMethod(
+	serviceID, methodID string,
+	strm srpc.Stream,
+) (bool, error) {
+	if serviceID != "" && serviceID != d.GetServiceID() {
+		return false, nil
+	}
+
+	switch methodID {
+	case "Dial":
+		return true, d.InvokeMethod_Dial(d.impl, strm)
+	default:
+		return false, nil
+	}
+}
+
+func (SRPCTunnelHandler) InvokeMethod_Dial(impl SRPCTunnelServer, strm srpc.Stream) error {
+	clientStrm := &srpcTunnel_DialStream{strm}
+	return impl.Dial(clientStrm)
+}
+
+func SRPCRegisterTunnel(mux srpc.Mux, impl SRPCTunnelServer) error {
+	return mux.Register(&SRPCTunnelHandler{impl: impl})
+}
+
+type SRPCTunnel_DialStream interface {
+	srpc.Stream
+	Send(*rpcstream.RpcStreamPacket) error
+	Recv() (*rpcstream.RpcStreamPacket, error)
+}
+
+type srpcTunnel_DialStream struct {
+	srpc.Stream
+}
+
+func (x *srpcTunnel_DialStream) Send(m *rpcstream.RpcStreamPacket) error {
+	return x.MsgSend(m)
+}
+
+func (x *srpcTunnel_DialStream) Recv() (*rpcstream.RpcStreamPacket, error) {
+	m := new(rpcstream.RpcStreamPacket)
+	if err := x.MsgRecv(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcTunnel_DialStream) RecvTo(m *rpcstream.RpcStreamPacket) error {
+	return x.MsgRecv(m)
+}
@@ -6,11 +6,13 @@ replace github.com/libp2p/go-libp2p => github.com/paralin/go-libp2p v0.20.1-0.20
 
 require (
 	github.com/pkg/errors v0.9.1
+	go.uber.org/goleak v1.1.12
 	google.golang.org/protobuf v1.27.1
 	nhooyr.io/websocket v1.8.8-0.20210410000328-8dee580a7f74
 )
 
 require (
+	github.com/klauspost/compress v1.15.1
 	github.com/libp2p/go-libp2p v0.20.1-0.20220622205512-3cf611ad8c9c
 	github.com/libp2p/go-libp2p-core v0.19.0
 	github.com/libp2p/go-mplex v0.7.1-0.20220702225122-8cbdf39b21f5
@@ -25,7 +27,6 @@ require (
 	github.com/gogo/protobuf v1.3.2 // indirect
 	github.com/ipfs/go-cid v0.2.0 // indirect
 	github.com/ipfs/go-log/v2 v2.5.1 // indirect
-	github.com/klauspost/compress v1.15.1 // indirect
 	github.com/klauspost/cpuid/v2 v2.0.12 // indirect
 	github.com/libp2p/go-buffer-pool v0.0.2 // indirect
 	github.com/libp2p/go-openssl v0.0.7 // indirect
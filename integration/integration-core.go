@@ -0,0 +1,11 @@
+//go:build starpc_core
+
+package main
+
+import "github.com/sirupsen/logrus"
+
+// main is not supported in the core build: the integration test uses the
+// websocket transport.
+func main() {
+	logrus.Fatal("integration: the websocket transport is excluded by the starpc_core tag")
+}
@@ -1,3 +1,5 @@
+//go:build !starpc_core
+
 package main
 
 import (
@@ -14,7 +14,7 @@ import (
 type SRPCIntegrationServiceClient interface {
 	SRPCClient() srpc.Client
 
-	RpcStream(ctx context.Context) (SRPCIntegrationService_RpcStreamClient, error)
+	RpcStream(ctx context.Context, opts ...srpc.CallOption) (SRPCIntegrationService_RpcStreamClient, error)
 }
 
 type srpcIntegrationServiceClient struct {
@@ -27,8 +27,9 @@ func NewSRPCIntegrationServiceClient(cc srpc.Client) SRPCIntegrationServiceClien
 
 func (c *srpcIntegrationServiceClient) SRPCClient() srpc.Client { return c.cc }
 
-func (c *srpcIntegrationServiceClient) RpcStream(ctx context.Context) (SRPCIntegrationService_RpcStreamClient, error) {
-	stream, err := c.cc.NewStream(ctx, "main.IntegrationService", "RpcStream", nil)
+func (c *srpcIntegrationServiceClient) RpcStream(ctx context.Context, opts ...srpc.CallOption) (SRPCIntegrationService_RpcStreamClient, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	stream, err := c.cc.NewStream(ctx, SRPCIntegrationServiceServiceID, SRPCIntegrationServiceRpcStreamMethodID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -39,6 +40,7 @@ func (c *srpcIntegrationServiceClient) RpcStream(ctx context.Context) (SRPCInteg
 type SRPCIntegrationService_RpcStreamClient interface {
 	srpc.Stream
 	Send(*rpcstream.RpcStreamPacket) error
+	SendCtx(context.Context, *rpcstream.RpcStreamPacket) error
 	Recv() (*rpcstream.RpcStreamPacket, error)
 	RecvTo(*rpcstream.RpcStreamPacket) error
 }
@@ -51,6 +53,10 @@ func (x *srpcIntegrationService_RpcStreamClient) Send(m *rpcstream.RpcStreamPack
 	return x.MsgSend(m)
 }
 
+func (x *srpcIntegrationService_RpcStreamClient) SendCtx(ctx context.Context, m *rpcstream.RpcStreamPacket) error {
+	return srpc.MsgSendCtx(ctx, x.Stream, m)
+}
+
 func (x *srpcIntegrationService_RpcStreamClient) Recv() (*rpcstream.RpcStreamPacket, error) {
 	m := new(rpcstream.RpcStreamPacket)
 	if err := x.MsgRecv(m); err != nil {
@@ -75,6 +81,17 @@ func (s *SRPCIntegrationServiceUnimplementedServer) RpcStream(SRPCIntegrationSer
 
 const SRPCIntegrationServiceServiceID = "main.IntegrationService"
 
+const (
+	SRPCIntegrationServiceRpcStreamMethodID = "RpcStream"
+)
+
+// SRPCIntegrationServiceService contains the references to the methods of the service.
+var SRPCIntegrationServiceService = struct {
+	RpcStreamMethod srpc.MethodRef
+}{
+	RpcStreamMethod: srpc.MethodRef{ServiceID: SRPCIntegrationServiceServiceID, MethodID: SRPCIntegrationServiceRpcStreamMethodID},
+}
+
 type SRPCIntegrationServiceHandler struct {
 	impl SRPCIntegrationServiceServer
 }
@@ -0,0 +1,87 @@
+// Package broadcast implements a wait/notify primitive.
+//
+// Mirrors the HoldLock API of the aperturerobotics/util broadcast package so
+// the srpc package does not need to depend on it.
+package broadcast
+
+import (
+	"context"
+	"sync"
+)
+
+// Broadcast wakes waiters when the state guarded by its lock changes.
+//
+// The zero value is ready to use.
+type Broadcast struct {
+	// mtx guards ch and the state of the caller
+	mtx sync.Mutex
+	// ch is closed when broadcast is called
+	ch chan struct{}
+}
+
+// HoldLock locks the mutex and calls the callback.
+//
+// broadcast wakes all waiters.
+// getWaitCh returns a channel which is closed on the next broadcast.
+// Do not call HoldLock from within the callback.
+func (c *Broadcast) HoldLock(cb func(broadcast func(), getWaitCh func() <-chan struct{})) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	cb(c.broadcastLocked, c.getWaitChLocked)
+}
+
+// TryHoldLock attempts to lock the mutex and call the callback.
+//
+// Returns false if the mutex was already locked.
+func (c *Broadcast) TryHoldLock(cb func(broadcast func(), getWaitCh func() <-chan struct{})) bool {
+	if !c.mtx.TryLock() {
+		return false
+	}
+	defer c.mtx.Unlock()
+	cb(c.broadcastLocked, c.getWaitChLocked)
+	return true
+}
+
+// Wait calls the callback with the lock held until it returns true or an error.
+//
+// Waits for the next broadcast between calls.
+// Returns context.Canceled if ctx is canceled.
+func (c *Broadcast) Wait(ctx context.Context, cb func(broadcast func(), getWaitCh func() <-chan struct{}) (bool, error)) error {
+	for {
+		var done bool
+		var err error
+		var waitCh <-chan struct{}
+		c.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+			done, err = cb(broadcast, getWaitCh)
+			if !done && err == nil {
+				waitCh = getWaitCh()
+			}
+		})
+		if done || err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		case <-waitCh:
+		}
+	}
+}
+
+// broadcastLocked wakes all waiters.
+// expects mtx to be locked.
+func (c *Broadcast) broadcastLocked() {
+	if c.ch != nil {
+		close(c.ch)
+		c.ch = nil
+	}
+}
+
+// getWaitChLocked returns the channel closed on the next broadcast.
+// expects mtx to be locked.
+func (c *Broadcast) getWaitChLocked() <-chan struct{} {
+	if c.ch == nil {
+		c.ch = make(chan struct{})
+	}
+	return c.ch
+}
@@ -0,0 +1,55 @@
+package broadcast
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBroadcast(t *testing.T) {
+	var bcast Broadcast
+	var value int
+
+	const waiters = 10
+	var wg sync.WaitGroup
+	errCh := make(chan error, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, ctxCancel := context.WithTimeout(context.Background(), time.Second*5)
+			defer ctxCancel()
+			errCh <- bcast.Wait(ctx, func(broadcast func(), getWaitCh func() <-chan struct{}) (bool, error) {
+				return value >= 3, nil
+			})
+		}()
+	}
+
+	for i := 0; i < 3; i++ {
+		bcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+			value++
+			broadcast()
+		})
+	}
+
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+}
+
+func TestBroadcast_WaitCanceled(t *testing.T) {
+	var bcast Broadcast
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	ctxCancel()
+	err := bcast.Wait(ctx, func(broadcast func(), getWaitCh func() <-chan struct{}) (bool, error) {
+		return false, nil
+	})
+	if err != context.Canceled {
+		t.Fatalf("expected %v got %v", context.Canceled, err)
+	}
+}
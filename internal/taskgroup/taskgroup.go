@@ -0,0 +1,29 @@
+// Package taskgroup tracks the goroutines owned by a connection or a call.
+//
+// The owner starts goroutines with Go and joins them with Wait, so closing
+// the owner deterministically waits for all of its goroutines to exit.
+package taskgroup
+
+import "sync"
+
+// Group is a set of goroutines owned by a single owner.
+//
+// The zero value is ready to use.
+type Group struct {
+	// wg tracks the running goroutines
+	wg sync.WaitGroup
+}
+
+// Go calls fn in a new goroutine tracked by the group.
+func (g *Group) Go(fn func()) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		fn()
+	}()
+}
+
+// Wait waits for all of the goroutines started with Go to exit.
+func (g *Group) Wait() {
+	g.wg.Wait()
+}
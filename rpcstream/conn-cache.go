@@ -0,0 +1,208 @@
+//go:build !starpc_core
+
+package rpcstream
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/libp2p/go-libp2p-core/network"
+)
+
+// ConnCache keeps one muxed RpcStream per component ID open.
+//
+// Calls through the cache open a muxed stream over the cached RpcStream
+// instead of a new RpcStream per call, reducing the per-call latency through
+// deep tunnels. A RpcStream is closed after it has been idle for the idle
+// timeout.
+type ConnCache struct {
+	// caller starts the RpcStream call.
+	caller RpcStreamCaller
+	// idleTimeout is how long an idle RpcStream is kept open.
+	idleTimeout time.Duration
+	// mtx guards conns
+	mtx sync.Mutex
+	// conns contains the cached conns by component id.
+	conns map[string]*cachedConn
+}
+
+// cachedConn is a muxed RpcStream with a component.
+type cachedConn struct {
+	// ctxCancel closes the RpcStream.
+	ctxCancel context.CancelFunc
+	// mconn is the muxed conn
+	mconn network.MuxedConn
+	// refs is the number of open streams.
+	// guarded by ConnCache.mtx
+	refs int
+	// idleTimer closes the conn when idle.
+	// guarded by ConnCache.mtx
+	idleTimer *time.Timer
+}
+
+// NewConnCache constructs a new ConnCache.
+//
+// If idleTimeout is zero, RpcStreams are closed as soon as they are idle.
+func NewConnCache(caller RpcStreamCaller, idleTimeout time.Duration) *ConnCache {
+	return &ConnCache{
+		caller:      caller,
+		idleTimeout: idleTimeout,
+		conns:       make(map[string]*cachedConn),
+	}
+}
+
+// NewClient constructs a client for the component using the cache.
+func (c *ConnCache) NewClient(componentID string) srpc.Client {
+	return srpc.NewClient(c.NewOpenStream(componentID))
+}
+
+// NewOpenStream constructs an OpenStream func for the component using the cache.
+func (c *ConnCache) NewOpenStream(componentID string) srpc.OpenStreamFunc {
+	return func(ctx context.Context, msgHandler srpc.PacketHandler, closeHandler srpc.CloseHandler) (srpc.Writer, error) {
+		strm, err := c.openStream(ctx, componentID)
+		if err != nil {
+			return nil, err
+		}
+		prw := srpc.NewPacketReadWriter(strm)
+		go prw.ReadPump(msgHandler, closeHandler)
+		return prw, nil
+	}
+}
+
+// Close closes all cached RpcStreams.
+func (c *ConnCache) Close() {
+	c.mtx.Lock()
+	conns := c.conns
+	c.conns = make(map[string]*cachedConn)
+	c.mtx.Unlock()
+	for _, conn := range conns {
+		conn.close()
+	}
+}
+
+// openStream opens a muxed stream with the component.
+func (c *ConnCache) openStream(ctx context.Context, componentID string) (io.ReadWriteCloser, error) {
+	conn, err := c.getConn(ctx, componentID)
+	if err != nil {
+		return nil, err
+	}
+	mstrm, err := conn.mconn.OpenStream(ctx)
+	if err != nil {
+		c.releaseConn(componentID, conn)
+		// the conn is broken: remove it so the next call reconnects.
+		c.removeConn(componentID, conn)
+		return nil, err
+	}
+	return &cachedStream{
+		MuxedStream: mstrm,
+		release: func() {
+			c.releaseConn(componentID, conn)
+		},
+	}, nil
+}
+
+// getConn returns the cached conn for the component adding a reference.
+func (c *ConnCache) getConn(ctx context.Context, componentID string) (*cachedConn, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	conn := c.conns[componentID]
+	if conn != nil && conn.mconn.IsClosed() {
+		conn.close()
+		delete(c.conns, componentID)
+		conn = nil
+	}
+	if conn == nil {
+		// the rpc stream outlives the call which opened it
+		connCtx, connCtxCancel := context.WithCancel(context.Background())
+		rpcStream, err := openRpcStream(connCtx, c.caller, componentID, true)
+		if err != nil {
+			connCtxCancel()
+			return nil, err
+		}
+		mconn, err := srpc.NewMuxedConn(NewRpcStreamReadWriter(rpcStream), true)
+		if err != nil {
+			_ = rpcStream.Close()
+			connCtxCancel()
+			return nil, err
+		}
+		conn = &cachedConn{ctxCancel: connCtxCancel, mconn: mconn}
+		c.conns[componentID] = conn
+	}
+	if conn.idleTimer != nil {
+		conn.idleTimer.Stop()
+		conn.idleTimer = nil
+	}
+	conn.refs++
+	return conn, nil
+}
+
+// releaseConn removes a reference to the conn starting the idle timer.
+func (c *ConnCache) releaseConn(componentID string, conn *cachedConn) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	conn.refs--
+	if conn.refs != 0 || c.conns[componentID] != conn {
+		return
+	}
+	if c.idleTimeout <= 0 {
+		delete(c.conns, componentID)
+		conn.close()
+		return
+	}
+	conn.idleTimer = time.AfterFunc(c.idleTimeout, func() {
+		c.mtx.Lock()
+		idle := conn.refs == 0 && c.conns[componentID] == conn
+		if idle {
+			delete(c.conns, componentID)
+		}
+		c.mtx.Unlock()
+		if idle {
+			conn.close()
+		}
+	})
+}
+
+// removeConn removes the conn from the cache and closes it.
+func (c *ConnCache) removeConn(componentID string, conn *cachedConn) {
+	c.mtx.Lock()
+	if c.conns[componentID] == conn {
+		delete(c.conns, componentID)
+	}
+	c.mtx.Unlock()
+	conn.close()
+}
+
+// close closes the RpcStream and the muxed conn.
+func (c *cachedConn) close() {
+	// cancel the rpc stream first to unblock the muxer read loop
+	c.ctxCancel()
+	_ = c.mconn.Close()
+}
+
+// cachedStream is a muxed stream releasing the conn when done.
+type cachedStream struct {
+	network.MuxedStream
+	// release releases the conn.
+	release func()
+	// releaseOnce guards release.
+	releaseOnce sync.Once
+}
+
+// Read reads from the stream, releasing the conn when the stream ends.
+func (s *cachedStream) Read(p []byte) (int, error) {
+	n, err := s.MuxedStream.Read(p)
+	if err != nil {
+		s.releaseOnce.Do(s.release)
+	}
+	return n, err
+}
+
+// Close closes the stream and releases the conn.
+func (s *cachedStream) Close() error {
+	err := s.MuxedStream.Close()
+	s.releaseOnce.Do(s.release)
+	return err
+}
@@ -0,0 +1,14 @@
+package rpcstream
+
+import "errors"
+
+var (
+	// ErrMuxedUnsupported is returned if muxed rpc streams are not supported.
+	ErrMuxedUnsupported = errors.New("muxed rpc streams are not supported")
+	// ErrNestedClientReleased is returned if the nested client was released.
+	ErrNestedClientReleased = errors.New("nested client released")
+	// ErrRawDialDenied is returned if the raw dial target is not allowed.
+	ErrRawDialDenied = errors.New("raw dial target not allowed")
+	// ErrInvalidRawDialTarget is returned if the raw dial target is invalid.
+	ErrInvalidRawDialTarget = errors.New("invalid raw dial target: expected tcp:host:port or unix:/path")
+)
@@ -0,0 +1,63 @@
+package rpcstream
+
+import (
+	"errors"
+	"strconv"
+)
+
+// HopError is an error returned by a hop in a chain of nested RpcStream.
+//
+// Hop zero is the remote which received the init packet, hop one is the remote
+// it forwarded the stream to (see HandleProxyRpcStream), and so on.
+type HopError struct {
+	// Hop is the index of the hop which returned the error.
+	Hop int
+	// ComponentID is the component id requested at the hop.
+	ComponentID string
+	// Err is the error returned by the hop.
+	Err error
+}
+
+// NewHopError constructs a new HopError.
+func NewHopError(hop int, componentID string, err error) *HopError {
+	return &HopError{Hop: hop, ComponentID: componentID, Err: err}
+}
+
+// Error returns the error string.
+func (e *HopError) Error() string {
+	var msg string
+	if e.Err != nil {
+		msg = e.Err.Error()
+	}
+	return "hop " + strconv.Itoa(e.Hop) + " (component " + strconv.Quote(e.ComponentID) + "): " + msg
+}
+
+// Unwrap returns the error returned by the hop.
+func (e *HopError) Unwrap() error {
+	return e.Err
+}
+
+// newAckError builds a HopError from an ack packet with an error.
+//
+// componentID is used if the ack does not specify the component id.
+func newAckError(ack *RpcAck, componentID string) *HopError {
+	if ackComponentID := ack.GetErrorComponentId(); ackComponentID != "" {
+		componentID = ackComponentID
+	}
+	return NewHopError(int(ack.GetErrorHop()), componentID, errors.New(ack.GetError()))
+}
+
+// newErrorAck builds an ack packet for an error.
+//
+// If err is a HopError from the next hop, it is forwarded with hop+1.
+func newErrorAck(err error, componentID string) *RpcAck {
+	var herr *HopError
+	if errors.As(err, &herr) && herr.Err != nil {
+		return &RpcAck{
+			Error:            herr.Err.Error(),
+			ErrorHop:         uint32(herr.Hop + 1),
+			ErrorComponentId: herr.ComponentID,
+		}
+	}
+	return &RpcAck{Error: err.Error(), ErrorComponentId: componentID}
+}
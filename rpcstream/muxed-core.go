@@ -0,0 +1,17 @@
+//go:build starpc_core
+
+package rpcstream
+
+import (
+	"context"
+
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// muxedSupported indicates muxed rpc streams are supported.
+const muxedSupported = false
+
+// handleMuxedRpcStream is not supported in the core build.
+func handleMuxedRpcStream(ctx context.Context, stream RpcStream, mux srpc.Mux) error {
+	return ErrMuxedUnsupported
+}
@@ -0,0 +1,27 @@
+//go:build !starpc_core
+
+package rpcstream
+
+import (
+	"context"
+	"io"
+
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// muxedSupported indicates muxed rpc streams are supported.
+const muxedSupported = true
+
+// handleMuxedRpcStream serves calls over a muxed rpc stream.
+func handleMuxedRpcStream(ctx context.Context, stream RpcStream, mux srpc.Mux) error {
+	mconn, err := srpc.NewMuxedConn(NewRpcStreamReadWriter(stream), false)
+	if err != nil {
+		return err
+	}
+	defer mconn.Close()
+	err = srpc.NewServer(mux).AcceptMuxedConn(ctx, mconn)
+	if err == io.EOF || err == context.Canceled || mconn.IsClosed() {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,37 @@
+package rpcstream
+
+import (
+	"context"
+
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// NewNestedClient constructs a typed client for a component over a RpcStream.
+//
+// newClient wraps the srpc.Client, for example NewSRPCEchoerClient. Each call
+// opens a nested RpcStream with the component. The returned release func
+// cancels any on-going calls and fails any further calls.
+func NewNestedClient[T any](caller RpcStreamCaller, componentID string, newClient func(srpc.Client) T) (T, func()) {
+	relCtx, relCtxCancel := context.WithCancel(context.Background())
+	openStream := NewRpcStreamOpenStream(caller, componentID)
+	client := srpc.NewClient(func(ctx context.Context, msgHandler srpc.PacketHandler, closeHandler srpc.CloseHandler) (srpc.Writer, error) {
+		if relCtx.Err() != nil {
+			return nil, ErrNestedClientReleased
+		}
+		callCtx, callCtxCancel := context.WithCancel(ctx)
+		go func() {
+			select {
+			case <-relCtx.Done():
+				callCtxCancel()
+			case <-callCtx.Done():
+			}
+		}()
+		w, err := openStream(callCtx, msgHandler, closeHandler)
+		if err != nil {
+			callCtxCancel()
+			return nil, err
+		}
+		return w, nil
+	})
+	return newClient(client), relCtxCancel
+}
@@ -0,0 +1,82 @@
+package rpcstream
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// RpcProxyGetter returns the caller and component ID of the next hop to forward
+// the RpcStream for the component ID to.
+type RpcProxyGetter func(ctx context.Context, componentID string) (RpcStreamCaller, string, error)
+
+// HandleProxyRpcStream handles an incoming RPC stream by forwarding it to the
+// next hop returned by getter.
+//
+// Errors opening the next hop are returned to the initiator as a HopError with
+// the index of the hop which failed.
+func HandleProxyRpcStream(stream RpcStream, getter RpcProxyGetter) (rerr error) {
+	// Read the "init" packet.
+	streamInit, err := readInit(stream)
+	if err != nil {
+		return err
+	}
+	componentID := streamInit.GetComponentId()
+	ctx, endHop := startHandleHop(stream.Context(), streamInit)
+	defer func() {
+		endHop(rerr)
+	}()
+
+	// lookup the next hop for this component id
+	rpcCaller, nextComponentID, err := getter(ctx, componentID)
+	if err == nil && rpcCaller == nil {
+		err = errors.New("no proxy for that component")
+	}
+
+	// open the stream to the next hop
+	var next RpcStream
+	if err == nil {
+		next, err = openRpcStream(ctx, rpcCaller, nextComponentID, streamInit.GetMuxed())
+	}
+
+	// send ack
+	if err := sendAck(stream, componentID, err); err != nil {
+		if next != nil {
+			_ = next.Close()
+		}
+		return err
+	}
+	defer next.Close()
+
+	// forward packets to the next hop
+	go func() {
+		if err := copyRpcStream(next, stream); err != nil {
+			_ = next.Close()
+		}
+	}()
+
+	// forward packets from the next hop
+	if err := copyRpcStream(stream, next); err != nil {
+		return NewHopError(0, nextComponentID, err)
+	}
+	return nil
+}
+
+// copyRpcStream copies packets from src to dst until src is closed.
+//
+// Calls CloseSend on dst if src was closed cleanly.
+func copyRpcStream(dst, src RpcStream) error {
+	for {
+		pkt, err := src.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return dst.CloseSend()
+			}
+			return err
+		}
+		if err := dst.Send(pkt); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package rpcstream
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+)
+
+// RawDialPolicy checks if dialing the network address is allowed.
+//
+// Returns an error if the dial is not allowed.
+type RawDialPolicy func(ctx context.Context, network, address string) error
+
+// NewRawDialAllowlist constructs a RawDialPolicy allowing the listed targets.
+//
+// Targets are formatted the same as component IDs, i.e. tcp:host:port.
+func NewRawDialAllowlist(targets ...string) RawDialPolicy {
+	allowed := make(map[string]struct{}, len(targets))
+	for _, target := range targets {
+		allowed[target] = struct{}{}
+	}
+	return func(ctx context.Context, network, address string) error {
+		if _, ok := allowed[network+":"+address]; !ok {
+			return ErrRawDialDenied
+		}
+		return nil
+	}
+}
+
+// ParseRawDialTarget parses a component ID like tcp:host:port or unix:/path.
+func ParseRawDialTarget(componentID string) (network, address string, err error) {
+	network, address, ok := strings.Cut(componentID, ":")
+	if !ok || address == "" {
+		return "", "", ErrInvalidRawDialTarget
+	}
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+	default:
+		return "", "", ErrInvalidRawDialTarget
+	}
+	return network, address, nil
+}
+
+// NewRawDialGetter constructs a RpcRawGetter which dials the target in the
+// component ID on demand, see ParseRawDialTarget.
+//
+// Every dial is checked with policy, which must not be nil.
+func NewRawDialGetter(policy RawDialPolicy) RpcRawGetter {
+	var dialer net.Dialer
+	return func(ctx context.Context, componentID string) (io.ReadWriteCloser, func(), error) {
+		network, address, err := ParseRawDialTarget(componentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := policy(ctx, network, address); err != nil {
+			return nil, nil, err
+		}
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil {
+			return nil, nil, err
+		}
+		return conn, nil, nil
+	}
+}
@@ -0,0 +1,84 @@
+package rpcstream
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// RpcRawGetter returns a read/write/closer to proxy data to/from.
+//
+// Returns nil if not found. The release function is called when done and may
+// be nil.
+type RpcRawGetter func(ctx context.Context, componentID string) (io.ReadWriteCloser, func(), error)
+
+// OpenRawRpcStream opens a RPC stream with a remote serving a raw component.
+//
+// Data written to the stream is forwarded to the component and vice-versa.
+func OpenRawRpcStream(ctx context.Context, rpcCaller RpcStreamCaller, componentID string) (*RpcStreamReadWriter, error) {
+	rpcStream, err := openRpcStream(ctx, rpcCaller, componentID, false)
+	if err != nil {
+		return nil, err
+	}
+	return NewRpcStreamReadWriter(rpcStream), nil
+}
+
+// HandleRawRpcStream handles an incoming RPC stream (remote is the initiator)
+// by proxying data to/from the read/write/closer returned by the getter.
+func HandleRawRpcStream(stream RpcStream, getter RpcRawGetter) (rerr error) {
+	// Read the "init" packet.
+	streamInit, err := readInit(stream)
+	if err != nil {
+		return err
+	}
+	componentID := streamInit.GetComponentId()
+	ctx, endHop := startHandleHop(stream.Context(), streamInit)
+	defer func() {
+		endHop(rerr)
+	}()
+
+	// lookup the read/writer for this component id
+	rwc, rel, err := getter(ctx, componentID)
+	if rel != nil {
+		defer rel()
+	}
+	if err == nil && rwc == nil {
+		err = errors.New("no read/writer for that component")
+	}
+	if err == nil && streamInit.GetMuxed() {
+		err = ErrMuxedUnsupported
+	}
+
+	// send ack
+	if err := sendAck(stream, componentID, err); err != nil {
+		if rwc != nil {
+			_ = rwc.Close()
+		}
+		return err
+	}
+	defer rwc.Close()
+
+	// forward data from the remote
+	srw := NewRpcStreamReadWriter(stream)
+	go func() {
+		_, err := io.Copy(rwc, srw)
+		if cw, ok := rwc.(closeWriter); ok && err == nil {
+			_ = cw.CloseWrite()
+		} else {
+			_ = rwc.Close()
+		}
+	}()
+
+	// forward data to the remote
+	_, err = io.Copy(srw, rwc)
+	if err != nil {
+		return err
+	}
+	return stream.CloseSend()
+}
+
+// closeWriter is a read/writer which can close the write side only.
+type closeWriter interface {
+	CloseWrite() error
+}
@@ -25,10 +25,38 @@ type RpcStreamCaller func(ctx context.Context) (RpcStream, error)
 
 // OpenRpcStream opens a RPC stream with a remote.
 func OpenRpcStream(ctx context.Context, rpcCaller RpcStreamCaller, componentID string) (*srpc.PacketReaderWriter, error) {
+	rpcStream, err := openRpcStream(ctx, rpcCaller, componentID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// ready
+	rw := NewRpcStreamReadWriter(rpcStream)
+	return srpc.NewPacketReadWriter(rw), nil
+}
+
+// openRpcStream opens a RPC stream and waits for the ack.
+//
+// Errors are wrapped with a HopError indicating which hop failed.
+func openRpcStream(ctx context.Context, rpcCaller RpcStreamCaller, componentID string, muxed bool) (RpcStream, error) {
+	t := getTracer()
+	if t == nil {
+		return openRpcStreamWithMetadata(ctx, rpcCaller, componentID, muxed, nil)
+	}
+	md, end := t.OpenHop(ctx, componentID)
+	rpcStream, err := openRpcStreamWithMetadata(ctx, rpcCaller, componentID, muxed, md)
+	if end != nil {
+		end(err)
+	}
+	return rpcStream, err
+}
+
+// openRpcStreamWithMetadata opens a RPC stream sending the metadata with init.
+func openRpcStreamWithMetadata(ctx context.Context, rpcCaller RpcStreamCaller, componentID string, muxed bool, md srpc.Metadata) (RpcStream, error) {
 	// open the rpc stream
 	rpcStream, err := rpcCaller(ctx)
 	if err != nil {
-		return nil, err
+		return nil, NewHopError(0, componentID, err)
 	}
 
 	// write the component id
@@ -36,12 +64,14 @@ func OpenRpcStream(ctx context.Context, rpcCaller RpcStreamCaller, componentID s
 		Body: &RpcStreamPacket_Init{
 			Init: &RpcStreamInit{
 				ComponentId: componentID,
+				Muxed:       muxed,
+				Metadata:    md,
 			},
 		},
 	})
 	if err != nil {
 		_ = rpcStream.Close()
-		return nil, err
+		return nil, NewHopError(0, componentID, err)
 	}
 
 	// wait for ack
@@ -49,8 +79,9 @@ func OpenRpcStream(ctx context.Context, rpcCaller RpcStreamCaller, componentID s
 	if err == nil {
 		switch b := pkt.GetBody().(type) {
 		case *RpcStreamPacket_Ack:
-			if errStr := b.Ack.GetError(); errStr != "" {
-				err = errors.Errorf("remote: %s", errStr)
+			if b.Ack.GetError() != "" {
+				_ = rpcStream.Close()
+				return nil, newAckError(b.Ack, componentID)
 			}
 		default:
 			err = errors.New("expected ack packet")
@@ -58,12 +89,10 @@ func OpenRpcStream(ctx context.Context, rpcCaller RpcStreamCaller, componentID s
 	}
 	if err != nil {
 		_ = rpcStream.Close()
-		return nil, err
+		return nil, NewHopError(0, componentID, err)
 	}
 
-	// ready
-	rw := NewRpcStreamReadWriter(rpcStream)
-	return srpc.NewPacketReadWriter(rw), nil
+	return rpcStream, nil
 }
 
 // NewRpcStreamOpenStream constructs an OpenStream function with a RpcStream.
@@ -84,43 +113,37 @@ func NewRpcStreamOpenStream(rpcCaller RpcStreamCaller, componentID string) srpc.
 }
 
 // HandleRpcStream handles an incoming RPC stream (remote is the initiator).
-func HandleRpcStream(stream RpcStream, getter RpcStreamGetter) error {
+//
+// If the initiator requested a muxed stream, serves many calls over the stream.
+func HandleRpcStream(stream RpcStream, getter RpcStreamGetter) (rerr error) {
 	// Read the "init" packet.
-	initPkt, err := stream.Recv()
+	streamInit, err := readInit(stream)
 	if err != nil {
 		return err
 	}
-	initInner, ok := initPkt.GetBody().(*RpcStreamPacket_Init)
-	if !ok || initInner.Init == nil {
-		return errors.New("expected init packet")
-	}
-	componentID := initInner.Init.GetComponentId()
-	if componentID == "" {
-		return errors.New("invalid init packet: empty component id")
-	}
+	componentID := streamInit.GetComponentId()
+	ctx, endHop := startHandleHop(stream.Context(), streamInit)
+	defer func() {
+		endHop(rerr)
+	}()
 
 	// lookup the server for this component id
-	ctx := stream.Context()
 	mux, err := getter(ctx, componentID)
 	if err == nil && mux == nil {
 		err = errors.New("no server for that component")
 	}
+	if err == nil && streamInit.GetMuxed() && !muxedSupported {
+		err = ErrMuxedUnsupported
+	}
 
 	// send ack
-	var errStr string
-	if err != nil {
-		errStr = err.Error()
-	}
-	sendErr := stream.Send(&RpcStreamPacket{
-		Body: &RpcStreamPacket_Ack{
-			Ack: &RpcAck{Error: errStr},
-		},
-	})
-	if err != nil {
+	if err := sendAck(stream, componentID, err); err != nil {
 		return err
 	}
-	if sendErr != nil {
-		return sendErr
+
+	// handle the muxed rpc stream
+	if streamInit.GetMuxed() {
+		return handleMuxedRpcStream(ctx, stream, mux)
 	}
 
 	// handle the rpc
@@ -129,7 +152,43 @@ func HandleRpcStream(stream RpcStream, getter RpcStreamGetter) error {
 	prw := srpc.NewPacketReadWriter(srw)
 	serverRPC.SetWriter(prw)
 	go prw.ReadPump(serverRPC.HandlePacket, serverRPC.HandleStreamClose)
-	return serverRPC.Wait(ctx)
+	err = serverRPC.Wait(ctx)
+	// the read pump exits when the caller closes stream: join the handler.
+	serverRPC.Join()
+	return err
+}
+
+// readInit reads the init packet from the stream.
+func readInit(stream RpcStream) (*RpcStreamInit, error) {
+	initPkt, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	initInner, ok := initPkt.GetBody().(*RpcStreamPacket_Init)
+	if !ok || initInner.Init == nil {
+		return nil, errors.New("expected init packet")
+	}
+	if initInner.Init.GetComponentId() == "" {
+		return nil, errors.New("invalid init packet: empty component id")
+	}
+	return initInner.Init, nil
+}
+
+// sendAck sends the ack packet for the init with the given error.
+//
+// Returns setupErr if set, otherwise any error sending the ack.
+func sendAck(stream RpcStream, componentID string, setupErr error) error {
+	ack := &RpcAck{}
+	if setupErr != nil {
+		ack = newErrorAck(setupErr, componentID)
+	}
+	sendErr := stream.Send(&RpcStreamPacket{
+		Body: &RpcStreamPacket_Ack{Ack: ack},
+	})
+	if setupErr != nil {
+		return setupErr
+	}
+	return sendErr
 }
 
 // RpcStreamReadWriter reads and writes a buffered RpcStream.
@@ -129,6 +129,13 @@ type RpcStreamInit struct {
 
 	// ComponentId is the identifier of the component making the request.
 	ComponentId string `protobuf:"bytes,1,opt,name=component_id,json=componentId,proto3" json:"component_id,omitempty"`
+	// Muxed requests running a stream muxer over the RPC stream.
+	// If set, the initiator can open many RPC calls over the single stream.
+	Muxed bool `protobuf:"varint,2,opt,name=muxed,proto3" json:"muxed,omitempty"`
+	// Metadata contains key/value pairs sent with the init packet.
+	// Used to propagate the trace context across hops, see Tracer.
+	// Optional.
+	Metadata map[string]string `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *RpcStreamInit) Reset() {
@@ -170,6 +177,20 @@ func (x *RpcStreamInit) GetComponentId() string {
 	return ""
 }
 
+func (x *RpcStreamInit) GetMuxed() bool {
+	if x != nil {
+		return x.Muxed
+	}
+	return false
+}
+
+func (x *RpcStreamInit) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 // RpcAck is the ack message in a RPC stream.
 type RpcAck struct {
 	state         protoimpl.MessageState
@@ -178,6 +199,11 @@ type RpcAck struct {
 
 	// Error indicates there was some error setting up the stream.
 	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// ErrorHop is the index of the hop which returned the error.
+	// Zero is the remote which received the init packet.
+	ErrorHop uint32 `protobuf:"varint,2,opt,name=error_hop,json=errorHop,proto3" json:"error_hop,omitempty"`
+	// ErrorComponentId is the component id at the hop which returned the error.
+	ErrorComponentId string `protobuf:"bytes,3,opt,name=error_component_id,json=errorComponentId,proto3" json:"error_component_id,omitempty"`
 }
 
 func (x *RpcAck) Reset() {
@@ -219,6 +245,20 @@ func (x *RpcAck) GetError() string {
 	return ""
 }
 
+func (x *RpcAck) GetErrorHop() uint32 {
+	if x != nil {
+		return x.ErrorHop
+	}
+	return 0
+}
+
+func (x *RpcAck) GetErrorComponentId() string {
+	if x != nil {
+		return x.ErrorComponentId
+	}
+	return ""
+}
+
 var File_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto protoreflect.FileDescriptor
 
 var file_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto_rawDesc = []byte{
@@ -235,12 +275,27 @@ var file_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto_rawDesc =
 	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x52, 0x70, 0x63, 0x41, 0x63, 0x6b, 0x48, 0x00, 0x52,
 	0x03, 0x61, 0x63, 0x6b, 0x12, 0x14, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01,
 	0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x42, 0x06, 0x0a, 0x04, 0x62, 0x6f,
-	0x64, 0x79, 0x22, 0x32, 0x0a, 0x0d, 0x52, 0x70, 0x63, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49,
-	0x6e, 0x69, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x6f,
-	0x6e, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x1e, 0x0a, 0x06, 0x52, 0x70, 0x63, 0x41, 0x63, 0x6b,
-	0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x64, 0x79, 0x22, 0xc9, 0x01, 0x0a, 0x0d, 0x52, 0x70, 0x63, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x49, 0x6e, 0x69, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70,
+	0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x75, 0x78, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x6d, 0x75, 0x78, 0x65, 0x64, 0x12, 0x42, 0x0a,
+	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x26, 0x2e, 0x72, 0x70, 0x63, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x2e, 0x52, 0x70, 0x63, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x6e, 0x69, 0x74, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x69,
+	0x0a, 0x06, 0x52, 0x70, 0x63, 0x41, 0x63, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1b,
+	0x0a, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x68, 0x6f, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x08, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x48, 0x6f, 0x70, 0x12, 0x2c, 0x0a, 0x12, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f,
+	0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -255,20 +310,22 @@ func file_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto_rawDescGZ
 	return file_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto_rawDescData
 }
 
-var file_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto_goTypes = []interface{}{
 	(*RpcStreamPacket)(nil), // 0: rpcstream.RpcStreamPacket
 	(*RpcStreamInit)(nil),   // 1: rpcstream.RpcStreamInit
 	(*RpcAck)(nil),          // 2: rpcstream.RpcAck
+	nil,                     // 3: rpcstream.RpcStreamInit.MetadataEntry
 }
 var file_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto_depIdxs = []int32{
 	1, // 0: rpcstream.RpcStreamPacket.init:type_name -> rpcstream.RpcStreamInit
 	2, // 1: rpcstream.RpcStreamPacket.ack:type_name -> rpcstream.RpcAck
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	3, // 2: rpcstream.RpcStreamInit.metadata:type_name -> rpcstream.RpcStreamInit.MetadataEntry
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto_init() }
@@ -325,7 +382,7 @@ func file_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_github_com_aperturerobotics_starpc_rpcstream_rpcstream_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,243 @@
+package rpcstream_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/rpcstream"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// hopEchoServer handles RpcStream with a custom handler.
+type hopEchoServer struct {
+	*echo.EchoServer
+	handleRpcStream func(stream rpcstream.RpcStream) error
+}
+
+func TestRpcStreamHopError(t *testing.T) {
+	ctx := context.Background()
+
+	// hop 1: serves the "echo" component only
+	muxB := srpc.NewMux()
+	serverB := &hopEchoServer{EchoServer: echo.NewEchoServer(muxB)}
+	serverB.handleRpcStream = func(stream rpcstream.RpcStream) error {
+		return rpcstream.HandleRpcStream(stream, func(ctx context.Context, componentID string) (srpc.Mux, error) {
+			if componentID != "echo" {
+				return nil, errors.New("unknown component")
+			}
+			return muxB, nil
+		})
+	}
+	if err := echo.SRPCRegisterEchoer(muxB, serverB); err != nil {
+		t.Fatal(err.Error())
+	}
+	clientB := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(muxB))))
+
+	// hop 0: proxies all components except "blocked" to hop 1
+	muxA := srpc.NewMux()
+	serverA := &hopEchoServer{EchoServer: echo.NewEchoServer(muxA)}
+	serverA.handleRpcStream = func(stream rpcstream.RpcStream) error {
+		return rpcstream.HandleProxyRpcStream(stream, func(ctx context.Context, componentID string) (rpcstream.RpcStreamCaller, string, error) {
+			if componentID == "blocked" {
+				return nil, "", errors.New("blocked component")
+			}
+			return func(ctx context.Context) (rpcstream.RpcStream, error) {
+				return clientB.RpcStream(ctx)
+			}, componentID, nil
+		})
+	}
+	if err := echo.SRPCRegisterEchoer(muxA, serverA); err != nil {
+		t.Fatal(err.Error())
+	}
+	clientA := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(muxA))))
+	callerA := func(ctx context.Context) (rpcstream.RpcStream, error) {
+		return clientA.RpcStream(ctx)
+	}
+
+	// expect calls to be forwarded over both hops
+	nestedClient, release := rpcstream.NewNestedClient(callerA, "echo", echo.NewSRPCEchoerClient)
+	resp, err := nestedClient.Echo(ctx, &echo.EchoMsg{Body: "hello"})
+	release()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != "hello" {
+		t.Fatalf("expected hello got %q", resp.GetBody())
+	}
+
+	for componentID, expectedHop := range map[string]int{"blocked": 0, "missing": 1} {
+		nestedClient, release := rpcstream.NewNestedClient(callerA, componentID, echo.NewSRPCEchoerClient)
+		_, err := nestedClient.Echo(ctx, &echo.EchoMsg{Body: "hello"})
+		release()
+		var herr *rpcstream.HopError
+		if !errors.As(err, &herr) {
+			t.Fatalf("%s: expected hop error: %v", componentID, err)
+		}
+		if herr.Hop != expectedHop || herr.ComponentID != componentID {
+			t.Fatalf("%s: expected hop %d got hop %d component %q: %v", componentID, expectedHop, herr.Hop, herr.ComponentID, herr)
+		}
+		t.Log(herr.Error())
+	}
+}
+
+// traceSpanCtxKey is the context key for the test span name.
+type traceSpanCtxKey struct{}
+
+// testTracer builds span names from the hops.
+type testTracer struct {
+	// openEnds is the number of OpenHop spans ended.
+	openEnds int32
+}
+
+// traceEchoServer returns the span name in the Echo response.
+type traceEchoServer struct {
+	*hopEchoServer
+}
+
+func TestRpcStreamTracer(t *testing.T) {
+	tracer := &testTracer{}
+	rpcstream.SetTracer(tracer)
+	defer rpcstream.SetTracer(nil)
+	ctx := context.Background()
+
+	// component: serves the traced echo server
+	muxB := srpc.NewMux()
+	serverB := &traceEchoServer{hopEchoServer: &hopEchoServer{EchoServer: echo.NewEchoServer(muxB)}}
+	serverB.handleRpcStream = func(stream rpcstream.RpcStream) error {
+		return rpcstream.HandleRpcStream(stream, func(ctx context.Context, componentID string) (srpc.Mux, error) {
+			return muxB, nil
+		})
+	}
+	if err := echo.SRPCRegisterEchoer(muxB, serverB); err != nil {
+		t.Fatal(err.Error())
+	}
+	clientB := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(muxB))))
+
+	// proxy: forwards to the component
+	muxA := srpc.NewMux()
+	serverA := &hopEchoServer{EchoServer: echo.NewEchoServer(muxA)}
+	serverA.handleRpcStream = func(stream rpcstream.RpcStream) error {
+		return rpcstream.HandleProxyRpcStream(stream, func(ctx context.Context, componentID string) (rpcstream.RpcStreamCaller, string, error) {
+			return func(ctx context.Context) (rpcstream.RpcStream, error) {
+				return clientB.RpcStream(ctx)
+			}, componentID, nil
+		})
+	}
+	if err := echo.SRPCRegisterEchoer(muxA, serverA); err != nil {
+		t.Fatal(err.Error())
+	}
+	clientA := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(muxA))))
+
+	// expect the handler span to be linked through each hop
+	nestedClient, release := rpcstream.NewNestedClient(func(ctx context.Context) (rpcstream.RpcStream, error) {
+		return clientA.RpcStream(ctx)
+	}, "echo", echo.NewSRPCEchoerClient)
+	defer release()
+	resp, err := nestedClient.Echo(ctx, &echo.EchoMsg{Body: "hello"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := "open:echo>handle:echo>open:echo>handle:echo"
+	if resp.GetBody() != expected {
+		t.Fatalf("expected span %q got %q", expected, resp.GetBody())
+	}
+	if n := atomic.LoadInt32(&tracer.openEnds); n != 2 {
+		t.Fatalf("expected 2 open hop spans ended got %d", n)
+	}
+}
+
+func TestRpcStreamRawDial(t *testing.T) {
+	ctx := context.Background()
+
+	// start a tcp echo target
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer lis.Close()
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(conn, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+	target := "tcp:" + lis.Addr().String()
+
+	mux := srpc.NewMux()
+	echoServer := &hopEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	getter := rpcstream.NewRawDialGetter(rpcstream.NewRawDialAllowlist(target))
+	echoServer.handleRpcStream = func(stream rpcstream.RpcStream) error {
+		return rpcstream.HandleRawRpcStream(stream, getter)
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	caller := func(ctx context.Context) (rpcstream.RpcStream, error) {
+		return client.RpcStream(ctx)
+	}
+
+	// expect data to be forwarded to the allowed target
+	rw, err := rpcstream.OpenRawRpcStream(ctx, caller, target)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer rw.Close()
+	if _, err := rw.Write([]byte("hello")); err != nil {
+		t.Fatal(err.Error())
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(rw, buf); err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(buf) != "hello" {
+		t.Fatalf("expected hello got %q", string(buf))
+	}
+
+	// expect other targets to be rejected
+	for _, componentID := range []string{"tcp:127.0.0.1:1", "udp:127.0.0.1:53", "echo"} {
+		_, err := rpcstream.OpenRawRpcStream(ctx, caller, componentID)
+		if err == nil {
+			t.Fatalf("expected %s to be rejected", componentID)
+		}
+		t.Log(err.Error())
+	}
+}
+
+// RpcStream handles the rpc stream.
+func (s *hopEchoServer) RpcStream(stream echo.SRPCEchoer_RpcStreamStream) error {
+	return s.handleRpcStream(stream)
+}
+
+// OpenHop starts a span for opening the hop.
+func (t *testTracer) OpenHop(ctx context.Context, componentID string) (srpc.Metadata, func(err error)) {
+	span := "open:" + componentID
+	if parent, _ := ctx.Value(traceSpanCtxKey{}).(string); parent != "" {
+		span = parent + ">" + span
+	}
+	return srpc.Metadata{"span": span}, func(err error) {
+		atomic.AddInt32(&t.openEnds, 1)
+	}
+}
+
+// HandleHop starts a span linked to the remote span.
+func (t *testTracer) HandleHop(ctx context.Context, componentID string, md srpc.Metadata) (context.Context, func(err error)) {
+	return context.WithValue(ctx, traceSpanCtxKey{}, md.Get("span")+">handle:"+componentID), nil
+}
+
+// Echo returns the span name from the context.
+func (s *traceEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	span, _ := ctx.Value(traceSpanCtxKey{}).(string)
+	return &echo.EchoMsg{Body: span}, nil
+}
@@ -0,0 +1,69 @@
+package rpcstream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// Tracer traces the hops of RpcStream tunnels.
+//
+// For example, an OpenTelemetry Tracer starts a span in OpenHop, injects the
+// span context into the metadata, and in HandleHop extracts it to start a
+// linked span for the hop. The spans of calls made with the context returned
+// by HandleHop are children of the hop, so a trace shows client, proxy,
+// component and handler with the latency of each hop.
+type Tracer interface {
+	// OpenHop is called when opening a RpcStream with the component.
+	//
+	// The returned metadata is sent to the remote with the init packet.
+	// end is called once the stream was opened or failed and may be nil.
+	OpenHop(ctx context.Context, componentID string) (md srpc.Metadata, end func(err error))
+	// HandleHop is called when handling a RpcStream for the component with
+	// the metadata sent by the remote.
+	//
+	// The returned context is used to handle the stream.
+	// end is called once the stream is done and may be nil.
+	HandleHop(ctx context.Context, componentID string, md srpc.Metadata) (hopCtx context.Context, end func(err error))
+}
+
+// tracer is the Tracer set by SetTracer.
+var (
+	tracerMtx sync.RWMutex
+	tracer    Tracer
+)
+
+// SetTracer sets the Tracer used for all RpcStreams.
+//
+// If t is nil, disables tracing.
+func SetTracer(t Tracer) {
+	tracerMtx.Lock()
+	tracer = t
+	tracerMtx.Unlock()
+}
+
+// getTracer returns the Tracer set by SetTracer or nil.
+func getTracer() Tracer {
+	tracerMtx.RLock()
+	defer tracerMtx.RUnlock()
+	return tracer
+}
+
+// startHandleHop calls HandleHop on the Tracer if set.
+//
+// The returned end func is never nil.
+func startHandleHop(ctx context.Context, streamInit *RpcStreamInit) (context.Context, func(err error)) {
+	t := getTracer()
+	if t == nil {
+		return ctx, func(err error) {}
+	}
+	hopCtx, end := t.HandleHop(ctx, streamInit.GetComponentId(), srpc.Metadata(streamInit.GetMetadata()))
+	if hopCtx == nil {
+		hopCtx = ctx
+	}
+	if end == nil {
+		end = func(err error) {}
+	}
+	return hopCtx, end
+}
@@ -1,3 +1,5 @@
+//go:build !starpc_core
+
 package srpc
 
 import (
@@ -8,22 +10,30 @@ import (
 // AcceptMuxedListener accepts incoming connections from a net.Listener.
 //
 // Uses the default mplex muxer.
-func AcceptMuxedListener(ctx context.Context, lis net.Listener, srv *Server) error {
+// Serves each connection in a separate goroutine.
+// Applies the TCP options to accepted TCP connections.
+func AcceptMuxedListener(ctx context.Context, lis net.Listener, srv *Server, opts ...TCPOption) error {
 	for {
 		nc, err := lis.Accept()
 		if err != nil {
 			return err
 		}
 
-		mc, err := NewMuxedConn(nc, false)
-		if err != nil {
+		if err := ApplyTCPOptions(nc, opts...); err != nil {
 			_ = nc.Close()
 			continue
 		}
 
-		if err := srv.AcceptMuxedConn(ctx, mc); err != nil {
+		mc, err := NewMuxedConn(nc, false)
+		if err != nil {
 			_ = nc.Close()
 			continue
 		}
+
+		connCtx := WithConnInfo(ctx, NewConnInfo(nc, MuxerMplex))
+		go func() {
+			_ = srv.AcceptMuxedConn(connCtx, mc)
+			_ = mc.Close()
+		}()
 	}
 }
@@ -0,0 +1,115 @@
+package srpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/aperturerobotics/starpc/internal/broadcast"
+)
+
+// CoDelAdmission is an adaptive AdmissionController based on CoDel.
+//
+// Admits up to limit concurrent calls and queues the others. While the queue
+// drains regularly, queued calls wait up to interval for a slot. If the queue
+// has not been empty for longer than interval the server is saturated: queued
+// calls wait at most target before failing with ErrOverloaded, which keeps the
+// queueing latency bounded. Calls with a priority above zero always wait up to
+// interval.
+type CoDelAdmission struct {
+	// target is the queue timeout when saturated
+	target time.Duration
+	// interval is the queue timeout when not saturated
+	interval time.Duration
+	// limit is the maximum number of concurrent calls
+	limit int
+
+	// bcast guards below fields and is broadcast when a slot is released
+	bcast broadcast.Broadcast
+	// active is the number of admitted calls
+	active int
+	// queued is the number of queued calls
+	queued int
+	// lastEmpty is the last time the queue was empty
+	lastEmpty time.Time
+}
+
+// NewCoDelAdmission constructs a new CoDelAdmission.
+//
+// limit is the maximum number of concurrent calls and must be at least one.
+// Typical values are 5ms for target and 100ms for interval.
+func NewCoDelAdmission(limit int, target, interval time.Duration) *CoDelAdmission {
+	if limit < 1 {
+		limit = 1
+	}
+	return &CoDelAdmission{
+		target:    target,
+		interval:  interval,
+		limit:     limit,
+		lastEmpty: time.Now(),
+	}
+}
+
+// Admit waits for a slot to handle the call.
+//
+// Returns ErrOverloaded if the call waited longer than the queue timeout.
+func (c *CoDelAdmission) Admit(ctx context.Context, req *AdmissionRequest) (func(), error) {
+	var admitted bool
+	var timeout time.Duration
+	c.bcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		now := time.Now()
+		if c.queued == 0 {
+			c.lastEmpty = now
+			if c.active < c.limit {
+				c.active++
+				admitted = true
+				return
+			}
+		}
+		timeout = c.interval
+		if req.Priority <= 0 && now.Sub(c.lastEmpty) > c.interval {
+			timeout = c.target
+		}
+		c.queued++
+	})
+	if admitted {
+		return c.release, nil
+	}
+
+	waitCtx, waitCtxCancel := context.WithTimeout(ctx, timeout)
+	defer waitCtxCancel()
+	err := c.bcast.Wait(waitCtx, func(broadcast func(), getWaitCh func() <-chan struct{}) (bool, error) {
+		if c.active >= c.limit {
+			return false, nil
+		}
+		c.active++
+		c.queued--
+		if c.queued == 0 {
+			c.lastEmpty = time.Now()
+		}
+		return true, nil
+	})
+	if err != nil {
+		c.bcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+			c.queued--
+			if c.queued == 0 {
+				c.lastEmpty = time.Now()
+			}
+		})
+		if ctx.Err() != nil {
+			return nil, context.Canceled
+		}
+		return nil, ErrOverloaded
+	}
+	return c.release, nil
+}
+
+// release releases a slot and wakes the queued calls.
+func (c *CoDelAdmission) release() {
+	c.bcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		c.active--
+		broadcast()
+	})
+}
+
+// _ is a type assertion
+var _ AdmissionController = ((*CoDelAdmission)(nil))
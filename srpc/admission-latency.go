@@ -0,0 +1,140 @@
+package srpc
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyShedStep is the fraction added to the shed fraction when the p99
+// latency of a window exceeds the target.
+const latencyShedStep = 0.1
+
+// latencyRecoverStep is the fraction removed from the shed fraction when the
+// p99 latency of a window is within the target.
+const latencyRecoverStep = 0.05
+
+// latencyShedMax is the maximum fraction of calls to shed.
+//
+// Some calls are always admitted to measure the latency.
+const latencyShedMax = 0.9
+
+// LatencySLOAdmission is an AdmissionController which sheds load when the
+// latency of a method exceeds the target.
+//
+// The latency of each method is measured over windows of calls. When the p99
+// latency of a window exceeds the target, an increasing fraction of the calls
+// to the method fail fast with ErrOverloaded. The fraction decreases gradually
+// while the p99 latency is within the target. Calls with a priority above zero
+// are never shed.
+type LatencySLOAdmission struct {
+	// target is the target p99 latency
+	target time.Duration
+	// windowSize is the number of calls in each window
+	windowSize int
+
+	// mtx guards below fields
+	mtx sync.Mutex
+	// methods contains the state for each method
+	methods map[latencyMethodKey]*latencyMethod
+}
+
+// latencyMethodKey is the key of a method in the methods map.
+type latencyMethodKey struct {
+	serviceID, methodID string
+}
+
+// latencyMethod is the latency state of a method.
+type latencyMethod struct {
+	// samples contains the latencies of the current window
+	samples []time.Duration
+	// shedFraction is the fraction of calls to shed
+	shedFraction float64
+	// shedCredit accumulates shedFraction for each call
+	shedCredit float64
+}
+
+// NewLatencySLOAdmission constructs a new LatencySLOAdmission.
+//
+// windowSize is the number of calls used to compute the p99 latency, if zero
+// defaults to 100.
+func NewLatencySLOAdmission(target time.Duration, windowSize int) *LatencySLOAdmission {
+	if windowSize <= 0 {
+		windowSize = 100
+	}
+	return &LatencySLOAdmission{
+		target:     target,
+		windowSize: windowSize,
+		methods:    make(map[latencyMethodKey]*latencyMethod),
+	}
+}
+
+// GetShedFraction returns the fraction of calls to the method which are shed.
+func (a *LatencySLOAdmission) GetShedFraction(serviceID, methodID string) float64 {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if m := a.methods[latencyMethodKey{serviceID, methodID}]; m != nil {
+		return m.shedFraction
+	}
+	return 0
+}
+
+// Admit sheds the call or measures the latency of the call.
+//
+// Returns ErrOverloaded if the call was shed.
+func (a *LatencySLOAdmission) Admit(ctx context.Context, req *AdmissionRequest) (func(), error) {
+	key := latencyMethodKey{req.ServiceID, req.MethodID}
+	a.mtx.Lock()
+	m := a.methods[key]
+	if m == nil {
+		m = &latencyMethod{samples: make([]time.Duration, 0, a.windowSize)}
+		a.methods[key] = m
+	}
+	if req.Priority <= 0 && m.shedFraction > 0 {
+		// shed exactly shedFraction of the calls
+		m.shedCredit += m.shedFraction
+		if m.shedCredit >= 1 {
+			m.shedCredit--
+			a.mtx.Unlock()
+			return nil, ErrOverloaded
+		}
+	}
+	a.mtx.Unlock()
+
+	start := time.Now()
+	return func() {
+		a.observe(m, time.Since(start))
+	}, nil
+}
+
+// observe records the latency of a call to the method.
+func (a *LatencySLOAdmission) observe(m *latencyMethod, latency time.Duration) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	m.samples = append(m.samples, latency)
+	if len(m.samples) < a.windowSize {
+		return
+	}
+
+	sort.Slice(m.samples, func(i, j int) bool {
+		return m.samples[i] < m.samples[j]
+	})
+	p99 := m.samples[(len(m.samples)*99-1)/100]
+	m.samples = m.samples[:0]
+	if p99 > a.target {
+		m.shedFraction += latencyShedStep
+		if m.shedFraction > latencyShedMax {
+			m.shedFraction = latencyShedMax
+		}
+	} else if m.shedFraction > 0 {
+		m.shedFraction -= latencyRecoverStep
+		if m.shedFraction < latencyRecoverStep/2 {
+			m.shedFraction = 0
+			m.shedCredit = 0
+		}
+	}
+}
+
+// _ is a type assertion
+var _ AdmissionController = ((*LatencySLOAdmission)(nil))
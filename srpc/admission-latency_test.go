@@ -0,0 +1,77 @@
+package srpc_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// delayEchoServer delays Echo by the configured duration.
+type delayEchoServer struct {
+	*echo.EchoServer
+	delay int64
+}
+
+func TestLatencySLOAdmission(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &delayEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		delay:      int64(time.Millisecond * 10),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx := context.Background()
+	msg := &echo.EchoMsg{Body: "hello world"}
+	shedder := srpc.NewLatencySLOAdmission(time.Millisecond*5, 5)
+	server := srpc.NewServer(mux, srpc.WithAdmissionController(srpc.ChainAdmission(shedder)))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+
+	// expect calls to be shed while the latency exceeds the target
+	var shed int
+	for i := 0; i < 30; i++ {
+		_, err := client.Echo(ctx, msg)
+		if err != nil {
+			if err.Error() != srpc.ErrOverloaded.Error() {
+				t.Fatal(err.Error())
+			}
+			shed++
+		}
+	}
+	fraction := shedder.GetShedFraction(echo.SRPCEchoerServiceID, "Echo")
+	if shed == 0 || fraction == 0 {
+		t.Fatalf("expected calls to be shed: shed %d fraction %v", shed, fraction)
+	}
+
+	// expect high priority calls to never be shed
+	prioCtx := srpc.AppendToOutgoingContext(ctx, srpc.PriorityMetadataKey, "1")
+	for i := 0; i < 10; i++ {
+		if _, err := client.Echo(prioCtx, msg); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	// expect the shed fraction to recover once the latency is within the target
+	atomic.StoreInt64(&echoServer.delay, 0)
+	for i := 0; i < 500 && shedder.GetShedFraction(echo.SRPCEchoerServiceID, "Echo") != 0; i++ {
+		_, _ = client.Echo(ctx, msg)
+	}
+	if fraction := shedder.GetShedFraction(echo.SRPCEchoerServiceID, "Echo"); fraction != 0 {
+		t.Fatalf("expected shed fraction to recover got %v", fraction)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := client.Echo(ctx, msg); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+}
+
+// Echo waits for the delay and echoes the message.
+func (s *delayEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	<-time.After(time.Duration(atomic.LoadInt64(&s.delay)))
+	return s.EchoServer.Echo(ctx, msg)
+}
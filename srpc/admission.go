@@ -0,0 +1,91 @@
+package srpc
+
+import (
+	"context"
+	"strconv"
+)
+
+// PriorityMetadataKey is the call metadata key containing the call priority.
+//
+// The priority is an integer: higher values are more important.
+const PriorityMetadataKey = "srpc-priority"
+
+// AdmissionRequest describes a call waiting to be admitted.
+type AdmissionRequest struct {
+	// ServiceID is the service of the call.
+	ServiceID string
+	// MethodID is the method of the call.
+	MethodID string
+	// ConnInfo describes the connection to the peer, if known.
+	ConnInfo *ConnInfo
+	// Metadata contains the call metadata.
+	Metadata Metadata
+	// Priority is the call priority from the PriorityMetadataKey metadata.
+	// Higher values are more important, zero if unset.
+	Priority int
+	// QueueDepth is the number of active calls including this call.
+	QueueDepth int
+}
+
+// AdmissionController decides if calls are handled when the server is busy.
+type AdmissionController interface {
+	// Admit is called before the handler is invoked.
+	//
+	// May block to delay the call. Returns an error to reject the call, for
+	// example ErrOverloaded. Otherwise done must be called when the call
+	// completes. Returns context.Canceled if ctx is canceled.
+	Admit(ctx context.Context, req *AdmissionRequest) (done func(), err error)
+}
+
+// WithAdmissionController admits calls with the AdmissionController.
+//
+// Calls rejected by the controller fail with the returned error.
+func WithAdmissionController(ctrl AdmissionController) ServerOption {
+	return func(s *Server) {
+		s.admission = ctrl
+	}
+}
+
+// newAdmissionRequest builds the AdmissionRequest for a call.
+func newAdmissionRequest(ctx context.Context, serviceID, methodID string, queueDepth int) *AdmissionRequest {
+	md := MetadataFromIncomingContext(ctx)
+	priority, _ := strconv.Atoi(md.Get(PriorityMetadataKey))
+	return &AdmissionRequest{
+		ServiceID:  serviceID,
+		MethodID:   methodID,
+		ConnInfo:   ConnInfoFromContext(ctx),
+		Metadata:   md,
+		Priority:   priority,
+		QueueDepth: queueDepth,
+	}
+}
+
+// chainAdmission admits calls with each controller in order.
+type chainAdmission []AdmissionController
+
+// ChainAdmission returns an AdmissionController which admits calls with each of
+// the controllers in order.
+//
+// The call is rejected if any of the controllers rejects it.
+func ChainAdmission(ctrls ...AdmissionController) AdmissionController {
+	return chainAdmission(ctrls)
+}
+
+// Admit admits the call with each of the controllers.
+func (c chainAdmission) Admit(ctx context.Context, req *AdmissionRequest) (func(), error) {
+	dones := make([]func(), 0, len(c))
+	done := func() {
+		for i := len(dones) - 1; i >= 0; i-- {
+			dones[i]()
+		}
+	}
+	for _, ctrl := range c {
+		ctrlDone, err := ctrl.Admit(ctx, req)
+		if err != nil {
+			done()
+			return nil, err
+		}
+		dones = append(dones, ctrlDone)
+	}
+	return done, nil
+}
@@ -0,0 +1,96 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// recordAdmission records the admission requests.
+type recordAdmission struct {
+	reqs chan *srpc.AdmissionRequest
+}
+
+func TestAdmissionController(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &blockingEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		started:    make(chan struct{}, 1),
+		release:    make(chan struct{}),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx := context.Background()
+	msg := &echo.EchoMsg{Body: "hello world"}
+	admission := srpc.NewCoDelAdmission(1, time.Millisecond*5, time.Millisecond*20)
+	server := srpc.NewServer(mux, srpc.WithAdmissionController(admission))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+
+	// occupy the only slot
+	callErrCh := make(chan error, 1)
+	go func() {
+		_, err := client.Echo(ctx, msg)
+		callErrCh <- err
+	}()
+	<-echoServer.started
+
+	// expect the queued call to be rejected after the queue timeout
+	if _, err := client.Echo(ctx, msg); err == nil || err.Error() != srpc.ErrOverloaded.Error() {
+		t.Fatalf("expected %v got %v", srpc.ErrOverloaded, err)
+	}
+
+	// expect the queued call to be admitted once the slot is released
+	go func() {
+		_, err := client.Echo(ctx, msg)
+		callErrCh <- err
+	}()
+	<-time.After(time.Millisecond * 5)
+	echoServer.release <- struct{}{}
+	if err := <-callErrCh; err != nil {
+		t.Fatal(err.Error())
+	}
+	<-echoServer.started
+	echoServer.release <- struct{}{}
+	if err := <-callErrCh; err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// expect a custom controller to receive the call priority
+	recorder := &recordAdmission{reqs: make(chan *srpc.AdmissionRequest, 2)}
+	server = srpc.NewServer(mux, srpc.WithAdmissionController(recorder))
+	client = echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	if _, err := client.Echo(ctx, msg); err == nil || err.Error() != srpc.ErrOverloaded.Error() {
+		t.Fatalf("expected %v got %v", srpc.ErrOverloaded, err)
+	}
+	req := <-recorder.reqs
+	if req.ServiceID != echo.SRPCEchoerServiceID || req.MethodID != "Echo" || req.Priority != 0 || req.QueueDepth != 1 {
+		t.Fatalf("unexpected admission request: %#v", req)
+	}
+
+	prioCtx := srpc.AppendToOutgoingContext(ctx, srpc.PriorityMetadataKey, "10")
+	go func() {
+		_, err := client.Echo(prioCtx, msg)
+		callErrCh <- err
+	}()
+	<-echoServer.started
+	echoServer.release <- struct{}{}
+	if err := <-callErrCh; err != nil {
+		t.Fatal(err.Error())
+	}
+	if req := <-recorder.reqs; req.Priority != 10 {
+		t.Fatalf("expected priority 10 got %d", req.Priority)
+	}
+}
+
+// Admit records the request and rejects low priority calls.
+func (r *recordAdmission) Admit(ctx context.Context, req *srpc.AdmissionRequest) (func(), error) {
+	r.reqs <- req
+	if req.Priority <= 0 {
+		return nil, srpc.ErrOverloaded
+	}
+	return func() {}, nil
+}
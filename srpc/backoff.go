@@ -0,0 +1,130 @@
+package srpc
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff computes the delay before retrying after a failed attempt.
+//
+// Must be concurrency safe.
+type Backoff interface {
+	// Backoff returns the delay after the failed attempt, starting at 1.
+	Backoff(attempt int) time.Duration
+}
+
+// BackoffFunc is a func implementing Backoff.
+type BackoffFunc func(attempt int) time.Duration
+
+// Backoff returns the delay after the failed attempt, starting at 1.
+func (f BackoffFunc) Backoff(attempt int) time.Duration {
+	return f(attempt)
+}
+
+// ExponentialBackoff is a Backoff growing the delay by a factor after each
+// attempt up to a max, with optional jitter.
+//
+// Zero fields use the defaults of RetryPolicy.
+type ExponentialBackoff struct {
+	// InitialBackoff is the delay after the first attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff is the max delay.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows by after each attempt.
+	Multiplier float64
+	// Jitter is the fraction of the delay to randomly subtract, from 0 to 1.
+	Jitter float64
+	// Rand returns a random number in [0, 1) for the jitter.
+	//
+	// Defaults to math/rand. Set for reproducible delays in simulations.
+	Rand func() float64
+}
+
+// Backoff returns the delay after the failed attempt, starting at 1.
+func (b *ExponentialBackoff) Backoff(attempt int) time.Duration {
+	initial, maxBackoff, mult := b.InitialBackoff, b.MaxBackoff, b.Multiplier
+	if initial <= 0 {
+		initial = DefaultRetryInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+	if mult < 1 {
+		mult = DefaultRetryMultiplier
+	}
+	delay := math.Min(float64(initial)*math.Pow(mult, float64(attempt-1)), float64(maxBackoff))
+	if jitter := math.Min(b.Jitter, 1); jitter > 0 {
+		randFn := b.Rand
+		if randFn == nil {
+			randFn = rand.Float64
+		}
+		delay -= delay * jitter * randFn()
+	}
+	return time.Duration(delay)
+}
+
+// Clock is the time source of the backoff timers.
+//
+// Substitute to control the timing of retries in simulations and tests.
+type Clock interface {
+	// NewTimer starts a timer which fires after the duration.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer started by a Clock.
+type Timer interface {
+	// C returns the channel which receives the time when the timer fires.
+	C() <-chan time.Time
+	// Stop stops the timer. Returns false if the timer already fired.
+	Stop() bool
+}
+
+// SystemClock is the Clock using the time package.
+type SystemClock struct{}
+
+// NewTimer starts a timer which fires after the duration.
+func (SystemClock) NewTimer(d time.Duration) Timer {
+	return &systemTimer{timer: time.NewTimer(d)}
+}
+
+// systemTimer is a Timer using the time package.
+type systemTimer struct {
+	timer *time.Timer
+}
+
+// C returns the channel which receives the time when the timer fires.
+func (t *systemTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+// Stop stops the timer. Returns false if the timer already fired.
+func (t *systemTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// waitBackoff waits for the delay with the clock.
+//
+// If clock is nil, uses SystemClock. Returns false if ctx is done first.
+func waitBackoff(ctx context.Context, clock Clock, d time.Duration) bool {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	timer := clock.NewTimer(d)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return false
+	case <-timer.C():
+		return true
+	}
+}
+
+// _ is a type assertion
+var (
+	_ Backoff = ((*ExponentialBackoff)(nil))
+	_ Backoff = BackoffFunc(nil)
+	_ Clock   = SystemClock{}
+	_ Timer   = ((*systemTimer)(nil))
+)
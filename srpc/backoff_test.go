@@ -0,0 +1,80 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// manualClock is a Clock recording the timers and firing them immediately.
+type manualClock struct {
+	delays []time.Duration
+}
+
+func (c *manualClock) NewTimer(d time.Duration) srpc.Timer {
+	c.delays = append(c.delays, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return &manualTimer{ch: ch}
+}
+
+// manualTimer is a Timer which has already fired.
+type manualTimer struct {
+	ch chan time.Time
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *manualTimer) Stop() bool {
+	return false
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := &srpc.ExponentialBackoff{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Second * 5,
+		Multiplier:     2,
+		Jitter:         0.5,
+		Rand:           func() float64 { return 0.5 },
+	}
+	for attempt, expected := range []time.Duration{
+		time.Millisecond * 750,
+		time.Millisecond * 1500,
+		time.Second * 3,
+		time.Millisecond * 3750,
+	} {
+		if delay := b.Backoff(attempt + 1); delay != expected {
+			t.Fatalf("attempt %d: expected %v got %v", attempt+1, expected, delay)
+		}
+	}
+}
+
+func TestRetryingClient_Clock(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	clock := &manualClock{}
+	policy := &srpc.RetryPolicy{
+		MaxAttempts: 4,
+		Backoff: srpc.BackoffFunc(func(attempt int) time.Duration {
+			return time.Hour * time.Duration(attempt)
+		}),
+		Clock: clock,
+	}
+	flaky := &flakyClient{Client: srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))), err: srpc.ErrUnavailable, failures: 3}
+	client := echo.NewSRPCEchoerClient(srpc.NewRetryingClient(flaky, policy))
+
+	// expect the retries to use the clock instead of waiting hours
+	if resp, err := client.Echo(context.Background(), &echo.EchoMsg{Body: "hello"}); err != nil || resp.GetBody() != "hello" {
+		t.Fatalf("expected response got %v: %v", resp, err)
+	}
+	if len(clock.delays) != 3 || clock.delays[0] != time.Hour || clock.delays[2] != time.Hour*3 {
+		t.Fatalf("unexpected delays %v", clock.delays)
+	}
+}
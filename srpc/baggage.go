@@ -0,0 +1,125 @@
+package srpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// BaggageMetadataPrefix is the prefix for baggage keys in the call metadata.
+const BaggageMetadataPrefix = "baggage-"
+
+// BaggageCodec encodes and decodes a context value propagated with calls.
+//
+// Registered codecs are used to serialize context values into the call
+// metadata and to restore them into the context of the server handler. The
+// restored context is used for any nested calls, so the values flow through
+// nested rpcstream hops.
+type BaggageCodec interface {
+	// EncodeBaggage encodes the value from the context.
+	// Returns false if the value is not set.
+	EncodeBaggage(ctx context.Context) (string, bool, error)
+	// DecodeBaggage decodes the value and returns a context with the value.
+	DecodeBaggage(ctx context.Context, value string) (context.Context, error)
+}
+
+// baggageCodecs contains the registered baggage codecs.
+var (
+	baggageCodecsMtx sync.RWMutex
+	baggageCodecs    = make(map[string]BaggageCodec)
+)
+
+// RegisterBaggage registers a codec for a context value with a baggage key.
+//
+// Overwrites any existing codec with the same key.
+func RegisterBaggage(key string, codec BaggageCodec) {
+	baggageCodecsMtx.Lock()
+	if codec == nil {
+		delete(baggageCodecs, key)
+	} else {
+		baggageCodecs[key] = codec
+	}
+	baggageCodecsMtx.Unlock()
+}
+
+// UnregisterBaggage removes the codec registered with the baggage key.
+func UnregisterBaggage(key string) {
+	RegisterBaggage(key, nil)
+}
+
+// EncodeBaggage encodes all registered baggage values in the context to metadata.
+//
+// Returns nil if no values were set.
+func EncodeBaggage(ctx context.Context) (map[string]string, error) {
+	baggageCodecsMtx.RLock()
+	defer baggageCodecsMtx.RUnlock()
+
+	var md map[string]string
+	for key, codec := range baggageCodecs {
+		value, ok, err := codec.EncodeBaggage(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "encode baggage %s", key)
+		}
+		if !ok {
+			continue
+		}
+		if md == nil {
+			md = make(map[string]string)
+		}
+		md[BaggageMetadataPrefix+key] = value
+	}
+	return md, nil
+}
+
+// DecodeBaggage restores all registered baggage values in the metadata to the context.
+//
+// Ignores any baggage keys without a registered codec.
+func DecodeBaggage(ctx context.Context, md map[string]string) (context.Context, error) {
+	if len(md) == 0 {
+		return ctx, nil
+	}
+
+	baggageCodecsMtx.RLock()
+	defer baggageCodecsMtx.RUnlock()
+
+	for key, codec := range baggageCodecs {
+		value, ok := md[BaggageMetadataPrefix+key]
+		if !ok {
+			continue
+		}
+		var err error
+		ctx, err = codec.DecodeBaggage(ctx, value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decode baggage %s", key)
+		}
+	}
+	return ctx, nil
+}
+
+// stringBaggage implements BaggageCodec for a string context value.
+type stringBaggage struct {
+	// ctxKey is the context key
+	ctxKey interface{}
+}
+
+// NewStringBaggage constructs a BaggageCodec for a string context value.
+//
+// ctxKey is the key passed to context.WithValue.
+func NewStringBaggage(ctxKey interface{}) BaggageCodec {
+	return &stringBaggage{ctxKey: ctxKey}
+}
+
+// EncodeBaggage encodes the value from the context.
+func (b *stringBaggage) EncodeBaggage(ctx context.Context) (string, bool, error) {
+	value, ok := ctx.Value(b.ctxKey).(string)
+	return value, ok, nil
+}
+
+// DecodeBaggage decodes the value and returns a context with the value.
+func (b *stringBaggage) DecodeBaggage(ctx context.Context, value string) (context.Context, error) {
+	return context.WithValue(ctx, b.ctxKey, value), nil
+}
+
+// _ is a type assertion
+var _ BaggageCodec = ((*stringBaggage)(nil))
@@ -0,0 +1,58 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/rpcstream"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// baggageTestKey is the context key used in the baggage test.
+type baggageTestKey struct{}
+
+// baggageEchoServer echoes the baggage value in the context.
+type baggageEchoServer struct {
+	*echo.EchoServer
+}
+
+func TestBaggage(t *testing.T) {
+	srpc.RegisterBaggage("test-tenant", srpc.NewStringBaggage(baggageTestKey{}))
+	defer srpc.UnregisterBaggage("test-tenant")
+
+	mux := srpc.NewMux()
+	echoServer := &baggageEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	ctx := context.WithValue(context.Background(), baggageTestKey{}, "tenant-1")
+	resp, err := client.Echo(ctx, &echo.EchoMsg{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != "tenant-1" {
+		t.Fatalf("expected baggage %q got %q", "tenant-1", resp.GetBody())
+	}
+
+	// expect the baggage to flow through a nested rpcstream hop
+	openStreamFn := rpcstream.NewRpcStreamOpenStream(func(ctx context.Context) (rpcstream.RpcStream, error) {
+		return client.RpcStream(ctx)
+	}, "test")
+	proxiedSvc := echo.NewSRPCEchoerClient(srpc.NewClient(openStreamFn))
+	resp, err = proxiedSvc.Echo(ctx, &echo.EchoMsg{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != "tenant-1" {
+		t.Fatalf("expected nested baggage %q got %q", "tenant-1", resp.GetBody())
+	}
+}
+
+// Echo returns the baggage value in the message body.
+func (s *baggageEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	value, _ := ctx.Value(baggageTestKey{}).(string)
+	return &echo.EchoMsg{Body: value}, nil
+}
@@ -0,0 +1,53 @@
+package srpc
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// ProtocolVersion is the version of the starpc protocol implemented.
+const ProtocolVersion = 1
+
+// BuildInfo contains build and version information for a program.
+type BuildInfo struct {
+	// Name is the name of the program or module.
+	Name string
+	// Version is the semantic version of the program.
+	Version string
+}
+
+// buildInfo is the build info set by SetBuildInfo.
+var (
+	buildInfoMtx sync.RWMutex
+	buildInfo    *BuildInfo
+)
+
+// SetBuildInfo sets the build info advertised to remotes.
+//
+// If info is nil, the build info is read from the Go module build info.
+func SetBuildInfo(info *BuildInfo) {
+	buildInfoMtx.Lock()
+	buildInfo = info
+	buildInfoMtx.Unlock()
+}
+
+// GetBuildInfo returns the build info advertised to remotes.
+//
+// Defaults to the main module path and version from the Go build info.
+func GetBuildInfo() BuildInfo {
+	buildInfoMtx.RLock()
+	info := buildInfo
+	buildInfoMtx.RUnlock()
+	if info != nil {
+		return *info
+	}
+
+	var out BuildInfo
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		out.Name, out.Version = bi.Main.Path, bi.Main.Version
+		if out.Version == "(devel)" {
+			out.Version = ""
+		}
+	}
+	return out
+}
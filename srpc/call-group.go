@@ -0,0 +1,141 @@
+package srpc
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// callGroup coalesces concurrent unary calls with the same key.
+type callGroup struct {
+	// mtx guards pending
+	mtx sync.Mutex
+	// pending contains the in-flight calls by key.
+	pending map[string]*callGroupCall
+}
+
+// callGroupCall is an in-flight call.
+type callGroupCall struct {
+	// doneCh is closed when the call is done.
+	doneCh chan struct{}
+	// data is the response payload.
+	// immutable after doneCh is closed.
+	data []byte
+	// err is the call error.
+	// immutable after doneCh is closed.
+	err error
+}
+
+// invoke calls the unary handler or waits for the in-flight call with the key.
+//
+// Sends the response to strm. lookup is called with mtx locked before starting
+// a call and returns a stored response, if any. onResult is called with the
+// response before releasing the waiting calls. Both may be nil. If the
+// in-flight call fails, each waiting call invokes the handler itself.
+func (g *callGroup) invoke(
+	handler Handler,
+	serviceID, methodID, key string,
+	req []byte,
+	strm Stream,
+	lookup func() ([]byte, bool),
+	onResult func(data []byte),
+) error {
+	g.mtx.Lock()
+	if lookup != nil {
+		// check under mtx: the result is stored before the pending call is removed.
+		if data, ok := lookup(); ok {
+			g.mtx.Unlock()
+			return strm.MsgSend(NewRawMessage(data))
+		}
+	}
+	if call := g.pending[key]; call != nil {
+		g.mtx.Unlock()
+		select {
+		case <-strm.Context().Done():
+			return context.Canceled
+		case <-call.doneCh:
+		}
+		if call.err != nil {
+			data, err := callUnaryHandler(handler, serviceID, methodID, req, strm)
+			if err != nil {
+				return err
+			}
+			return strm.MsgSend(NewRawMessage(data))
+		}
+		return strm.MsgSend(NewRawMessage(call.data))
+	}
+	if g.pending == nil {
+		g.pending = make(map[string]*callGroupCall)
+	}
+	call := &callGroupCall{doneCh: make(chan struct{})}
+	g.pending[key] = call
+	g.mtx.Unlock()
+
+	call.data, call.err = callUnaryHandler(handler, serviceID, methodID, req, strm)
+	if call.err == nil && onResult != nil {
+		onResult(call.data)
+	}
+
+	g.mtx.Lock()
+	delete(g.pending, key)
+	g.mtx.Unlock()
+	close(call.doneCh)
+
+	if call.err != nil {
+		return call.err
+	}
+	return strm.MsgSend(NewRawMessage(call.data))
+}
+
+// callUnaryHandler invokes a unary handler and returns the response payload.
+func callUnaryHandler(handler Handler, serviceID, methodID string, req []byte, strm Stream) ([]byte, error) {
+	ustrm := &unaryCallStream{Stream: strm, req: req, codec: callCodecFromContext(strm.Context())}
+	_, err := handler.InvokeMethod(serviceID, methodID, ustrm)
+	if err == nil && !ustrm.sent {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ustrm.resp, nil
+}
+
+// unaryCallStream passes the request to the handler and records the response.
+type unaryCallStream struct {
+	Stream
+	// req is the request payload.
+	req []byte
+	// codec encodes the messages, if set.
+	codec Codec
+	// recvd is set after the request was received.
+	recvd bool
+	// resp is the response payload.
+	resp []byte
+	// sent is set after the response was sent.
+	sent bool
+}
+
+// MsgRecv receives the request.
+func (s *unaryCallStream) MsgRecv(msg Message) error {
+	if s.recvd {
+		return io.EOF
+	}
+	s.recvd = true
+	return unmarshalWithCodec(s.codec, msg, s.req)
+}
+
+// MsgSend records the response.
+func (s *unaryCallStream) MsgSend(msg Message) error {
+	if s.sent {
+		return ErrCompleted
+	}
+	data, err := marshalWithCodec(s.codec, msg)
+	if err != nil {
+		return err
+	}
+	s.resp, s.sent = data, true
+	return nil
+}
+
+// _ is a type assertion
+var _ Stream = ((*unaryCallStream)(nil))
@@ -0,0 +1,189 @@
+package srpc
+
+import (
+	"context"
+	"time"
+)
+
+// CallOption configures a call made with a Client.
+type CallOption func(o *callOptions)
+
+// callOptions contains the options for a call.
+type callOptions struct {
+	// rawResponse receives the raw response bytes of a unary call.
+	rawResponse *[]byte
+	// progress is called with keepalive progress sent by the server.
+	progress func(progress Metadata)
+	// errorDetails is called with error details sent by the server.
+	errorDetails func(details []string)
+	// responseMetadata is called with the response metadata sent by the server.
+	responseMetadata func(md Metadata)
+	// compression is the compression algorithm for the call.
+	compression string
+	// heartbeat configures the heartbeat of the call.
+	heartbeat HeartbeatParams
+	// signalHandler is called with signals sent by the server.
+	signalHandler func(sig *Signal)
+	// waitForReady retries opening the stream until the transport is ready.
+	waitForReady bool
+	// timeout is the timeout for the call.
+	timeout time.Duration
+	// metadata is added to the metadata sent with the call.
+	metadata Metadata
+	// maxRecvSize is the max size of a received message.
+	maxRecvSize int
+	// codec is the name of the codec for the call.
+	codec string
+}
+
+// callOptionsCtxKey is the context key for the call options.
+type callOptionsCtxKey struct{}
+
+// WithCallOptions attaches call options to the context.
+//
+// The options apply to all calls made with the returned context, but not to
+// the nested calls made with the context of a call or of its server handler.
+// Appends to any options already attached to the context.
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	existing, _ := ctx.Value(callOptionsCtxKey{}).([]CallOption)
+	merged := make([]CallOption, 0, len(existing)+len(opts))
+	merged = append(merged, existing...)
+	merged = append(merged, opts...)
+	return context.WithValue(ctx, callOptionsCtxKey{}, merged)
+}
+
+// withoutCallOptions removes the call options attached to the context.
+//
+// Used where a call starts: the options of a call do not apply to the calls
+// made with the context of the call or of its server handler.
+func withoutCallOptions(ctx context.Context) context.Context {
+	if opts, _ := ctx.Value(callOptionsCtxKey{}).([]CallOption); len(opts) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, callOptionsCtxKey{}, []CallOption(nil))
+}
+
+// getCallOptions builds the call options attached to the context.
+func getCallOptions(ctx context.Context) *callOptions {
+	o := &callOptions{}
+	opts, _ := ctx.Value(callOptionsCtxKey{}).([]CallOption)
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// WithRawResponse stores the raw response payload of a unary call in buf.
+//
+// The response is also decoded into the out message unless out is nil, which
+// avoids a marshal and unmarshal round trip when relaying the response.
+func WithRawResponse(buf *[]byte) CallOption {
+	return func(o *callOptions) {
+		o.rawResponse = buf
+	}
+}
+
+// WithProgress calls cb with keepalive progress metadata sent by the server.
+//
+// cb is called from the packet read loop and must not block.
+// See SendProgress and KeepAlive.
+func WithProgress(cb func(progress Metadata)) CallOption {
+	return func(o *callOptions) {
+		o.progress = cb
+	}
+}
+
+// WithErrorDetails calls cb with diagnostic error details sent by the server.
+//
+// cb is called from the packet read loop and must not block. The details are
+// also attached to the error returned if the call fails: see GetErrorDetails.
+// See SendErrorDetails.
+func WithErrorDetails(cb func(details []string)) CallOption {
+	return func(o *callOptions) {
+		o.errorDetails = cb
+	}
+}
+
+// WithResponseMetadata calls cb with the response metadata sent by the server.
+//
+// cb is called from the packet read loop when the call completes and must
+// not block. Not called if the server did not set any metadata.
+// See SetResponseMetadata.
+func WithResponseMetadata(cb func(md Metadata)) CallOption {
+	return func(o *callOptions) {
+		o.responseMetadata = cb
+	}
+}
+
+// WithClientHeartbeat pings the server while the call is idle.
+//
+// The call fails with ErrHeartbeatTimeout if the server does not respond in
+// time. Servers which do not support Ping packets are not pinged. Pass zero
+// params to disable. See HeartbeatParams.
+func WithClientHeartbeat(params HeartbeatParams) CallOption {
+	return func(o *callOptions) {
+		o.heartbeat = params
+	}
+}
+
+// WithTimeout cancels the call if it does not complete within the duration.
+//
+// Applies in addition to the deadline of the call ctx. Pass zero to disable.
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithMetadata adds the metadata to the metadata sent with the call.
+//
+// Overwrites the values with the same keys in the outgoing context metadata.
+// See AppendToOutgoingContext.
+func WithMetadata(md Metadata) CallOption {
+	return func(o *callOptions) {
+		if o.metadata == nil {
+			o.metadata = make(Metadata, len(md))
+		}
+		for k, v := range md {
+			o.metadata[k] = v
+		}
+	}
+}
+
+// WithMaxRecvSize fails the call with ErrRecvMsgTooLarge if a received message
+// is larger than size bytes.
+//
+// The size is checked after decompression. Pass zero to disable.
+func WithMaxRecvSize(size int) CallOption {
+	return func(o *callOptions) {
+		o.maxRecvSize = size
+	}
+}
+
+// WithWaitForReady blocks the call until the transport is ready.
+//
+// If opening the stream fails, for example while the connection is down, the
+// call retries with a backoff until it succeeds or ctx is canceled instead of
+// failing immediately. Returns the last error if ctx is canceled. Calls
+// already started are not retried.
+func WithWaitForReady(waitForReady bool) CallOption {
+	return func(o *callOptions) {
+		o.waitForReady = waitForReady
+	}
+}
+
+// WithSignalHandler calls cb with out-of-band signals sent by the server.
+//
+// cb is called from the packet read loop and must not block. Unlike
+// SetSignalHandler, receives the signals sent before the stream is returned.
+// See SendSignal.
+func WithSignalHandler(cb func(sig *Signal)) CallOption {
+	return func(o *callOptions) {
+		o.signalHandler = cb
+	}
+}
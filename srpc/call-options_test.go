@@ -0,0 +1,131 @@
+package srpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestCallOptions_Metadata(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &metadataEchoServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	// expect the option to override the context metadata
+	ctx := srpc.AppendToOutgoingContext(context.Background(), "x-user", "user-1", "x-request-id", "req-1")
+	opt := srpc.WithMetadata(srpc.Metadata{"x-user": "user-2"})
+	for key, expected := range map[string]string{"x-user": "user-2", "x-request-id": "req-1"} {
+		resp, err := client.Echo(ctx, &echo.EchoMsg{Body: key}, opt)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resp.GetBody() != expected {
+			t.Fatalf("expected metadata %s=%q got %q", key, expected, resp.GetBody())
+		}
+	}
+}
+
+func TestCallOptions_Timeout(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &blockingEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		started:    make(chan struct{}, 1),
+		release:    make(chan struct{}),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	start := time.Now()
+	_, err := client.Echo(context.Background(), &echo.EchoMsg{Body: "hello"}, srpc.WithTimeout(time.Millisecond*50))
+	if err != context.Canceled {
+		t.Fatalf("expected context canceled but got %v", err)
+	}
+	if dur := time.Since(start); dur > time.Second*5 {
+		t.Fatalf("expected the call to time out but took %v", dur)
+	}
+}
+
+func TestCallOptions_MaxRecvSize(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	ctx := context.Background()
+	body := strings.Repeat("a", 256)
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: body}, srpc.WithMaxRecvSize(1024)); err != nil {
+		t.Fatal(err.Error())
+	}
+	_, err := client.Echo(ctx, &echo.EchoMsg{Body: body}, srpc.WithMaxRecvSize(64))
+	if err != srpc.ErrRecvMsgTooLarge {
+		t.Fatalf("expected %v but got %v", srpc.ErrRecvMsgTooLarge, err)
+	}
+
+	strm, err := client.EchoServerStream(ctx, &echo.EchoMsg{Body: body}, srpc.WithMaxRecvSize(64))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer strm.Close()
+	if _, err := strm.Recv(); err != srpc.ErrRecvMsgTooLarge {
+		t.Fatalf("expected %v but got %v", srpc.ErrRecvMsgTooLarge, err)
+	}
+}
+
+// nestedEchoServer makes a nested call with the handler context.
+type nestedEchoServer struct {
+	*metadataEchoServer
+	// client is the client for the nested call
+	client echo.SRPCEchoerClient
+}
+
+// Echo returns the result of a nested call if the body is "nested".
+func (s *nestedEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	if msg.GetBody() == "nested" {
+		return s.client.Echo(ctx, &echo.EchoMsg{Body: "x-user"})
+	}
+	return s.metadataEchoServer.Echo(ctx, msg)
+}
+
+func TestCallOptions_Nested(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &nestedEchoServer{metadataEchoServer: &metadataEchoServer{EchoServer: echo.NewEchoServer(mux)}}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	echoServer.client = client
+
+	// expect the options to not apply to the calls of the handler
+	ctx := context.Background()
+	opt := srpc.WithMetadata(srpc.Metadata{"x-user": "user-1"})
+	resp, err := client.Echo(ctx, &echo.EchoMsg{Body: "nested"}, opt)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != "" {
+		t.Fatalf("expected the handler call to not use the options got %q", resp.GetBody())
+	}
+
+	// expect the options to not apply to the calls made with the stream context
+	strm, err := client.EchoServerStream(ctx, &echo.EchoMsg{Body: "hello"}, opt)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer strm.Close()
+	resp, err = client.Echo(strm.Context(), &echo.EchoMsg{Body: "x-user"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != "" {
+		t.Fatalf("expected the stream context to not use the options got %q", resp.GetBody())
+	}
+}
@@ -0,0 +1,300 @@
+package srpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Channelz is a registry of the live connections and streams of a server.
+//
+// Pass it to the server with WithChannelz. Query it with GetConns and
+// GetStreams, or serve it as JSON on an operator-only port.
+type Channelz struct {
+	// mtx guards below fields
+	mtx sync.Mutex
+	// nextID is the id of the next conn or stream
+	nextID uint64
+	// conns contains the live connections by id
+	conns map[uint64]*channelzConn
+	// streams contains the live streams by id
+	streams map[uint64]*channelzStream
+	// protocolErrors is the number of protocol errors
+	protocolErrors uint64
+	// closeReasons counts the closed connections by reason
+	closeReasons map[CloseReason]uint64
+}
+
+// ChannelzConn is a snapshot of a live connection.
+type ChannelzConn struct {
+	// ID is the unique id of the connection.
+	ID uint64
+	// Transport is the transport type, if known.
+	Transport string
+	// LocalAddr is the local address, if known.
+	LocalAddr string
+	// RemoteAddr is the remote address, if known.
+	RemoteAddr string
+	// Start is when the connection was accepted.
+	Start time.Time
+	// ActiveStreams is the number of live streams.
+	ActiveStreams int
+	// TotalStreams is the number of streams handled.
+	TotalStreams uint64
+	// ProtocolErrors is the number of streams failed by a protocol error.
+	// See ProtocolError.
+	ProtocolErrors uint64
+}
+
+// ChannelzStream is a snapshot of a live stream.
+type ChannelzStream struct {
+	// ID is the unique id of the stream.
+	ID uint64
+	// ConnID is the id of the connection, zero if unknown.
+	ConnID uint64
+	// RemoteAddr is the remote address, if known.
+	RemoteAddr string
+	// ServiceID is the service of the call, empty until the call starts.
+	ServiceID string
+	// MethodID is the method of the call, empty until the call starts.
+	MethodID string
+	// Start is when the stream was accepted.
+	Start time.Time
+	// MsgsSent is the number of messages sent to the remote.
+	MsgsSent uint64
+	// MsgsRecv is the number of messages received from the remote.
+	MsgsRecv uint64
+}
+
+// channelzConn is a live connection.
+type channelzConn struct {
+	// snap contains the immutable fields and the stream counts.
+	// guarded by Channelz.mtx
+	snap ChannelzConn
+}
+
+// channelzStream is a live stream.
+type channelzStream struct {
+	// sent is the number of messages sent
+	sent uint64
+	// recv is the number of messages received
+	recv uint64
+	// c is the registry
+	c *Channelz
+	// conn is the connection, nil if unknown
+	conn *channelzConn
+	// snap contains the immutable fields and the call.
+	// guarded by Channelz.mtx
+	snap ChannelzStream
+}
+
+// channelzConnCtxKey is the context key for the channelzConn.
+type channelzConnCtxKey struct{}
+
+// NewChannelz constructs a new Channelz.
+func NewChannelz() *Channelz {
+	return &Channelz{
+		conns:        make(map[uint64]*channelzConn),
+		streams:      make(map[uint64]*channelzStream),
+		closeReasons: make(map[CloseReason]uint64),
+	}
+}
+
+// WithChannelz registers the connections and streams with the Channelz.
+func WithChannelz(c *Channelz) ServerOption {
+	return func(s *Server) {
+		s.channelz = c
+	}
+}
+
+// GetConns returns the live connections sorted by id.
+func (c *Channelz) GetConns() []*ChannelzConn {
+	c.mtx.Lock()
+	conns := make([]*ChannelzConn, 0, len(c.conns))
+	for _, conn := range c.conns {
+		snap := conn.snap
+		conns = append(conns, &snap)
+	}
+	c.mtx.Unlock()
+	sort.Slice(conns, func(i, j int) bool {
+		return conns[i].ID < conns[j].ID
+	})
+	return conns
+}
+
+// GetStreams returns the live streams sorted by id.
+func (c *Channelz) GetStreams() []*ChannelzStream {
+	c.mtx.Lock()
+	strms := make([]*ChannelzStream, 0, len(c.streams))
+	for _, strm := range c.streams {
+		snap := strm.snap
+		snap.MsgsSent = atomic.LoadUint64(&strm.sent)
+		snap.MsgsRecv = atomic.LoadUint64(&strm.recv)
+		strms = append(strms, &snap)
+	}
+	c.mtx.Unlock()
+	sort.Slice(strms, func(i, j int) bool {
+		return strms[i].ID < strms[j].ID
+	})
+	return strms
+}
+
+// GetProtocolErrors returns the number of streams failed by a protocol error,
+// including the streams of closed connections.
+//
+// See ProtocolError.
+func (c *Channelz) GetProtocolErrors() uint64 {
+	return atomic.LoadUint64(&c.protocolErrors)
+}
+
+// GetCloseReasons returns the number of closed connections by CloseReason.
+func (c *Channelz) GetCloseReasons() map[CloseReason]uint64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	reasons := make(map[CloseReason]uint64, len(c.closeReasons))
+	for reason, count := range c.closeReasons {
+		reasons[reason] = count
+	}
+	return reasons
+}
+
+// ServeHTTP writes the live connections and streams as JSON.
+func (c *Channelz) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(&struct {
+		Conns          []*ChannelzConn
+		Streams        []*ChannelzStream
+		ProtocolErrors uint64
+		CloseReasons   map[CloseReason]uint64
+	}{c.GetConns(), c.GetStreams(), c.GetProtocolErrors(), c.GetCloseReasons()})
+}
+
+// addConn registers a connection and returns the context for its streams.
+//
+// The returned func unregisters the connection closed with the reason.
+func (c *Channelz) addConn(ctx context.Context) (context.Context, func(reason CloseReason)) {
+	conn := &channelzConn{snap: ChannelzConn{Start: time.Now()}}
+	if info := ConnInfoFromContext(ctx); info != nil {
+		conn.snap.Transport = info.Transport
+		conn.snap.LocalAddr = info.LocalAddr
+		conn.snap.RemoteAddr = info.RemoteAddr
+	}
+	c.mtx.Lock()
+	c.nextID++
+	conn.snap.ID = c.nextID
+	c.conns[conn.snap.ID] = conn
+	c.mtx.Unlock()
+	return context.WithValue(ctx, channelzConnCtxKey{}, conn), func(reason CloseReason) {
+		c.mtx.Lock()
+		delete(c.conns, conn.snap.ID)
+		c.closeReasons[reason]++
+		c.mtx.Unlock()
+	}
+}
+
+// addStream registers a stream of the connection of ctx, if any.
+func (c *Channelz) addStream(ctx context.Context) *channelzStream {
+	strm := &channelzStream{c: c, snap: ChannelzStream{Start: time.Now()}}
+	if info := ConnInfoFromContext(ctx); info != nil {
+		strm.snap.RemoteAddr = info.RemoteAddr
+	}
+	conn, _ := ctx.Value(channelzConnCtxKey{}).(*channelzConn)
+	c.mtx.Lock()
+	c.nextID++
+	strm.snap.ID = c.nextID
+	if conn != nil {
+		strm.conn = conn
+		strm.snap.ConnID = conn.snap.ID
+		conn.snap.ActiveStreams++
+		conn.snap.TotalStreams++
+	}
+	c.streams[strm.snap.ID] = strm
+	c.mtx.Unlock()
+	return strm
+}
+
+// removeStream unregisters the stream.
+func (c *Channelz) removeStream(strm *channelzStream) {
+	c.mtx.Lock()
+	delete(c.streams, strm.snap.ID)
+	if conn, ok := c.conns[strm.snap.ConnID]; ok {
+		conn.snap.ActiveStreams--
+	}
+	c.mtx.Unlock()
+}
+
+// handlePacket wraps a packet handler to track the received messages and
+// the protocol errors.
+func (s *channelzStream) handlePacket(handler PacketHandler) PacketHandler {
+	return func(pkt *Packet) error {
+		switch b := pkt.GetBody().(type) {
+		case *Packet_CallStart:
+			s.c.mtx.Lock()
+			s.snap.ServiceID, s.snap.MethodID = b.CallStart.GetRpcService(), b.CallStart.GetRpcMethod()
+			s.c.mtx.Unlock()
+			if len(b.CallStart.GetData()) != 0 || b.CallStart.GetDataIsZero() {
+				atomic.AddUint64(&s.recv, 1)
+			}
+		case *Packet_CallData:
+			if len(b.CallData.GetData()) != 0 || b.CallData.GetDataIsZero() {
+				atomic.AddUint64(&s.recv, 1)
+			}
+		}
+		err := handler(pkt)
+		if isProtocolError(err) {
+			atomic.AddUint64(&s.c.protocolErrors, 1)
+			if s.conn != nil {
+				s.c.mtx.Lock()
+				s.conn.snap.ProtocolErrors++
+				s.c.mtx.Unlock()
+			}
+		}
+		return err
+	}
+}
+
+// channelzWriter counts the messages written to a stream.
+type channelzWriter struct {
+	Writer
+	// strm is the stream
+	strm *channelzStream
+}
+
+// WritePacket writes a packet to the remote.
+func (w *channelzWriter) WritePacket(p *Packet) error {
+	w.count(p)
+	return w.Writer.WritePacket(p)
+}
+
+// WritePackets writes the packets to the remote in order.
+func (w *channelzWriter) WritePackets(pkts []*Packet) error {
+	for _, p := range pkts {
+		w.count(p)
+	}
+	return writePackets(w.Writer, pkts)
+}
+
+// Reset aborts the stream.
+func (w *channelzWriter) Reset() error {
+	return resetWriter(w.Writer)
+}
+
+// count counts the packet if it contains a message.
+func (w *channelzWriter) count(p *Packet) {
+	if data := p.GetCallData(); data != nil && (len(data.GetData()) != 0 || data.GetDataIsZero()) {
+		atomic.AddUint64(&w.strm.sent, 1)
+	}
+}
+
+// _ is a type assertion
+var (
+	_ http.Handler = ((*Channelz)(nil))
+	_ BatchWriter  = ((*channelzWriter)(nil))
+	_ ResetWriter  = ((*channelzWriter)(nil))
+)
@@ -0,0 +1,70 @@
+package srpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestChannelz(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &blockingEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		started:    make(chan struct{}, 1),
+		release:    make(chan struct{}),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	cz := srpc.NewChannelz()
+	server := srpc.NewServer(mux, srpc.WithChannelz(cz))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	ctx := context.Background()
+
+	// expect the blocked call to be listed with the received message
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"})
+		errCh <- err
+	}()
+	<-echoServer.started
+	strms := cz.GetStreams()
+	if len(strms) != 1 {
+		t.Fatalf("expected 1 stream got %v", strms)
+	}
+	strm := strms[0]
+	if strm.ServiceID != echo.SRPCEchoerServiceID || strm.MethodID != "Echo" || strm.MsgsRecv != 1 || strm.MsgsSent != 0 || strm.Start.IsZero() {
+		t.Fatalf("unexpected stream %v", strm)
+	}
+
+	rec := httptest.NewRecorder()
+	cz.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	var page struct {
+		Conns   []*srpc.ChannelzConn
+		Streams []*srpc.ChannelzStream
+	}
+	if err := json.NewDecoder(rec.Result().Body).Decode(&page); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(page.Conns) != 0 || len(page.Streams) != 1 || page.Streams[0].ID != strm.ID {
+		t.Fatalf("unexpected page %v %v", page.Conns, page.Streams)
+	}
+
+	close(echoServer.release)
+	if err := <-errCh; err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// the stream is removed after the result is sent.
+	for i := 0; len(cz.GetStreams()) != 0; i++ {
+		if i == 100 {
+			t.Fatalf("expected no streams got %v", cz.GetStreams())
+		}
+		<-time.After(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,133 @@
+package srpc
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultHedgeMaxAttempts is the default max number of hedged attempts.
+const DefaultHedgeMaxAttempts = 2
+
+// HedgingClient is a Client which hedges unary calls across replicas.
+//
+// Starts the call with the first Client, then starts a duplicate of the call
+// with the next Client each time the delay elapses without a response. The
+// first successful response is used and the other attempts are canceled.
+// Only use with idempotent methods: all attempts may reach a server.
+//
+// Streams and pings are started with the first Client.
+type HedgingClient struct {
+	// clients are the replicas, attempts cycle through them in order
+	clients []Client
+	// delay is the delay before starting the next attempt
+	delay time.Duration
+	// maxAttempts is the max number of attempts including the first
+	maxAttempts int
+}
+
+// NewHedgingClient constructs a HedgingClient with the replica Clients.
+//
+// If maxAttempts is zero, uses DefaultHedgeMaxAttempts. Pass the same Client
+// more than once to hedge multiple attempts to a single remote.
+func NewHedgingClient(delay time.Duration, maxAttempts int, clients ...Client) *HedgingClient {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultHedgeMaxAttempts
+	}
+	return &HedgingClient{clients: clients, delay: delay, maxAttempts: maxAttempts}
+}
+
+// hedgeResult is the result of an attempt.
+type hedgeResult struct {
+	out *RawMessage
+	err error
+}
+
+// Invoke executes a unary RPC hedged across the replicas.
+//
+// Transient errors (see IsRetryableError) start the next attempt immediately.
+// Other errors are the response of the call and are returned. If all attempts
+// fail, returns the error of the last attempt.
+func (c *HedgingClient) Invoke(ctx context.Context, service, method string, in, out Message) error {
+	if len(c.clients) == 0 {
+		return ErrNoAvailableClients
+	}
+	// encode the request once for all attempts.
+	codec, err := lookupCodec(CodecFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	data, err := marshalWithCodec(codec, in)
+	if err != nil {
+		return err
+	}
+	req := NewRawMessage(data)
+
+	ctx, ctxCancel := context.WithCancel(ctx)
+	defer ctxCancel()
+
+	results := make(chan *hedgeResult, c.maxAttempts)
+	started, pending := 0, 0
+	start := func() {
+		client := c.clients[started%len(c.clients)]
+		started++
+		pending++
+		go func() {
+			res := &hedgeResult{out: &RawMessage{}}
+			res.err = client.Invoke(ctx, service, method, req, res.out)
+			results <- res
+		}()
+	}
+	start()
+
+	timer := time.NewTimer(c.delay)
+	defer timer.Stop()
+	for {
+		var timerCh <-chan time.Time
+		if started < c.maxAttempts {
+			timerCh = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		case <-timerCh:
+			start()
+			timer.Reset(c.delay)
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				return unmarshalWithCodec(codec, out, res.out.GetData())
+			}
+			if !IsRetryableError(res.err) {
+				return res.err
+			}
+			if started < c.maxAttempts {
+				start()
+				if !timer.Stop() {
+					<-timer.C
+				}
+				timer.Reset(c.delay)
+			} else if pending == 0 {
+				return res.err
+			}
+		}
+	}
+}
+
+// NewStream starts a streaming RPC with the first Client.
+func (c *HedgingClient) NewStream(ctx context.Context, service, method string, firstMsg Message) (Stream, error) {
+	if len(c.clients) == 0 {
+		return nil, ErrNoAvailableClients
+	}
+	return c.clients[0].NewStream(ctx, service, method, firstMsg)
+}
+
+// Ping calls the built-in ping service with the first Client.
+func (c *HedgingClient) Ping(ctx context.Context) (time.Duration, error) {
+	if len(c.clients) == 0 {
+		return 0, ErrNoAvailableClients
+	}
+	return c.clients[0].Ping(ctx)
+}
+
+// _ is a type assertion
+var _ Client = ((*HedgingClient)(nil))
@@ -0,0 +1,67 @@
+package srpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// stallClient blocks calls until they are canceled.
+type stallClient struct {
+	srpc.Client
+	// canceled is closed when a call is canceled
+	canceled chan struct{}
+}
+
+func (c *stallClient) Invoke(ctx context.Context, service, method string, in, out srpc.Message) error {
+	<-ctx.Done()
+	close(c.canceled)
+	return context.Canceled
+}
+
+func TestHedgingClient(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx := context.Background()
+	replica := srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux)))
+
+	// expect the duplicate to answer and the slow attempt to be canceled
+	stall := &stallClient{canceled: make(chan struct{})}
+	client := echo.NewSRPCEchoerClient(srpc.NewHedgingClient(10*time.Millisecond, 2, stall, replica))
+	if resp, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil || resp.GetBody() != "hello" {
+		t.Fatalf("expected response got %v: %v", resp, err)
+	}
+	select {
+	case <-stall.canceled:
+	case <-time.After(time.Second):
+		t.Fatal("expected slow attempt to be canceled")
+	}
+
+	// expect transient errors to start the next attempt without the delay
+	flaky := &flakyClient{Client: replica, err: srpc.ErrUnavailable, failures: 1}
+	client = echo.NewSRPCEchoerClient(srpc.NewHedgingClient(time.Hour, 2, flaky, replica))
+	if resp, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil || resp.GetBody() != "hello" {
+		t.Fatalf("expected response got %v: %v", resp, err)
+	}
+
+	// expect other errors to be returned
+	appErr := errors.New("not found")
+	flaky = &flakyClient{Client: replica, err: appErr, failures: 1}
+	client = echo.NewSRPCEchoerClient(srpc.NewHedgingClient(time.Hour, 2, flaky, replica))
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != appErr {
+		t.Fatalf("expected %v got %v", appErr, err)
+	}
+
+	// expect the last error if all attempts fail
+	flaky = &flakyClient{Client: replica, err: srpc.ErrUnavailable, failures: 2}
+	client = echo.NewSRPCEchoerClient(srpc.NewHedgingClient(time.Hour, 2, flaky))
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != srpc.ErrUnavailable || flaky.calls != 2 {
+		t.Fatalf("expected %v after 2 attempts got %v after %d", srpc.ErrUnavailable, err, flaky.calls)
+	}
+}
@@ -0,0 +1,140 @@
+package srpc
+
+import (
+	"context"
+	"io"
+)
+
+// MessageTransform rewrites a message proxied by a ClientInvoker.
+//
+// data is the encoded message. Returning an error fails the call.
+type MessageTransform func(ctx context.Context, serviceID, methodID string, data []byte) ([]byte, error)
+
+// ClientInvoker proxies calls to a Client.
+//
+// Forwards the messages in both directions and the incoming metadata. Use
+// InvokeMethod as the next Invoker in a ServerInterceptor or in a Handler to
+// forward calls to another server.
+type ClientInvoker struct {
+	// client is the client to forward calls to
+	client Client
+	// transformRequest rewrites messages sent to the client, if set.
+	transformRequest MessageTransform
+	// transformResponse rewrites messages received from the client, if set.
+	transformResponse MessageTransform
+	// sem limits the concurrent calls, if set.
+	sem chan struct{}
+}
+
+// ClientInvokerOption is an option passed to NewClientInvoker.
+type ClientInvokerOption func(i *ClientInvoker)
+
+// WithRequestTransform rewrites the messages forwarded to the client.
+//
+// For example to redact fields or inject a tenant ID.
+func WithRequestTransform(fn MessageTransform) ClientInvokerOption {
+	return func(i *ClientInvoker) {
+		i.transformRequest = fn
+	}
+}
+
+// WithResponseTransform rewrites the messages forwarded from the client.
+func WithResponseTransform(fn MessageTransform) ClientInvokerOption {
+	return func(i *ClientInvoker) {
+		i.transformResponse = fn
+	}
+}
+
+// WithMaxProxiedCalls limits the number of calls proxied concurrently.
+//
+// Calls over the limit fail with ErrTooManyCalls. Zero for no limit.
+func WithMaxProxiedCalls(limit int) ClientInvokerOption {
+	return func(i *ClientInvoker) {
+		if limit > 0 {
+			i.sem = make(chan struct{}, limit)
+		} else {
+			i.sem = nil
+		}
+	}
+}
+
+// NewClientInvoker constructs a new ClientInvoker.
+func NewClientInvoker(client Client, opts ...ClientInvokerOption) *ClientInvoker {
+	i := &ClientInvoker{client: client}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// InvokeMethod forwards the call to the client.
+//
+// Always returns true: the remote returns ErrUnimplemented for unknown methods.
+func (i *ClientInvoker) InvokeMethod(serviceID, methodID string, strm Stream) (bool, error) {
+	if i.sem != nil {
+		select {
+		case i.sem <- struct{}{}:
+			defer func() { <-i.sem }()
+		default:
+			return true, ErrTooManyCalls
+		}
+	}
+
+	ctx, ctxCancel := context.WithCancel(strm.Context())
+	defer ctxCancel()
+	outCtx := NewContextWithMetadata(ctx, MetadataFromIncomingContext(ctx))
+	if codec := callCodecFromContext(ctx); codec != nil {
+		// forward the encoded messages with the same codec
+		outCtx = WithCallOptions(outCtx, WithCodec(codec.Name()))
+	}
+	clientStrm, err := i.client.NewStream(outCtx, serviceID, methodID, nil)
+	if err != nil {
+		return true, err
+	}
+	defer clientStrm.Close()
+
+	// forward the requests in a separate goroutine
+	reqErrCh := make(chan error, 1)
+	go func() {
+		err := i.forward(ctx, serviceID, methodID, strm, clientStrm, i.transformRequest)
+		if err == nil {
+			err = clientStrm.CloseSend()
+		}
+		if err != nil {
+			reqErrCh <- err
+			ctxCancel()
+		}
+	}()
+
+	err = i.forward(ctx, serviceID, methodID, clientStrm, strm, i.transformResponse)
+	select {
+	case reqErr := <-reqErrCh:
+		return true, reqErr
+	default:
+	}
+	return true, err
+}
+
+// forward forwards messages from src to dst until src returns io.EOF.
+func (i *ClientInvoker) forward(ctx context.Context, serviceID, methodID string, src, dst Stream, transform MessageTransform) error {
+	msg := NewRawMessage(nil)
+	for {
+		if err := src.MsgRecv(msg); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		data := msg.GetData()
+		if transform != nil {
+			var err error
+			data, err = transform(ctx, serviceID, methodID, data)
+			if err != nil {
+				return err
+			}
+		}
+		if err := dst.MsgSend(NewRawMessage(data)); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package srpc_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// newProxyServer constructs a server forwarding all calls to the invoker.
+func newProxyServer(invoker *srpc.ClientInvoker) *srpc.Server {
+	return srpc.NewServer(srpc.NewMux(), srpc.WithInterceptors(
+		func(ctx context.Context, serviceID, methodID string, strm srpc.Stream, next srpc.Invoker) (bool, error) {
+			return invoker.InvokeMethod(serviceID, methodID, strm)
+		},
+	))
+}
+
+// transformEchoMsg returns a MessageTransform rewriting the EchoMsg body.
+func transformEchoMsg(fn func(body string) string) srpc.MessageTransform {
+	return func(ctx context.Context, serviceID, methodID string, data []byte) ([]byte, error) {
+		msg := &echo.EchoMsg{}
+		if err := msg.UnmarshalVT(data); err != nil {
+			return nil, err
+		}
+		msg.Body = fn(msg.GetBody())
+		return msg.MarshalVT()
+	}
+}
+
+func TestClientInvoker(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	backend := srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux)))
+	invoker := srpc.NewClientInvoker(
+		backend,
+		srpc.WithRequestTransform(transformEchoMsg(strings.ToUpper)),
+		srpc.WithResponseTransform(transformEchoMsg(func(body string) string {
+			return body + "!"
+		})),
+	)
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(newProxyServer(invoker))))
+	ctx := context.Background()
+
+	// expect the messages to be transformed in both directions
+	resp, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != "HELLO!" {
+		t.Fatalf("expected transformed response got %q", resp.GetBody())
+	}
+
+	// expect bidi streams to be proxied
+	strm, err := client.EchoBidiStream(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if msg, err := strm.Recv(); err != nil || msg.GetBody() != "hello from server!" {
+		t.Fatalf("unexpected initial message %v: %v", msg, err)
+	}
+	if err := strm.Send(&echo.EchoMsg{Body: "bidi"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if msg, err := strm.Recv(); err != nil || msg.GetBody() != "BIDI!" {
+		t.Fatalf("unexpected message %v: %v", msg, err)
+	}
+	if err := strm.CloseSend(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != io.EOF {
+		t.Fatalf("expected EOF got %v", err)
+	}
+
+	// expect errors from the remote to be returned
+	if err := client.SRPCClient().Invoke(ctx, "unknown.Service", "Method", &echo.EchoMsg{}, &echo.EchoMsg{}); err == nil || err.Error() != srpc.ErrUnimplemented.Error() {
+		t.Fatalf("expected %v got %v", srpc.ErrUnimplemented, err)
+	}
+}
+
+func TestClientInvoker_Metadata(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &metadataEchoServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	invoker := srpc.NewClientInvoker(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(newProxyServer(invoker))))
+
+	ctx := srpc.AppendToOutgoingContext(context.Background(), "x-tenant", "tenant-1")
+	resp, err := client.Echo(ctx, &echo.EchoMsg{Body: "x-tenant"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != "tenant-1" {
+		t.Fatalf("expected forwarded metadata got %q", resp.GetBody())
+	}
+}
+
+func TestClientInvoker_MaxProxiedCalls(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &blockingEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		started:    make(chan struct{}, 1),
+		release:    make(chan struct{}),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	invoker := srpc.NewClientInvoker(
+		srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))),
+		srpc.WithMaxProxiedCalls(1),
+	)
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(newProxyServer(invoker))))
+	ctx := context.Background()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"})
+		errCh <- err
+	}()
+	<-echoServer.started
+
+	// expect calls over the limit to be rejected
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err == nil || err.Error() != srpc.ErrTooManyCalls.Error() {
+		t.Fatalf("expected %v got %v", srpc.ErrTooManyCalls, err)
+	}
+	close(echoServer.release)
+	if err := <-errCh; err != nil {
+		t.Fatal(err.Error())
+	}
+}
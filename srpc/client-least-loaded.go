@@ -0,0 +1,160 @@
+package srpc
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// leastLoadedClient picks the Client with the lowest load score for each call.
+type leastLoadedClient struct {
+	// clients contains the clients to pick from.
+	clients []*leastLoadedEntry
+	// outlierDetection configures ejecting backends, may be nil.
+	outlierDetection *OutlierDetection
+}
+
+// leastLoadedEntry is a client with the number of calls in-flight.
+type leastLoadedEntry struct {
+	// client is the client
+	client Client
+	// inflight is the number of calls started with the client.
+	inflight int32
+	// outcomes tracks the call outcomes.
+	outcomes backendOutcomes
+}
+
+// NewLeastLoadedClient constructs a Client which starts each call with the
+// Client with the lowest LoadScore, using the number of in-flight calls to
+// break ties.
+//
+// Streams are placed on the Client with the fewest active streams (see
+// GetActiveStreams), using the LoadScore to break ties, so long-lived streams
+// are spread evenly instead of following the unary load.
+//
+// The load reports are tracked by Clients constructed with NewClient. Clients
+// without a load report have a score of zero.
+//
+// The outcomes of the calls to each Client are tracked, see GetBackendStats.
+func NewLeastLoadedClient(clients ...Client) Client {
+	return NewLeastLoadedClientWithOutlierDetection(nil, clients...)
+}
+
+// NewLeastLoadedClientWithOutlierDetection constructs a least loaded Client
+// which ejects Clients with an error rate above the threshold.
+//
+// Ejected Clients are not used until the ejection ends, unless all Clients are
+// ejected. See NewLeastLoadedClient and OutlierDetection.
+func NewLeastLoadedClientWithOutlierDetection(conf *OutlierDetection, clients ...Client) Client {
+	entries := make([]*leastLoadedEntry, len(clients))
+	for i, client := range clients {
+		entries[i] = &leastLoadedEntry{client: client}
+	}
+	return &leastLoadedClient{clients: entries, outlierDetection: conf}
+}
+
+// Invoke executes a unary RPC with the least loaded Client.
+func (c *leastLoadedClient) Invoke(ctx context.Context, service, method string, in, out Message) error {
+	entry := c.pick(leastLoadedScore, inflightScore)
+	if entry == nil {
+		return ErrNoAvailableClients
+	}
+	defer atomic.AddInt32(&entry.inflight, -1)
+	err := entry.client.Invoke(ctx, service, method, in, out)
+	entry.outcomes.record(ctx, err, c.outlierDetection)
+	return err
+}
+
+// NewStream starts a streaming RPC with the Client with the fewest streams.
+func (c *leastLoadedClient) NewStream(ctx context.Context, service, method string, firstMsg Message) (Stream, error) {
+	entry := c.pick(activeStreamsScore, leastLoadedScore)
+	if entry == nil {
+		return nil, ErrNoAvailableClients
+	}
+	defer atomic.AddInt32(&entry.inflight, -1)
+	strm, err := entry.client.NewStream(ctx, service, method, firstMsg)
+	entry.outcomes.record(ctx, err, c.outlierDetection)
+	return strm, err
+}
+
+// Ping calls the built-in ping service with the least loaded Client.
+func (c *leastLoadedClient) Ping(ctx context.Context) (time.Duration, error) {
+	return pingClient(ctx, c)
+}
+
+// GetActiveStreams returns the number of active streams for each Client.
+func (c *leastLoadedClient) GetActiveStreams() int {
+	var n int
+	for _, entry := range c.clients {
+		n += GetActiveStreams(entry.client)
+	}
+	return n
+}
+
+// GetBackendStats returns the stats for each Client.
+func (c *leastLoadedClient) GetBackendStats() []*BackendStats {
+	now := time.Now()
+	stats := make([]*BackendStats, len(c.clients))
+	for i, entry := range c.clients {
+		stats[i] = entry.outcomes.snapshot(entry.client, now)
+	}
+	return stats
+}
+
+// leastLoadedScoreFunc returns a score for the entry where lower is better.
+type leastLoadedScoreFunc func(entry *leastLoadedEntry) float64
+
+// leastLoadedScore scores the entry with the LoadScore of the last report.
+func leastLoadedScore(entry *leastLoadedEntry) float64 {
+	return LoadScore(GetLoadReport(entry.client))
+}
+
+// inflightScore scores the entry with the number of in-flight calls.
+func inflightScore(entry *leastLoadedEntry) float64 {
+	return float64(atomic.LoadInt32(&entry.inflight))
+}
+
+// activeStreamsScore scores the entry with the number of active and starting streams.
+func activeStreamsScore(entry *leastLoadedEntry) float64 {
+	return float64(GetActiveStreams(entry.client)) + inflightScore(entry)
+}
+
+// pick returns the entry with the lowest score incrementing inflight.
+//
+// Later score funcs are used to break ties of earlier ones.
+// Ejected entries are used only if all entries are ejected.
+func (c *leastLoadedClient) pick(scoreFns ...leastLoadedScoreFunc) *leastLoadedEntry {
+	now := time.Now()
+	var best *leastLoadedEntry
+	var bestEjected bool
+	for _, entry := range c.clients {
+		if IsClientDraining(entry.client) {
+			continue
+		}
+		ejected := entry.outcomes.isEjected(now)
+		if best == nil || (bestEjected && !ejected) || (bestEjected == ejected && c.less(entry, best, scoreFns)) {
+			best, bestEjected = entry, ejected
+		}
+	}
+	if best != nil {
+		atomic.AddInt32(&best.inflight, 1)
+	}
+	return best
+}
+
+// less checks if a scores lower than b.
+func (c *leastLoadedClient) less(a, b *leastLoadedEntry, scoreFns []leastLoadedScoreFunc) bool {
+	for _, scoreFn := range scoreFns {
+		if aScore, bScore := scoreFn(a), scoreFn(b); aScore != bScore {
+			return aScore < bScore
+		}
+	}
+	return false
+}
+
+// _ is a type assertion
+var (
+	_ Client              = ((*leastLoadedClient)(nil))
+	_ ActiveStreamsGetter = ((*leastLoadedClient)(nil))
+	_ BackendStatsGetter  = ((*leastLoadedClient)(nil))
+)
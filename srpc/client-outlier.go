@@ -0,0 +1,159 @@
+package srpc
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OutlierDetection configures ejecting backends with a high error rate.
+type OutlierDetection struct {
+	// MaxErrorRate is the maximum fraction of failed calls, from 0 to 1.
+	//
+	// Backends with a higher error rate are ejected.
+	MaxErrorRate float64
+	// MinRequests is the number of calls used to compute the error rate.
+	//
+	// If zero, defaults to 10.
+	MinRequests int
+	// EjectionTime is the base time a backend is ejected for.
+	//
+	// Multiplied by the number of consecutive ejections.
+	// If zero, defaults to 30 seconds.
+	EjectionTime time.Duration
+}
+
+// BackendStats contains the call outcomes of a backend in a pool.
+type BackendStats struct {
+	// Client is the backend.
+	Client Client
+	// Successes is the number of calls which succeeded.
+	Successes uint64
+	// Failures is the number of calls which failed.
+	Failures uint64
+	// Ejected indicates the backend is currently ejected.
+	Ejected bool
+	// EjectedUntil is the time the ejection ends if Ejected is set.
+	//
+	// The backend is on probation after the ejection ends: it is ejected again
+	// for longer if the error rate is still too high.
+	EjectedUntil time.Time
+	// Ejections is the number of consecutive ejections.
+	Ejections int
+}
+
+// SuccessRate returns the fraction of calls which succeeded.
+//
+// Returns 1 if no calls were made.
+func (s *BackendStats) SuccessRate() float64 {
+	total := s.Successes + s.Failures
+	if total == 0 {
+		return 1
+	}
+	return float64(s.Successes) / float64(total)
+}
+
+// BackendStatsGetter is a Client which tracks the outcomes of calls to each backend.
+type BackendStatsGetter interface {
+	// GetBackendStats returns the stats for each backend.
+	GetBackendStats() []*BackendStats
+}
+
+// GetBackendStats returns the stats for each backend of the Client.
+//
+// Returns nil if the Client does not track backends.
+func GetBackendStats(client Client) []*BackendStats {
+	getter, ok := client.(BackendStatsGetter)
+	if !ok {
+		return nil
+	}
+	return getter.GetBackendStats()
+}
+
+// backendOutcomes tracks the call outcomes of a backend.
+type backendOutcomes struct {
+	// mtx guards below fields
+	mtx sync.Mutex
+	// successes is the total number of calls which succeeded
+	successes uint64
+	// failures is the total number of calls which failed
+	failures uint64
+	// windowSuccesses is the number of calls which succeeded in the window
+	windowSuccesses int
+	// windowFailures is the number of calls which failed in the window
+	windowFailures int
+	// ejectedUntil is the time the ejection ends
+	ejectedUntil time.Time
+	// ejections is the number of consecutive ejections
+	ejections int
+}
+
+// isEjected checks if the backend is ejected.
+func (o *backendOutcomes) isEjected(now time.Time) bool {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return now.Before(o.ejectedUntil)
+}
+
+// record records the outcome of a call and ejects the backend if needed.
+//
+// conf may be nil to disable ejecting the backend.
+func (o *backendOutcomes) record(ctx context.Context, err error, conf *OutlierDetection) {
+	// calls canceled by the caller do not count against the backend
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	if err != nil {
+		o.failures++
+		o.windowFailures++
+	} else {
+		o.successes++
+		o.windowSuccesses++
+	}
+	if conf == nil {
+		return
+	}
+
+	minRequests := conf.MinRequests
+	if minRequests <= 0 {
+		minRequests = 10
+	}
+	total := o.windowSuccesses + o.windowFailures
+	if total < minRequests {
+		return
+	}
+	errorRate := float64(o.windowFailures) / float64(total)
+	o.windowSuccesses, o.windowFailures = 0, 0
+	if errorRate <= conf.MaxErrorRate {
+		// passed probation
+		o.ejections = 0
+		return
+	}
+
+	ejectionTime := conf.EjectionTime
+	if ejectionTime <= 0 {
+		ejectionTime = time.Second * 30
+	}
+	o.ejections++
+	o.ejectedUntil = time.Now().Add(ejectionTime * time.Duration(o.ejections))
+}
+
+// snapshot returns the stats for the backend.
+func (o *backendOutcomes) snapshot(client Client, now time.Time) *BackendStats {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	stats := &BackendStats{
+		Client:    client,
+		Successes: o.successes,
+		Failures:  o.failures,
+		Ejections: o.ejections,
+	}
+	if now.Before(o.ejectedUntil) {
+		stats.Ejected = true
+		stats.EjectedUntil = o.ejectedUntil
+	}
+	return stats
+}
@@ -0,0 +1,91 @@
+package srpc_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// failingEchoServer fails Echo while failing is set.
+type failingEchoServer struct {
+	*echo.EchoServer
+	failing int32
+}
+
+func TestOutlierDetection(t *testing.T) {
+	ctx := context.Background()
+	msg := &echo.EchoMsg{Body: "hello world"}
+
+	// construct a failing and a healthy server
+	var echoServers []*failingEchoServer
+	var clients []srpc.Client
+	for _, failing := range []int32{1, 0} {
+		mux := srpc.NewMux()
+		echoServer := &failingEchoServer{EchoServer: echo.NewEchoServer(mux), failing: failing}
+		if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+			t.Fatal(err.Error())
+		}
+		echoServers = append(echoServers, echoServer)
+		clients = append(clients, srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	}
+	pool := srpc.NewLeastLoadedClientWithOutlierDetection(&srpc.OutlierDetection{
+		MaxErrorRate: 0.5,
+		MinRequests:  4,
+		EjectionTime: time.Millisecond * 50,
+	}, clients...)
+	client := echo.NewSRPCEchoerClient(pool)
+
+	// expect the failing server to be ejected after the window
+	var failed int
+	for i := 0; i < 8; i++ {
+		if _, err := client.Echo(ctx, msg); err != nil {
+			failed++
+		}
+	}
+	if failed != 4 {
+		t.Fatalf("expected 4 failed calls got %d", failed)
+	}
+	stats := srpc.GetBackendStats(pool)
+	if len(stats) != 2 || !stats[0].Ejected || stats[0].Failures != 4 || stats[0].SuccessRate() != 0 {
+		t.Fatalf("expected failing server to be ejected: %#v", stats[0])
+	}
+	if stats[1].Ejected || stats[1].Successes != 4 || stats[1].SuccessRate() != 1 {
+		t.Fatalf("expected healthy server to be used: %#v", stats[1])
+	}
+
+	// expect the server to be ejected for longer if it fails probation
+	<-time.After(time.Until(stats[0].EjectedUntil))
+	for i := 0; i < 4; i++ {
+		_, _ = client.Echo(ctx, msg)
+	}
+	stats = srpc.GetBackendStats(pool)
+	if !stats[0].Ejected || stats[0].Ejections != 2 {
+		t.Fatalf("expected server to be ejected again: %#v", stats[0])
+	}
+
+	// expect the server to pass probation once it recovers
+	atomic.StoreInt32(&echoServers[0].failing, 0)
+	<-time.After(time.Until(stats[0].EjectedUntil))
+	for i := 0; i < 4; i++ {
+		if _, err := client.Echo(ctx, msg); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	stats = srpc.GetBackendStats(pool)
+	if stats[0].Ejected || stats[0].Ejections != 0 || stats[0].Successes != 4 {
+		t.Fatalf("expected server to pass probation: %#v", stats[0])
+	}
+}
+
+// Echo fails if failing is set or echoes the message.
+func (s *failingEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	if atomic.LoadInt32(&s.failing) != 0 {
+		return nil, errors.New("backend unavailable")
+	}
+	return s.EchoServer.Echo(ctx, msg)
+}
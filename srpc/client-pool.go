@@ -0,0 +1,247 @@
+//go:build !starpc_core
+
+package srpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+)
+
+// DefaultClientPoolSize is the default max number of connections of a ClientPool.
+const DefaultClientPoolSize = 4
+
+// ClientPoolConfig configures a ClientPool.
+type ClientPoolConfig struct {
+	// Size is the max number of connections.
+	// If zero, uses DefaultClientPoolSize.
+	Size int
+	// IdleTimeout closes connections without open streams after the duration.
+	// If zero, idle connections are kept open.
+	IdleTimeout time.Duration
+	// MaxAge is the max age of a connection used for new streams.
+	// Older connections are closed once their streams complete.
+	// If zero, connections are only replaced after they are closed.
+	MaxAge time.Duration
+}
+
+// ClientPool is a Client which spreads the streams across a pool of muxed
+// connections to the same address.
+//
+// Each stream uses the connection with the fewest open streams. Another
+// connection is dialed while all connections are in use, up to Size, so a
+// single muxed connection does not become a bottleneck under high stream
+// counts.
+type ClientPool struct {
+	// client is the client opening streams with the pool.
+	client Client
+	// dial dials a new connection.
+	dial MuxedConnDialer
+	// conf is the pool config.
+	conf ClientPoolConfig
+	// mtx guards the fields below
+	mtx sync.Mutex
+	// conns contains the connections used for new streams.
+	conns []*poolConn
+	// closed indicates Close was called.
+	closed bool
+}
+
+// poolConn is a muxed connection managed by ClientPool.
+type poolConn struct {
+	// mconn is the muxed connection.
+	mconn network.MuxedConn
+	// expires is when the connection should no longer be used for new streams.
+	expires time.Time
+	// refs is the number of open streams.
+	// guarded by ClientPool.mtx
+	refs int
+	// retired indicates the connection is closed after refs reaches zero.
+	// guarded by ClientPool.mtx
+	retired bool
+	// idleTimer closes the connection after the idle timeout, if set.
+	// guarded by ClientPool.mtx
+	idleTimer *time.Timer
+}
+
+// NewClientPool constructs a new ClientPool.
+//
+// Connections are dialed on demand. If conf is nil, uses the defaults. Call
+// Close to close the connections.
+func NewClientPool(dial MuxedConnDialer, conf *ClientPoolConfig) *ClientPool {
+	p := &ClientPool{dial: dial}
+	if conf != nil {
+		p.conf = *conf
+	}
+	if p.conf.Size <= 0 {
+		p.conf.Size = DefaultClientPoolSize
+	}
+	p.client = NewClient(p.OpenStream)
+	return p
+}
+
+// GetConnCount returns the number of connections used for new streams.
+func (p *ClientPool) GetConnCount() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return len(p.conns)
+}
+
+// Invoke executes a unary RPC over a pooled connection.
+func (p *ClientPool) Invoke(ctx context.Context, service, method string, in, out Message) error {
+	return p.client.Invoke(ctx, service, method, in, out)
+}
+
+// NewStream starts a streaming RPC over a pooled connection.
+func (p *ClientPool) NewStream(ctx context.Context, service, method string, firstMsg Message) (Stream, error) {
+	return p.client.NewStream(ctx, service, method, firstMsg)
+}
+
+// Ping calls the built-in ping service over a pooled connection.
+func (p *ClientPool) Ping(ctx context.Context) (time.Duration, error) {
+	return p.client.Ping(ctx)
+}
+
+// OpenStream opens a stream over the connection with the fewest open streams.
+//
+// Dials a new connection if all connections are in use and the pool is not full.
+func (p *ClientPool) OpenStream(ctx context.Context, msgHandler PacketHandler, closeHandler CloseHandler) (Writer, error) {
+	conn, err := p.getConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mstrm, err := conn.mconn.OpenStream(ctx)
+	if err != nil {
+		p.releaseConn(conn)
+		return nil, err
+	}
+	pooledStrm := &rotatedStream{
+		MuxedStream: mstrm,
+		release: func() {
+			p.releaseConn(conn)
+		},
+	}
+	rw := NewPacketReadWriter(pooledStrm)
+	go rw.ReadPump(msgHandler, closeHandler)
+	return rw, nil
+}
+
+// Close closes the connections.
+//
+// Any further calls will fail with ErrClientClosed.
+func (p *ClientPool) Close() {
+	p.mtx.Lock()
+	p.closed = true
+	conns := p.conns
+	p.conns = nil
+	for _, conn := range conns {
+		if conn.idleTimer != nil {
+			conn.idleTimer.Stop()
+		}
+	}
+	p.mtx.Unlock()
+	for _, conn := range conns {
+		_ = conn.mconn.Close()
+	}
+}
+
+// getConn returns the connection for a new stream adding a reference.
+func (p *ClientPool) getConn(ctx context.Context) (*poolConn, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.closed {
+		return nil, ErrClientClosed
+	}
+
+	// retire the closed and expired connections
+	now := time.Now()
+	conns := p.conns[:0]
+	for _, conn := range p.conns {
+		if conn.mconn.IsClosed() || (p.conf.MaxAge > 0 && !now.Before(conn.expires)) {
+			p.retireConnLocked(conn)
+			continue
+		}
+		conns = append(conns, conn)
+	}
+	for i := len(conns); i < len(p.conns); i++ {
+		p.conns[i] = nil
+	}
+	p.conns = conns
+
+	var best *poolConn
+	for _, conn := range p.conns {
+		if best == nil || conn.refs < best.refs {
+			best = conn
+		}
+	}
+	if best == nil || (best.refs != 0 && len(p.conns) < p.conf.Size) {
+		mconn, err := p.dial(ctx)
+		if err != nil {
+			if best == nil {
+				return nil, err
+			}
+		} else {
+			best = &poolConn{mconn: mconn, expires: now.Add(p.conf.MaxAge)}
+			p.conns = append(p.conns, best)
+		}
+	}
+	best.refs++
+	if best.idleTimer != nil {
+		best.idleTimer.Stop()
+		best.idleTimer = nil
+	}
+	return best, nil
+}
+
+// releaseConn removes a reference to the connection.
+func (p *ClientPool) releaseConn(conn *poolConn) {
+	p.mtx.Lock()
+	conn.refs--
+	closeConn := conn.retired && conn.refs == 0
+	if !conn.retired && conn.refs == 0 && p.conf.IdleTimeout > 0 {
+		conn.idleTimer = time.AfterFunc(p.conf.IdleTimeout, func() {
+			p.closeIdleConn(conn)
+		})
+	}
+	p.mtx.Unlock()
+	if closeConn {
+		_ = conn.mconn.Close()
+	}
+}
+
+// closeIdleConn closes the connection if it is still idle.
+func (p *ClientPool) closeIdleConn(conn *poolConn) {
+	p.mtx.Lock()
+	if conn.refs != 0 || conn.retired {
+		p.mtx.Unlock()
+		return
+	}
+	conn.retired = true
+	conn.idleTimer = nil
+	for i, c := range p.conns {
+		if c == conn {
+			p.conns = append(p.conns[:i], p.conns[i+1:]...)
+			break
+		}
+	}
+	p.mtx.Unlock()
+	_ = conn.mconn.Close()
+}
+
+// retireConnLocked marks the connection to be closed after it is idle.
+// expects mtx to be locked
+func (p *ClientPool) retireConnLocked(conn *poolConn) {
+	conn.retired = true
+	if conn.idleTimer != nil {
+		conn.idleTimer.Stop()
+		conn.idleTimer = nil
+	}
+	if conn.refs == 0 {
+		go conn.mconn.Close()
+	}
+}
+
+// _ is a type assertion
+var _ Client = ((*ClientPool)(nil))
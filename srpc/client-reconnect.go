@@ -0,0 +1,291 @@
+//go:build !starpc_core
+
+package srpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/pkg/errors"
+)
+
+// connResetGrace is how long to wait for the muxer to report a dropped
+// connection after a call failed with a transport error.
+const connResetGrace = time.Millisecond * 10
+
+// ReconnectPolicy configures a ReconnectingClient.
+//
+// Zero fields use the defaults of RetryPolicy for the backoff.
+type ReconnectPolicy struct {
+	// InitialBackoff is the delay before the first redial.
+	InitialBackoff time.Duration
+	// MaxBackoff is the max delay between dial attempts.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows by after each failed dial.
+	Multiplier float64
+	// Jitter is the fraction of the delay to randomly subtract, from 0 to 1.
+	Jitter float64
+	// Backoff computes the delay before each redial, if set.
+	//
+	// Overrides InitialBackoff, MaxBackoff, Multiplier and Jitter.
+	Backoff Backoff
+	// Clock is the time source of the redial timers.
+	//
+	// Defaults to SystemClock.
+	Clock Clock
+	// FailFast fails new calls with ErrUnavailable while disconnected.
+	//
+	// By default new calls wait until the connection is established or the
+	// call ctx is canceled. Calls with WithWaitForReady always wait.
+	FailFast bool
+}
+
+// backoff returns the delay before the dial after the failed attempt.
+func (p *ReconnectPolicy) backoff(attempt int) time.Duration {
+	retryPolicy := RetryPolicy{
+		InitialBackoff: p.InitialBackoff,
+		MaxBackoff:     p.MaxBackoff,
+		Multiplier:     p.Multiplier,
+		Jitter:         p.Jitter,
+		Backoff:        p.Backoff,
+	}
+	return retryPolicy.backoff(attempt)
+}
+
+// ReconnectingClient is a Client over a muxed connection which is redialed
+// when it drops.
+//
+// Calls in flight when the connection drops fail with ErrConnReset. New calls
+// wait for the next connection, or fail with ErrUnavailable if FailFast is
+// set. Wrap with NewRetryingClient to retry the failed calls.
+type ReconnectingClient struct {
+	// dial dials a new connection.
+	dial MuxedConnDialer
+	// policy is the reconnect policy.
+	policy ReconnectPolicy
+	// ctx is canceled when the client is closed.
+	ctx context.Context
+	// ctxCancel cancels ctx.
+	ctxCancel context.CancelFunc
+	// mtx guards the fields below
+	mtx sync.Mutex
+	// conn is the current connection, nil if disconnected.
+	conn *reconnectConn
+	// dialing indicates the dial loop is running.
+	dialing bool
+	// ready is closed and replaced when the connection is established.
+	ready chan struct{}
+	// closed indicates Close was called.
+	closed bool
+}
+
+// reconnectConn is a connection managed by ReconnectingClient.
+type reconnectConn struct {
+	// mconn is the muxed connection.
+	mconn network.MuxedConn
+	// client is the client over the connection.
+	client Client
+}
+
+// NewReconnectingClient constructs a ReconnectingClient and starts dialing.
+//
+// If policy is nil, uses the defaults. Call Close to close the connection.
+func NewReconnectingClient(dial MuxedConnDialer, policy *ReconnectPolicy) *ReconnectingClient {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	c := &ReconnectingClient{
+		dial:      dial,
+		ctx:       ctx,
+		ctxCancel: ctxCancel,
+		ready:     make(chan struct{}),
+	}
+	if policy != nil {
+		c.policy = *policy
+	}
+	c.mtx.Lock()
+	c.startDialLocked()
+	c.mtx.Unlock()
+	return c
+}
+
+// IsConnected checks if the connection is currently established.
+func (c *ReconnectingClient) IsConnected() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.conn != nil && !c.conn.mconn.IsClosed()
+}
+
+// Invoke executes a unary RPC with the remote.
+//
+// Returns ErrConnReset if the connection dropped during the call.
+func (c *ReconnectingClient) Invoke(ctx context.Context, service, method string, in, out Message) error {
+	conn, err := c.getConn(ctx)
+	if err != nil {
+		return err
+	}
+	return c.checkErr(conn, conn.client.Invoke(ctx, service, method, in, out))
+}
+
+// NewStream starts a streaming RPC with the remote.
+//
+// The stream returns ErrConnReset if the connection drops.
+func (c *ReconnectingClient) NewStream(ctx context.Context, service, method string, firstMsg Message) (Stream, error) {
+	conn, err := c.getConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	strm, err := conn.client.NewStream(ctx, service, method, firstMsg)
+	if err != nil {
+		return nil, c.checkErr(conn, err)
+	}
+	return &reconnectStream{Stream: strm, c: c, conn: conn}, nil
+}
+
+// Ping calls the built-in ping service with the remote.
+func (c *ReconnectingClient) Ping(ctx context.Context) (time.Duration, error) {
+	return pingClient(ctx, c)
+}
+
+// Close closes the connection and stops redialing.
+//
+// Any further calls will fail with ErrClientClosed.
+func (c *ReconnectingClient) Close() {
+	c.ctxCancel()
+	c.mtx.Lock()
+	c.closed = true
+	conn := c.conn
+	c.conn = nil
+	close(c.ready)
+	c.ready = make(chan struct{})
+	c.mtx.Unlock()
+	if conn != nil {
+		_ = conn.mconn.Close()
+	}
+}
+
+// getConn returns the current connection, waiting for it if disconnected.
+func (c *ReconnectingClient) getConn(ctx context.Context) (*reconnectConn, error) {
+	for {
+		c.mtx.Lock()
+		if c.closed {
+			c.mtx.Unlock()
+			return nil, ErrClientClosed
+		}
+		if conn := c.conn; conn != nil {
+			if !conn.mconn.IsClosed() {
+				c.mtx.Unlock()
+				return conn, nil
+			}
+			c.resetLocked(conn)
+		}
+		if c.policy.FailFast && !getCallOptions(ctx).waitForReady {
+			c.mtx.Unlock()
+			return nil, ErrUnavailable
+		}
+		ready := c.ready
+		c.mtx.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, context.Canceled
+		case <-ready:
+		}
+	}
+}
+
+// checkErr returns ErrConnReset if the call failed with a transport error
+// because conn dropped.
+func (c *ReconnectingClient) checkErr(conn *reconnectConn, err error) error {
+	if err == nil || (!IsRetryableError(err) && !errors.Is(err, network.ErrReset)) {
+		return err
+	}
+	for wait := time.Duration(0); !conn.mconn.IsClosed(); wait += time.Millisecond {
+		if wait >= connResetGrace {
+			return err
+		}
+		<-time.After(time.Millisecond)
+	}
+	c.mtx.Lock()
+	c.resetLocked(conn)
+	c.mtx.Unlock()
+	return ErrConnReset
+}
+
+// resetLocked drops the connection if it is current and starts redialing.
+// expects mtx to be locked
+func (c *ReconnectingClient) resetLocked(conn *reconnectConn) {
+	if c.conn != conn || c.closed {
+		return
+	}
+	c.conn = nil
+	go conn.mconn.Close()
+	c.startDialLocked()
+}
+
+// startDialLocked starts the dial loop if not running.
+// expects mtx to be locked
+func (c *ReconnectingClient) startDialLocked() {
+	if c.dialing {
+		return
+	}
+	c.dialing = true
+	go c.dialLoop()
+}
+
+// dialLoop dials until a connection is established or the client is closed.
+func (c *ReconnectingClient) dialLoop() {
+	for attempt := 1; ; attempt++ {
+		mconn, err := c.dial(c.ctx)
+		c.mtx.Lock()
+		if c.closed {
+			c.dialing = false
+			c.mtx.Unlock()
+			if mconn != nil {
+				_ = mconn.Close()
+			}
+			return
+		}
+		if err == nil {
+			c.conn = &reconnectConn{mconn: mconn, client: NewClientWithMuxedConn(mconn)}
+			c.dialing = false
+			close(c.ready)
+			c.ready = make(chan struct{})
+			c.mtx.Unlock()
+			return
+		}
+		c.mtx.Unlock()
+
+		_ = waitBackoff(c.ctx, c.policy.Clock, c.policy.backoff(attempt))
+	}
+}
+
+// reconnectStream is a stream returning ErrConnReset if the connection drops.
+type reconnectStream struct {
+	Stream
+	// c is the client
+	c *ReconnectingClient
+	// conn is the connection of the stream
+	conn *reconnectConn
+}
+
+// MsgSend sends the message to the remote.
+func (s *reconnectStream) MsgSend(msg Message) error {
+	return s.c.checkErr(s.conn, s.Stream.MsgSend(msg))
+}
+
+// MsgRecv receives an incoming message from the remote.
+func (s *reconnectStream) MsgRecv(msg Message) error {
+	return s.c.checkErr(s.conn, s.Stream.MsgRecv(msg))
+}
+
+// CloseSend signals to the remote that we will no longer send any messages.
+func (s *reconnectStream) CloseSend() error {
+	return s.c.checkErr(s.conn, s.Stream.CloseSend())
+}
+
+// _ is a type assertion
+var (
+	_ Client = ((*ReconnectingClient)(nil))
+	_ Stream = ((*reconnectStream)(nil))
+)
@@ -0,0 +1,216 @@
+package srpc
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AddrDialer dials an address returning a Client and a Closer to release it.
+type AddrDialer func(ctx context.Context, addr string) (Client, io.Closer, error)
+
+// BalancerFunc constructs a Client balancing the calls across the Clients.
+//
+// For example NewRoundRobinClient or NewLeastLoadedClient.
+type BalancerFunc func(clients ...Client) Client
+
+// ResolvedClient is a Client which balances the calls across the addresses
+// of a target.
+//
+// The target is re-resolved with the Resolver registered for its scheme. New
+// addresses are dialed and the connections to removed addresses are closed.
+type ResolvedClient struct {
+	// ctxCancel stops watching the resolver
+	ctxCancel context.CancelFunc
+	// dial dials an address
+	dial AddrDialer
+	// balancer constructs the balanced client
+	balancer BalancerFunc
+	// mtx guards below fields
+	mtx sync.Mutex
+	// conns contains the connections by address
+	conns map[string]*resolvedConn
+	// balanced balances the calls across conns, nil if empty
+	balanced Client
+	// closed indicates Close was called
+	closed bool
+}
+
+// resolvedConn is a connection to an address.
+type resolvedConn struct {
+	// client is the client
+	client Client
+	// closer releases the connection
+	closer io.Closer
+}
+
+// NewResolvedClient resolves the target and dials its addresses.
+//
+// Returns an error if the target cannot be resolved or no address can be
+// dialed. If balancer is nil, uses NewRoundRobinClient. Call Close to stop
+// watching the resolver and close the connections.
+func NewResolvedClient(ctx context.Context, target string, dial AddrDialer, balancer BalancerFunc) (*ResolvedClient, error) {
+	resolver, endpoint, addrs, err := ResolveTarget(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if balancer == nil {
+		balancer = NewRoundRobinClient
+	}
+	watchCtx, watchCtxCancel := context.WithCancel(context.Background())
+	c := &ResolvedClient{
+		ctxCancel: watchCtxCancel,
+		dial:      dial,
+		balancer:  balancer,
+		conns:     make(map[string]*resolvedConn),
+	}
+	if err := c.update(ctx, addrs); err != nil && len(c.GetAddrs()) == 0 {
+		c.Close()
+		return nil, err
+	}
+	go func() {
+		_ = resolver.Watch(watchCtx, endpoint, func(addrs []Address) {
+			_ = c.update(watchCtx, addrs)
+		})
+	}()
+	return c, nil
+}
+
+// GetAddrs returns the sorted addresses with an open connection.
+func (c *ResolvedClient) GetAddrs() []string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	addrs := make([]string, 0, len(c.conns))
+	for addr := range c.conns {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	return addrs
+}
+
+// Invoke executes a unary RPC with the balanced Client.
+func (c *ResolvedClient) Invoke(ctx context.Context, service, method string, in, out Message) error {
+	client := c.getBalanced()
+	if client == nil {
+		return ErrNoAvailableClients
+	}
+	return client.Invoke(ctx, service, method, in, out)
+}
+
+// NewStream starts a streaming RPC with the balanced Client.
+func (c *ResolvedClient) NewStream(ctx context.Context, service, method string, firstMsg Message) (Stream, error) {
+	client := c.getBalanced()
+	if client == nil {
+		return nil, ErrNoAvailableClients
+	}
+	return client.NewStream(ctx, service, method, firstMsg)
+}
+
+// Ping calls the built-in ping service with the balanced Client.
+func (c *ResolvedClient) Ping(ctx context.Context) (time.Duration, error) {
+	return pingClient(ctx, c)
+}
+
+// GetBackendStats returns the stats of the balanced Client, if tracked.
+func (c *ResolvedClient) GetBackendStats() []*BackendStats {
+	client := c.getBalanced()
+	if client == nil {
+		return nil
+	}
+	return GetBackendStats(client)
+}
+
+// Close stops watching the resolver and closes the connections.
+func (c *ResolvedClient) Close() {
+	c.ctxCancel()
+	c.mtx.Lock()
+	conns := c.conns
+	c.conns, c.balanced, c.closed = nil, nil, true
+	c.mtx.Unlock()
+	for _, conn := range conns {
+		_ = conn.closer.Close()
+	}
+}
+
+// getBalanced returns the balanced client or nil.
+func (c *ResolvedClient) getBalanced() Client {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.balanced
+}
+
+// update dials the new addresses and closes the connections to removed ones.
+//
+// Returns the last dial error, if any.
+func (c *ResolvedClient) update(ctx context.Context, addrs []Address) error {
+	want := make(map[string]struct{}, len(addrs))
+	for _, addr := range addrs {
+		want[addr.Addr] = struct{}{}
+	}
+	c.mtx.Lock()
+	var toDial []string
+	for addr := range want {
+		if _, ok := c.conns[addr]; !ok {
+			toDial = append(toDial, addr)
+		}
+	}
+	c.mtx.Unlock()
+
+	// dial without holding the lock
+	var dialErr error
+	dialed := make(map[string]*resolvedConn, len(toDial))
+	for _, addr := range toDial {
+		client, closer, err := c.dial(ctx, addr)
+		if err != nil {
+			dialErr = err
+			continue
+		}
+		dialed[addr] = &resolvedConn{client: client, closer: closer}
+	}
+
+	c.mtx.Lock()
+	if c.closed {
+		c.mtx.Unlock()
+		for _, conn := range dialed {
+			_ = conn.closer.Close()
+		}
+		return context.Canceled
+	}
+	var removed []*resolvedConn
+	for addr, conn := range c.conns {
+		if _, ok := want[addr]; !ok {
+			removed = append(removed, conn)
+			delete(c.conns, addr)
+		}
+	}
+	for addr, conn := range dialed {
+		c.conns[addr] = conn
+	}
+	c.balanced = nil
+	if len(c.conns) != 0 {
+		sorted := make([]string, 0, len(c.conns))
+		for addr := range c.conns {
+			sorted = append(sorted, addr)
+		}
+		sort.Strings(sorted)
+		clients := make([]Client, len(sorted))
+		for i, addr := range sorted {
+			clients[i] = c.conns[addr].client
+		}
+		c.balanced = c.balancer(clients...)
+	}
+	c.mtx.Unlock()
+
+	for _, conn := range removed {
+		_ = conn.closer.Close()
+	}
+	return dialErr
+}
+
+// _ is a type assertion
+var (
+	_ Client             = ((*ResolvedClient)(nil))
+	_ BackendStatsGetter = ((*ResolvedClient)(nil))
+)
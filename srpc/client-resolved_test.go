@@ -0,0 +1,120 @@
+package srpc_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// staticResolver resolves to the addresses set with update.
+type staticResolver struct {
+	mtx   sync.Mutex
+	addrs []srpc.Address
+	cbCh  chan func(addrs []srpc.Address)
+}
+
+func (r *staticResolver) Resolve(ctx context.Context, endpoint string) ([]srpc.Address, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.addrs, nil
+}
+
+func (r *staticResolver) Watch(ctx context.Context, endpoint string, cb func(addrs []srpc.Address)) error {
+	r.cbCh <- cb
+	<-ctx.Done()
+	return context.Canceled
+}
+
+// closerFunc implements io.Closer with a func.
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+func TestParseTarget(t *testing.T) {
+	for _, tc := range []struct {
+		target, scheme, endpoint string
+	}{
+		{"dns:///example.com:443", "dns", "example.com:443"},
+		{"dns://8.8.8.8/example.com:443", "dns", "example.com:443"},
+		{"example.com:443", "passthrough", "example.com:443"},
+	} {
+		if scheme, endpoint := srpc.ParseTarget(tc.target); scheme != tc.scheme || endpoint != tc.endpoint {
+			t.Fatalf("%s: expected %s %s got %s %s", tc.target, tc.scheme, tc.endpoint, scheme, endpoint)
+		}
+	}
+
+	addrs, err := (&srpc.DNSResolver{}).Resolve(context.Background(), "127.0.0.1:5000")
+	if err != nil || len(addrs) != 1 || addrs[0].Addr != "127.0.0.1:5000" {
+		t.Fatalf("unexpected addrs %v: %v", addrs, err)
+	}
+	if _, _, _, err := srpc.ResolveTarget(context.Background(), "unknown:///host:1"); err == nil {
+		t.Fatal("expected error for unknown scheme")
+	}
+}
+
+func TestResolvedClient(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	resolver := &staticResolver{
+		addrs: []srpc.Address{{Addr: "a"}, {Addr: "b"}},
+		cbCh:  make(chan func(addrs []srpc.Address), 1),
+	}
+	srpc.RegisterResolver("static-test", resolver)
+
+	var mtx sync.Mutex
+	closed := make(map[string]bool)
+	dial := func(ctx context.Context, addr string) (srpc.Client, io.Closer, error) {
+		return srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))), closerFunc(func() error {
+			mtx.Lock()
+			closed[addr] = true
+			mtx.Unlock()
+			return nil
+		}), nil
+	}
+	ctx := context.Background()
+	client, err := srpc.NewResolvedClient(ctx, "static-test:///svc", dial, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer client.Close()
+	if addrs := client.GetAddrs(); !reflect.DeepEqual(addrs, []string{"a", "b"}) {
+		t.Fatalf("unexpected addrs %v", addrs)
+	}
+
+	// expect the calls to be balanced across the addresses
+	echoClient := echo.NewSRPCEchoerClient(client)
+	for i := 0; i < 4; i++ {
+		if _, err := echoClient.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	for _, stats := range client.GetBackendStats() {
+		if stats.Successes != 2 {
+			t.Fatalf("expected 2 calls per address got %#v", stats)
+		}
+	}
+
+	// expect removed addresses to be closed and new ones dialed
+	cb := <-resolver.cbCh
+	cb([]srpc.Address{{Addr: "b"}, {Addr: "c"}})
+	if addrs := client.GetAddrs(); !reflect.DeepEqual(addrs, []string{"b", "c"}) {
+		t.Fatalf("unexpected addrs %v", addrs)
+	}
+	mtx.Lock()
+	if !closed["a"] || closed["b"] {
+		t.Fatalf("expected only a to be closed: %v", closed)
+	}
+	mtx.Unlock()
+	if _, err := echoClient.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+}
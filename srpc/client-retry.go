@@ -0,0 +1,177 @@
+package srpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Defaults for the zero fields of RetryPolicy.
+const (
+	// DefaultRetryMaxAttempts is the default max number of attempts.
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryInitialBackoff is the default delay before the first retry.
+	DefaultRetryInitialBackoff = time.Millisecond * 100
+	// DefaultRetryMaxBackoff is the default max delay between attempts.
+	DefaultRetryMaxBackoff = time.Second * 5
+	// DefaultRetryMultiplier is the default backoff growth factor.
+	DefaultRetryMultiplier = 2
+)
+
+// RetryPolicy configures the retries of a RetryingClient.
+//
+// Zero fields use the defaults. Only unary calls are retried by default: the
+// handlers of streams may have received messages before the stream failed.
+type RetryPolicy struct {
+	// MaxAttempts is the max number of attempts including the first.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff is the max delay between attempts.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows by after each retry.
+	Multiplier float64
+	// Jitter is the fraction of the delay to randomly subtract, from 0 to 1.
+	//
+	// Spreads out the retries of many clients failing at once.
+	Jitter float64
+	// Backoff computes the delay before each retry, if set.
+	//
+	// Overrides InitialBackoff, MaxBackoff, Multiplier and Jitter.
+	Backoff Backoff
+	// Clock is the time source of the retry timers.
+	//
+	// Defaults to SystemClock.
+	Clock Clock
+	// Retryable checks if a failed call may be retried.
+	//
+	// Defaults to IsRetryableError. Return false for non-idempotent methods.
+	Retryable func(service, method string, err error) bool
+	// RetryStreams retries starting streams if NewStream fails.
+	//
+	// Streams which were started are never retried.
+	RetryStreams bool
+}
+
+// backoff returns the delay before the retry after the attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	if p.Backoff != nil {
+		return p.Backoff.Backoff(attempt)
+	}
+	b := ExponentialBackoff{
+		InitialBackoff: p.InitialBackoff,
+		MaxBackoff:     p.MaxBackoff,
+		Multiplier:     p.Multiplier,
+		Jitter:         p.Jitter,
+	}
+	return b.Backoff(attempt)
+}
+
+// retryable checks if the call may be retried after err.
+func (p *RetryPolicy) retryable(service, method string, err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(service, method, err)
+	}
+	return IsRetryableError(err)
+}
+
+// retryableErrors are the built-in errors which are retried by default.
+var retryableErrors = []error{
+	ErrUnavailable,
+	ErrDraining,
+	ErrTooManyCalls,
+	ErrTooManyStreams,
+	ErrOverloaded,
+	ErrHeartbeatTimeout,
+	ErrStreamClosed,
+	ErrConnReset,
+}
+
+// IsRetryableError checks if the error is a transient transport or server error.
+//
+// Returns true for the built-in errors of an unavailable, draining or
+// overloaded server, closed streams and network errors.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, rerr := range retryableErrors {
+		if err.Error() == rerr.Error() {
+			return true
+		}
+	}
+	var netErr net.Error
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
+
+// RetryingClient is a Client which retries failed calls with a RetryPolicy.
+type RetryingClient struct {
+	// client is the underlying client
+	client Client
+	// policy is the retry policy
+	policy RetryPolicy
+}
+
+// NewRetryingClient constructs a RetryingClient.
+//
+// If policy is nil, uses the defaults.
+func NewRetryingClient(client Client, policy *RetryPolicy) *RetryingClient {
+	c := &RetryingClient{client: client}
+	if policy != nil {
+		c.policy = *policy
+	}
+	if c.policy.MaxAttempts <= 0 {
+		c.policy.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	return c
+}
+
+// Invoke executes a unary RPC with the remote, retrying transient errors.
+//
+// Returns the error of the last attempt.
+func (c *RetryingClient) Invoke(ctx context.Context, service, method string, in, out Message) error {
+	return c.retry(ctx, service, method, func() error {
+		return c.client.Invoke(ctx, service, method, in, out)
+	})
+}
+
+// NewStream starts a streaming RPC with the remote.
+//
+// Retries starting the stream if RetryStreams is set.
+func (c *RetryingClient) NewStream(ctx context.Context, service, method string, firstMsg Message) (Stream, error) {
+	if !c.policy.RetryStreams {
+		return c.client.NewStream(ctx, service, method, firstMsg)
+	}
+	var strm Stream
+	err := c.retry(ctx, service, method, func() error {
+		var err error
+		strm, err = c.client.NewStream(ctx, service, method, firstMsg)
+		return err
+	})
+	return strm, err
+}
+
+// Ping calls the built-in ping service with the remote without retries.
+func (c *RetryingClient) Ping(ctx context.Context) (time.Duration, error) {
+	return c.client.Ping(ctx)
+}
+
+// retry calls fn until it succeeds, fails with a non-retryable error or the
+// attempts are exhausted.
+func (c *RetryingClient) retry(ctx context.Context, service, method string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= c.policy.MaxAttempts || !c.policy.retryable(service, method, err) {
+			return err
+		}
+		if !waitBackoff(ctx, c.policy.Clock, c.policy.backoff(attempt)) {
+			return err
+		}
+	}
+}
+
+// _ is a type assertion
+var _ Client = ((*RetryingClient)(nil))
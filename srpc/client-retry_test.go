@@ -0,0 +1,102 @@
+package srpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// flakyClient fails the first calls with err.
+type flakyClient struct {
+	srpc.Client
+	// err is returned by the failing calls
+	err error
+	// failures is the number of calls left to fail
+	failures int
+	// calls is the number of calls
+	calls int
+}
+
+func (c *flakyClient) Invoke(ctx context.Context, service, method string, in, out srpc.Message) error {
+	c.calls++
+	if c.failures > 0 {
+		c.failures--
+		return c.err
+	}
+	return c.Client.Invoke(ctx, service, method, in, out)
+}
+
+func (c *flakyClient) NewStream(ctx context.Context, service, method string, firstMsg srpc.Message) (srpc.Stream, error) {
+	c.calls++
+	if c.failures > 0 {
+		c.failures--
+		return nil, c.err
+	}
+	return c.Client.NewStream(ctx, service, method, firstMsg)
+}
+
+func TestRetryingClient(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx := context.Background()
+	policy := &srpc.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Jitter: 0.5}
+	newClient := func(err error, failures int) *flakyClient {
+		return &flakyClient{Client: srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))), err: err, failures: failures}
+	}
+
+	// expect transient errors to be retried
+	flaky := newClient(srpc.ErrUnavailable, 2)
+	client := echo.NewSRPCEchoerClient(srpc.NewRetryingClient(flaky, policy))
+	if resp, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil || resp.GetBody() != "hello" {
+		t.Fatalf("expected response got %v: %v", resp, err)
+	}
+	if flaky.calls != 3 {
+		t.Fatalf("expected 3 attempts got %d", flaky.calls)
+	}
+
+	// expect the last error after the max attempts
+	flaky = newClient(srpc.ErrUnavailable, 5)
+	client = echo.NewSRPCEchoerClient(srpc.NewRetryingClient(flaky, policy))
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != srpc.ErrUnavailable || flaky.calls != 3 {
+		t.Fatalf("expected %v after 3 attempts got %v after %d", srpc.ErrUnavailable, err, flaky.calls)
+	}
+
+	// expect other errors not to be retried
+	flaky = newClient(errors.New("not found"), 1)
+	client = echo.NewSRPCEchoerClient(srpc.NewRetryingClient(flaky, policy))
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err == nil || flaky.calls != 1 {
+		t.Fatalf("expected error after 1 attempt got %v after %d", err, flaky.calls)
+	}
+
+	// expect streams not to be retried by default
+	flaky = newClient(srpc.ErrUnavailable, 1)
+	client = echo.NewSRPCEchoerClient(srpc.NewRetryingClient(flaky, policy))
+	if _, err := client.EchoServerStream(ctx, &echo.EchoMsg{Body: "hello"}); err != srpc.ErrUnavailable {
+		t.Fatalf("expected %v got %v", srpc.ErrUnavailable, err)
+	}
+	streamPolicy := *policy
+	streamPolicy.RetryStreams = true
+	flaky = newClient(srpc.ErrUnavailable, 1)
+	client = echo.NewSRPCEchoerClient(srpc.NewRetryingClient(flaky, &streamPolicy))
+	strm, err := client.EchoServerStream(ctx, &echo.EchoMsg{Body: "hello"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	_ = strm.Close()
+}
+
+func TestRetryingClient_Canceled(t *testing.T) {
+	flaky := &flakyClient{err: srpc.ErrUnavailable, failures: 5}
+	client := srpc.NewRetryingClient(flaky, &srpc.RetryPolicy{InitialBackoff: time.Hour})
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer ctxCancel()
+	if err := client.Invoke(ctx, "test.Service", "Method", &echo.EchoMsg{}, nil); err != srpc.ErrUnavailable || flaky.calls != 1 {
+		t.Fatalf("expected %v after 1 attempt got %v after %d", srpc.ErrUnavailable, err, flaky.calls)
+	}
+}
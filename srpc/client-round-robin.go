@@ -0,0 +1,136 @@
+package srpc
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// roundRobinClient starts each call with the next Client in order.
+type roundRobinClient struct {
+	// next is the index of the next client
+	next uint32
+	// clients contains the clients to cycle through.
+	clients []*roundRobinEntry
+	// outlierDetection configures ejecting backends, may be nil.
+	outlierDetection *OutlierDetection
+}
+
+// roundRobinEntry is a client with the call outcomes.
+type roundRobinEntry struct {
+	// client is the client
+	client Client
+	// outcomes tracks the call outcomes.
+	outcomes backendOutcomes
+}
+
+// NewRoundRobinClient constructs a Client which starts each call and stream
+// with the next of the Clients in order.
+//
+// Draining Clients are skipped. The outcomes of the calls to each Client are
+// tracked, see GetBackendStats.
+func NewRoundRobinClient(clients ...Client) Client {
+	return NewRoundRobinClientWithOutlierDetection(nil, clients...)
+}
+
+// NewRoundRobinClientWithOutlierDetection constructs a round robin Client
+// which skips Clients with an error rate above the threshold.
+//
+// Ejected Clients are not used until the ejection ends, unless all Clients are
+// ejected. See NewRoundRobinClient and OutlierDetection.
+func NewRoundRobinClientWithOutlierDetection(conf *OutlierDetection, clients ...Client) Client {
+	entries := make([]*roundRobinEntry, len(clients))
+	for i, client := range clients {
+		entries[i] = &roundRobinEntry{client: client}
+	}
+	return &roundRobinClient{clients: entries, outlierDetection: conf}
+}
+
+// NewRoundRobinOpenStreamClient constructs a round robin Client with a Client
+// for each of the OpenStreamFunc.
+func NewRoundRobinOpenStreamClient(openStreams ...OpenStreamFunc) Client {
+	clients := make([]Client, len(openStreams))
+	for i, openStream := range openStreams {
+		clients[i] = NewClient(openStream)
+	}
+	return NewRoundRobinClient(clients...)
+}
+
+// Invoke executes a unary RPC with the next Client.
+func (c *roundRobinClient) Invoke(ctx context.Context, service, method string, in, out Message) error {
+	entry := c.pick()
+	if entry == nil {
+		return ErrNoAvailableClients
+	}
+	err := entry.client.Invoke(ctx, service, method, in, out)
+	entry.outcomes.record(ctx, err, c.outlierDetection)
+	return err
+}
+
+// NewStream starts a streaming RPC with the next Client.
+func (c *roundRobinClient) NewStream(ctx context.Context, service, method string, firstMsg Message) (Stream, error) {
+	entry := c.pick()
+	if entry == nil {
+		return nil, ErrNoAvailableClients
+	}
+	strm, err := entry.client.NewStream(ctx, service, method, firstMsg)
+	entry.outcomes.record(ctx, err, c.outlierDetection)
+	return strm, err
+}
+
+// Ping calls the built-in ping service with the next Client.
+func (c *roundRobinClient) Ping(ctx context.Context) (time.Duration, error) {
+	return pingClient(ctx, c)
+}
+
+// GetActiveStreams returns the number of active streams for each Client.
+func (c *roundRobinClient) GetActiveStreams() int {
+	var n int
+	for _, entry := range c.clients {
+		n += GetActiveStreams(entry.client)
+	}
+	return n
+}
+
+// GetBackendStats returns the stats for each Client.
+func (c *roundRobinClient) GetBackendStats() []*BackendStats {
+	now := time.Now()
+	stats := make([]*BackendStats, len(c.clients))
+	for i, entry := range c.clients {
+		stats[i] = entry.outcomes.snapshot(entry.client, now)
+	}
+	return stats
+}
+
+// pick returns the next entry which is not draining or ejected.
+//
+// Ejected entries are used only if all entries are ejected.
+func (c *roundRobinClient) pick() *roundRobinEntry {
+	n := len(c.clients)
+	if n == 0 {
+		return nil
+	}
+	now := time.Now()
+	start := int((atomic.AddUint32(&c.next, 1) - 1) % uint32(n))
+	var fallback *roundRobinEntry
+	for i := 0; i < n; i++ {
+		entry := c.clients[(start+i)%n]
+		if IsClientDraining(entry.client) {
+			continue
+		}
+		if !entry.outcomes.isEjected(now) {
+			return entry
+		}
+		if fallback == nil {
+			fallback = entry
+		}
+	}
+	return fallback
+}
+
+// _ is a type assertion
+var (
+	_ Client              = ((*roundRobinClient)(nil))
+	_ ActiveStreamsGetter = ((*roundRobinClient)(nil))
+	_ BackendStatsGetter  = ((*roundRobinClient)(nil))
+)
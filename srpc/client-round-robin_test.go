@@ -0,0 +1,62 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestRoundRobinClient(t *testing.T) {
+	ctx := context.Background()
+	msg := &echo.EchoMsg{Body: "hello world"}
+
+	// construct a failing and two healthy servers
+	var openStreams []srpc.OpenStreamFunc
+	for _, failing := range []int32{0, 1, 0} {
+		mux := srpc.NewMux()
+		echoServer := &failingEchoServer{EchoServer: echo.NewEchoServer(mux), failing: failing}
+		if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+			t.Fatal(err.Error())
+		}
+		openStreams = append(openStreams, srpc.NewServerPipe(srpc.NewServer(mux)))
+	}
+	var clients []srpc.Client
+	for _, openStream := range openStreams {
+		clients = append(clients, srpc.NewClient(openStream))
+	}
+
+	// expect the calls to be spread evenly
+	pool := srpc.NewRoundRobinOpenStreamClient(openStreams...)
+	client := echo.NewSRPCEchoerClient(pool)
+	for i := 0; i < 6; i++ {
+		_, _ = client.Echo(ctx, msg)
+	}
+	for i, stats := range srpc.GetBackendStats(pool) {
+		if stats.Successes+stats.Failures != 2 {
+			t.Fatalf("expected 2 calls to server %d: %#v", i, stats)
+		}
+	}
+
+	// expect the failing server to be skipped once ejected
+	pool = srpc.NewRoundRobinClientWithOutlierDetection(&srpc.OutlierDetection{
+		MaxErrorRate: 0.5,
+		MinRequests:  2,
+		EjectionTime: time.Minute,
+	}, clients...)
+	client = echo.NewSRPCEchoerClient(pool)
+	var failed int
+	for i := 0; i < 12; i++ {
+		if _, err := client.Echo(ctx, msg); err != nil {
+			failed++
+		}
+	}
+	if failed != 2 {
+		t.Fatalf("expected 2 failed calls got %d", failed)
+	}
+	if stats := srpc.GetBackendStats(pool); !stats[1].Ejected || stats[0].Successes+stats[2].Successes != 10 {
+		t.Fatalf("expected failing server to be ejected: %#v", stats)
+	}
+}
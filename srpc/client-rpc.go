@@ -3,6 +3,9 @@ package srpc
 import (
 	"context"
 	"io"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -30,6 +33,69 @@ type ClientRPC struct {
 	// before dataCh is closed, managed by HandlePacket.
 	// immutable after dataCh is closed.
 	serverErr error
+	// trace contains the client trace hooks, if set.
+	trace *ClientTrace
+	// gotFirstByte is set after the first packet was received.
+	// controlled by HandlePacket.
+	gotFirstByte bool
+	// completeOnce guards calling the Complete trace hook and the stats.
+	completeOnce sync.Once
+	// peerCanceled is closed when the remote cancels the call.
+	// controlled by HandlePacket.
+	peerCanceled chan struct{}
+	// doneCh is closed when the call is done.
+	// the remote is not sent CallCancel after doneCh is closed.
+	doneCh chan struct{}
+	// doneOnce guards closing doneCh.
+	doneOnce sync.Once
+	// progress is called with keepalive progress from the server.
+	// may be nil
+	progress func(progress Metadata)
+	// onErrorDetails is called with error details from the server.
+	// may be nil
+	onErrorDetails func(details []string)
+	// errorDetails contains the error details sent by the server.
+	// controlled by HandlePacket.
+	errorDetails []string
+	// responseMetadata is called with the response metadata from the server.
+	// may be nil
+	responseMetadata func(md Metadata)
+	// drain is called when the server sends a Drain packet.
+	// may be nil
+	drain func(pkt *Drain)
+	// loadReport is called with the load report from the server.
+	// may be nil
+	loadReport func(report *LoadReport)
+	// metadata is set with WithMetadata and overrides the outgoing metadata.
+	// may be nil
+	metadata Metadata
+	// compression is the compression algorithm for the call.
+	compression string
+	// codec is the name of the codec for the call.
+	codec string
+	// waitForReady retries opening the stream until the transport is ready.
+	waitForReady bool
+	// maxRecvSize is the max size of a received message, if set.
+	maxRecvSize int
+	// compressor compresses the messages, if set.
+	compressor Compressor
+	// heartbeat pings the server, if set.
+	heartbeat *heartbeat
+	// writeMtx serializes writing packets.
+	writeMtx sync.Mutex
+	// signalMtx guards signalHandler.
+	signalMtx sync.Mutex
+	// signalHandler is called with signals from the server.
+	// may be nil
+	signalHandler func(sig *Signal)
+	// stats receives the stats of the call, if set.
+	stats StatsHandler
+	// start is the time the call started, if stats is set.
+	start time.Time
+	// bytesRead is the size of the packets received, if stats is set.
+	bytesRead uint64
+	// bytesWritten is the size of the packets written, if stats is set.
+	bytesWritten uint64
 }
 
 // NewClientRPC constructs a new ClientRPC session and writes CallStart.
@@ -37,12 +103,36 @@ type ClientRPC struct {
 // service and method must be specified.
 // must call Start after creating the RPC object.
 func NewClientRPC(ctx context.Context, service, method string) *ClientRPC {
+	opts := getCallOptions(ctx)
+	ctx = withoutCallOptions(ctx)
 	rpc := &ClientRPC{
-		service: service,
-		method:  method,
-		dataCh:  make(chan []byte, 5),
+		service:          service,
+		method:           method,
+		dataCh:           make(chan []byte, 5),
+		trace:            ContextClientTrace(ctx),
+		peerCanceled:     make(chan struct{}),
+		doneCh:           make(chan struct{}),
+		progress:         opts.progress,
+		onErrorDetails:   opts.errorDetails,
+		responseMetadata: opts.responseMetadata,
+		compression:      opts.compression,
+		codec:            opts.codec,
+		heartbeat:        newHeartbeat(opts.heartbeat),
+		signalHandler:    opts.signalHandler,
+		waitForReady:     opts.waitForReady,
+		maxRecvSize:      opts.maxRecvSize,
+		metadata:         opts.metadata,
+		stats:            ContextClientStatsHandler(ctx),
+	}
+	if opts.timeout > 0 {
+		rpc.ctx, rpc.ctxCancel = context.WithTimeout(ctx, opts.timeout)
+	} else {
+		rpc.ctx, rpc.ctxCancel = context.WithCancel(ctx)
+	}
+	if rpc.stats != nil {
+		rpc.start = time.Now()
+		rpc.stats.HandleRPCBegin(ctx, &RPCBegin{Client: true, ServiceID: service, MethodID: method, Start: rpc.start})
 	}
-	rpc.ctx, rpc.ctxCancel = context.WithCancel(ctx)
 	return rpc
 }
 
@@ -52,9 +142,23 @@ func (r *ClientRPC) Start(writer Writer, writeFirstMsg bool, firstMsg []byte) er
 	select {
 	case <-r.ctx.Done():
 		r.Close()
+		r.traceComplete(context.Canceled)
 		return context.Canceled
 	default:
 	}
+	comp, err := lookupCompressor(r.compression)
+	if err != nil {
+		r.Close()
+		r.traceComplete(err)
+		return err
+	}
+	if r.stats != nil {
+		writer = &statsWriter{Writer: writer, written: &r.bytesWritten}
+	}
+	if comp != nil {
+		writer = newCompressWriter(writer, comp)
+		r.compressor = comp
+	}
 	r.writer = writer
 	var firstMsgEmpty bool
 	if writeFirstMsg {
@@ -62,46 +166,139 @@ func (r *ClientRPC) Start(writer Writer, writeFirstMsg bool, firstMsg []byte) er
 	} else {
 		firstMsg = nil
 	}
+	mdCtx := r.ctx
+	if len(r.metadata) != 0 {
+		// the call metadata does not apply to the calls made with r.ctx.
+		md := MetadataFromOutgoingContext(mdCtx).Clone()
+		if md == nil {
+			md = make(Metadata, len(r.metadata))
+		}
+		for k, v := range r.metadata {
+			md[k] = v
+		}
+		mdCtx = NewContextWithMetadata(mdCtx, md)
+	}
+	md, err := buildOutgoingMetadata(mdCtx)
+	if err != nil {
+		r.Close()
+		r.traceComplete(err)
+		return err
+	}
 	pkt := NewCallStartPacket(r.service, r.method, firstMsg, firstMsgEmpty)
-	if err := writer.WritePacket(pkt); err != nil {
+	pkt.GetCallStart().Metadata = md
+	pkt.GetCallStart().Compression = r.compression
+	pkt.GetCallStart().Codec = r.codec
+	pkt.GetCallStart().PingSupported = true
+	pkt.GetCallStart().Heartbeat = r.heartbeat != nil
+	pkt.GetCallStart().SignalsSupported = true
+	// the remote may send Ping packets as soon as it receives CallStart.
+	r.writeMtx.Lock()
+	err = writer.WritePacket(pkt)
+	r.writeMtx.Unlock()
+	if err != nil {
 		r.Close()
+		r.traceComplete(err)
 		return err
 	}
+	r.trace.streamOpened()
+	go r.watchCancel()
+	if r.heartbeat != nil {
+		go r.heartbeat.run(r.writeControlPacket, func() { _ = writer.Close() }, r.doneCh)
+	}
 	return nil
 }
 
+// watchCancel sends CallCancel to the remote if the context is canceled
+// before the call is done.
+func (r *ClientRPC) watchCancel() {
+	select {
+	case <-r.doneCh:
+		return
+	case <-r.ctx.Done():
+	}
+	select {
+	case <-r.doneCh:
+		return
+	default:
+	}
+	r.markDone()
+	// the stream may be sending a message concurrently.
+	r.writeMtx.Lock()
+	_ = r.writer.WritePacket(NewCallCancelPacket())
+	r.writeMtx.Unlock()
+	_ = r.writer.Close()
+}
+
+// markDone marks the call as done.
+func (r *ClientRPC) markDone() {
+	r.doneOnce.Do(func() {
+		close(r.doneCh)
+	})
+}
+
 // ReadAll reads all returned Data packets and returns any error.
 // intended for use with unary rpcs.
 func (r *ClientRPC) ReadAll() ([][]byte, error) {
 	msgs := make([][]byte, 0, 1)
 	for {
-		select {
-		case <-r.ctx.Done():
-			return msgs, context.Canceled
-		case data, ok := <-r.dataCh:
-			if !ok {
-				return msgs, r.serverErr
+		data, err := r.ReadOne()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
 			}
-			msgs = append(msgs, data)
+			return msgs, err
 		}
+		msgs = append(msgs, data)
 	}
 }
 
 // ReadOne reads a single message and returns.
 //
 // returns io.EOF if the stream ended.
+// Messages and the result received before the context was canceled are
+// returned before the cancellation error.
 func (r *ClientRPC) ReadOne() ([]byte, error) {
 	select {
+	case data, ok := <-r.dataCh:
+		return r.handleRead(data, ok)
 	case <-r.ctx.Done():
-		return nil, context.Canceled
+	}
+	select {
 	case data, ok := <-r.dataCh:
-		if !ok {
-			if err := r.serverErr; err != nil {
-				return nil, err
+		return r.handleRead(data, ok)
+	default:
+	}
+	if err := r.heartbeat.err(); err != nil {
+		return nil, err
+	}
+	return nil, context.Canceled
+}
+
+// handleRead returns the result of a read from dataCh.
+func (r *ClientRPC) handleRead(data []byte, ok bool) ([]byte, error) {
+	if !ok {
+		if err := r.serverErr; err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return data, nil
+}
+
+// waitComplete waits for the remote to complete the call after the response.
+//
+// The final packet contains the load report, if any.
+// Returns the error if the call did not complete successfully.
+func (r *ClientRPC) waitComplete() error {
+	for {
+		select {
+		case <-r.ctx.Done():
+			return nil
+		case _, ok := <-r.dataCh:
+			if !ok {
+				return r.serverErr
 			}
-			return nil, io.EOF
 		}
-		return data, nil
 	}
 }
 
@@ -121,27 +318,86 @@ func (r *ClientRPC) HandlePacketData(data []byte) error {
 }
 
 // HandleStreamClose handles the incoming stream closing w/ optional error.
+//
+// The result of the call is final once the completion packet was handled: the
+// close does not change it. If the stream closed before the completion packet,
+// completes the call with the close error or ErrStreamClosed, never io.EOF.
+// Completes calls canceled locally with context.Canceled.
 func (r *ClientRPC) HandleStreamClose(closeErr error) {
-	if closeErr != nil {
+	if r.heartbeat.timedOut() {
+		closeErr = ErrHeartbeatTimeout
+	}
+	if !r.dataChClosed {
 		if r.serverErr == nil {
-			r.serverErr = closeErr
+			switch {
+			case closeErr == ErrHeartbeatTimeout:
+				r.serverErr = closeErr
+			case r.ctx.Err() != nil:
+				// the call was canceled locally
+				r.serverErr = context.Canceled
+			case closeErr != nil:
+				r.serverErr = closeErr
+			default:
+				r.serverErr = ErrStreamClosed
+			}
 		}
+		r.dataChClosed = true
+		close(r.dataCh)
+	}
+	if closeErr != nil {
 		r.Close()
 	}
+	r.markDone()
+	r.traceComplete(r.serverErr)
 }
 
 // HandlePacket handles an incoming parsed message packet.
 // Not concurrency safe: use a mutex if calling concurrently.
 func (r *ClientRPC) HandlePacket(msg *Packet) error {
+	err := r.handlePacket(msg)
+	if r.stats != nil && isProtocolError(err) {
+		handleProtocolError(r.ctx, r.stats, &ProtocolError{
+			Client:    true,
+			ServiceID: r.service,
+			MethodID:  r.method,
+			Err:       err,
+		})
+	}
+	return err
+}
+
+// handlePacket handles an incoming parsed message packet.
+func (r *ClientRPC) handlePacket(msg *Packet) error {
 	if err := msg.Validate(); err != nil {
 		return err
 	}
+	r.heartbeat.touch()
+	if r.stats != nil {
+		atomic.AddUint64(&r.bytesRead, uint64(msg.SizeVT()))
+	}
 
 	switch b := msg.GetBody().(type) {
 	case *Packet_CallStart:
 		return r.HandleCallStart(b.CallStart)
 	case *Packet_CallData:
 		return r.HandleCallData(b.CallData)
+	case *Packet_CallCancel:
+		return r.HandleCallCancel()
+	case *Packet_Drain:
+		return r.HandleDrain(b.Drain)
+	case *Packet_Ping:
+		// write from a goroutine: the remote may be blocked writing to us.
+		go func() {
+			_ = r.writeControlPacket(NewPongPacket(b.Ping))
+		}()
+		return nil
+	case *Packet_Pong:
+		// the server acknowledged the heartbeat
+		r.heartbeat.enable()
+		return nil
+	case *Packet_Signal:
+		r.handleSignal(b.Signal)
+		return nil
 	default:
 		return nil
 	}
@@ -159,7 +415,38 @@ func (r *ClientRPC) HandleCallData(pkt *CallData) error {
 		return ErrCompleted
 	}
 
+	if !r.gotFirstByte {
+		r.gotFirstByte = true
+		r.trace.firstByte()
+	}
+
+	if progress := pkt.GetProgress(); len(progress) != 0 && r.progress != nil {
+		r.progress(Metadata(progress))
+	}
+
+	if details := pkt.GetErrorDetails(); len(details) != 0 {
+		r.errorDetails = append(r.errorDetails, details...)
+		if r.onErrorDetails != nil {
+			r.onErrorDetails(details)
+		}
+	}
+
+	if report := pkt.GetLoadReport(); report != nil && r.loadReport != nil {
+		r.loadReport(report)
+	}
+
+	if md := pkt.GetMetadata(); len(md) != 0 && r.responseMetadata != nil {
+		r.responseMetadata(Metadata(md))
+	}
+
 	if data := pkt.GetData(); len(data) != 0 || pkt.GetDataIsZero() {
+		data, err := decompressData(r.compressor, data, pkt.GetDataCompressed())
+		if err != nil {
+			return err
+		}
+		if r.maxRecvSize > 0 && len(data) > r.maxRecvSize {
+			return ErrRecvMsgTooLarge
+		}
 		select {
 		case <-r.ctx.Done():
 			return context.Canceled
@@ -170,20 +457,174 @@ func (r *ClientRPC) HandleCallData(pkt *CallData) error {
 	complete := pkt.GetComplete()
 	if err := pkt.GetError(); len(err) != 0 {
 		complete = true
-		r.serverErr = errors.New(err)
+		if st := pkt.GetStatus(); st != nil {
+			r.serverErr = &StatusError{Status: st}
+		} else {
+			r.serverErr = errors.New(err)
+		}
+		if len(r.errorDetails) != 0 {
+			r.serverErr = NewDetailedError(r.serverErr, r.errorDetails...)
+		}
 	}
 
 	if complete {
 		r.dataChClosed = true
 		close(r.dataCh)
+		r.markDone()
+		r.traceComplete(r.serverErr)
 	}
 
 	return nil
 }
 
+// closedErr returns the error which completed the call after dataCh is closed.
+//
+// Returns nil if the call completed successfully.
+func (r *ClientRPC) closedErr() error {
+	if r.heartbeat != nil {
+		if err := r.heartbeat.err(); err != nil {
+			return err
+		}
+	}
+	return r.serverErr
+}
+
+// HandleCallCancel handles the call cancel packet.
+func (r *ClientRPC) HandleCallCancel() error {
+	if r.dataChClosed {
+		return nil
+	}
+	r.serverErr = ErrCanceledByPeer
+	close(r.peerCanceled)
+	r.dataChClosed = true
+	close(r.dataCh)
+	r.markDone()
+	r.traceComplete(r.serverErr)
+	return nil
+}
+
+// HandleDrain handles the drain packet.
+//
+// Open calls continue until they complete. If the server rejected the call,
+// completes the call with ErrDraining.
+func (r *ClientRPC) HandleDrain(pkt *Drain) error {
+	if r.drain != nil {
+		r.drain(pkt)
+	}
+	if !pkt.GetCallRejected() || r.dataChClosed {
+		return nil
+	}
+	r.serverErr = ErrDraining
+	r.dataChClosed = true
+	close(r.dataCh)
+	r.markDone()
+	r.traceComplete(r.serverErr)
+	return nil
+}
+
+// handleSignal calls the signal handler, if set.
+func (r *ClientRPC) handleSignal(sig *Signal) {
+	r.signalMtx.Lock()
+	cb := r.signalHandler
+	r.signalMtx.Unlock()
+	if cb != nil {
+		cb(sig)
+	}
+}
+
+// sendSignal writes a signal packet.
+func (r *ClientRPC) sendSignal(sig *Signal) error {
+	return r.writeControlPacket(&Packet{Body: &Packet_Signal{Signal: sig}})
+}
+
+// setSignalHandler sets the callback for signals from the server.
+func (r *ClientRPC) setSignalHandler(cb func(sig *Signal)) {
+	r.signalMtx.Lock()
+	r.signalHandler = cb
+	r.signalMtx.Unlock()
+}
+
+// writeControlPacket writes a packet if the call is not done yet.
+func (r *ClientRPC) writeControlPacket(pkt *Packet) error {
+	r.writeMtx.Lock()
+	defer r.writeMtx.Unlock()
+	select {
+	case <-r.doneCh:
+		return ErrCompleted
+	default:
+	}
+	return r.writer.WritePacket(pkt)
+}
+
+// clientRPCWriter writes the packets of the stream with the write lock held.
+//
+// The packets are not interleaved with the control packets written by other
+// goroutines.
+type clientRPCWriter struct {
+	// r is the client rpc
+	r *ClientRPC
+}
+
+// WritePacket writes a packet to the remote.
+func (w *clientRPCWriter) WritePacket(p *Packet) error {
+	w.r.writeMtx.Lock()
+	defer w.r.writeMtx.Unlock()
+	return w.r.writer.WritePacket(p)
+}
+
+// WritePackets writes the packets to the remote in order.
+func (w *clientRPCWriter) WritePackets(pkts []*Packet) error {
+	w.r.writeMtx.Lock()
+	defer w.r.writeMtx.Unlock()
+	return writePackets(w.r.writer, pkts)
+}
+
+// Close closes the writer.
+func (w *clientRPCWriter) Close() error {
+	return w.r.writer.Close()
+}
+
+// Reset resets the writer if supported, otherwise closes it.
+func (w *clientRPCWriter) Reset() error {
+	return resetWriter(w.r.writer)
+}
+
 // Close releases any resources held by the ClientRPC.
 // not concurrency safe with HandlePacket.
 func (r *ClientRPC) Close() {
 	r.ctxCancel()
-	_ = r.writer.Close()
+	if r.writer != nil {
+		_ = r.writer.Close()
+	}
+}
+
+// traceComplete calls the Complete trace hook and the StatsHandler once.
+func (r *ClientRPC) traceComplete(err error) {
+	if (r.trace == nil || r.trace.Complete == nil) && r.stats == nil {
+		return
+	}
+	r.completeOnce.Do(func() {
+		if r.trace != nil && r.trace.Complete != nil {
+			r.trace.Complete(err)
+		}
+		if r.stats != nil {
+			r.stats.HandleRPCEnd(r.ctx, &StreamStats{
+				Client:       true,
+				ServiceID:    r.service,
+				MethodID:     r.method,
+				Start:        r.start,
+				Duration:     time.Since(r.start),
+				BytesRead:    atomic.LoadUint64(&r.bytesRead),
+				BytesWritten: atomic.LoadUint64(&r.bytesWritten),
+				Err:          err,
+			})
+		}
+	})
 }
+
+// _ is a type assertion
+var (
+	_ signalEndpoint = ((*ClientRPC)(nil))
+	_ BatchWriter    = ((*clientRPCWriter)(nil))
+	_ ResetWriter    = ((*clientRPCWriter)(nil))
+)
@@ -0,0 +1,185 @@
+package srpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// cancelingWriter cancels the call from the remote side when the call starts.
+type cancelingWriter struct {
+	// msgHandler handles packets from the remote
+	msgHandler srpc.PacketHandler
+}
+
+func TestClientSendAfterCancel(t *testing.T) {
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(func(
+		ctx context.Context,
+		msgHandler srpc.PacketHandler,
+		closeHandler srpc.CloseHandler,
+	) (srpc.Writer, error) {
+		return &cancelingWriter{msgHandler: msgHandler}, nil
+	}))
+
+	strm, err := client.EchoBidiStream(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := strm.Send(&echo.EchoMsg{Body: "hello world"}); err != srpc.ErrCanceledByPeer {
+		t.Fatalf("expected client send to return %v got %v", srpc.ErrCanceledByPeer, err)
+	}
+	if _, err := strm.Recv(); err != srpc.ErrCanceledByPeer {
+		t.Fatalf("expected client recv to return %v got %v", srpc.ErrCanceledByPeer, err)
+	}
+}
+
+// WritePacket writes a packet to the remote.
+func (w *cancelingWriter) WritePacket(pkt *srpc.Packet) error {
+	if pkt.GetCallStart() != nil {
+		return w.msgHandler(srpc.NewCallCancelPacket())
+	}
+	return nil
+}
+
+// Close closes the writer.
+func (w *cancelingWriter) Close() error {
+	return nil
+}
+
+// closingWriter completes the call with the packets and closes the stream with
+// closeErr when the call starts.
+type closingWriter struct {
+	// msgHandler handles packets from the remote
+	msgHandler srpc.PacketHandler
+	// closeHandler handles the remote closing the stream
+	closeHandler srpc.CloseHandler
+	// pkts are the packets sent by the remote
+	pkts []*srpc.Packet
+	// closeErr is passed to closeHandler
+	closeErr error
+}
+
+// newClosingClient constructs a client with a closingWriter.
+func newClosingClient(closeErr error, pkts ...*srpc.Packet) echo.SRPCEchoerClient {
+	return echo.NewSRPCEchoerClient(srpc.NewClient(func(
+		ctx context.Context,
+		msgHandler srpc.PacketHandler,
+		closeHandler srpc.CloseHandler,
+	) (srpc.Writer, error) {
+		return &closingWriter{msgHandler: msgHandler, closeHandler: closeHandler, pkts: pkts, closeErr: closeErr}, nil
+	}))
+}
+
+func TestClientCloseOrdering(t *testing.T) {
+	ctx := context.Background()
+	resp, err := (&echo.EchoMsg{Body: "hello"}).MarshalVT()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 0; i < 100; i++ {
+		// expect the result to be returned, not the close error
+		client := newClosingClient(io.ErrClosedPipe, srpc.NewCallDataPacket(resp, false, true, nil))
+		if out, err := client.Echo(ctx, &echo.EchoMsg{}); err != nil || out.GetBody() != "hello" {
+			t.Fatalf("expected response got %v: %v", out, err)
+		}
+		client = newClosingClient(io.ErrClosedPipe, srpc.NewCallDataPacket(nil, false, true, errors.New("final error")))
+		if _, err := client.Echo(ctx, &echo.EchoMsg{}); err == nil || err.Error() != "final error" {
+			t.Fatalf("expected final error got %v", err)
+		}
+
+		// expect the stream to end with EOF after the completion packet
+		client = newClosingClient(io.ErrClosedPipe, srpc.NewCallDataPacket(resp, false, false, nil), srpc.NewCallDataPacket(nil, false, true, nil))
+		strm, err := client.EchoServerStream(ctx, &echo.EchoMsg{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if msg, err := strm.Recv(); err != nil || msg.GetBody() != "hello" {
+			t.Fatalf("expected message got %v: %v", msg, err)
+		}
+		if _, err := strm.Recv(); err != io.EOF {
+			t.Fatalf("expected EOF got %v", err)
+		}
+	}
+
+	// expect a stream closed before the completion packet not to end with EOF
+	client := newClosingClient(nil, srpc.NewCallDataPacket(resp, false, false, nil))
+	strm, err := client.EchoServerStream(ctx, &echo.EchoMsg{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if msg, err := strm.Recv(); err != nil || msg.GetBody() != "hello" {
+		t.Fatalf("expected message got %v: %v", msg, err)
+	}
+	if _, err := strm.Recv(); err != srpc.ErrStreamClosed {
+		t.Fatalf("expected %v got %v", srpc.ErrStreamClosed, err)
+	}
+	if _, err := client.Echo(ctx, &echo.EchoMsg{}); err != srpc.ErrStreamClosed {
+		t.Fatalf("expected %v got %v", srpc.ErrStreamClosed, err)
+	}
+}
+
+// WritePacket writes a packet to the remote.
+func (w *closingWriter) WritePacket(pkt *srpc.Packet) error {
+	if pkt.GetCallStart() != nil {
+		go func() {
+			for _, pkt := range w.pkts {
+				_ = w.msgHandler(pkt)
+			}
+			w.closeHandler(w.closeErr)
+		}()
+	}
+	return nil
+}
+
+// Close closes the writer.
+func (w *closingWriter) Close() error {
+	return nil
+}
+
+func TestClientCancelConcurrentSend(t *testing.T) {
+	// the remote reads the packets slowly until the stream is closed
+	var conn *writeCheckConn
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(func(ctx context.Context, msgHandler srpc.PacketHandler, closeHandler srpc.CloseHandler) (srpc.Writer, error) {
+		remotePipe, localPipe := net.Pipe()
+		conn = &writeCheckConn{Conn: localPipe}
+		go readPackets(srpc.NewPacketReadWriter(remotePipe), func(pkt *srpc.Packet) bool {
+			<-time.After(time.Millisecond)
+			return true
+		})
+		prw := srpc.NewPacketReadWriter(conn)
+		go prw.ReadPump(msgHandler, closeHandler)
+		return prw, nil
+	}))
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	strm, err := client.EchoBidiStream(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		for {
+			if err := strm.Send(&echo.EchoMsg{Body: "hello"}); err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+	<-time.After(time.Millisecond * 5)
+	ctxCancel()
+	<-errCh
+	_ = strm.Close()
+
+	// expect the CallCancel packet to be serialized with the messages
+	if atomic.LoadInt32(&conn.concurrent) != 0 {
+		t.Fatal("expected the writes to the stream to be serialized")
+	}
+}
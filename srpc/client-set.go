@@ -0,0 +1,114 @@
+package srpc
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClientSet is a Client which starts calls with one of a set of Clients.
+//
+// Calls to services matching a route are started with the Client of the
+// longest matching service prefix. Unary calls to other services are tried
+// with each of the fallback Clients in order until one does not return
+// ErrUnimplemented. Streams to other services are started with the first
+// fallback Client.
+type ClientSet struct {
+	// mtx guards below fields
+	mtx sync.RWMutex
+	// routes maps service prefixes to clients
+	routes map[string]Client
+	// fallback contains the clients to probe for unrouted services
+	fallback []Client
+}
+
+// NewClientSet constructs a ClientSet with the fallback clients.
+func NewClientSet(fallback ...Client) *ClientSet {
+	return &ClientSet{
+		routes:   make(map[string]Client),
+		fallback: fallback,
+	}
+}
+
+// SetRoute routes calls to services starting with servicePrefix to the client.
+//
+// Use the full service ID to route a single service, or a package prefix such
+// as "echo." to route all services of a package. If client is nil, removes
+// the route.
+func (c *ClientSet) SetRoute(servicePrefix string, client Client) {
+	c.mtx.Lock()
+	if client == nil {
+		delete(c.routes, servicePrefix)
+	} else {
+		c.routes[servicePrefix] = client
+	}
+	c.mtx.Unlock()
+}
+
+// AddFallback adds a client to the end of the fallback clients.
+func (c *ClientSet) AddFallback(client Client) {
+	c.mtx.Lock()
+	c.fallback = append(c.fallback, client)
+	c.mtx.Unlock()
+}
+
+// Route returns the client with the longest prefix matching the service.
+//
+// Returns nil if no route matches the service.
+func (c *ClientSet) Route(service string) Client {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	var best Client
+	bestLen := -1
+	for prefix, client := range c.routes {
+		if len(prefix) > bestLen && strings.HasPrefix(service, prefix) {
+			best, bestLen = client, len(prefix)
+		}
+	}
+	return best
+}
+
+// getFallback returns a copy of the fallback clients.
+func (c *ClientSet) getFallback() []Client {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return append([]Client(nil), c.fallback...)
+}
+
+// Invoke executes a unary RPC with the Client for the service.
+func (c *ClientSet) Invoke(ctx context.Context, service, method string, in, out Message) error {
+	if client := c.Route(service); client != nil {
+		return client.Invoke(ctx, service, method, in, out)
+	}
+	err := error(ErrNoAvailableClients)
+	for _, client := range c.getFallback() {
+		err = client.Invoke(ctx, service, method, in, out)
+		if err == nil || err.Error() != ErrUnimplemented.Error() {
+			return err
+		}
+	}
+	return err
+}
+
+// NewStream starts a streaming RPC with the Client for the service.
+func (c *ClientSet) NewStream(ctx context.Context, service, method string, firstMsg Message) (Stream, error) {
+	client := c.Route(service)
+	if client == nil {
+		if fallback := c.getFallback(); len(fallback) != 0 {
+			client = fallback[0]
+		}
+	}
+	if client == nil {
+		return nil, ErrNoAvailableClients
+	}
+	return client.NewStream(ctx, service, method, firstMsg)
+}
+
+// Ping calls the built-in ping service with the Client for the ping service.
+func (c *ClientSet) Ping(ctx context.Context) (time.Duration, error) {
+	return pingClient(ctx, c)
+}
+
+// _ is a type assertion
+var _ Client = ((*ClientSet)(nil))
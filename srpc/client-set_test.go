@@ -0,0 +1,58 @@
+package srpc_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestClientSet(t *testing.T) {
+	ctx := context.Background()
+	msg := &echo.EchoMsg{Body: "hello world"}
+
+	// construct a server without the echo service and one with it
+	echoServer := &countingEchoServer{EchoServer: echo.NewEchoServer(nil)}
+	emptyClient := srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(srpc.NewMux())))
+	echoMux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(echoMux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	echoClient := srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(echoMux)))
+
+	// expect unrouted calls to fall through to the server with the service
+	set := srpc.NewClientSet(emptyClient, echoClient)
+	client := echo.NewSRPCEchoerClient(set)
+	if _, err := client.Echo(ctx, msg); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// expect routed calls to target the server directly
+	set.SetRoute("echo.", echoClient)
+	if set.Route(echo.SRPCEchoerServiceID) != echoClient {
+		t.Fatal("expected echo service to be routed to the echo server")
+	}
+	strm, err := client.EchoServerStream(ctx, msg)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != nil {
+		t.Fatal(err.Error())
+	}
+	_ = strm.Close()
+
+	// expect a longer prefix to take precedence
+	set.SetRoute(echo.SRPCEchoerServiceID, emptyClient)
+	if _, err := client.Echo(ctx, msg); err == nil || err.Error() != srpc.ErrUnimplemented.Error() {
+		t.Fatalf("expected %v got %v", srpc.ErrUnimplemented, err)
+	}
+	set.SetRoute(echo.SRPCEchoerServiceID, nil)
+	if _, err := client.Echo(ctx, msg); err != nil {
+		t.Fatal(err.Error())
+	}
+	if calls := atomic.LoadInt32(&echoServer.calls); calls != 2 {
+		t.Fatalf("expected 2 unary calls got %d", calls)
+	}
+}
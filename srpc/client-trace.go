@@ -0,0 +1,76 @@
+package srpc
+
+import "context"
+
+// ClientTrace contains hooks called at the phases of an outgoing call.
+//
+// Any of the hooks may be nil. Hooks may be called from any goroutine.
+type ClientTrace struct {
+	// ConnectStart is called before opening the stream for the call.
+	ConnectStart func(service, method string)
+	// ConnectDone is called after opening the stream with any error.
+	ConnectDone func(err error)
+	// StreamOpened is called after the CallStart packet was written.
+	StreamOpened func()
+	// FirstByte is called when the first response packet is received.
+	FirstByte func()
+	// Complete is called once when the call completes with any error.
+	Complete func(err error)
+	// MultiStatus is called after a unary call succeeded if the response has
+	// item statuses. Allows counting partially failed batch calls separately.
+	MultiStatus func(outcome MultiStatusOutcome)
+}
+
+// clientTraceCtxKey is the context key for the client trace.
+type clientTraceCtxKey struct{}
+
+// WithClientTrace attaches a ClientTrace to the context.
+//
+// The hooks are called for all calls made with the returned context.
+func WithClientTrace(ctx context.Context, trace *ClientTrace) context.Context {
+	return context.WithValue(ctx, clientTraceCtxKey{}, trace)
+}
+
+// ContextClientTrace returns the ClientTrace attached to the context or nil.
+func ContextClientTrace(ctx context.Context) *ClientTrace {
+	trace, _ := ctx.Value(clientTraceCtxKey{}).(*ClientTrace)
+	return trace
+}
+
+// connectStart calls the ConnectStart hook if set.
+func (t *ClientTrace) connectStart(service, method string) {
+	if t != nil && t.ConnectStart != nil {
+		t.ConnectStart(service, method)
+	}
+}
+
+// connectDone calls the ConnectDone hook if set.
+func (t *ClientTrace) connectDone(err error) {
+	if t != nil && t.ConnectDone != nil {
+		t.ConnectDone(err)
+	}
+}
+
+// streamOpened calls the StreamOpened hook if set.
+func (t *ClientTrace) streamOpened() {
+	if t != nil && t.StreamOpened != nil {
+		t.StreamOpened()
+	}
+}
+
+// firstByte calls the FirstByte hook if set.
+func (t *ClientTrace) firstByte() {
+	if t != nil && t.FirstByte != nil {
+		t.FirstByte()
+	}
+}
+
+// multiStatus calls the MultiStatus hook if set and the response has statuses.
+func (t *ClientTrace) multiStatus(out Message) {
+	if t == nil || t.MultiStatus == nil {
+		return
+	}
+	if outcome := GetMultiStatusOutcome(out); outcome != MultiStatusNone {
+		t.MultiStatus(outcome)
+	}
+}
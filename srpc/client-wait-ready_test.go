@@ -0,0 +1,72 @@
+package srpc_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// newFlakyOpener returns an OpenStreamFunc failing the first n attempts.
+func newFlakyOpener(t *testing.T, n int32) (srpc.OpenStreamFunc, *int32) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	openServer := srpc.NewServerPipe(srpc.NewServer(mux))
+	var attempts int32
+	return func(ctx context.Context, msgHandler srpc.PacketHandler, closeHandler srpc.CloseHandler) (srpc.Writer, error) {
+		if atomic.AddInt32(&attempts, 1) <= n {
+			return nil, srpc.ErrUnavailable
+		}
+		return openServer(ctx, msgHandler, closeHandler)
+	}, &attempts
+}
+
+func TestWaitForReady(t *testing.T) {
+	openStream, attempts := newFlakyOpener(t, 2)
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(openStream))
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer ctxCancel()
+	ctx = srpc.WithCallOptions(ctx, srpc.WithWaitForReady(true))
+	out, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if out.GetBody() != "hello" {
+		t.Fatalf("expected hello but got %q", out.GetBody())
+	}
+	if n := atomic.LoadInt32(attempts); n != 3 {
+		t.Fatalf("expected 3 attempts but got %d", n)
+	}
+}
+
+func TestWaitForReady_Disabled(t *testing.T) {
+	openStream, attempts := newFlakyOpener(t, 1)
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(openStream))
+
+	_, err := client.Echo(context.Background(), &echo.EchoMsg{Body: "hello"})
+	if err != srpc.ErrUnavailable {
+		t.Fatalf("expected ErrUnavailable but got %v", err)
+	}
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Fatalf("expected 1 attempt but got %d", n)
+	}
+}
+
+func TestWaitForReady_Deadline(t *testing.T) {
+	openStream, _ := newFlakyOpener(t, 1<<30)
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(openStream))
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+	defer ctxCancel()
+	ctx = srpc.WithCallOptions(ctx, srpc.WithWaitForReady(true))
+	_, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"})
+	if err != srpc.ErrUnavailable {
+		t.Fatalf("expected ErrUnavailable but got %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package srpc
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -9,11 +11,51 @@ import (
 // Client implements a SRPC client which can initiate RPC streams.
 type Client interface {
 	// Invoke executes a unary RPC with the remote.
+	// If out is nil, the response is not decoded.
 	Invoke(ctx context.Context, service, method string, in, out Message) error
 
 	// NewStream starts a streaming RPC with the remote & returns the stream.
 	// firstMsg is optional.
 	NewStream(ctx context.Context, service, method string, firstMsg Message) (Stream, error)
+
+	// Ping calls the built-in ping service with the remote.
+	//
+	// Returns the round trip time of the call. Verifies the remote is handling
+	// calls, beyond the liveness of the transport.
+	Ping(ctx context.Context) (time.Duration, error)
+}
+
+// DrainingClient is a Client which tracks if the server is draining.
+type DrainingClient interface {
+	// IsDraining checks if the server sent a Drain packet.
+	//
+	// New calls fail with ErrDraining without contacting the server.
+	IsDraining() bool
+}
+
+// IsClientDraining checks if the server of the Client is draining.
+//
+// Returns false if the Client does not implement DrainingClient.
+func IsClientDraining(client Client) bool {
+	dc, ok := client.(DrainingClient)
+	return ok && dc.IsDraining()
+}
+
+// ActiveStreamsGetter is a Client which tracks the number of open streams.
+type ActiveStreamsGetter interface {
+	// GetActiveStreams returns the number of open streams started with NewStream.
+	GetActiveStreams() int
+}
+
+// GetActiveStreams returns the number of open streams for the Client.
+//
+// Returns zero if the Client does not implement ActiveStreamsGetter.
+func GetActiveStreams(client Client) int {
+	getter, ok := client.(ActiveStreamsGetter)
+	if !ok {
+		return 0
+	}
+	return getter.GetActiveStreams()
 }
 
 // OpenStreamFunc opens a stream with a remote.
@@ -28,6 +70,14 @@ type OpenStreamFunc = func(
 type client struct {
 	// openStream opens a new stream.
 	openStream OpenStreamFunc
+	// connInfo is the connection info, if known.
+	connInfo *ConnInfo
+	// draining is set to 1 after the server sent a Drain packet.
+	draining uint32
+	// loadReport contains the last *LoadReport from the server.
+	loadReport atomic.Value
+	// activeStreams is the number of open streams started with NewStream.
+	activeStreams int32
 }
 
 // NewClient constructs a client with a OpenStreamFunc.
@@ -37,17 +87,37 @@ func NewClient(openStream OpenStreamFunc) Client {
 	}
 }
 
+// NewClientWithConnInfo constructs a client with a OpenStreamFunc and ConnInfo.
+func NewClientWithConnInfo(openStream OpenStreamFunc, connInfo *ConnInfo) Client {
+	return &client{
+		openStream: openStream,
+		connInfo:   connInfo,
+	}
+}
+
+// GetConnInfo returns the connection info or nil if unknown.
+func (c *client) GetConnInfo() *ConnInfo {
+	return c.connInfo
+}
+
 // Invoke executes a unary RPC with the remote.
+// If out is nil, the response is not decoded.
 func (c *client) Invoke(rctx context.Context, service, method string, in, out Message) error {
 	ctx, ctxCancel := context.WithCancel(rctx)
 	defer ctxCancel()
 
-	firstMsg, err := in.MarshalVT()
+	opts := getCallOptions(ctx)
+	codec, err := lookupCodec(opts.codec)
+	if err != nil {
+		return err
+	}
+
+	firstMsg, err := marshalWithCodec(codec, in)
 	if err != nil {
 		return err
 	}
 	clientRPC := NewClientRPC(ctx, service, method)
-	writer, err := c.openStream(ctx, clientRPC.HandlePacket, clientRPC.HandleStreamClose)
+	writer, err := c.openClientRPC(ctx, clientRPC)
 	if err != nil {
 		return err
 	}
@@ -55,13 +125,25 @@ func (c *client) Invoke(rctx context.Context, service, method string, in, out Me
 		return err
 	}
 	msg, err := clientRPC.ReadOne()
+	if err == nil {
+		err = clientRPC.waitComplete()
+		clientRPC.markDone()
+	}
+	clientRPC.traceComplete(err)
 	if err != nil {
 		// this includes any server returned error.
 		return err
 	}
-	if err := out.UnmarshalVT(msg); err != nil {
+	if opts.rawResponse != nil {
+		*opts.rawResponse = msg
+	}
+	if out == nil {
+		return nil
+	}
+	if err := unmarshalWithCodec(codec, out, msg); err != nil {
 		return errors.Wrap(ErrInvalidMessage, err.Error())
 	}
+	clientRPC.trace.multiStatus(out)
 	// done
 	return nil
 }
@@ -69,17 +151,20 @@ func (c *client) Invoke(rctx context.Context, service, method string, in, out Me
 // NewStream starts a streaming RPC with the remote & returns the stream.
 // firstMsg is optional.
 func (c *client) NewStream(ctx context.Context, service, method string, firstMsg Message) (Stream, error) {
+	codec, err := lookupCodec(getCallOptions(ctx).codec)
+	if err != nil {
+		return nil, err
+	}
 	var firstMsgData []byte
 	if firstMsg != nil {
-		var err error
-		firstMsgData, err = firstMsg.MarshalVT()
+		firstMsgData, err = marshalWithCodec(codec, firstMsg)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	clientRPC := NewClientRPC(ctx, service, method)
-	writer, err := c.openStream(ctx, clientRPC.HandlePacket, clientRPC.HandleStreamClose)
+	writer, err := c.openClientRPC(ctx, clientRPC)
 	if err != nil {
 		return nil, err
 	}
@@ -87,8 +172,86 @@ func (c *client) NewStream(ctx context.Context, service, method string, firstMsg
 		return nil, err
 	}
 
-	return NewMsgStream(ctx, clientRPC.writer, clientRPC.dataCh), nil
+	atomic.AddInt32(&c.activeStreams, 1)
+	go func() {
+		<-clientRPC.doneCh
+		atomic.AddInt32(&c.activeStreams, -1)
+	}()
+
+	// the call options do not apply to the calls made with the stream context.
+	strmCtx := withSignalEndpoint(withoutCallOptions(ctx), clientRPC)
+	strm := NewMsgStream(strmCtx, &clientRPCWriter{r: clientRPC}, clientRPC.dataCh)
+	strm.peerCanceled = clientRPC.peerCanceled
+	strm.closedErr = clientRPC.closedErr
+	strm.codec = codec
+	return strm, nil
+}
+
+// Ping calls the built-in ping service with the remote.
+func (c *client) Ping(ctx context.Context) (time.Duration, error) {
+	return pingClient(ctx, c)
+}
+
+// GetActiveStreams returns the number of open streams started with NewStream.
+func (c *client) GetActiveStreams() int {
+	return int(atomic.LoadInt32(&c.activeStreams))
+}
+
+// IsDraining checks if the server sent a Drain packet.
+//
+// New calls fail with ErrDraining without contacting the server.
+func (c *client) IsDraining() bool {
+	return atomic.LoadUint32(&c.draining) != 0
+}
+
+// handleDrain marks the client as draining.
+func (c *client) handleDrain(pkt *Drain) {
+	atomic.StoreUint32(&c.draining, 1)
+}
+
+// openClientRPC opens a stream for the ClientRPC calling the trace hooks.
+func (c *client) openClientRPC(ctx context.Context, clientRPC *ClientRPC) (Writer, error) {
+	if c.IsDraining() {
+		clientRPC.traceComplete(ErrDraining)
+		return nil, ErrDraining
+	}
+	clientRPC.drain = c.handleDrain
+	clientRPC.loadReport = c.handleLoadReport
+	clientRPC.trace.connectStart(clientRPC.service, clientRPC.method)
+	writer, err := c.openStream(ctx, clientRPC.HandlePacket, clientRPC.HandleStreamClose)
+	if err != nil && err != ErrClientClosed && clientRPC.waitForReady {
+		writer, err = c.waitForReady(ctx, clientRPC, err)
+	}
+	clientRPC.trace.connectDone(err)
+	if err != nil {
+		clientRPC.traceComplete(err)
+		return nil, err
+	}
+	return writer, nil
+}
+
+// waitForReady retries opening the stream with a backoff until it succeeds,
+// the client is closed or ctx is canceled.
+//
+// Returns the last error if ctx is canceled.
+func (c *client) waitForReady(ctx context.Context, clientRPC *ClientRPC, err error) (Writer, error) {
+	var policy RetryPolicy
+	for attempt := 1; ; attempt++ {
+		if !waitBackoff(clientRPC.ctx, nil, policy.backoff(attempt)) {
+			return nil, err
+		}
+		var writer Writer
+		writer, err = c.openStream(ctx, clientRPC.HandlePacket, clientRPC.HandleStreamClose)
+		if err == nil || err == ErrClientClosed {
+			return writer, err
+		}
+	}
 }
 
 // _ is a type assertion
-var _ Client = ((*client)(nil))
+var (
+	_ Client              = ((*client)(nil))
+	_ ConnInfoGetter      = ((*client)(nil))
+	_ DrainingClient      = ((*client)(nil))
+	_ ActiveStreamsGetter = ((*client)(nil))
+)
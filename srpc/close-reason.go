@@ -0,0 +1,101 @@
+package srpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// CloseReason classifies why a connection was closed.
+//
+// Passed to StatsHandler.HandleConn and counted by the Channelz.
+type CloseReason int32
+
+const (
+	// CloseReasonUnknown indicates the reason is not known.
+	CloseReasonUnknown CloseReason = iota
+	// CloseReasonClientClose indicates the client closed the connection.
+	CloseReasonClientClose
+	// CloseReasonServerClose indicates the server closed the connection.
+	CloseReasonServerClose
+	// CloseReasonServerDrain indicates the connection was closed after Shutdown.
+	CloseReasonServerDrain
+	// CloseReasonKeepaliveTimeout indicates the remote stopped responding.
+	CloseReasonKeepaliveTimeout
+	// CloseReasonProtocolError indicates the remote violated the protocol.
+	CloseReasonProtocolError
+	// CloseReasonTransportError indicates the transport failed.
+	CloseReasonTransportError
+)
+
+// closeReasonNames contains the names of the close reasons.
+var closeReasonNames = [...]string{
+	CloseReasonUnknown:          "unknown",
+	CloseReasonClientClose:      "client_close",
+	CloseReasonServerClose:      "server_close",
+	CloseReasonServerDrain:      "server_drain",
+	CloseReasonKeepaliveTimeout: "keepalive_timeout",
+	CloseReasonProtocolError:    "protocol_error",
+	CloseReasonTransportError:   "transport_error",
+}
+
+// String returns the name of the close reason.
+func (r CloseReason) String() string {
+	if r >= 0 && int(r) < len(closeReasonNames) {
+		return closeReasonNames[r]
+	}
+	return "CloseReason(" + strconv.Itoa(int(r)) + ")"
+}
+
+// MarshalText returns the name of the close reason.
+func (r CloseReason) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// ClassifyCloseReason classifies the error which closed a connection.
+//
+// A nil error or io.EOF is a client close, context.Canceled or net.ErrClosed
+// is a server close, and timeouts are keepalive timeouts. Other errors are
+// transport errors.
+func ClassifyCloseReason(err error) CloseReason {
+	switch {
+	case err == nil || errors.Is(err, io.EOF):
+		return CloseReasonClientClose
+	case errors.Is(err, ErrDraining):
+		return CloseReasonServerDrain
+	case errors.Is(err, context.Canceled) || errors.Is(err, net.ErrClosed):
+		return CloseReasonServerClose
+	case isTimeoutError(err):
+		return CloseReasonKeepaliveTimeout
+	}
+	for _, protoErr := range connProtocolErrors {
+		if errors.Is(err, protoErr) {
+			return CloseReasonProtocolError
+		}
+	}
+	return CloseReasonTransportError
+}
+
+// connProtocolErrors contains the errors caused by a protocol violation.
+var connProtocolErrors = []error{
+	ErrUnrecognizedPacket,
+	ErrEmptyPacket,
+	ErrInvalidMessage,
+	ErrCompleted,
+}
+
+// isTimeoutError checks if the error is a timeout of the remote.
+func isTimeoutError(err error) bool {
+	if errors.Is(err, ErrHeartbeatTimeout) ||
+		errors.Is(err, os.ErrDeadlineExceeded) ||
+		errors.Is(err, syscall.ETIMEDOUT) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
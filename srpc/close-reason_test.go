@@ -0,0 +1,33 @@
+package srpc_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+func TestClassifyCloseReason(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		reason srpc.CloseReason
+	}{
+		{io.EOF, srpc.CloseReasonClientClose},
+		{context.Canceled, srpc.CloseReasonServerClose},
+		{srpc.ErrDraining, srpc.CloseReasonServerDrain},
+		{errors.Wrap(srpc.ErrHeartbeatTimeout, "conn"), srpc.CloseReasonKeepaliveTimeout},
+		{os.ErrDeadlineExceeded, srpc.CloseReasonKeepaliveTimeout},
+		{srpc.ErrUnrecognizedPacket, srpc.CloseReasonProtocolError},
+		{errors.New("connection reset by peer"), srpc.CloseReasonTransportError},
+	} {
+		if reason := srpc.ClassifyCloseReason(tc.err); reason != tc.reason {
+			t.Fatalf("%v: expected %v got %v", tc.err, tc.reason, reason)
+		}
+	}
+	if name := srpc.CloseReasonKeepaliveTimeout.String(); name != "keepalive_timeout" {
+		t.Fatalf("unexpected name %q", name)
+	}
+}
@@ -0,0 +1,65 @@
+package srpc
+
+// CoalesceKeyFunc returns the key to coalesce a unary call on.
+//
+// req is the request payload. Concurrent calls with the same key share a
+// single call to the handler.
+type CoalesceKeyFunc = func(serviceID, methodID string, req []byte) string
+
+// DefaultCoalesceKey keys on the service, method and request payload.
+func DefaultCoalesceKey(serviceID, methodID string, req []byte) string {
+	return serviceID + "/" + methodID + "\x00" + string(req)
+}
+
+// Coalescer coalesces identical concurrent unary calls (singleflight).
+//
+// Concurrent calls with the same key wait for a single call to the handler and
+// receive its response. If the call fails each waiting call invokes the
+// handler itself, so one canceled caller does not fail the others.
+type Coalescer struct {
+	// keyFn returns the key for a call.
+	keyFn CoalesceKeyFunc
+	// methods contains the coalesced methods by service.
+	// immutable after construction
+	methods map[string]map[string]struct{}
+	// group coalesces the calls.
+	group callGroup
+}
+
+// NewCoalescer constructs a new Coalescer for the given unary methods.
+//
+// methods contains the method IDs to coalesce by service ID. Only unary
+// methods can be coalesced. If keyFn is nil, uses DefaultCoalesceKey.
+func NewCoalescer(keyFn CoalesceKeyFunc, methods map[string][]string) *Coalescer {
+	if keyFn == nil {
+		keyFn = DefaultCoalesceKey
+	}
+	c := &Coalescer{
+		keyFn:   keyFn,
+		methods: make(map[string]map[string]struct{}, len(methods)),
+	}
+	for serviceID, methodIDs := range methods {
+		svcMethods := make(map[string]struct{}, len(methodIDs))
+		for _, methodID := range methodIDs {
+			svcMethods[methodID] = struct{}{}
+		}
+		c.methods[serviceID] = svcMethods
+	}
+	return c
+}
+
+// isCoalesced checks if the method is coalesced.
+func (c *Coalescer) isCoalesced(serviceID, methodID string) bool {
+	_, ok := c.methods[serviceID][methodID]
+	return ok
+}
+
+// invokeMethod invokes a unary method with the handler coalescing calls.
+func (c *Coalescer) invokeMethod(handler Handler, serviceID, methodID string, strm Stream) error {
+	req := &RawMessage{}
+	if err := strm.MsgRecv(req); err != nil {
+		return err
+	}
+	key := codecKey(strm.Context(), c.keyFn(serviceID, methodID, req.GetData()))
+	return c.group.invoke(handler, serviceID, methodID, key, req.GetData(), strm, nil, nil)
+}
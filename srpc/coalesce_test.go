@@ -0,0 +1,61 @@
+package srpc_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+func TestCoalescer(t *testing.T) {
+	var keyCalls int32
+	coalescer := srpc.NewCoalescer(
+		func(serviceID, methodID string, req []byte) string {
+			atomic.AddInt32(&keyCalls, 1)
+			return srpc.DefaultCoalesceKey(serviceID, methodID, req)
+		},
+		map[string][]string{echo.SRPCEchoerServiceID: {"Echo"}},
+	)
+	mux := srpc.NewMux(srpc.WithCoalescer(coalescer))
+	echoServer := &countingEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	ctx := context.Background()
+
+	// concurrent identical calls invoke the handler once
+	const concurrency = 10
+	errCh := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			out, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello world"})
+			if err == nil && out.GetBody() != "hello world" {
+				err = errors.Errorf("unexpected response: %q", out.GetBody())
+			}
+			errCh <- err
+		}()
+	}
+	for i := 0; i < concurrency; i++ {
+		if err := <-errCh; err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if calls := atomic.LoadInt32(&echoServer.calls); calls != 1 {
+		t.Fatalf("expected 1 call got %d", calls)
+	}
+	if calls := atomic.LoadInt32(&keyCalls); calls != concurrency {
+		t.Fatalf("expected %d key calls got %d", concurrency, calls)
+	}
+
+	// sequential calls are not coalesced
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello world"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if calls := atomic.LoadInt32(&echoServer.calls); calls != 2 {
+		t.Fatalf("expected 2 calls got %d", calls)
+	}
+}
@@ -0,0 +1,194 @@
+package srpc
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnsupportedCodec is returned if the codec is unknown.
+var ErrUnsupportedCodec = errors.New("unsupported codec")
+
+// Codec encodes and decodes the messages of a call.
+//
+// Must be concurrency safe.
+type Codec interface {
+	// Name returns the name of the codec sent to the remote.
+	Name() string
+	// Marshal encodes the message.
+	Marshal(msg Message) ([]byte, error)
+	// Unmarshal decodes the data into the message.
+	Unmarshal(data []byte, msg Message) error
+}
+
+// ProtoCodecName is the name of the default protobuf codec.
+const ProtoCodecName = "proto"
+
+// JSONCodecName is the name of the protojson codec.
+const JSONCodecName = "json"
+
+// ProtoCodec encodes the messages with the vtprotobuf methods.
+//
+// Used for calls without a codec.
+type ProtoCodec struct{}
+
+// Name returns the name of the codec sent to the remote.
+func (ProtoCodec) Name() string {
+	return ProtoCodecName
+}
+
+// Marshal encodes the message.
+func (ProtoCodec) Marshal(msg Message) ([]byte, error) {
+	return MarshalMessage(msg)
+}
+
+// Unmarshal decodes the data into the message.
+func (ProtoCodec) Unmarshal(data []byte, msg Message) error {
+	return UnmarshalMessage(msg, data)
+}
+
+// JSONCodec encodes the messages in the canonical protojson format.
+//
+// Useful for debugging. The messages must implement proto.Message.
+type JSONCodec struct{}
+
+// Name returns the name of the codec sent to the remote.
+func (JSONCodec) Name() string {
+	return JSONCodecName
+}
+
+// Marshal encodes the message.
+func (JSONCodec) Marshal(msg Message) ([]byte, error) {
+	return MarshalProtoJSON(msg)
+}
+
+// Unmarshal decodes the data into the message.
+//
+// Empty data leaves the message unchanged.
+func (JSONCodec) Unmarshal(data []byte, msg Message) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return UnmarshalProtoJSON(data, msg)
+}
+
+// codecs contains the registered codecs by name.
+var codecs = struct {
+	sync.RWMutex
+	m map[string]Codec
+}{m: map[string]Codec{
+	ProtoCodecName: ProtoCodec{},
+	JSONCodecName:  JSONCodec{},
+}}
+
+// RegisterCodec registers the codec by name.
+//
+// Replaces any codec with the same name. The proto and json codecs are
+// registered by default.
+func RegisterCodec(c Codec) {
+	codecs.Lock()
+	codecs.m[c.Name()] = c
+	codecs.Unlock()
+}
+
+// GetCodec returns the registered codec with the name or nil.
+func GetCodec(name string) Codec {
+	codecs.RLock()
+	defer codecs.RUnlock()
+	return codecs.m[name]
+}
+
+// CodecNames returns the sorted names of the registered codecs.
+func CodecNames() []string {
+	codecs.RLock()
+	names := make([]string, 0, len(codecs.m))
+	for name := range codecs.m {
+		names = append(names, name)
+	}
+	codecs.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// WithCodec encodes the messages of the call with the codec.
+//
+// The server uses the same codec for the responses. The call fails with
+// ErrUnsupportedCodec if the remote does not support the codec: see the
+// capabilities package to check before calling. Empty for the default
+// protobuf encoding.
+func WithCodec(name string) CallOption {
+	return func(o *callOptions) {
+		o.codec = name
+	}
+}
+
+// CodecFromContext returns the codec set with WithCodec.
+func CodecFromContext(ctx context.Context) string {
+	return getCallOptions(ctx).codec
+}
+
+// lookupCodec returns the codec with the name.
+//
+// Returns nil, nil if the name is empty.
+func lookupCodec(name string) (Codec, error) {
+	if name == "" {
+		return nil, nil
+	}
+	codec := GetCodec(name)
+	if codec == nil {
+		return nil, errors.Wrap(ErrUnsupportedCodec, name)
+	}
+	return codec, nil
+}
+
+// marshalWithCodec marshals the message with the codec.
+//
+// RawMessage payloads are passed through. If codec is nil, uses MarshalMessage.
+func marshalWithCodec(codec Codec, msg Message) ([]byte, error) {
+	if _, ok := msg.(*RawMessage); ok || codec == nil {
+		return MarshalMessage(msg)
+	}
+	return codec.Marshal(msg)
+}
+
+// unmarshalWithCodec unmarshals the data into the message with the codec.
+//
+// RawMessage payloads are passed through. If codec is nil, uses UnmarshalMessage.
+func unmarshalWithCodec(codec Codec, msg Message, data []byte) error {
+	if _, ok := msg.(*RawMessage); ok || codec == nil {
+		return UnmarshalMessage(msg, data)
+	}
+	return codec.Unmarshal(data, msg)
+}
+
+// callCodecCtxKey is the context key for the codec of a server call.
+type callCodecCtxKey struct{}
+
+// withCallCodec attaches the codec of the server call to the context.
+func withCallCodec(ctx context.Context, codec Codec) context.Context {
+	return context.WithValue(ctx, callCodecCtxKey{}, codec)
+}
+
+// callCodecFromContext returns the codec of the server call or nil.
+func callCodecFromContext(ctx context.Context) Codec {
+	codec, _ := ctx.Value(callCodecCtxKey{}).(Codec)
+	return codec
+}
+
+// codecKey prefixes the key with the codec name of the server call, if any.
+//
+// Keeps the encoded responses of calls with different codecs apart.
+func codecKey(ctx context.Context, key string) string {
+	if codec := callCodecFromContext(ctx); codec != nil {
+		return codec.Name() + "/" + key
+	}
+	return key
+}
+
+// _ is a type assertion
+var (
+	_ Codec = ProtoCodec{}
+	_ Codec = JSONCodec{}
+)
@@ -0,0 +1,76 @@
+package srpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestJSONCodec(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	// expect the response to be encoded with the codec
+	ctx := context.Background()
+	var raw []byte
+	out, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}, srpc.WithCodec(srpc.JSONCodecName), srpc.WithRawResponse(&raw))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if out.GetBody() != "hello" {
+		t.Fatalf("expected hello got %q", out.GetBody())
+	}
+	if string(raw) != `{"body":"hello"}` {
+		t.Fatalf("expected json response got %q", string(raw))
+	}
+
+	strm, err := client.EchoBidiStream(ctx, srpc.WithCodec(srpc.JSONCodecName))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer strm.Close()
+	msg, err := strm.Recv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if msg.GetBody() != "hello from server" {
+		t.Fatalf("expected greeting got %q", msg.GetBody())
+	}
+	for _, body := range []string{"a", "b"} {
+		if err := strm.Send(&echo.EchoMsg{Body: body}); err != nil {
+			t.Fatal(err.Error())
+		}
+		msg, err := strm.Recv()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if msg.GetBody() != body {
+			t.Fatalf("expected %q got %q", body, msg.GetBody())
+		}
+	}
+	if err := strm.CloseSend(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != io.EOF {
+		t.Fatalf("expected EOF got %v", err)
+	}
+}
+
+func TestCodec_Unsupported(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	_, err := client.Echo(context.Background(), &echo.EchoMsg{Body: "hello"}, srpc.WithCodec("e2e-unknown"))
+	if !errors.Is(err, srpc.ErrUnsupportedCodec) {
+		t.Fatalf("expected %v got %v", srpc.ErrUnsupportedCodec, err)
+	}
+}
@@ -0,0 +1,123 @@
+package compress
+
+import (
+	"sync"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/frame"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// ZstdCompression is the name of the zstd compression algorithm.
+const ZstdCompression = "zstd"
+
+// SnappyCompression is the name of the snappy compression algorithm.
+const SnappyCompression = "snappy"
+
+// maxDecompressedSize is the max size in bytes of a decompressed message.
+const maxDecompressedSize = frame.DefaultMaxSize
+
+// zstdCompressor is the zstd Compressor.
+//
+// The encoder and decoder are constructed on first use.
+type zstdCompressor struct {
+	once sync.Once
+	enc  *zstd.Encoder
+	dec  *zstd.Decoder
+	err  error
+}
+
+// NewZstdCompressor constructs the zstd Compressor.
+func NewZstdCompressor() (srpc.Compressor, error) {
+	c := &zstdCompressor{}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// load constructs the encoder and decoder once.
+func (c *zstdCompressor) load() error {
+	c.once.Do(func() {
+		c.enc, c.err = zstd.NewWriter(nil)
+		if c.err != nil {
+			return
+		}
+		c.dec, c.err = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedSize))
+		if c.err != nil {
+			_ = c.enc.Close()
+		}
+	})
+	return c.err
+}
+
+// Name returns the name of the algorithm.
+func (c *zstdCompressor) Name() string {
+	return ZstdCompression
+}
+
+// Compress compresses the data.
+func (c *zstdCompressor) Compress(data []byte) ([]byte, error) {
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c.enc.EncodeAll(data, nil), nil
+}
+
+// Decompress decompresses the data.
+//
+// Returns srpc.ErrDecompressedTooLarge if the data is larger than the max message size.
+func (c *zstdCompressor) Decompress(data []byte) ([]byte, error) {
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	out, err := c.dec.DecodeAll(data, nil)
+	if err == zstd.ErrDecoderSizeExceeded {
+		return nil, srpc.ErrDecompressedTooLarge
+	}
+	return out, err
+}
+
+// snappyCompressor is the snappy Compressor.
+type snappyCompressor struct{}
+
+// NewSnappyCompressor constructs the snappy Compressor.
+func NewSnappyCompressor() srpc.Compressor {
+	return snappyCompressor{}
+}
+
+// Name returns the name of the algorithm.
+func (snappyCompressor) Name() string {
+	return SnappyCompression
+}
+
+// Compress compresses the data in the snappy block format.
+func (snappyCompressor) Compress(data []byte) ([]byte, error) {
+	return s2.EncodeSnappy(nil, data), nil
+}
+
+// Decompress decompresses the data in the snappy block format.
+//
+// Returns srpc.ErrDecompressedTooLarge if the data is larger than the max message size.
+func (snappyCompressor) Decompress(data []byte) ([]byte, error) {
+	size, err := s2.DecodedLen(data)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxDecompressedSize {
+		return nil, srpc.ErrDecompressedTooLarge
+	}
+	return s2.Decode(nil, data)
+}
+
+func init() {
+	srpc.RegisterCompressor(&zstdCompressor{})
+	srpc.RegisterCompressor(NewSnappyCompressor())
+}
+
+// _ is a type assertion
+var (
+	_ srpc.Compressor = ((*zstdCompressor)(nil))
+	_ srpc.Compressor = snappyCompressor{}
+)
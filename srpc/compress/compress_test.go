@@ -0,0 +1,44 @@
+package compress_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/compress"
+	"github.com/aperturerobotics/starpc/srpc/frame"
+)
+
+func TestCompressors(t *testing.T) {
+	for _, name := range []string{compress.ZstdCompression, compress.SnappyCompression} {
+		comp := srpc.GetCompressor(name)
+		if comp == nil {
+			t.Fatalf("expected %s to be registered", name)
+		}
+		msg := bytes.Repeat([]byte("hello world "), 100)
+		data, err := comp.Compress(msg)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		out, err := comp.Decompress(data)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if !bytes.Equal(out, msg) {
+			t.Fatalf("%s: unexpected decompressed data", name)
+		}
+	}
+}
+
+func TestCompressors_Oversized(t *testing.T) {
+	for _, name := range []string{compress.ZstdCompression, compress.SnappyCompression} {
+		comp := srpc.GetCompressor(name)
+		data, err := comp.Compress(make([]byte, frame.DefaultMaxSize+1))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if _, err := comp.Decompress(data); err != srpc.ErrDecompressedTooLarge {
+			t.Fatalf("%s: expected %v got %v", name, srpc.ErrDecompressedTooLarge, err)
+		}
+	}
+}
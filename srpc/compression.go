@@ -0,0 +1,236 @@
+package srpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnsupportedCompression is returned if the compression algorithm is unknown.
+var ErrUnsupportedCompression = errors.New("unsupported compression")
+
+// MinCompressSize is the minimum message size in bytes to compress.
+//
+// Smaller messages are sent uncompressed.
+const MinCompressSize = 512
+
+// Compressor compresses and decompresses messages.
+//
+// Must be concurrency safe.
+type Compressor interface {
+	// Name returns the name of the algorithm sent to the remote.
+	Name() string
+	// Compress compresses the data.
+	Compress(data []byte) ([]byte, error)
+	// Decompress decompresses the data.
+	// Must return an error if the data decompresses to more than the max message size.
+	Decompress(data []byte) ([]byte, error)
+}
+
+// compressors contains the registered compressors by name.
+var compressors = struct {
+	sync.RWMutex
+	m map[string]Compressor
+}{m: make(map[string]Compressor)}
+
+// RegisterCompressor registers the compressor by name.
+//
+// Replaces any compressor with the same name. The gzip compressor is
+// registered by default.
+func RegisterCompressor(c Compressor) {
+	compressors.Lock()
+	compressors.m[c.Name()] = c
+	compressors.Unlock()
+}
+
+// GetCompressor returns the registered compressor with the name or nil.
+func GetCompressor(name string) Compressor {
+	compressors.RLock()
+	defer compressors.RUnlock()
+	return compressors.m[name]
+}
+
+// CompressorNames returns the sorted names of the registered compressors.
+func CompressorNames() []string {
+	compressors.RLock()
+	names := make([]string, 0, len(compressors.m))
+	for name := range compressors.m {
+		names = append(names, name)
+	}
+	compressors.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// WithCompression compresses the messages of the call with the algorithm.
+//
+// The server uses the same algorithm for the responses. Messages smaller than
+// MinCompressSize or which do not shrink are sent uncompressed. The call fails
+// with ErrUnsupportedCompression if the remote does not support the algorithm:
+// see the capabilities package to check before calling. Empty to disable.
+func WithCompression(name string) CallOption {
+	return func(o *callOptions) {
+		o.compression = name
+	}
+}
+
+// CompressionFromContext returns the compression set with WithCompression.
+func CompressionFromContext(ctx context.Context) string {
+	return getCallOptions(ctx).compression
+}
+
+// lookupCompressor returns the compressor with the name.
+//
+// Returns nil, nil if the name is empty.
+func lookupCompressor(name string) (Compressor, error) {
+	if name == "" {
+		return nil, nil
+	}
+	comp := GetCompressor(name)
+	if comp == nil {
+		return nil, errors.Wrap(ErrUnsupportedCompression, name)
+	}
+	return comp, nil
+}
+
+// decompressData decompresses the data of a packet if compressed is set.
+func decompressData(comp Compressor, data []byte, compressed bool) ([]byte, error) {
+	if !compressed {
+		return data, nil
+	}
+	if comp == nil {
+		return nil, errors.Wrap(ErrUnsupportedCompression, "compressed data without compression")
+	}
+	return comp.Decompress(data)
+}
+
+// compressWriter is a Writer which compresses the data of the packets.
+type compressWriter struct {
+	Writer
+	comp Compressor
+}
+
+// newCompressWriter wraps the writer to compress the data with comp.
+func newCompressWriter(writer Writer, comp Compressor) *compressWriter {
+	return &compressWriter{Writer: writer, comp: comp}
+}
+
+// WritePacket compresses the data of the packet and writes it.
+func (w *compressWriter) WritePacket(p *Packet) error {
+	switch b := p.GetBody().(type) {
+	case *Packet_CallStart:
+		data, compressed, err := w.compress(b.CallStart.GetData())
+		if err != nil {
+			return err
+		}
+		b.CallStart.Data, b.CallStart.DataCompressed = data, compressed
+	case *Packet_CallData:
+		data, compressed, err := w.compress(b.CallData.GetData())
+		if err != nil {
+			return err
+		}
+		b.CallData.Data, b.CallData.DataCompressed = data, compressed
+	}
+	return w.Writer.WritePacket(p)
+}
+
+// WritePackets compresses the data of the packets and writes them.
+func (w *compressWriter) WritePackets(pkts []*Packet) error {
+	for _, p := range pkts {
+		if b, ok := p.GetBody().(*Packet_CallData); ok {
+			data, compressed, err := w.compress(b.CallData.GetData())
+			if err != nil {
+				return err
+			}
+			b.CallData.Data, b.CallData.DataCompressed = data, compressed
+		}
+	}
+	return writePackets(w.Writer, pkts)
+}
+
+// Reset aborts the stream.
+func (w *compressWriter) Reset() error {
+	return resetWriter(w.Writer)
+}
+
+// compress compresses the data if it is large enough and shrinks.
+func (w *compressWriter) compress(data []byte) ([]byte, bool, error) {
+	if len(data) < MinCompressSize {
+		return data, false, nil
+	}
+	out, err := w.comp.Compress(data)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(out) >= len(data) {
+		return data, false, nil
+	}
+	return out, true, nil
+}
+
+// gzipCompressor is the gzip Compressor.
+type gzipCompressor struct {
+	writers sync.Pool
+}
+
+// GzipCompression is the name of the gzip compression algorithm.
+const GzipCompression = "gzip"
+
+// Name returns the name of the algorithm.
+func (c *gzipCompressor) Name() string {
+	return GzipCompression
+}
+
+// Compress compresses the data.
+func (c *gzipCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gw, _ := c.writers.Get().(*gzip.Writer)
+	if gw == nil {
+		gw = gzip.NewWriter(&buf)
+	} else {
+		gw.Reset(&buf)
+	}
+	defer c.writers.Put(gw)
+	if _, err := gw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses the data.
+//
+// Returns ErrDecompressedTooLarge if the data is larger than the max message size.
+func (c *gzipCompressor) Decompress(data []byte) ([]byte, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	out, err := io.ReadAll(io.LimitReader(gr, int64(maxMessageSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(out) > maxMessageSize {
+		return nil, ErrDecompressedTooLarge
+	}
+	return out, nil
+}
+
+func init() {
+	RegisterCompressor(&gzipCompressor{})
+}
+
+// _ is a type assertion
+var (
+	_ Compressor  = ((*gzipCompressor)(nil))
+	_ BatchWriter = ((*compressWriter)(nil))
+	_ ResetWriter = ((*compressWriter)(nil))
+)
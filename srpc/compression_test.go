@@ -0,0 +1,115 @@
+package srpc_test
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/rpcstream"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/frame"
+	"github.com/pkg/errors"
+)
+
+// countingCompressor counts the calls to the wrapped compressor.
+type countingCompressor struct {
+	srpc.Compressor
+	compressed, decompressed int32
+}
+
+func TestCompression(t *testing.T) {
+	comp := &countingCompressor{Compressor: srpc.GetCompressor(srpc.GzipCompression)}
+	srpc.RegisterCompressor(comp)
+
+	mux := srpc.NewMux()
+	echoServer := echo.NewEchoServer(mux)
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	ctx := srpc.WithCallOptions(context.Background(), srpc.WithCompression(comp.Name()))
+
+	// small messages are not compressed
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if n := atomic.LoadInt32(&comp.compressed); n != 0 {
+		t.Fatalf("expected no compressed messages got %d", n)
+	}
+
+	// the request and response are compressed
+	body := strings.Repeat("hello world ", 1024)
+	resp, err := client.Echo(ctx, &echo.EchoMsg{Body: body})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != body {
+		t.Fatal("response body incorrect")
+	}
+	if c, d := atomic.LoadInt32(&comp.compressed), atomic.LoadInt32(&comp.decompressed); c != 2 || d != 2 {
+		t.Fatalf("expected 2 compressed and decompressed messages got %d and %d", c, d)
+	}
+
+	// calls proxied over a rpcstream are compressed
+	proxiedClient := echo.NewSRPCEchoerClient(srpc.NewClient(rpcstream.NewRpcStreamOpenStream(func(ctx context.Context) (rpcstream.RpcStream, error) {
+		return client.RpcStream(ctx)
+	}, "test")))
+	resp, err = proxiedClient.Echo(ctx, &echo.EchoMsg{Body: body})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != body {
+		t.Fatal("proxied response body incorrect")
+	}
+	if c := atomic.LoadInt32(&comp.compressed); c != 4 {
+		t.Fatalf("expected 4 compressed messages got %d", c)
+	}
+
+	_, err = client.Echo(srpc.WithCallOptions(ctx, srpc.WithCompression("e2e-unknown")), &echo.EchoMsg{Body: body})
+	if !errors.Is(err, srpc.ErrUnsupportedCompression) {
+		t.Fatalf("expected unsupported compression error got %v", err)
+	}
+}
+
+// Name returns the name of the algorithm.
+func (c *countingCompressor) Name() string {
+	return "e2e-counting"
+}
+
+// Compress compresses the data.
+func (c *countingCompressor) Compress(data []byte) ([]byte, error) {
+	atomic.AddInt32(&c.compressed, 1)
+	return c.Compressor.Compress(data)
+}
+
+// Decompress decompresses the data.
+func (c *countingCompressor) Decompress(data []byte) ([]byte, error) {
+	atomic.AddInt32(&c.decompressed, 1)
+	return c.Compressor.Decompress(data)
+}
+
+func TestCompression_Oversized(t *testing.T) {
+	comp := srpc.GetCompressor(srpc.GzipCompression)
+	data, err := comp.Compress(make([]byte, frame.DefaultMaxSize+1))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := comp.Decompress(data); err != srpc.ErrDecompressedTooLarge {
+		t.Fatalf("expected %v got %v", srpc.ErrDecompressedTooLarge, err)
+	}
+
+	// expect data of exactly the max size to be decompressed
+	data, err = comp.Compress(make([]byte, frame.DefaultMaxSize))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	out, err := comp.Decompress(data)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(out) != frame.DefaultMaxSize {
+		t.Fatalf("expected %d bytes got %d", frame.DefaultMaxSize, len(out))
+	}
+}
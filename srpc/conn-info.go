@@ -0,0 +1,114 @@
+package srpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+)
+
+// MuxerMplex is the name of the default mplex stream muxer.
+const MuxerMplex = "mplex"
+
+// TransportWebSocket is the transport name for WebSocket connections.
+const TransportWebSocket = "websocket"
+
+// TransportHTTP2 is the transport name for HTTP/2 streams.
+const TransportHTTP2 = "http2"
+
+// ConnInfo contains information about the transport of a connection.
+type ConnInfo struct {
+	// Transport is the transport type, e.g. tcp, unix, websocket.
+	Transport string
+	// Muxer is the name of the stream muxer, if any.
+	Muxer string
+	// ProtocolVersion is the starpc protocol version.
+	ProtocolVersion int
+	// LocalAddr is the local address, if known.
+	LocalAddr string
+	// RemoteAddr is the remote address, if known.
+	RemoteAddr string
+	// TLS contains the TLS connection state, nil if not using TLS.
+	TLS *tls.ConnectionState
+}
+
+// NewConnInfo builds the ConnInfo for a net.Conn.
+//
+// muxer is the name of the stream muxer used with the conn.
+// Detects TLS if conn is a *tls.Conn with a completed handshake.
+func NewConnInfo(conn net.Conn, muxer string) *ConnInfo {
+	info := &ConnInfo{
+		Muxer:           muxer,
+		ProtocolVersion: ProtocolVersion,
+	}
+	if laddr := conn.LocalAddr(); laddr != nil {
+		info.Transport = laddr.Network()
+		info.LocalAddr = laddr.String()
+	}
+	if raddr := conn.RemoteAddr(); raddr != nil {
+		info.RemoteAddr = raddr.String()
+	}
+	if tc, ok := conn.(*tls.Conn); ok {
+		state := tc.ConnectionState()
+		if state.HandshakeComplete {
+			info.TLS = &state
+		}
+	}
+	return info
+}
+
+// GetTLSVersion returns the negotiated TLS version or zero if not using TLS.
+func (i *ConnInfo) GetTLSVersion() uint16 {
+	if i == nil || i.TLS == nil {
+		return 0
+	}
+	return i.TLS.Version
+}
+
+// GetCipherSuite returns the name of the negotiated cipher suite or empty.
+func (i *ConnInfo) GetCipherSuite() string {
+	if i == nil || i.TLS == nil {
+		return ""
+	}
+	return tls.CipherSuiteName(i.TLS.CipherSuite)
+}
+
+// GetNegotiatedProtocol returns the ALPN negotiated protocol or empty.
+func (i *ConnInfo) GetNegotiatedProtocol() string {
+	if i == nil || i.TLS == nil {
+		return ""
+	}
+	return i.TLS.NegotiatedProtocol
+}
+
+// ConnInfoGetter returns the ConnInfo of the underlying connection.
+//
+// Implemented by the Client constructed with NewClientWithConn.
+type ConnInfoGetter interface {
+	// GetConnInfo returns the connection info or nil if unknown.
+	GetConnInfo() *ConnInfo
+}
+
+// GetConnInfo returns the ConnInfo of the Client or nil if unknown.
+func GetConnInfo(client Client) *ConnInfo {
+	getter, ok := client.(ConnInfoGetter)
+	if !ok {
+		return nil
+	}
+	return getter.GetConnInfo()
+}
+
+// connInfoCtxKey is the context key for the conn info.
+type connInfoCtxKey struct{}
+
+// WithConnInfo attaches the ConnInfo to the context.
+func WithConnInfo(ctx context.Context, info *ConnInfo) context.Context {
+	return context.WithValue(ctx, connInfoCtxKey{}, info)
+}
+
+// ConnInfoFromContext returns the ConnInfo attached to the context or nil.
+//
+// The Server attaches the ConnInfo to the context passed to handlers.
+func ConnInfoFromContext(ctx context.Context) *ConnInfo {
+	info, _ := ctx.Value(connInfoCtxKey{}).(*ConnInfo)
+	return info
+}
@@ -0,0 +1,185 @@
+//go:build !starpc_core
+
+package srpc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+)
+
+// MuxedConnDialer dials a new muxed connection.
+type MuxedConnDialer func(ctx context.Context) (network.MuxedConn, error)
+
+// NewTCPMuxedConnDialer constructs a MuxedConnDialer dialing a TCP address.
+//
+// Applies the TCP options to the connection.
+func NewTCPMuxedConnDialer(addr string, opts ...TCPOption) MuxedConnDialer {
+	return func(ctx context.Context) (network.MuxedConn, error) {
+		var dialer net.Dialer
+		nc, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		if err := ApplyTCPOptions(nc, opts...); err != nil {
+			_ = nc.Close()
+			return nil, err
+		}
+		mconn, err := NewMuxedConn(nc, true)
+		if err != nil {
+			_ = nc.Close()
+			return nil, err
+		}
+		return mconn, nil
+	}
+}
+
+// ConnRotator opens streams over a muxed connection which is replaced with a
+// newly dialed connection once it reaches the max age.
+//
+// The old connection is drained: it is closed once the calls using it are
+// complete. Spreads the load after the servers are scaled up and works around
+// idle limits of load balancers. Use with NewClient(rotator.OpenStream).
+type ConnRotator struct {
+	// dial dials a new connection.
+	dial MuxedConnDialer
+	// maxAge is the max age of a connection used for new calls.
+	maxAge time.Duration
+	// mtx guards the fields below
+	mtx sync.Mutex
+	// curr is the current connection, if any.
+	curr *rotatedConn
+	// closed indicates Close was called.
+	closed bool
+}
+
+// rotatedConn is a muxed connection managed by ConnRotator.
+type rotatedConn struct {
+	// mconn is the muxed connection.
+	mconn network.MuxedConn
+	// expires is when the connection should no longer be used for new calls.
+	expires time.Time
+	// refs is the number of open streams.
+	// guarded by ConnRotator.mtx
+	refs int
+	// retired indicates the connection is closed after refs reaches zero.
+	// guarded by ConnRotator.mtx
+	retired bool
+}
+
+// NewConnRotator constructs a new ConnRotator.
+//
+// If maxAge is zero, the connection is only replaced after it is closed.
+func NewConnRotator(dial MuxedConnDialer, maxAge time.Duration) *ConnRotator {
+	return &ConnRotator{dial: dial, maxAge: maxAge}
+}
+
+// OpenStream opens a stream over the current connection.
+//
+// Dials a new connection if the current connection is closed or expired.
+func (r *ConnRotator) OpenStream(ctx context.Context, msgHandler PacketHandler, closeHandler CloseHandler) (Writer, error) {
+	conn, err := r.getConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mstrm, err := conn.mconn.OpenStream(ctx)
+	if err != nil {
+		r.releaseConn(conn)
+		return nil, err
+	}
+	rotatedStrm := &rotatedStream{
+		MuxedStream: mstrm,
+		release: func() {
+			r.releaseConn(conn)
+		},
+	}
+	rw := NewPacketReadWriter(rotatedStrm)
+	go rw.ReadPump(msgHandler, closeHandler)
+	return rw, nil
+}
+
+// Close closes the current connection.
+//
+// Any further calls to OpenStream will fail with ErrClientClosed.
+func (r *ConnRotator) Close() {
+	r.mtx.Lock()
+	r.closed = true
+	curr := r.curr
+	r.curr = nil
+	r.mtx.Unlock()
+	if curr != nil {
+		_ = curr.mconn.Close()
+	}
+}
+
+// getConn returns the current connection adding a reference.
+func (r *ConnRotator) getConn(ctx context.Context) (*rotatedConn, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.closed {
+		return nil, ErrClientClosed
+	}
+	if curr := r.curr; curr != nil {
+		if curr.mconn.IsClosed() || (r.maxAge > 0 && !time.Now().Before(curr.expires)) {
+			r.retireConnLocked(curr)
+			r.curr = nil
+		}
+	}
+	if r.curr == nil {
+		mconn, err := r.dial(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.curr = &rotatedConn{mconn: mconn, expires: time.Now().Add(r.maxAge)}
+	}
+	r.curr.refs++
+	return r.curr, nil
+}
+
+// releaseConn removes a reference to the connection.
+func (r *ConnRotator) releaseConn(conn *rotatedConn) {
+	r.mtx.Lock()
+	conn.refs--
+	closeConn := conn.retired && conn.refs == 0
+	r.mtx.Unlock()
+	if closeConn {
+		_ = conn.mconn.Close()
+	}
+}
+
+// retireConnLocked marks the connection to be closed after it is idle.
+// expects mtx to be locked
+func (r *ConnRotator) retireConnLocked(conn *rotatedConn) {
+	conn.retired = true
+	if conn.refs == 0 {
+		go conn.mconn.Close()
+	}
+}
+
+// rotatedStream is a muxed stream releasing the connection when done.
+type rotatedStream struct {
+	network.MuxedStream
+	// release releases the connection.
+	release func()
+	// releaseOnce guards release.
+	releaseOnce sync.Once
+}
+
+// Read reads from the stream, releasing the connection when the stream ends.
+func (s *rotatedStream) Read(p []byte) (int, error) {
+	n, err := s.MuxedStream.Read(p)
+	if err != nil {
+		s.releaseOnce.Do(s.release)
+	}
+	return n, err
+}
+
+// Close closes the stream and releases the connection.
+func (s *rotatedStream) Close() error {
+	err := s.MuxedStream.Close()
+	s.releaseOnce.Do(s.release)
+	return err
+}
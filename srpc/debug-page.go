@@ -0,0 +1,155 @@
+package srpc
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DebugPageMaxErrors is the number of recent errors listed by DebugPage.
+const DebugPageMaxErrors = 32
+
+// DebugPage is a http.Handler rendering the services, active calls and recent
+// errors of a server.
+//
+// Serve it on an operator-only port, separately from the RPC endpoint. Add
+// Intercept to the server interceptors to track the calls.
+type DebugPage struct {
+	// mux contains the registered services
+	mux Mux
+	// mtx guards below fields
+	mtx sync.Mutex
+	// nextID is the id of the next call
+	nextID uint64
+	// active contains the active calls by id
+	active map[uint64]*DebugCall
+	// errors contains the recent errors, oldest first
+	errors []*DebugCall
+}
+
+// DebugCall is a call listed on the debug page.
+type DebugCall struct {
+	// ServiceID is the service of the call.
+	ServiceID string
+	// MethodID is the method of the call.
+	MethodID string
+	// Started is when the call started.
+	Started time.Time
+	// Finished is when the call finished, zero if active.
+	Finished time.Time
+	// Err is the error returned by the call, if any.
+	Err error
+}
+
+// NewDebugPage constructs a new DebugPage for the mux.
+func NewDebugPage(mux Mux) *DebugPage {
+	return &DebugPage{mux: mux, active: make(map[uint64]*DebugCall)}
+}
+
+// Intercept tracks the call as a ServerInterceptor.
+func (p *DebugPage) Intercept(ctx context.Context, serviceID, methodID string, strm Stream, next Invoker) (bool, error) {
+	call := &DebugCall{ServiceID: serviceID, MethodID: methodID, Started: time.Now()}
+	p.mtx.Lock()
+	id := p.nextID
+	p.nextID++
+	p.active[id] = call
+	p.mtx.Unlock()
+
+	handled, err := next(serviceID, methodID, strm)
+
+	p.mtx.Lock()
+	delete(p.active, id)
+	if err != nil {
+		call := *call
+		call.Finished, call.Err = time.Now(), err
+		if len(p.errors) == DebugPageMaxErrors {
+			p.errors = append(p.errors[:0], p.errors[1:]...)
+		}
+		p.errors = append(p.errors, &call)
+	}
+	p.mtx.Unlock()
+	return handled, err
+}
+
+// GetActiveCalls returns the active calls sorted by start time.
+func (p *DebugPage) GetActiveCalls() []*DebugCall {
+	p.mtx.Lock()
+	calls := make([]*DebugCall, 0, len(p.active))
+	for _, call := range p.active {
+		calls = append(calls, call)
+	}
+	p.mtx.Unlock()
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].Started.Before(calls[j].Started)
+	})
+	return calls
+}
+
+// GetRecentErrors returns the recent failed calls, newest first.
+func (p *DebugPage) GetRecentErrors() []*DebugCall {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	calls := make([]*DebugCall, len(p.errors))
+	for i, call := range p.errors {
+		calls[len(calls)-1-i] = call
+	}
+	return calls
+}
+
+// ServeHTTP renders the debug page.
+func (p *DebugPage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = debugPageTmpl.Execute(w, &debugPageData{
+		Services: p.mux.Services(),
+		Active:   p.GetActiveCalls(),
+		Errors:   p.GetRecentErrors(),
+		Now:      time.Now(),
+	})
+}
+
+// debugPageData is the data rendered by debugPageTmpl.
+type debugPageData struct {
+	Services []ServiceInfo
+	Active   []*DebugCall
+	Errors   []*DebugCall
+	Now      time.Time
+}
+
+// debugPageTmpl is the template of the debug page.
+var debugPageTmpl = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head><title>srpc</title></head>
+<body>
+<h2>Services</h2>
+<table>
+<tr><th>Service</th><th>Method</th><th>Handler</th><th>Cache TTL</th><th>Deprecated calls</th></tr>
+{{- range $svc := .Services}}{{range .Methods}}
+<tr><td>{{$svc.ServiceID}}</td><td>{{.MethodID}}</td><td>{{.HandlerType}}</td><td>{{if .CacheTTL}}{{.CacheTTL}}{{end}}</td><td>{{if .Deprecated}}{{.DeprecatedCalls}}{{end}}</td></tr>
+{{- end}}{{end}}
+</table>
+<h2>Active calls ({{len .Active}})</h2>
+<table>
+<tr><th>Service</th><th>Method</th><th>Duration</th></tr>
+{{- range .Active}}
+<tr><td>{{.ServiceID}}</td><td>{{.MethodID}}</td><td>{{$.Now.Sub .Started}}</td></tr>
+{{- end}}
+</table>
+<h2>Recent errors</h2>
+<table>
+<tr><th>Time</th><th>Service</th><th>Method</th><th>Error</th></tr>
+{{- range .Errors}}
+<tr><td>{{.Finished.Format "2006-01-02 15:04:05.000"}}</td><td>{{.ServiceID}}</td><td>{{.MethodID}}</td><td>{{.Err}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// _ is a type assertion
+var (
+	_ http.Handler      = ((*DebugPage)(nil))
+	_ ServerInterceptor = ((*DebugPage)(nil)).Intercept
+)
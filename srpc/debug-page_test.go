@@ -0,0 +1,73 @@
+package srpc_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestDebugPage(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &blockingEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		started:    make(chan struct{}, 1),
+		release:    make(chan struct{}),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	page := srpc.NewDebugPage(mux)
+	server := srpc.NewServer(mux, srpc.WithInterceptors(page.Intercept))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	ctx := context.Background()
+
+	// expect the blocked call to be listed as active
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"})
+		errCh <- err
+	}()
+	<-echoServer.started
+	if calls := page.GetActiveCalls(); len(calls) != 1 || calls[0].MethodID != "Echo" {
+		t.Fatalf("expected 1 active call got %v", calls)
+	}
+	close(echoServer.release)
+	if err := <-errCh; err != nil {
+		t.Fatal(err.Error())
+	}
+	if calls := page.GetActiveCalls(); len(calls) != 0 {
+		t.Fatalf("expected no active calls got %v", calls)
+	}
+
+	// expect failed calls to be listed as recent errors
+	errMux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(errMux, &errorEchoServer{EchoServer: echo.NewEchoServer(errMux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	errServer := srpc.NewServer(errMux, srpc.WithInterceptors(page.Intercept))
+	if _, err := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(errServer))).Echo(ctx, &echo.EchoMsg{Body: "not found"}); err == nil {
+		t.Fatal("expected error")
+	}
+	errs := page.GetRecentErrors()
+	if len(errs) != 1 || errs[0].MethodID != "Echo" || errs[0].Err == nil {
+		t.Fatalf("expected 1 recent error got %v", errs)
+	}
+
+	rec := httptest.NewRecorder()
+	page.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	body, _ := io.ReadAll(rec.Result().Body)
+	for _, expected := range []string{
+		"<td>" + echo.SRPCEchoerServiceID + "</td><td>EchoBidiStream</td>",
+		"Active calls (0)",
+		"<td>Echo</td><td>not found</td>",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Fatalf("expected page to contain %q: %s", expected, body)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package srpc
+
+// DeprecatedMetadataKey is the response metadata key set when a deprecated
+// method is called. The value is a warning naming the method.
+//
+// See WithResponseMetadata.
+const DeprecatedMetadataKey = "srpc-deprecated"
+
+// DeprecationHandler is called when a deprecated method is invoked.
+//
+// Use this to log a warning or increment a metric before removing a method.
+// Must not block: called before invoking the method handler.
+type DeprecationHandler = func(serviceID, methodID string)
@@ -0,0 +1,57 @@
+package srpc
+
+import (
+	"context"
+	"time"
+)
+
+// serverCtxKey is the context key for the server lifetime context.
+type serverCtxKey struct{}
+
+// withServerContext attaches the server lifetime context to ctx.
+func withServerContext(ctx, serverCtx context.Context) context.Context {
+	return context.WithValue(ctx, serverCtxKey{}, serverCtx)
+}
+
+// DetachContext returns a context which is not canceled when ctx is canceled.
+//
+// The returned context carries the values of ctx (metadata, ConnInfo, etc.)
+// but is bound by the lifetime of the server instead of the RPC. Use it for
+// cleanup work which should outlive the call. Set the server lifetime with
+// WithServerContext; if unset the returned context is never canceled.
+func DetachContext(ctx context.Context) context.Context {
+	serverCtx, _ := ctx.Value(serverCtxKey{}).(context.Context)
+	if serverCtx == nil {
+		serverCtx = context.Background()
+	}
+	return &detachedContext{parent: ctx, lifetime: serverCtx}
+}
+
+// detachedContext takes values from parent and cancellation from lifetime.
+type detachedContext struct {
+	parent   context.Context
+	lifetime context.Context
+}
+
+// Deadline returns the deadline of the lifetime context.
+func (c *detachedContext) Deadline() (time.Time, bool) {
+	return c.lifetime.Deadline()
+}
+
+// Done returns the done channel of the lifetime context.
+func (c *detachedContext) Done() <-chan struct{} {
+	return c.lifetime.Done()
+}
+
+// Err returns the error of the lifetime context.
+func (c *detachedContext) Err() error {
+	return c.lifetime.Err()
+}
+
+// Value looks up the value in the parent context.
+func (c *detachedContext) Value(key any) any {
+	return c.parent.Value(key)
+}
+
+// _ is a type assertion
+var _ context.Context = ((*detachedContext)(nil))
@@ -0,0 +1,78 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// detachEchoServer hands a detached context to the test.
+type detachEchoServer struct {
+	*echo.EchoServer
+	// detachedCh receives the detached context.
+	detachedCh chan context.Context
+}
+
+func TestDetachContext(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &detachEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		detachedCh: make(chan context.Context, 1),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	serverCtx, serverCtxCancel := context.WithCancel(context.Background())
+	defer serverCtxCancel()
+	server := srpc.NewServer(mux, srpc.WithServerContext(serverCtx))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	ctx = srpc.AppendToOutgoingContext(ctx, "key", "value")
+	defer ctxCancel()
+	strm, err := client.EchoServerStream(ctx, &echo.EchoMsg{Body: "hello world"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var detached context.Context
+	select {
+	case detached = <-echoServer.detachedCh:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the detached context")
+	}
+	ctxCancel()
+
+	// expect the detached context to outlive the call
+	<-time.After(time.Millisecond * 50)
+	if err := detached.Err(); err != nil {
+		t.Fatalf("expected detached context to be active: %v", err)
+	}
+	if val := srpc.MetadataFromIncomingContext(detached).Get("key"); val != "value" {
+		t.Fatalf("expected detached context to carry metadata, got %q", val)
+	}
+
+	// expect the detached context to be canceled with the server
+	serverCtxCancel()
+	select {
+	case <-detached.Done():
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the detached context to be canceled")
+	}
+}
+
+// EchoServerStream sends one message, then detaches and waits for cancel.
+func (s *detachEchoServer) EchoServerStream(msg *echo.EchoMsg, strm echo.SRPCEchoer_EchoServerStreamStream) error {
+	if err := strm.Send(msg); err != nil {
+		return err
+	}
+	s.detachedCh <- srpc.DetachContext(strm.Context())
+	<-strm.Context().Done()
+	return context.Canceled
+}
@@ -0,0 +1,81 @@
+package srpc
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// errorDetailsSenderCtxKey is the context key for the errorDetailsSender.
+type errorDetailsSenderCtxKey struct{}
+
+// errorDetailsSender sends diagnostic error details for a server call.
+type errorDetailsSender interface {
+	// sendErrorDetails writes an error details packet.
+	sendErrorDetails(details []string) error
+}
+
+// withErrorDetailsSender attaches the errorDetailsSender to the context.
+func withErrorDetailsSender(ctx context.Context, sender errorDetailsSender) context.Context {
+	return context.WithValue(ctx, errorDetailsSenderCtxKey{}, sender)
+}
+
+// SendErrorDetails sends diagnostic error messages to the client of a call.
+//
+// ctx must be the handler context of a server call. The call stays open: the
+// handler can report many problems (for example validation errors) as they
+// are found and complete the call afterwards. The client receives them via
+// WithErrorDetails and with the DetailedError returned if the call fails.
+// The server error policy is applied to the details, see WithErrorPolicy.
+//
+// Returns ErrNoServerCall if ctx does not belong to a server call, or
+// ErrCompleted if the call already completed.
+func SendErrorDetails(ctx context.Context, details ...string) error {
+	sender, _ := ctx.Value(errorDetailsSenderCtxKey{}).(errorDetailsSender)
+	if sender == nil {
+		return ErrNoServerCall
+	}
+	if len(details) == 0 {
+		return nil
+	}
+	return sender.sendErrorDetails(details)
+}
+
+// DetailedError is an error with diagnostic error details.
+//
+// Return a DetailedError from a handler to send the details with the final
+// packet of the call. The client returns a DetailedError with all of the
+// details sent during the call if the call fails.
+type DetailedError struct {
+	// Err is the error which completed the call.
+	Err error
+	// Details contains the diagnostic error messages.
+	Details []string
+}
+
+// NewDetailedError constructs a new DetailedError.
+func NewDetailedError(err error, details ...string) *DetailedError {
+	return &DetailedError{Err: err, Details: details}
+}
+
+// Error returns the error string of the wrapped error.
+func (e *DetailedError) Error() string {
+	if e.Err == nil {
+		return "error details"
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *DetailedError) Unwrap() error {
+	return e.Err
+}
+
+// GetErrorDetails returns the error details attached to err, if any.
+func GetErrorDetails(err error) []string {
+	var de *DetailedError
+	if !errors.As(err, &de) {
+		return nil
+	}
+	return de.Details
+}
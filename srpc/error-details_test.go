@@ -0,0 +1,90 @@
+package srpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// validatingEchoServer reports validation problems as error details.
+type validatingEchoServer struct {
+	*echo.EchoServer
+}
+
+func TestErrorDetails(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &validatingEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	var streamed []string
+	ctx := srpc.WithCallOptions(context.Background(), srpc.WithErrorDetails(func(details []string) {
+		streamed = append(streamed, details...)
+	}))
+	_, err := client.Echo(ctx, &echo.EchoMsg{Body: "hi"})
+	if err == nil || err.Error() != "invalid message" {
+		t.Fatalf("expected invalid message error got %v", err)
+	}
+	expected := []string{"body: too short", "body: not capitalized", "body: missing punctuation"}
+	details := srpc.GetErrorDetails(err)
+	if strings.Join(details, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected details %v got %v", expected, details)
+	}
+	if strings.Join(streamed, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected streamed details %v got %v", expected, streamed)
+	}
+
+	if err := srpc.SendErrorDetails(context.Background(), "x"); err != srpc.ErrNoServerCall {
+		t.Fatalf("expected %v got %v", srpc.ErrNoServerCall, err)
+	}
+}
+
+func TestErrorDetails_ErrorPolicy(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &validatingEchoServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	call := func(policy *srpc.ErrorPolicy) (streamed, details []string) {
+		client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux, srpc.WithErrorPolicy(policy)))))
+		ctx := srpc.WithCallOptions(context.Background(), srpc.WithErrorDetails(func(details []string) {
+			streamed = append(streamed, details...)
+		}))
+		_, err := client.Echo(ctx, &echo.EchoMsg{Body: "hi"})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		return streamed, srpc.GetErrorDetails(err)
+	}
+
+	// expect the details to be truncated to the max length
+	streamed, details := call(&srpc.ErrorPolicy{MaxLength: 10})
+	if strings.Join(streamed, ",") != "body: t...,body: n...,body: m..." {
+		t.Fatalf("expected truncated details got %v", streamed)
+	}
+	if strings.Join(details, ",") != strings.Join(streamed, ",") {
+		t.Fatalf("expected truncated error details got %v", details)
+	}
+
+	// expect opaque errors to drop the streamed details
+	streamed, details = call(srpc.ProdErrorPolicy(nil))
+	if len(streamed) != 0 || len(details) != 0 {
+		t.Fatalf("expected no details got %v %v", streamed, details)
+	}
+}
+
+// Echo reports each problem with the message, then fails the call.
+func (s *validatingEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	if err := srpc.SendErrorDetails(ctx, "body: too short"); err != nil {
+		return nil, err
+	}
+	if err := srpc.SendErrorDetails(ctx, "body: not capitalized"); err != nil {
+		return nil, err
+	}
+	return nil, srpc.NewDetailedError(errors.New("invalid message"), "body: missing punctuation")
+}
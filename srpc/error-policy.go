@@ -0,0 +1,284 @@
+package srpc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxErrorLength is the max error length used by ProdErrorPolicy.
+const DefaultMaxErrorLength = 1024
+
+// ErrorSanitizer rewrites an error returned by a handler before it is sent.
+//
+// ctx is the handler context. Must not return nil.
+type ErrorSanitizer func(ctx context.Context, err error) error
+
+// ErrorPolicy controls the errors sent to the client.
+type ErrorPolicy struct {
+	// MaxLength is the max length of error strings in bytes.
+	// Longer errors are truncated. Zero for no limit.
+	MaxLength int
+	// Sanitize rewrites errors before they are sent, if set.
+	Sanitize ErrorSanitizer
+	// Logger is called with the errors returned by handlers, if set.
+	//
+	// Each error is assigned an opaque ID which is appended to the error
+	// sent to the client, see GetErrorID. The built-in srpc errors, such as
+	// ErrUnimplemented, and context errors are not logged.
+	Logger ErrorLogger
+}
+
+// ErrorReport is an error returned by a handler.
+type ErrorReport struct {
+	// ID is the opaque error ID sent to the client.
+	ID string
+	// ServiceID is the service of the call.
+	ServiceID string
+	// MethodID is the method of the call.
+	MethodID string
+	// Err is the error returned by the handler before sanitizing.
+	Err error
+}
+
+// ErrorLogger logs errors returned by handlers.
+//
+// Log the error with %+v to include the stack trace, if any.
+type ErrorLogger func(ctx context.Context, report *ErrorReport)
+
+// DevErrorPolicy returns the policy sending errors verbatim.
+func DevErrorPolicy() *ErrorPolicy {
+	return &ErrorPolicy{}
+}
+
+// ProdErrorPolicy returns the policy hiding errors behind an opaque ID.
+//
+// Sends the status code and the opaque ID of the error logged with the logger.
+// See OpaqueErrors. Also limits errors to DefaultMaxErrorLength.
+func ProdErrorPolicy(logger ErrorLogger) *ErrorPolicy {
+	return &ErrorPolicy{
+		MaxLength: DefaultMaxErrorLength,
+		Sanitize:  OpaqueErrors,
+		Logger:    logger,
+	}
+}
+
+// WithErrorPolicy applies the policy to the errors returned by handlers.
+//
+// Defaults to sending errors verbatim.
+func WithErrorPolicy(policy *ErrorPolicy) ServerOption {
+	return func(s *Server) {
+		s.errorPolicy = policy
+	}
+}
+
+// OpaqueErrors replaces errors with "internal error".
+//
+// Keeps the status code of the error, if any. Error details are dropped. The
+// built-in srpc errors, such as ErrUnimplemented, and context errors are sent
+// verbatim. Use with ErrorPolicy.Logger to correlate errors with the logs.
+func OpaqueErrors(ctx context.Context, err error) error {
+	if isPassthroughError(err) {
+		return err
+	}
+	const msg = "internal error"
+	if st := StatusFromError(err); st != nil {
+		return &StatusError{Status: &Status{Code: st.GetCode(), Message: msg}}
+	}
+	return errors.New(msg)
+}
+
+// errorIDRe matches the error id appended to errors.
+var errorIDRe = regexp.MustCompile(`\(error id: ([0-9a-f]+)\)$`)
+
+// GetErrorID returns the opaque ID of an error received from the server.
+//
+// Returns empty if the error has no ID, see ErrorPolicy.Logger.
+func GetErrorID(err error) string {
+	if err == nil {
+		return ""
+	}
+	match := errorIDRe.FindStringSubmatch(err.Error())
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// errorPathRe matches file paths in error strings.
+var errorPathRe = regexp.MustCompile(`(?:[A-Za-z]:)?(?:[\\/][\w.\-@+]+)+[\\/]([\w.\-@+]+)`)
+
+// SanitizeErrorPaths strips stack traces and directories from errors.
+//
+// Keeps the first line of the error and replaces file paths with the base
+// name. The status code of the error is kept and the error details are
+// sanitized in the same way.
+func SanitizeErrorPaths(ctx context.Context, err error) error {
+	msg := SanitizeErrorString(err.Error())
+	changed := msg != err.Error()
+	details := GetErrorDetails(err)
+	if len(details) != 0 {
+		sanitized := make([]string, len(details))
+		for i, detail := range details {
+			sanitized[i] = SanitizeErrorString(detail)
+			changed = changed || sanitized[i] != detail
+		}
+		details = sanitized
+	}
+	if !changed {
+		return err
+	}
+	var out error
+	if st := StatusFromError(err); st != nil {
+		out = &StatusError{Status: &Status{Code: st.GetCode(), Message: msg}}
+	} else {
+		out = errors.New(msg)
+	}
+	if len(details) != 0 {
+		out = NewDetailedError(out, details...)
+	}
+	return out
+}
+
+// SanitizeErrorString strips stack traces and directories from an error string.
+func SanitizeErrorString(msg string) string {
+	if idx := strings.IndexByte(msg, '\n'); idx != -1 {
+		msg = msg[:idx]
+	}
+	return errorPathRe.ReplaceAllStringFunc(msg, func(p string) string {
+		return path.Base(strings.ReplaceAll(p, "\\", "/"))
+	})
+}
+
+// apply applies the policy to the error returned by the method.
+func (p *ErrorPolicy) apply(ctx context.Context, serviceID, methodID string, err error) error {
+	if p == nil || err == nil {
+		return err
+	}
+	var id string
+	if p.Logger != nil && !isPassthroughError(err) {
+		id = newErrorID()
+		p.Logger(ctx, &ErrorReport{ID: id, ServiceID: serviceID, MethodID: methodID, Err: err})
+	}
+	if p.Sanitize != nil {
+		err = p.Sanitize(ctx, err)
+	}
+	var suffix string
+	if id != "" {
+		suffix = " (error id: " + id + ")"
+	}
+	if p.MaxLength > 0 {
+		// reserve room for the error id so that it is never truncated
+		maxLen := p.MaxLength - len(suffix)
+		if maxLen < 0 {
+			maxLen = 0
+		}
+		if msg := err.Error(); len(msg) > maxLen {
+			err = &messageError{err: err, msg: truncateString(msg, maxLen)}
+		}
+	}
+	if suffix != "" {
+		err = &messageError{err: err, msg: err.Error() + suffix}
+	}
+	return err
+}
+
+// applyDetails applies the policy to the error details sent during the call.
+//
+// The details are sanitized as a DetailedError: details dropped by the
+// sanitizer are not sent. Each detail is truncated to MaxLength.
+func (p *ErrorPolicy) applyDetails(ctx context.Context, details []string) []string {
+	if p == nil {
+		return details
+	}
+	if p.Sanitize != nil {
+		details = GetErrorDetails(p.Sanitize(ctx, NewDetailedError(nil, details...)))
+	}
+	return p.truncateDetails(details)
+}
+
+// truncateDetails truncates each error detail to MaxLength.
+func (p *ErrorPolicy) truncateDetails(details []string) []string {
+	if p == nil || p.MaxLength <= 0 || len(details) == 0 {
+		return details
+	}
+	out := make([]string, len(details))
+	for i, detail := range details {
+		if len(detail) > p.MaxLength {
+			detail = truncateString(detail, p.MaxLength)
+		}
+		out[i] = detail
+	}
+	return out
+}
+
+// messageError is an error with a rewritten message.
+type messageError struct {
+	err error
+	msg string
+}
+
+// Error returns the rewritten message.
+func (e *messageError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error.
+func (e *messageError) Unwrap() error {
+	return e.err
+}
+
+// Cause returns the original error.
+func (e *messageError) Cause() error {
+	return e.err
+}
+
+// truncateString truncates s to at most maxLen bytes on a rune boundary.
+func truncateString(s string, maxLen int) string {
+	const suffix = "..."
+	if maxLen <= len(suffix) {
+		return s[:maxLen]
+	}
+	cut := maxLen - len(suffix)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + suffix
+}
+
+// isPassthroughError checks if the error is a built-in error sent verbatim.
+func isPassthroughError(err error) bool {
+	for _, perr := range passthroughErrors {
+		if err == perr {
+			return true
+		}
+	}
+	return false
+}
+
+// passthroughErrors are the errors not hidden by OpaqueErrors.
+var passthroughErrors = []error{
+	ErrUnimplemented,
+	ErrCanceledByPeer,
+	ErrUnavailable,
+	ErrTooManyCalls,
+	ErrTooManyStreams,
+	ErrDraining,
+	ErrOverloaded,
+	ErrInvalidMessage,
+	context.Canceled,
+	context.DeadlineExceeded,
+}
+
+// newErrorID returns a new random error id.
+func newErrorID() string {
+	var buf [8]byte
+	_, _ = rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
@@ -0,0 +1,120 @@
+package srpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/status"
+	"github.com/pkg/errors"
+)
+
+// errorEchoServer returns the message body as the error.
+type errorEchoServer struct {
+	*echo.EchoServer
+}
+
+func TestErrorPolicy(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &errorEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx := context.Background()
+
+	// dev: verbose with the max length.
+	devPolicy := srpc.DevErrorPolicy()
+	devPolicy.MaxLength = 16
+	devPolicy.Sanitize = srpc.SanitizeErrorPaths
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux, srpc.WithErrorPolicy(devPolicy)))))
+	_, err := client.Echo(ctx, &echo.EchoMsg{Body: "open /home/user/src/app/config.yaml: missing\ngoroutine 1"})
+	if err == nil || err.Error() != "open config.y..." {
+		t.Fatalf("expected truncated sanitized error got %v", err)
+	}
+	if code := status.Convert(err).Code(); code != status.NotFound {
+		t.Fatalf("expected NotFound got %v", code)
+	}
+
+	// prod: opaque id correlated with the logs.
+	var logged []*srpc.ErrorReport
+	prodPolicy := srpc.ProdErrorPolicy(func(ctx context.Context, report *srpc.ErrorReport) {
+		logged = append(logged, report)
+	})
+	client = echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux, srpc.WithErrorPolicy(prodPolicy)))))
+	_, err = client.Echo(ctx, &echo.EchoMsg{Body: "db password rejected"})
+	if len(logged) != 1 {
+		t.Fatalf("expected 1 logged error got %d", len(logged))
+	}
+	report := logged[0]
+	if err == nil || err.Error() != "internal error (error id: "+report.ID+")" {
+		t.Fatalf("expected opaque error got %v", err)
+	}
+	if id := srpc.GetErrorID(err); id != report.ID {
+		t.Fatalf("expected error id %s got %s", report.ID, id)
+	}
+	if report.Err.Error() != "db password rejected" || report.MethodID != "Echo" {
+		t.Fatalf("unexpected logged error %v for %s", report.Err, report.MethodID)
+	}
+	if code := status.Convert(err).Code(); code != status.NotFound {
+		t.Fatalf("expected NotFound got %v", code)
+	}
+	_, err = client.Echo(ctx, &echo.EchoMsg{Body: "unimplemented"})
+	if err == nil || err.Error() != srpc.ErrUnimplemented.Error() || srpc.GetErrorID(err) != "" {
+		t.Fatalf("expected unimplemented error got %v", err)
+	}
+	if len(logged) != 1 {
+		t.Fatalf("expected built-in error not logged got %d", len(logged))
+	}
+}
+
+func TestErrorPolicy_MaxLengthWithID(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &errorEchoServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	var logged []*srpc.ErrorReport
+	policy := &srpc.ErrorPolicy{
+		MaxLength: 64,
+		Logger: func(ctx context.Context, report *srpc.ErrorReport) {
+			logged = append(logged, report)
+		},
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux, srpc.WithErrorPolicy(policy)))))
+
+	// expect the message to be truncated to make room for the error id
+	_, err := client.Echo(context.Background(), &echo.EchoMsg{Body: strings.Repeat("x", 200)})
+	if err == nil || len(logged) != 1 {
+		t.Fatalf("expected logged error got %v", err)
+	}
+	if len(err.Error()) != policy.MaxLength {
+		t.Fatalf("expected error of %d bytes got %d: %v", policy.MaxLength, len(err.Error()), err)
+	}
+	if !strings.HasSuffix(err.Error(), "... (error id: "+logged[0].ID+")") {
+		t.Fatalf("expected truncated error with the id got %v", err)
+	}
+	if id := srpc.GetErrorID(err); id != logged[0].ID {
+		t.Fatalf("expected error id %s got %s", logged[0].ID, id)
+	}
+}
+
+func TestSanitizeErrorPaths_Details(t *testing.T) {
+	err := srpc.NewDetailedError(errors.New("invalid config"), "open /home/user/src/app/config.yaml: missing", "port: too large")
+	sanitized := srpc.SanitizeErrorPaths(context.Background(), err)
+	if sanitized.Error() != "invalid config" {
+		t.Fatalf("unexpected error: %v", sanitized)
+	}
+	details := srpc.GetErrorDetails(sanitized)
+	if strings.Join(details, ",") != "open config.yaml: missing,port: too large" {
+		t.Fatalf("expected sanitized details got %v", details)
+	}
+}
+
+// Echo returns the body as the error with the NotFound code.
+func (s *errorEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	if msg.GetBody() == "unimplemented" {
+		return nil, srpc.ErrUnimplemented
+	}
+	return nil, status.Error(status.NotFound, msg.GetBody())
+}
@@ -17,4 +17,46 @@ var (
 	ErrEmptyMethodID = errors.New("method id empty")
 	// ErrEmptyServiceID is returned if the service id was empty.
 	ErrEmptyServiceID = errors.New("service id empty")
+	// ErrCanceledByPeer is returned if the remote canceled the call.
+	ErrCanceledByPeer = errors.New("call canceled by peer")
+	// ErrRecvTimeout is returned if no message was received before the timeout.
+	ErrRecvTimeout = errors.New("timeout waiting for message")
+	// ErrSendTimeout is returned if the deadline of a send expired.
+	ErrSendTimeout = errors.New("timeout sending message")
+	// ErrStreamNotDetachable is returned if the stream cannot be detached.
+	ErrStreamNotDetachable = errors.New("stream cannot be detached")
+	// ErrUnavailable is returned if the server is not ready to handle calls.
+	ErrUnavailable = errors.New("unavailable")
+	// ErrTooManyCalls is returned if the server concurrent call limit was exceeded.
+	ErrTooManyCalls = errors.New("too many concurrent calls")
+	// ErrTooManyStreams is returned if the connection concurrent stream limit was exceeded.
+	ErrTooManyStreams = errors.New("too many concurrent streams on the connection")
+	// ErrNoServerCall is returned if the context does not belong to a server call.
+	ErrNoServerCall = errors.New("context does not belong to a server call")
+	// ErrDraining is returned if the server is shutting down and will not accept new calls.
+	// The call was not started and can be retried with another server.
+	ErrDraining = errors.New("server is draining")
+	// ErrClientClosed is returned if the client was closed.
+	ErrClientClosed = errors.New("client closed")
+	// ErrOverloaded is returned if the server rejected the call because it is overloaded.
+	ErrOverloaded = errors.New("server overloaded")
+	// ErrRegisterConflict is returned if a method handler is already registered.
+	ErrRegisterConflict = errors.New("method handler already registered")
+	// ErrNoAvailableClients is returned if there are no clients to start the call with.
+	ErrNoAvailableClients = errors.New("no available clients")
+	// ErrHeartbeatTimeout is returned if the remote did not respond to a heartbeat ping in time.
+	ErrHeartbeatTimeout = errors.New("heartbeat timed out")
+	// ErrStreamClosed is returned if the stream closed before the call completed.
+	ErrStreamClosed = errors.New("stream closed before the call completed")
+	// ErrDecompressedTooLarge is returned if a decompressed message exceeds the max message size.
+	ErrDecompressedTooLarge = errors.New("decompressed message larger than maximum")
+	// ErrRecvMsgTooLarge is returned if a received message exceeds the max receive size of the call.
+	ErrRecvMsgTooLarge = errors.New("received message larger than maximum")
+	// ErrConnReset is returned if the connection dropped while the call was in flight.
+	// The server may have handled the call.
+	ErrConnReset = errors.New("connection reset")
+	// ErrNoCall is returned if the context does not belong to a call.
+	ErrNoCall = errors.New("context does not belong to a call")
+	// ErrSignalsUnsupported is returned if the remote does not accept signals.
+	ErrSignalsUnsupported = errors.New("remote does not support signals")
 )
@@ -0,0 +1,92 @@
+// Package frame implements the length-prefixed framing used by starpc.
+//
+// Each frame is a length prefix followed by the frame data. The default
+// format used by starpc is a little-endian uint32 prefix. An unsigned varint
+// prefix is also supported for protocols which prefer it.
+//
+// The package has no dependency on the RPC logic and can be used to implement
+// or proxy the protocol.
+package frame
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// DefaultMaxSize is the default maximum frame size in bytes.
+const DefaultMaxSize = 10000000
+
+var (
+	// ErrFrameTooLarge is returned if a frame exceeds the maximum size.
+	ErrFrameTooLarge = errors.New("frame size greater than maximum")
+	// ErrUnknownFormat is returned if the framing format is not recognized.
+	ErrUnknownFormat = errors.New("unknown framing format")
+	// ErrInvalidPrefix is returned if the length prefix could not be parsed.
+	ErrInvalidPrefix = errors.New("invalid length prefix")
+)
+
+// Format is a length prefix format.
+type Format int
+
+const (
+	// FormatUint32LE is a little-endian uint32 length prefix.
+	// This is the default format used by starpc.
+	FormatUint32LE Format = iota
+	// FormatVarint is an unsigned varint length prefix.
+	FormatVarint
+)
+
+// Validate checks if the format is recognized.
+func (f Format) Validate() error {
+	switch f {
+	case FormatUint32LE, FormatVarint:
+		return nil
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// PrefixSize returns the size of the length prefix for a frame of size bytes.
+func (f Format) PrefixSize(size int) int {
+	if f == FormatVarint {
+		var buf [binary.MaxVarintLen64]byte
+		return binary.PutUvarint(buf[:], uint64(size))
+	}
+	return 4
+}
+
+// AppendPrefix appends the length prefix for a frame of size bytes to dst.
+func (f Format) AppendPrefix(dst []byte, size int) []byte {
+	if f == FormatVarint {
+		var buf [binary.MaxVarintLen64]byte
+		n := binary.PutUvarint(buf[:], uint64(size))
+		return append(dst, buf[:n]...)
+	}
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], uint32(size))
+	return append(dst, buf[:]...)
+}
+
+// ParsePrefix parses the length prefix at the start of b.
+//
+// Returns the frame size and the prefix length.
+// Returns a zero prefix length if more data is needed.
+func (f Format) ParsePrefix(b []byte) (size uint64, n int, err error) {
+	if f == FormatVarint {
+		size, n = binary.Uvarint(b)
+		if n < 0 {
+			return 0, 0, ErrInvalidPrefix
+		}
+		return size, n, nil
+	}
+	if len(b) < 4 {
+		return 0, 0, nil
+	}
+	return uint64(binary.LittleEndian.Uint32(b)), 4, nil
+}
+
+// AppendFrame appends the length prefix and data to dst.
+func AppendFrame(dst []byte, f Format, data []byte) []byte {
+	dst = f.AppendPrefix(dst, len(data))
+	return append(dst, data...)
+}
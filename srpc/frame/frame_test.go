@@ -0,0 +1,61 @@
+package frame
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+)
+
+func TestFrame_RoundTrip(t *testing.T) {
+	frames := [][]byte{
+		[]byte("hello world"),
+		{},
+		bytes.Repeat([]byte{0xab}, 5000),
+	}
+	for _, format := range []Format{FormatUint32LE, FormatVarint} {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, format)
+		for _, data := range frames {
+			if err := w.WriteFrame(data); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+
+		// read one byte at a time to exercise partial prefixes
+		r := NewReader(iotest.OneByteReader(&buf), format, 0)
+		for i, expected := range frames {
+			data, err := r.ReadFrame()
+			if err != nil {
+				t.Fatalf("format %d frame %d: %v", format, i, err)
+			}
+			if !bytes.Equal(data, expected) {
+				t.Fatalf("format %d frame %d: data mismatch", format, i)
+			}
+		}
+		if _, err := r.ReadFrame(); err != io.EOF {
+			t.Fatalf("format %d: expected io.EOF got %v", format, err)
+		}
+	}
+}
+
+func TestFrame_MaxSize(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewWriter(&buf, FormatUint32LE).WriteFrame(make([]byte, 100)); err != nil {
+		t.Fatal(err.Error())
+	}
+	_, err := NewReader(&buf, FormatUint32LE, 10).ReadFrame()
+	if errors.Cause(err) != ErrFrameTooLarge {
+		t.Fatalf("expected %v got %v", ErrFrameTooLarge, err)
+	}
+}
+
+func TestFrame_Truncated(t *testing.T) {
+	framed := AppendFrame(nil, FormatVarint, []byte("hello world"))
+	r := NewReader(bytes.NewReader(framed[:len(framed)-1]), FormatVarint, 0)
+	if _, err := r.ReadFrame(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected %v got %v", io.ErrUnexpectedEOF, err)
+	}
+}
@@ -0,0 +1,102 @@
+package frame
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Reader reads frames from an io.Reader.
+type Reader struct {
+	// r is the reader
+	r io.Reader
+	// format is the framing format
+	format Format
+	// maxSize is the maximum frame size
+	maxSize uint64
+	// buf contains the buffered data
+	buf bytes.Buffer
+	// readBuf is the buffer used for reads
+	readBuf []byte
+	// err is the error returned by the reader, if any
+	err error
+}
+
+// NewReader constructs a new Reader.
+//
+// If maxSize is zero, uses DefaultMaxSize.
+func NewReader(r io.Reader, format Format, maxSize int) *Reader {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &Reader{
+		r:       r,
+		format:  format,
+		maxSize: uint64(maxSize),
+		readBuf: make([]byte, 2048),
+	}
+}
+
+// ReadFrame reads the next frame.
+//
+// The returned data is valid until the next call to the Reader.
+// Returns io.EOF if the reader ended after a complete frame, or
+// io.ErrUnexpectedEOF if it ended within a frame.
+func (r *Reader) ReadFrame() ([]byte, error) {
+	for {
+		data, ok, err := r.nextBuffered()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return data, nil
+		}
+		if r.err != nil {
+			if r.err == io.EOF && r.buf.Len() != 0 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, r.err
+		}
+		n, err := r.r.Read(r.readBuf)
+		_, _ = r.buf.Write(r.readBuf[:n])
+		if err != nil {
+			r.err = err
+		}
+	}
+}
+
+// ReadToHandler reads frames and calls cb with each frame until closed.
+//
+// The data passed to cb is valid until cb returns.
+// Returns nil when the reader is closed with io.EOF or context.Canceled.
+func (r *Reader) ReadToHandler(cb func(data []byte) error) error {
+	for {
+		data, err := r.ReadFrame()
+		if err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF || err == context.Canceled {
+				return nil
+			}
+			return err
+		}
+		if err := cb(data); err != nil {
+			return err
+		}
+	}
+}
+
+// nextBuffered returns the next fully buffered frame, if any.
+func (r *Reader) nextBuffered() ([]byte, bool, error) {
+	size, n, err := r.format.ParsePrefix(r.buf.Bytes())
+	if err != nil || n == 0 {
+		return nil, false, err
+	}
+	if size > r.maxSize {
+		return nil, false, errors.Wrapf(ErrFrameTooLarge, "%v > %v", size, r.maxSize)
+	}
+	if uint64(r.buf.Len()-n) < size {
+		return nil, false, nil
+	}
+	return r.buf.Next(n + int(size))[n:], true, nil
+}
@@ -0,0 +1,36 @@
+package frame
+
+import "io"
+
+// Writer writes frames to an io.Writer.
+type Writer struct {
+	// w is the writer
+	w io.Writer
+	// format is the framing format
+	format Format
+}
+
+// NewWriter constructs a new Writer.
+func NewWriter(w io.Writer, format Format) *Writer {
+	return &Writer{w: w, format: format}
+}
+
+// WriteFrame writes a frame with the data.
+func (w *Writer) WriteFrame(data []byte) error {
+	buf := make([]byte, 0, w.format.PrefixSize(len(data))+len(data))
+	return w.WriteRaw(AppendFrame(buf, w.format, data))
+}
+
+// WriteRaw writes already framed data to the writer.
+//
+// Use with AppendPrefix to marshal messages directly after the prefix.
+func (w *Writer) WriteRaw(framed []byte) error {
+	for written := 0; written < len(framed); {
+		n, err := w.w.Write(framed[written:])
+		if err != nil {
+			return err
+		}
+		written += n
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package srpc
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HandlerFactory constructs a Handler.
+type HandlerFactory = func() (Handler, error)
+
+// LazyHandler is a Handler which is constructed on the first call.
+//
+// Register it with the Mux to avoid paying the startup and memory cost of
+// rarely used services until they are called. The handler is released after
+// it has been idle for the idle timeout, and constructed again on the next
+// call. If the handler implements io.Closer, Close is called on release.
+type LazyHandler struct {
+	// serviceID is the service id
+	serviceID string
+	// methodIDs is the list of method ids
+	methodIDs []string
+	// factory constructs the handler
+	factory HandlerFactory
+	// idleTimeout is the duration to wait after the last call before release
+	// if zero, the handler is never released
+	idleTimeout time.Duration
+
+	// mtx guards below fields
+	mtx sync.Mutex
+	// handler is the constructed handler, if any
+	handler Handler
+	// active is the number of active calls
+	active int
+	// idleTimer releases the handler after the idle timeout
+	idleTimer *time.Timer
+	// idleGen is incremented when idleTimer is started or stopped
+	idleGen uint64
+}
+
+// NewLazyHandler constructs a new LazyHandler.
+//
+// serviceID and methodIDs must match the handler returned by factory. If
+// idleTimeout is zero, the handler is kept after it was constructed.
+func NewLazyHandler(serviceID string, methodIDs []string, factory HandlerFactory, idleTimeout time.Duration) *LazyHandler {
+	return &LazyHandler{
+		serviceID:   serviceID,
+		methodIDs:   methodIDs,
+		factory:     factory,
+		idleTimeout: idleTimeout,
+	}
+}
+
+// GetServiceID returns the ID of the service.
+func (l *LazyHandler) GetServiceID() string {
+	return l.serviceID
+}
+
+// GetMethodIDs returns the list of methods for the service.
+func (l *LazyHandler) GetMethodIDs() []string {
+	return l.methodIDs
+}
+
+// InvokeMethod constructs the handler if necessary and invokes the method.
+func (l *LazyHandler) InvokeMethod(serviceID, methodID string, strm Stream) (bool, error) {
+	handler, err := l.acquire()
+	if err != nil {
+		return true, err
+	}
+	defer l.release()
+	return handler.InvokeMethod(serviceID, methodID, strm)
+}
+
+// IsLoaded checks if the handler is currently constructed.
+func (l *LazyHandler) IsLoaded() bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.handler != nil
+}
+
+// acquire constructs the handler if necessary and adds an active call.
+func (l *LazyHandler) acquire() (Handler, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.idleTimer != nil {
+		l.idleTimer.Stop()
+		l.idleTimer = nil
+		l.idleGen++
+	}
+	if l.handler == nil {
+		handler, err := l.factory()
+		if err == nil && handler == nil {
+			err = errors.New("handler factory returned nil")
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "construct handler for %s", l.serviceID)
+		}
+		l.handler = handler
+	}
+	l.active++
+	return l.handler, nil
+}
+
+// release removes an active call and starts the idle timer.
+func (l *LazyHandler) release() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.active--
+	if l.active != 0 || l.idleTimeout <= 0 {
+		return
+	}
+	l.idleGen++
+	gen := l.idleGen
+	l.idleTimer = time.AfterFunc(l.idleTimeout, func() {
+		l.expire(gen)
+	})
+}
+
+// expire releases the handler if it is still idle.
+func (l *LazyHandler) expire(gen uint64) {
+	l.mtx.Lock()
+	if l.idleGen != gen || l.active != 0 {
+		l.mtx.Unlock()
+		return
+	}
+	handler := l.handler
+	l.handler, l.idleTimer = nil, nil
+	l.mtx.Unlock()
+	if closer, ok := handler.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+// CaptureHandler returns the Handler passed to Mux.Register by register.
+//
+// Useful to construct a generated handler in a HandlerFactory:
+//
+//	func() (srpc.Handler, error) {
+//		return srpc.CaptureHandler(func(mux srpc.Mux) error {
+//			return echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(nil))
+//		})
+//	}
+func CaptureHandler(register func(mux Mux) error) (Handler, error) {
+	cm := &captureMux{}
+	if err := register(cm); err != nil {
+		return nil, err
+	}
+	if cm.handler == nil {
+		return nil, errors.New("no handler was registered")
+	}
+	return cm.handler, nil
+}
+
+// captureMux is a Mux which captures the registered Handler.
+type captureMux struct {
+	// handler is the registered handler
+	handler Handler
+}
+
+// Register captures the handler.
+func (m *captureMux) Register(handler Handler) error {
+	if m.handler != nil {
+		return errors.New("more than one handler was registered")
+	}
+	m.handler = handler
+	return nil
+}
+
+// InvokeMethod returns false, nil.
+func (m *captureMux) InvokeMethod(serviceID, methodID string, strm Stream) (bool, error) {
+	return false, nil
+}
+
+// Services returns nil.
+func (m *captureMux) Services() []ServiceInfo {
+	return nil
+}
+
+// _ is a type assertion
+var (
+	_ Handler = ((*LazyHandler)(nil))
+	_ Mux     = ((*captureMux)(nil))
+)
@@ -0,0 +1,62 @@
+package srpc_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestLazyHandler(t *testing.T) {
+	mux := srpc.NewMux()
+	var constructed int32
+	lazy := srpc.NewLazyHandler(
+		echo.SRPCEchoerServiceID,
+		echo.SRPCEchoerHandler{}.GetMethodIDs(),
+		func() (srpc.Handler, error) {
+			atomic.AddInt32(&constructed, 1)
+			return srpc.CaptureHandler(func(mux srpc.Mux) error {
+				return echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(nil))
+			})
+		},
+		time.Millisecond*50,
+	)
+	if err := mux.Register(lazy); err != nil {
+		t.Fatal(err.Error())
+	}
+	if lazy.IsLoaded() || atomic.LoadInt32(&constructed) != 0 {
+		t.Fatal("expected handler to be constructed on the first call")
+	}
+
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	msg := &echo.EchoMsg{Body: "hello world"}
+	for i := 0; i < 2; i++ {
+		out, err := client.Echo(context.Background(), msg)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if out.GetBody() != msg.GetBody() {
+			t.Fatalf("unexpected response: %q", out.GetBody())
+		}
+	}
+	if !lazy.IsLoaded() || atomic.LoadInt32(&constructed) != 1 {
+		t.Fatalf("expected handler to be constructed once got %d", atomic.LoadInt32(&constructed))
+	}
+
+	// expect the handler to be released after the idle timeout
+	for i := 0; i < 50 && lazy.IsLoaded(); i++ {
+		<-time.After(time.Millisecond * 10)
+	}
+	if lazy.IsLoaded() {
+		t.Fatal("expected handler to be released after the idle timeout")
+	}
+	if _, err := client.Echo(context.Background(), msg); err != nil {
+		t.Fatal(err.Error())
+	}
+	if n := atomic.LoadInt32(&constructed); n != 2 {
+		t.Fatalf("expected handler to be constructed again got %d", n)
+	}
+}
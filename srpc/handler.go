@@ -1,5 +1,7 @@
 package srpc
 
+import "time"
+
 // Handler describes a SRPC call handler implementation.
 type Handler interface {
 	// GetServiceID returns the ID of the service.
@@ -11,3 +13,17 @@ type Handler interface {
 	// If service string is empty, ignore it.
 	InvokeMethod(serviceID, methodID string, strm Stream) (bool, error)
 }
+
+// DeprecatedMethodsHandler is an optional interface implemented by Handlers
+// which contain methods marked with the "deprecated" proto option.
+type DeprecatedMethodsHandler interface {
+	// GetDeprecatedMethodIDs returns the list of deprecated methods for the service.
+	GetDeprecatedMethodIDs() []string
+}
+
+// CachedMethodsHandler is an optional interface implemented by Handlers which
+// contain unary methods marked with the "cache_ttl_ms" proto option.
+type CachedMethodsHandler interface {
+	// GetMethodCacheTTLs returns the cache duration for each cached method.
+	GetMethodCacheTTLs() map[string]time.Duration
+}
@@ -0,0 +1,131 @@
+package srpc
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// HeartbeatParams configures the heartbeat of a call.
+//
+// If no packets were received for Interval, sends a Ping packet to the remote.
+// If no packets are received within Timeout after the Ping, the stream is
+// closed and the call fails with ErrHeartbeatTimeout. This detects dead peers
+// on idle streams of transports without a keepalive, such as raw TCP and
+// websocket streams.
+//
+// Pings are only sent if the remote advertised support with the CallStart
+// packet (client) or by acknowledging the heartbeat with a Pong (server).
+// Calls with older peers continue without a heartbeat.
+type HeartbeatParams struct {
+	// Interval is the idle duration before sending a Ping.
+	// Disables the heartbeat if zero.
+	Interval time.Duration
+	// Timeout is the duration to wait for a packet after sending a Ping.
+	// Defaults to Interval if zero.
+	Timeout time.Duration
+}
+
+// heartbeat sends Ping packets on an idle stream and detects timeouts.
+type heartbeat struct {
+	// params are the heartbeat params
+	params HeartbeatParams
+	// start is the time the heartbeat was constructed.
+	start time.Time
+	// lastRecv is the duration since start when the last packet was received.
+	// accessed with atomic
+	lastRecv int64
+	// enabled is set to 1 after the remote advertised support for Ping packets.
+	// accessed with atomic
+	enabled uint32
+	// expired is set to 1 if the remote did not respond in time.
+	// accessed with atomic
+	expired uint32
+}
+
+// newHeartbeat constructs a new heartbeat.
+//
+// Returns nil if the heartbeat is disabled.
+func newHeartbeat(params HeartbeatParams) *heartbeat {
+	if params.Interval <= 0 {
+		return nil
+	}
+	if params.Timeout <= 0 {
+		params.Timeout = params.Interval
+	}
+	return &heartbeat{params: params, start: time.Now()}
+}
+
+// touch records that a packet was received.
+func (h *heartbeat) touch() {
+	if h != nil {
+		atomic.StoreInt64(&h.lastRecv, int64(time.Since(h.start)))
+	}
+}
+
+// enable starts sending Ping packets: the remote supports them.
+func (h *heartbeat) enable() {
+	if h != nil {
+		atomic.StoreUint32(&h.enabled, 1)
+	}
+}
+
+// timedOut checks if the remote did not respond to a Ping in time.
+func (h *heartbeat) timedOut() bool {
+	return h != nil && atomic.LoadUint32(&h.expired) != 0
+}
+
+// err returns ErrHeartbeatTimeout if the heartbeat timed out.
+func (h *heartbeat) err() error {
+	if h.timedOut() {
+		return ErrHeartbeatTimeout
+	}
+	return nil
+}
+
+// run sends Ping packets with send until done is closed.
+//
+// Calls closeStream if the remote does not respond in time.
+func (h *heartbeat) run(send func(pkt *Packet) error, closeStream func(), done <-chan struct{}) {
+	timer := time.NewTimer(h.params.Interval)
+	defer timer.Stop()
+
+	var pingID uint64
+	var pingSent time.Duration
+	for {
+		select {
+		case <-done:
+			return
+		case <-timer.C:
+		}
+
+		now := time.Since(h.start)
+		lastRecv := time.Duration(atomic.LoadInt64(&h.lastRecv))
+		if pingSent != 0 {
+			if lastRecv < pingSent {
+				if wait := h.params.Timeout - (now - pingSent); wait > 0 {
+					timer.Reset(wait)
+					continue
+				}
+				atomic.StoreUint32(&h.expired, 1)
+				closeStream()
+				return
+			}
+			pingSent = 0
+		}
+
+		if idle := now - lastRecv; idle < h.params.Interval || atomic.LoadUint32(&h.enabled) == 0 {
+			if idle >= h.params.Interval {
+				idle = 0
+			}
+			timer.Reset(h.params.Interval - idle)
+			continue
+		}
+
+		pingID++
+		pingSent = now
+		if err := send(NewPingPacket(pingID)); err != nil {
+			return
+		}
+		timer.Reset(h.params.Timeout)
+	}
+}
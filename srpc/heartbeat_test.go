@@ -0,0 +1,291 @@
+package srpc_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// heartbeatParams are the heartbeat params used by the tests.
+var heartbeatParams = srpc.HeartbeatParams{
+	Interval: 20 * time.Millisecond,
+	Timeout:  20 * time.Millisecond,
+}
+
+// newHeartbeatServer constructs a echo server with the heartbeat enabled.
+func newHeartbeatServer(t *testing.T) *srpc.Server {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	return srpc.NewServer(mux, srpc.WithServerHeartbeat(heartbeatParams))
+}
+
+// newRemoteOpenStream opens streams with a remote implemented by handle.
+func newRemoteOpenStream(handle func(prw *srpc.PacketReaderWriter)) srpc.OpenStreamFunc {
+	return func(ctx context.Context, msgHandler srpc.PacketHandler, closeHandler srpc.CloseHandler) (srpc.Writer, error) {
+		remotePipe, localPipe := net.Pipe()
+		go handle(srpc.NewPacketReadWriter(remotePipe))
+		prw := srpc.NewPacketReadWriter(localPipe)
+		go prw.ReadPump(msgHandler, closeHandler)
+		return prw, nil
+	}
+}
+
+// writeCheckConn is a net.Conn which detects concurrent writes.
+type writeCheckConn struct {
+	net.Conn
+	// writing is the number of writes in progress
+	writing int32
+	// concurrent is set to 1 if two writes overlapped
+	concurrent int32
+}
+
+// Write writes the data and records if another write is in progress.
+func (c *writeCheckConn) Write(p []byte) (int, error) {
+	if atomic.AddInt32(&c.writing, 1) != 1 {
+		atomic.StoreInt32(&c.concurrent, 1)
+	}
+	defer atomic.AddInt32(&c.writing, -1)
+	// let other writers run to widen the window for overlapping writes
+	runtime.Gosched()
+	return c.Conn.Write(p)
+}
+
+// readPackets reads packets from prw until it is closed.
+//
+// Calls cb with each packet and stops if cb returns false.
+func readPackets(prw *srpc.PacketReaderWriter, cb func(pkt *srpc.Packet) bool) {
+	_ = prw.ReadToHandler(func(pkt *srpc.Packet) error {
+		if !cb(pkt) {
+			return io.EOF
+		}
+		return nil
+	})
+}
+
+func TestHeartbeat(t *testing.T) {
+	ctx := srpc.WithCallOptions(context.Background(), srpc.WithClientHeartbeat(heartbeatParams))
+	server := newHeartbeatServer(t)
+
+	// expect idle calls with a responsive server to stay open
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	strm, err := client.EchoBidiStream(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != nil {
+		t.Fatal(err.Error())
+	}
+	<-time.After(heartbeatParams.Interval * 5)
+	if err := strm.Send(&echo.EchoMsg{Body: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if msg, err := strm.Recv(); err != nil || msg.GetBody() != "hello" {
+		t.Fatalf("expected echo got %v %v", msg, err)
+	}
+	if err := strm.CloseSend(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != io.EOF {
+		t.Fatalf("expected %v got %v", io.EOF, err)
+	}
+	_ = strm.Close()
+}
+
+func TestHeartbeat_UnresponsiveServer(t *testing.T) {
+	ctx := srpc.WithCallOptions(context.Background(), srpc.WithClientHeartbeat(heartbeatParams))
+
+	// the server acknowledges the heartbeat and never responds again
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(newRemoteOpenStream(func(prw *srpc.PacketReaderWriter) {
+		readPackets(prw, func(pkt *srpc.Packet) bool {
+			if pkt.GetCallStart().GetHeartbeat() {
+				_ = prw.WritePacket(srpc.NewPongPacket(0))
+			}
+			return true
+		})
+	})))
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != srpc.ErrHeartbeatTimeout {
+		t.Fatalf("expected %v got %v", srpc.ErrHeartbeatTimeout, err)
+	}
+	strm, err := client.EchoBidiStream(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != srpc.ErrHeartbeatTimeout {
+		t.Fatalf("expected %v got %v", srpc.ErrHeartbeatTimeout, err)
+	}
+	_ = strm.Close()
+}
+
+func TestHeartbeat_LegacyServer(t *testing.T) {
+	ctx := srpc.WithCallOptions(context.Background(), srpc.WithClientHeartbeat(heartbeatParams))
+
+	// the server does not support Ping packets and responds after a delay
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(newRemoteOpenStream(func(prw *srpc.PacketReaderWriter) {
+		defer prw.Close()
+		pktCh := make(chan *srpc.Packet, 10)
+		go func() {
+			readPackets(prw, func(pkt *srpc.Packet) bool {
+				pktCh <- pkt
+				return true
+			})
+			close(pktCh)
+		}()
+		start := <-pktCh
+		if start.GetCallStart() == nil {
+			return
+		}
+		timeout := time.After(heartbeatParams.Interval * 5)
+		for {
+			select {
+			case pkt, ok := <-pktCh:
+				if !ok || pkt.GetPing() != 0 || pkt.GetBody() == nil {
+					return
+				}
+				if _, isPing := pkt.GetBody().(*srpc.Packet_Ping); isPing {
+					// older servers fail the call with ErrUnrecognizedPacket
+					return
+				}
+			case <-timeout:
+				_ = prw.WritePacket(srpc.NewCallDataPacket(start.GetCallStart().GetData(), false, true, nil))
+				return
+			}
+		}
+	})))
+	out, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if out.GetBody() != "hello" {
+		t.Fatalf("expected echo got %v", out.String())
+	}
+}
+
+// startRawCall starts a EchoBidiStream call with the server with a raw client.
+//
+// Returns the client packet read/writer and the result of HandleStream.
+func startRawCall(t *testing.T, server *srpc.Server, pingSupported bool) (*srpc.PacketReaderWriter, <-chan error) {
+	srvPipe, clientPipe := net.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.HandleStream(context.Background(), srvPipe)
+	}()
+	prw := srpc.NewPacketReadWriter(clientPipe)
+	startPkt := srpc.NewCallStartPacket(echo.SRPCEchoerServiceID, "EchoBidiStream", nil, false)
+	startPkt.GetCallStart().PingSupported = pingSupported
+	if err := prw.WritePacket(startPkt); err != nil {
+		t.Fatal(err.Error())
+	}
+	return prw, errCh
+}
+
+func TestHeartbeat_UnresponsiveClient(t *testing.T) {
+	prw, errCh := startRawCall(t, newHeartbeatServer(t), true)
+	defer prw.Close()
+	go readPackets(prw, func(pkt *srpc.Packet) bool { return true })
+
+	select {
+	case err := <-errCh:
+		if err != srpc.ErrHeartbeatTimeout {
+			t.Fatalf("expected %v got %v", srpc.ErrHeartbeatTimeout, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the server to time out the call")
+	}
+}
+
+func TestHeartbeat_LegacyClient(t *testing.T) {
+	prw, errCh := startRawCall(t, newHeartbeatServer(t), false)
+
+	// expect the server to never ping a client without support
+	pingCh := make(chan struct{}, 1)
+	go readPackets(prw, func(pkt *srpc.Packet) bool {
+		if _, isPing := pkt.GetBody().(*srpc.Packet_Ping); isPing {
+			pingCh <- struct{}{}
+			return false
+		}
+		return true
+	})
+	select {
+	case <-pingCh:
+		t.Fatal("unexpected ping sent to a client without support")
+	case err := <-errCh:
+		t.Fatalf("unexpected call result: %v", err)
+	case <-time.After(heartbeatParams.Interval * 5):
+	}
+
+	_ = prw.Close()
+	if err := <-errCh; err == srpc.ErrHeartbeatTimeout {
+		t.Fatalf("unexpected %v", err)
+	}
+}
+
+func TestHeartbeat_ConcurrentWrites(t *testing.T) {
+	// the server pings the client while the client is sending messages
+	var conn *writeCheckConn
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(func(ctx context.Context, msgHandler srpc.PacketHandler, closeHandler srpc.CloseHandler) (srpc.Writer, error) {
+		remotePipe, localPipe := net.Pipe()
+		conn = &writeCheckConn{Conn: localPipe}
+		go func() {
+			prw := srpc.NewPacketReadWriter(remotePipe)
+			startCh, pongCh, doneCh := make(chan struct{}), make(chan struct{}, 1), make(chan struct{})
+			go readPackets(prw, func(pkt *srpc.Packet) bool {
+				switch {
+				case pkt.GetCallStart() != nil:
+					close(startCh)
+				case pkt.GetPong() != 0:
+					pongCh <- struct{}{}
+				case pkt.GetCallData().GetComplete():
+					close(doneCh)
+					return false
+				}
+				return true
+			})
+			<-startCh
+			for pingID := uint64(1); ; pingID++ {
+				if err := prw.WritePacket(srpc.NewPingPacket(pingID)); err != nil {
+					return
+				}
+				select {
+				case <-pongCh:
+				case <-doneCh:
+					_ = prw.WritePacket(srpc.NewCallDataPacket(nil, false, true, nil))
+					return
+				}
+			}
+		}()
+		prw := srpc.NewPacketReadWriter(conn)
+		go prw.ReadPump(msgHandler, closeHandler)
+		return prw, nil
+	}))
+
+	strm, err := client.EchoBidiStream(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 0; i < 200; i++ {
+		if err := strm.Send(&echo.EchoMsg{Body: "hello"}); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if err := strm.CloseSend(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != io.EOF {
+		t.Fatalf("expected %v got %v", io.EOF, err)
+	}
+	_ = strm.Close()
+
+	// expect the Pong packets to be serialized with the messages
+	if atomic.LoadInt32(&conn.concurrent) != 0 {
+		t.Fatal("expected the writes to the stream to be serialized")
+	}
+}
@@ -0,0 +1,171 @@
+package srpc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// HTTP2Handler is a http.Handler which handles each HTTP/2 request as a stream.
+//
+// Each call uses a separate HTTP/2 stream, so HTTP/2 aware load balancers can
+// balance the individual calls. The request body contains the packets from the
+// client and the response body contains the packets from the server.
+//
+// Serve with TLS, or wrap with h2c.NewHandler from golang.org/x/net to serve
+// unencrypted HTTP/2. HTTP/1 requests are rejected: they cannot stream in both
+// directions at the same time.
+type HTTP2Handler struct {
+	// server handles the streams
+	server *Server
+}
+
+// NewHTTP2Handler constructs a new HTTP2Handler.
+func NewHTTP2Handler(server *Server) *HTTP2Handler {
+	return &HTTP2Handler{server: server}
+}
+
+// ServeHTTP handles the request as a stream.
+func (h *HTTP2Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok || r.ProtoMajor < 2 {
+		// close the connection instead of waiting for the request body
+		w.Header().Set("Connection", "close")
+		w.WriteHeader(http.StatusHTTPVersionNotSupported)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx := WithConnInfo(r.Context(), &ConnInfo{
+		Transport:       TransportHTTP2,
+		ProtocolVersion: ProtocolVersion,
+		RemoteAddr:      r.RemoteAddr,
+		TLS:             r.TLS,
+	})
+	_ = h.server.HandleStream(ctx, &http2ServerStream{body: r.Body, w: w, flusher: flusher})
+}
+
+// http2ServerStream is the server side of a HTTP/2 stream.
+type http2ServerStream struct {
+	// body is the request body
+	body io.ReadCloser
+	// w is the response writer
+	w io.Writer
+	// flusher flushes the response
+	flusher http.Flusher
+	// mtx serializes the writes
+	mtx sync.Mutex
+	// closed is set to 1 when Close is called.
+	// accessed with atomic
+	closed uint32
+}
+
+// Read reads from the request body.
+func (s *http2ServerStream) Read(p []byte) (int, error) {
+	return s.body.Read(p)
+}
+
+// Write writes to the response body and flushes it.
+//
+// The response writer is not safe for concurrent use: the writes and flushes
+// are serialized. Returns io.ErrClosedPipe after Close.
+func (s *http2ServerStream) Write(p []byte) (int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if atomic.LoadUint32(&s.closed) != 0 {
+		return 0, io.ErrClosedPipe
+	}
+	n, err := s.w.Write(p)
+	if err == nil {
+		s.flusher.Flush()
+	}
+	return n, err
+}
+
+// Close closes the request body.
+//
+// The following writes fail. Does not wait for a write blocked by the flow
+// control. The response is completed when the handler returns.
+func (s *http2ServerStream) Close() error {
+	atomic.StoreUint32(&s.closed, 1)
+	return s.body.Close()
+}
+
+// NewHTTP2OpenStream constructs a OpenStreamFunc which starts each call with a
+// HTTP/2 request to the url of a HTTP2Handler.
+//
+// The client must use HTTP/2: for example a client with a TLS transport, or a
+// http2.Transport from golang.org/x/net with AllowHTTP for unencrypted h2c.
+func NewHTTP2OpenStream(client *http.Client, url string) OpenStreamFunc {
+	return func(ctx context.Context, msgHandler PacketHandler, closeHandler CloseHandler) (Writer, error) {
+		pr, pw := io.Pipe()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, pr)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/octet-stream")
+		resp, err := client.Do(req)
+		if err != nil {
+			_ = pw.Close()
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			_ = pw.Close()
+			_ = resp.Body.Close()
+			return nil, errors.Errorf("http2: unexpected status: %s", resp.Status)
+		}
+		if resp.ProtoMajor < 2 {
+			_ = pw.Close()
+			_ = resp.Body.Close()
+			return nil, errors.Errorf("http2: unexpected protocol: %s", resp.Proto)
+		}
+
+		prw := NewPacketReadWriter(&http2ClientStream{body: resp.Body, pw: pw})
+		go prw.ReadPump(msgHandler, closeHandler)
+		return prw, nil
+	}
+}
+
+// http2ClientStream is the client side of a HTTP/2 stream.
+type http2ClientStream struct {
+	// body is the response body
+	body io.ReadCloser
+	// pw writes to the request body
+	pw *io.PipeWriter
+}
+
+// Read reads from the response body.
+func (s *http2ClientStream) Read(p []byte) (int, error) {
+	return s.body.Read(p)
+}
+
+// Write writes to the request body.
+func (s *http2ClientStream) Write(p []byte) (int, error) {
+	return s.pw.Write(p)
+}
+
+// Close closes the request and the response body.
+func (s *http2ClientStream) Close() error {
+	err := s.pw.Close()
+	if cerr := s.body.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// _ is a type assertion
+var (
+	_ http.Handler       = ((*HTTP2Handler)(nil))
+	_ io.ReadWriteCloser = ((*http2ServerStream)(nil))
+	_ io.ReadWriteCloser = ((*http2ClientStream)(nil))
+)
@@ -0,0 +1,55 @@
+package srpc
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// Invoker invokes the method matching the service & method ID.
+//
+// Returns false, nil if not found.
+type Invoker func(serviceID, methodID string, strm Stream) (bool, error)
+
+// ServerInterceptor intercepts the unary and streaming calls handled by a Server.
+//
+// ctx is the stream context. Calls next to continue handling the call, with a
+// wrapped Stream if needed, or returns without calling next to reject it.
+type ServerInterceptor func(ctx context.Context, serviceID, methodID string, strm Stream, next Invoker) (bool, error)
+
+// WithInterceptors adds interceptors to the calls handled by the server.
+//
+// Interceptors run after the readiness gate, the concurrency limit and the
+// admission controller. Calls to the built-in ping service skip those but run
+// the interceptors. The first interceptor is the outermost. Interceptors
+// from multiple options are applied in order.
+func WithInterceptors(interceptors ...ServerInterceptor) ServerOption {
+	return func(s *Server) {
+		s.interceptors = append(s.interceptors, interceptors...)
+	}
+}
+
+// ChainInterceptors wraps the invoker with the interceptors.
+//
+// The first interceptor is the outermost.
+func ChainInterceptors(invoker Invoker, interceptors ...ServerInterceptor) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(serviceID, methodID string, strm Stream) (bool, error) {
+			return interceptor(strm.Context(), serviceID, methodID, strm, next)
+		}
+	}
+	return invoker
+}
+
+// RecoverInterceptor returns errors for panics in handlers.
+//
+// Without this interceptor a panic in a handler crashes the program.
+func RecoverInterceptor(ctx context.Context, serviceID, methodID string, strm Stream, next Invoker) (handled bool, err error) {
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			handled, err = true, errors.Errorf("panic in %s/%s: %v", serviceID, methodID, rerr)
+		}
+	}()
+	return next(serviceID, methodID, strm)
+}
@@ -0,0 +1,78 @@
+package srpc_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// panicEchoServer panics if the body is "panic".
+type panicEchoServer struct {
+	*echo.EchoServer
+}
+
+func TestInterceptors(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &panicEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var logMtx sync.Mutex
+	var logged []string
+	logInterceptor := func(ctx context.Context, serviceID, methodID string, strm srpc.Stream, next srpc.Invoker) (bool, error) {
+		handled, err := next(serviceID, methodID, strm)
+		logMtx.Lock()
+		logged = append(logged, methodID+":"+strconv.FormatBool(err == nil))
+		logMtx.Unlock()
+		return handled, err
+	}
+	errUnauthenticated := errors.New("unauthenticated")
+	authInterceptor := func(ctx context.Context, serviceID, methodID string, strm srpc.Stream, next srpc.Invoker) (bool, error) {
+		if srpc.MetadataFromIncomingContext(ctx).Get("token") != "secret" {
+			return true, errUnauthenticated
+		}
+		return next(serviceID, methodID, strm)
+	}
+	server := srpc.NewServer(
+		mux,
+		srpc.WithInterceptors(logInterceptor, authInterceptor),
+		srpc.WithInterceptors(srpc.RecoverInterceptor),
+	)
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+
+	ctx := context.Background()
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hi"}); err == nil || err.Error() != errUnauthenticated.Error() {
+		t.Fatalf("expected unauthenticated error got %v", err)
+	}
+	authCtx := srpc.AppendToOutgoingContext(ctx, "token", "secret")
+	out, err := client.Echo(authCtx, &echo.EchoMsg{Body: "hi"})
+	if err != nil || out.GetBody() != "hi" {
+		t.Fatalf("expected echo got %v %v", out, err)
+	}
+	_, err = client.Echo(authCtx, &echo.EchoMsg{Body: "panic"})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected recovered panic error got %v", err)
+	}
+
+	logMtx.Lock()
+	defer logMtx.Unlock()
+	expected := "Echo:false,Echo:true,Echo:false"
+	if strings.Join(logged, ",") != expected {
+		t.Fatalf("expected log %s got %v", expected, logged)
+	}
+}
+
+// Echo panics if the body is "panic".
+func (s *panicEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	if msg.GetBody() == "panic" {
+		panic("boom")
+	}
+	return s.EchoServer.Echo(ctx, msg)
+}
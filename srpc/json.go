@@ -0,0 +1,42 @@
+package srpc
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// MarshalProtoJSON marshals a message to the canonical protojson format.
+//
+// Uses the lowerCamelCase field names, matching the TypeScript toJSON helpers.
+// The message must implement proto.Message.
+func MarshalProtoJSON(msg Message) ([]byte, error) {
+	pmsg, ok := msg.(proto.Message)
+	if !ok {
+		return nil, errors.Errorf("message type %T does not implement proto.Message", msg)
+	}
+	return protojson.Marshal(pmsg)
+}
+
+// UnmarshalProtoJSON unmarshals a message from the protojson format.
+//
+// Unknown fields are discarded.
+// The message must implement proto.Message.
+func UnmarshalProtoJSON(data []byte, msg Message) error {
+	pmsg, ok := msg.(proto.Message)
+	if !ok {
+		return errors.Errorf("message type %T does not implement proto.Message", msg)
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, pmsg)
+}
+
+// ProtoJSONString marshals a message to a protojson string for logging.
+//
+// Returns the error string if the message could not be marshaled.
+func ProtoJSONString(msg Message) string {
+	data, err := MarshalProtoJSON(msg)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
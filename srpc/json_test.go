@@ -0,0 +1,61 @@
+package srpc_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestProtoJSON_RoundTrip(t *testing.T) {
+	msg := &srpc.CallStart{
+		RpcService: "echo.Echoer",
+		RpcMethod:  "Echo",
+		Data:       []byte("hello"),
+		Metadata:   map[string]string{"x-request-id": "req-1"},
+	}
+	data, err := srpc.MarshalProtoJSON(msg)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	// uses the lowerCamelCase field names
+	for _, field := range []string{`"rpcService"`, `"rpcMethod"`, `"data"`, `"metadata"`} {
+		if !strings.Contains(string(data), field) {
+			t.Fatalf("expected field %s in %s", field, string(data))
+		}
+	}
+
+	out := &srpc.CallStart{}
+	if err := srpc.UnmarshalProtoJSON(data, out); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !out.EqualVT(msg) {
+		t.Fatalf("round trip mismatch: %v != %v", out, msg)
+	}
+}
+
+func TestProtoJSON_DiscardUnknown(t *testing.T) {
+	out := &srpc.CallStart{}
+	if err := srpc.UnmarshalProtoJSON([]byte(`{"rpcMethod":"Echo","unknownField":1}`), out); err != nil {
+		t.Fatal(err.Error())
+	}
+	if out.GetRpcMethod() != "Echo" {
+		t.Fatalf("unexpected method: %q", out.GetRpcMethod())
+	}
+}
+
+func TestProtoJSON_NotProtoMessage(t *testing.T) {
+	msg := srpc.NewRawMessage(nil)
+	if _, err := srpc.MarshalProtoJSON(msg); err == nil {
+		t.Fatal("expected error marshaling a non-proto message")
+	}
+	if err := srpc.UnmarshalProtoJSON([]byte(`{}`), msg); err == nil {
+		t.Fatal("expected error unmarshaling a non-proto message")
+	}
+	if str := srpc.ProtoJSONString(msg); !strings.Contains(str, "does not implement proto.Message") {
+		t.Fatalf("unexpected string: %q", str)
+	}
+	if str := srpc.ProtoJSONString(&srpc.CallStart{RpcMethod: "Echo"}); !strings.Contains(str, `"rpcMethod"`) {
+		t.Fatalf("unexpected string: %q", str)
+	}
+}
@@ -0,0 +1,80 @@
+package srpc
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// progressSenderCtxKey is the context key for the progressSender.
+type progressSenderCtxKey struct{}
+
+// progressSender sends keepalive progress for a server call.
+type progressSender interface {
+	// sendProgress writes a keepalive progress packet.
+	sendProgress(progress Metadata) error
+}
+
+// withProgressSender attaches the progressSender to the context.
+func withProgressSender(ctx context.Context, sender progressSender) context.Context {
+	return context.WithValue(ctx, progressSenderCtxKey{}, sender)
+}
+
+// SendProgress sends keepalive progress metadata to the client of a call.
+//
+// ctx must be the handler context of a server call. Progress packets keep
+// data flowing while a long unary call is in progress so that intermediaries
+// and the client do not time out. The client receives them via WithProgress.
+//
+// Returns ErrNoServerCall if ctx does not belong to a server call, or
+// ErrCompleted if the call already completed.
+func SendProgress(ctx context.Context, progress Metadata) error {
+	sender, _ := ctx.Value(progressSenderCtxKey{}).(progressSender)
+	if sender == nil {
+		return ErrNoServerCall
+	}
+	if len(progress) == 0 {
+		// an empty progress packet is invalid: send a placeholder
+		progress = Metadata{"keepalive": "1"}
+	}
+	return sender.sendProgress(progress)
+}
+
+// KeepAlive sends progress metadata every interval until stopped.
+//
+// ctx must be the handler context of a server call. getProgress is called
+// before each send and may be nil to send a placeholder keepalive. The
+// returned func stops the loop and waits for it to exit: call it before
+// returning the response from the handler.
+func KeepAlive(ctx context.Context, interval time.Duration, getProgress func() Metadata) (stop func()) {
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stopCh:
+				return
+			case <-ticker.C:
+			}
+			var progress Metadata
+			if getProgress != nil {
+				progress = getProgress()
+			}
+			if err := SendProgress(ctx, progress); err != nil {
+				return
+			}
+		}
+	}()
+	var stopOnce sync.Once
+	return func() {
+		stopOnce.Do(func() {
+			close(stopCh)
+		})
+		<-doneCh
+	}
+}
@@ -0,0 +1,165 @@
+package srpc_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// keepAliveEchoServer sends keepalive progress before responding.
+type keepAliveEchoServer struct {
+	*echo.EchoServer
+}
+
+func TestKeepAlive(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &keepAliveEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	var progress []srpc.Metadata
+	ctx := srpc.WithCallOptions(context.Background(), srpc.WithProgress(func(md srpc.Metadata) {
+		progress = append(progress, md)
+	}))
+	out, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello world"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if out.GetBody() != "hello world" {
+		t.Fatalf("unexpected response: %q", out.GetBody())
+	}
+	if len(progress) == 0 {
+		t.Fatal("expected at least one progress update")
+	}
+	for i, md := range progress {
+		if md.Get("count") != strconv.Itoa(i+1) {
+			t.Fatalf("expected progress count %d got %q", i+1, md.Get("count"))
+		}
+	}
+
+	if err := srpc.SendProgress(context.Background(), nil); err != srpc.ErrNoServerCall {
+		t.Fatalf("expected %v got %v", srpc.ErrNoServerCall, err)
+	}
+}
+
+// Echo sends progress for a while, then echoes the message.
+func (s *keepAliveEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	var count int
+	stop := srpc.KeepAlive(ctx, time.Millisecond*10, func() srpc.Metadata {
+		count++
+		return srpc.Metadata{"count": strconv.Itoa(count)}
+	})
+	<-time.After(time.Millisecond * 100)
+	stop()
+	return &echo.EchoMsg{Body: msg.GetBody()}, nil
+}
+
+// newWriteCheckPipe opens streams with the server like NewServerPipe.
+//
+// The returned func checks if any writes of the server or the client overlapped.
+func newWriteCheckPipe(server *srpc.Server) (srpc.OpenStreamFunc, func() bool) {
+	var mtx sync.Mutex
+	var conns []*writeCheckConn
+	openStream := func(ctx context.Context, msgHandler srpc.PacketHandler, closeHandler srpc.CloseHandler) (srpc.Writer, error) {
+		srvPipe, clientPipe := net.Pipe()
+		srvConn, clientConn := &writeCheckConn{Conn: srvPipe}, &writeCheckConn{Conn: clientPipe}
+		mtx.Lock()
+		conns = append(conns, srvConn, clientConn)
+		mtx.Unlock()
+		go func() {
+			_ = server.HandleStream(context.Background(), srvConn)
+		}()
+		prw := srpc.NewPacketReadWriter(clientConn)
+		go prw.ReadPump(msgHandler, closeHandler)
+		return prw, nil
+	}
+	concurrent := func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		for _, conn := range conns {
+			if atomic.LoadInt32(&conn.concurrent) != 0 {
+				return true
+			}
+		}
+		return false
+	}
+	return openStream, concurrent
+}
+
+// progressStreamServer sends progress while sending the messages.
+type progressStreamServer struct {
+	*echo.EchoServer
+}
+
+func TestKeepAlive_ConcurrentSend(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &progressStreamServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	openStream, concurrent := newWriteCheckPipe(srpc.NewServer(mux))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(openStream))
+
+	var progress int32
+	ctx := srpc.WithCallOptions(context.Background(), srpc.WithProgress(func(md srpc.Metadata) {
+		atomic.AddInt32(&progress, 1)
+	}))
+	strm, err := client.EchoServerStream(ctx, &echo.EchoMsg{Body: "hello"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for {
+		if _, err := strm.Recv(); err != nil {
+			if err != io.EOF {
+				t.Fatal(err.Error())
+			}
+			break
+		}
+	}
+	if atomic.LoadInt32(&progress) == 0 {
+		t.Fatal("expected at least one progress update")
+	}
+
+	// expect the progress packets to be serialized with the messages
+	if concurrent() {
+		t.Fatal("expected the writes to the stream to be serialized")
+	}
+}
+
+// EchoServerStream sends the message repeatedly while sending progress.
+func (s *progressStreamServer) EchoServerStream(msg *echo.EchoMsg, strm echo.SRPCEchoer_EchoServerStreamStream) error {
+	ctx := strm.Context()
+	progress := srpc.Metadata{"sending": "1"}
+	if err := srpc.SendProgress(ctx, progress); err != nil {
+		return err
+	}
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	go func() {
+		for {
+			select {
+			case <-doneCh:
+				return
+			default:
+			}
+			if err := srpc.SendProgress(ctx, progress); err != nil {
+				return
+			}
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		if err := strm.Send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
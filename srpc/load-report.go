@@ -0,0 +1,54 @@
+package srpc
+
+// LoadReporter returns the current load of the server.
+//
+// Called when each call completes and must not block.
+type LoadReporter func() *LoadReport
+
+// WithLoadReporter attaches the server load to the result of each call.
+//
+// The client keeps the last report, see GetLoadReport.
+func WithLoadReporter(reporter LoadReporter) ServerOption {
+	return func(s *Server) {
+		s.loadReporter = reporter
+	}
+}
+
+// LoadReportGetter is a Client which tracks the last server load report.
+type LoadReportGetter interface {
+	// GetLoadReport returns the last load report from the server or nil.
+	GetLoadReport() *LoadReport
+}
+
+// GetLoadReport returns the last load report for the Client or nil if unknown.
+func GetLoadReport(client Client) *LoadReport {
+	getter, ok := client.(LoadReportGetter)
+	if !ok {
+		return nil
+	}
+	return getter.GetLoadReport()
+}
+
+// GetLoadReport returns the last load report from the server or nil.
+func (c *client) GetLoadReport() *LoadReport {
+	report, _ := c.loadReport.Load().(*LoadReport)
+	return report
+}
+
+// handleLoadReport stores the load report from the server.
+func (c *client) handleLoadReport(report *LoadReport) {
+	c.loadReport.Store(report)
+}
+
+// LoadScore returns a score for the load where lower is less loaded.
+//
+// Sums the cpu utilization and the queue depth.
+func LoadScore(report *LoadReport) float64 {
+	if report == nil {
+		return 0
+	}
+	return report.GetCpuUtilization() + float64(report.GetQueueDepth())
+}
+
+// _ is a type assertion
+var _ LoadReportGetter = ((*client)(nil))
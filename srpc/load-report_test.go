@@ -0,0 +1,54 @@
+package srpc_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestLoadReport(t *testing.T) {
+	ctx := context.Background()
+	msg := &echo.EchoMsg{Body: "hello world"}
+
+	// construct two servers reporting a fixed cpu utilization
+	var echoServers []*countingEchoServer
+	var clients []srpc.Client
+	for _, cpu := range []float64{0.9, 0.1} {
+		cpu := cpu
+		mux := srpc.NewMux()
+		echoServer := &countingEchoServer{EchoServer: echo.NewEchoServer(mux)}
+		if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+			t.Fatal(err.Error())
+		}
+		server := srpc.NewServer(mux, srpc.WithLoadReporter(func() *srpc.LoadReport {
+			return &srpc.LoadReport{CpuUtilization: cpu}
+		}))
+		echoServers = append(echoServers, echoServer)
+		clients = append(clients, srpc.NewClient(srpc.NewServerPipe(server)))
+	}
+
+	// expect the load report to be tracked by the client
+	if _, err := echo.NewSRPCEchoerClient(clients[0]).Echo(ctx, msg); err != nil {
+		t.Fatal(err.Error())
+	}
+	if report := srpc.GetLoadReport(clients[0]); report.GetCpuUtilization() != 0.9 {
+		t.Fatalf("expected cpu utilization 0.9 got %v", report.GetCpuUtilization())
+	}
+
+	// expect further calls to pick the least loaded server
+	client := echo.NewSRPCEchoerClient(srpc.NewLeastLoadedClient(clients...))
+	for i := 0; i < 3; i++ {
+		if _, err := client.Echo(ctx, msg); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if calls := atomic.LoadInt32(&echoServers[0].calls); calls != 1 {
+		t.Fatalf("expected 1 call to the loaded server got %d", calls)
+	}
+	if calls := atomic.LoadInt32(&echoServers[1].calls); calls != 3 {
+		t.Fatalf("expected 3 calls to the idle server got %d", calls)
+	}
+}
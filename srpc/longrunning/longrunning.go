@@ -0,0 +1,85 @@
+package longrunning
+
+import (
+	"io"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// ErrNoResult is returned if the stream ended without a result.
+var ErrNoResult = errors.New("stream ended without a result")
+
+// Reporter reports the progress of a long-running operation to the client.
+type Reporter struct {
+	// strm is the server stream
+	strm srpc.Stream
+}
+
+// NewReporter constructs a new Reporter writing Update messages to strm.
+func NewReporter(strm srpc.Stream) *Reporter {
+	return &Reporter{strm: strm}
+}
+
+// Report sends a progress update.
+//
+// percent is the completion percentage from 0 to 100.
+func (r *Reporter) Report(percent float32, note string) error {
+	if percent < 0 || percent > 100 {
+		return errors.Errorf("progress percent out of range: %v", percent)
+	}
+	return r.strm.MsgSend(&Update{Body: &Update_Progress{
+		Progress: &Progress{Percent: percent, Note: note},
+	}})
+}
+
+// Finish sends the result of the operation.
+//
+// Should be called once as the last message in the stream.
+func (r *Reporter) Finish(result srpc.Message) error {
+	data, err := result.MarshalVT()
+	if err != nil {
+		return err
+	}
+	return r.strm.MsgSend(&Update{Body: &Update_Result{Result: data}})
+}
+
+// Run calls fn with a report func and sends the returned result.
+//
+// Convenience for implementing a handler with a Reporter.
+func Run(strm srpc.Stream, fn func(report func(percent float32, note string) error) (srpc.Message, error)) error {
+	rep := NewReporter(strm)
+	result, err := fn(rep.Report)
+	if err != nil {
+		return err
+	}
+	return rep.Finish(result)
+}
+
+// Wait receives updates from strm until the result is received.
+//
+// onProgress is called with each progress update and may be nil.
+// The result is decoded into out.
+// Returns ErrNoResult if the stream ended without a result.
+func Wait(strm srpc.Stream, out srpc.Message, onProgress func(p *Progress)) error {
+	for {
+		update := &Update{}
+		if err := strm.MsgRecv(update); err != nil {
+			if err == io.EOF {
+				return ErrNoResult
+			}
+			return err
+		}
+		switch b := update.GetBody().(type) {
+		case *Update_Progress:
+			if onProgress != nil {
+				onProgress(b.Progress)
+			}
+		case *Update_Result:
+			if err := out.UnmarshalVT(b.Result); err != nil {
+				return errors.Wrap(srpc.ErrInvalidMessage, err.Error())
+			}
+			return nil
+		}
+	}
+}
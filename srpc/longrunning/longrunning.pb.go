@@ -0,0 +1,266 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1-devel
+// 	protoc        v3.19.3
+// source: github.com/aperturerobotics/starpc/srpc/longrunning/longrunning.proto
+
+package longrunning
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Update is a message in a long-running operation server stream.
+type Update struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Body:
+	//	*Update_Progress
+	//	*Update_Result
+	Body isUpdate_Body `protobuf_oneof:"body"`
+}
+
+func (x *Update) Reset() {
+	*x = Update{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Update) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Update) ProtoMessage() {}
+
+func (x *Update) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Update.ProtoReflect.Descriptor instead.
+func (*Update) Descriptor() ([]byte, []int) {
+	return file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *Update) GetBody() isUpdate_Body {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
+func (x *Update) GetProgress() *Progress {
+	if x, ok := x.GetBody().(*Update_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *Update) GetResult() []byte {
+	if x, ok := x.GetBody().(*Update_Result); ok {
+		return x.Result
+	}
+	return nil
+}
+
+type isUpdate_Body interface {
+	isUpdate_Body()
+}
+
+type Update_Progress struct {
+	// Progress reports the progress of the operation.
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type Update_Result struct {
+	// Result contains the encoded result of the operation.
+	// Sent once as the final message in the stream.
+	Result []byte `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+func (*Update_Progress) isUpdate_Body() {}
+
+func (*Update_Result) isUpdate_Body() {}
+
+// Progress reports the progress of a long-running operation.
+type Progress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Percent is the completion percentage from 0 to 100.
+	Percent float32 `protobuf:"fixed32,1,opt,name=percent,proto3" json:"percent,omitempty"`
+	// Note is an optional human-readable status note.
+	Note string `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Progress) GetPercent() float32 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+func (x *Progress) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+var File_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto protoreflect.FileDescriptor
+
+var file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDesc = []byte{
+	0x0a, 0x45, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x70, 0x65,
+	0x72, 0x74, 0x75, 0x72, 0x65, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x69, 0x63, 0x73, 0x2f, 0x73, 0x74,
+	0x61, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x72, 0x70, 0x63, 0x2f, 0x6c, 0x6f, 0x6e, 0x67, 0x72, 0x75,
+	0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x2f, 0x6c, 0x6f, 0x6e, 0x67, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e,
+	0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x6c, 0x6f, 0x6e, 0x67, 0x72, 0x75, 0x6e,
+	0x6e, 0x69, 0x6e, 0x67, 0x22, 0x5f, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x33,
+	0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x6c, 0x6f, 0x6e, 0x67, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x50,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x42, 0x06, 0x0a,
+	0x04, 0x62, 0x6f, 0x64, 0x79, 0x22, 0x38, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x02, 0x52, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x6f, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x6f, 0x74, 0x65, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDescOnce sync.Once
+	file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDescData = file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDesc
+)
+
+func file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDescGZIP() []byte {
+	file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDescOnce.Do(func() {
+		file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDescData)
+	})
+	return file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDescData
+}
+
+var file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_goTypes = []interface{}{
+	(*Update)(nil),   // 0: longrunning.Update
+	(*Progress)(nil), // 1: longrunning.Progress
+}
+var file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_depIdxs = []int32{
+	1, // 0: longrunning.Update.progress:type_name -> longrunning.Progress
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_init() }
+func file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_init() {
+	if File_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Update); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Progress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*Update_Progress)(nil),
+		(*Update_Result)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_goTypes,
+		DependencyIndexes: file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_depIdxs,
+		MessageInfos:      file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_msgTypes,
+	}.Build()
+	File_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto = out.File
+	file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_rawDesc = nil
+	file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_goTypes = nil
+	file_github_com_aperturerobotics_starpc_srpc_longrunning_longrunning_proto_depIdxs = nil
+}
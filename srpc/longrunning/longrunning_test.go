@@ -0,0 +1,66 @@
+package longrunning_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/longrunning"
+)
+
+// longRunningHandler implements a long-running operation reporting progress.
+type longRunningHandler struct{}
+
+func TestLongRunning(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := mux.Register(longRunningHandler{}); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux)))
+
+	strm, err := client.NewStream(context.Background(), "test.LongRunning", "Run", &echo.EchoMsg{Body: "hello world"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var percents []float32
+	result := &echo.EchoMsg{}
+	err = longrunning.Wait(strm, result, func(p *longrunning.Progress) {
+		percents = append(percents, p.GetPercent())
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if result.GetBody() != "hello world" {
+		t.Fatalf("unexpected result: %q", result.GetBody())
+	}
+	if len(percents) != 3 || percents[2] != 100 {
+		t.Fatalf("unexpected progress: %v", percents)
+	}
+}
+
+// GetServiceID returns the ID of the service.
+func (longRunningHandler) GetServiceID() string { return "test.LongRunning" }
+
+// GetMethodIDs returns the list of methods for the service.
+func (longRunningHandler) GetMethodIDs() []string { return []string{"Run"} }
+
+// InvokeMethod reports progress and returns the request as the result.
+func (longRunningHandler) InvokeMethod(serviceID, methodID string, strm srpc.Stream) (bool, error) {
+	if methodID != "Run" {
+		return false, nil
+	}
+	req := &echo.EchoMsg{}
+	if err := strm.MsgRecv(req); err != nil {
+		return true, err
+	}
+	return true, longrunning.Run(strm, func(report func(percent float32, note string) error) (srpc.Message, error) {
+		for _, percent := range []float32{0, 50, 100} {
+			if err := report(percent, "step "+strconv.Itoa(int(percent))); err != nil {
+				return nil, err
+			}
+		}
+		return req, nil
+	})
+}
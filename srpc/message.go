@@ -1,7 +1,36 @@
 package srpc
 
+import "google.golang.org/protobuf/proto"
+
 // Message is the vtprotobuf message interface.
 type Message interface {
 	MarshalVT() ([]byte, error)
 	UnmarshalVT([]byte) error
 }
+
+// sizedMessage is a Message which can compute the encoded size.
+type sizedMessage interface {
+	SizeVT() int
+}
+
+// MarshalMessage marshals the message.
+//
+// Skips marshaling and returns nil if the message has an encoded size of zero.
+func MarshalMessage(msg Message) ([]byte, error) {
+	if sm, ok := msg.(sizedMessage); ok && sm.SizeVT() == 0 {
+		return nil, nil
+	}
+	return msg.MarshalVT()
+}
+
+// UnmarshalMessage unmarshals the data into the message.
+//
+// Skips unmarshaling empty data into proto messages, which is a no-op.
+func UnmarshalMessage(msg Message, data []byte) error {
+	if len(data) == 0 {
+		if _, ok := msg.(proto.Message); ok {
+			return nil
+		}
+	}
+	return msg.UnmarshalVT(data)
+}
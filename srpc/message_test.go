@@ -0,0 +1,55 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func BenchmarkMarshalMessage_Empty(b *testing.B) {
+	msg := &echo.EchoMsg{}
+	b.Run("MarshalVT", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			data, err := msg.MarshalVT()
+			if err != nil {
+				b.Fatal(err.Error())
+			}
+			if err := msg.UnmarshalVT(data); err != nil {
+				b.Fatal(err.Error())
+			}
+		}
+	})
+	b.Run("MarshalMessage", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			data, err := srpc.MarshalMessage(msg)
+			if err != nil {
+				b.Fatal(err.Error())
+			}
+			if err := srpc.UnmarshalMessage(msg, data); err != nil {
+				b.Fatal(err.Error())
+			}
+		}
+	})
+}
+
+func BenchmarkEmptyUnary(b *testing.B) {
+	mux := srpc.NewMux()
+	echoServer := echo.NewEchoServer(mux)
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		b.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Echo(ctx, &echo.EchoMsg{}); err != nil {
+			b.Fatal(err.Error())
+		}
+	}
+}
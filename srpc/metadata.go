@@ -0,0 +1,126 @@
+package srpc
+
+import (
+	"context"
+	"fmt"
+)
+
+// Metadata contains key-value pairs sent with a call.
+type Metadata map[string]string
+
+// Get returns the value for the key or an empty string.
+func (m Metadata) Get(key string) string {
+	return m[key]
+}
+
+// Clone returns a copy of the metadata.
+func (m Metadata) Clone() Metadata {
+	if m == nil {
+		return nil
+	}
+	out := make(Metadata, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// outgoingMetadataCtxKey is the context key for the outgoing metadata.
+type outgoingMetadataCtxKey struct{}
+
+// incomingMetadataCtxKey is the context key for the incoming metadata.
+type incomingMetadataCtxKey struct{}
+
+// AppendToOutgoingContext returns a context with the key-value pairs added to
+// the metadata sent with calls made with the context.
+//
+// kv must contain an even number of strings: key1, value1, key2, value2, ...
+// Overwrites any existing values with the same keys.
+func AppendToOutgoingContext(ctx context.Context, kv ...string) context.Context {
+	if len(kv)%2 == 1 {
+		panic(fmt.Sprintf("srpc: AppendToOutgoingContext got an odd number of input pairs for metadata: %d", len(kv)))
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	md := MetadataFromOutgoingContext(ctx).Clone()
+	if md == nil {
+		md = make(Metadata, len(kv)/2)
+	}
+	for i := 0; i < len(kv); i += 2 {
+		md[kv[i]] = kv[i+1]
+	}
+	return context.WithValue(ctx, outgoingMetadataCtxKey{}, md)
+}
+
+// NewContextWithMetadata returns a context which sends the metadata with calls.
+//
+// Replaces any metadata previously attached to the context.
+// See AppendToOutgoingContext to add to the existing metadata.
+func NewContextWithMetadata(ctx context.Context, md Metadata) context.Context {
+	return context.WithValue(ctx, outgoingMetadataCtxKey{}, md.Clone())
+}
+
+// WithoutOutgoingMetadata returns a context which sends no metadata with calls.
+func WithoutOutgoingMetadata(ctx context.Context) context.Context {
+	return context.WithValue(ctx, outgoingMetadataCtxKey{}, Metadata(nil))
+}
+
+// MetadataFromOutgoingContext returns the metadata to send with calls made with the context.
+//
+// The returned metadata must not be modified.
+func MetadataFromOutgoingContext(ctx context.Context) Metadata {
+	md, _ := ctx.Value(outgoingMetadataCtxKey{}).(Metadata)
+	return md
+}
+
+// MetadataFromIncomingContext returns the metadata received with the call.
+//
+// Used by handlers to read the metadata sent by the client.
+// The returned metadata must not be modified.
+func MetadataFromIncomingContext(ctx context.Context) Metadata {
+	md, _ := ctx.Value(incomingMetadataCtxKey{}).(Metadata)
+	return md
+}
+
+// MetadataFromContext returns the metadata received with the call.
+//
+// Equivalent to MetadataFromIncomingContext.
+func MetadataFromContext(ctx context.Context) Metadata {
+	return MetadataFromIncomingContext(ctx)
+}
+
+// newIncomingContext returns a context with the incoming metadata.
+//
+// Replaces the incoming metadata of a parent call, for example with the
+// context values passed by the server pipe.
+func newIncomingContext(ctx context.Context, md Metadata) context.Context {
+	if len(md) == 0 && len(MetadataFromIncomingContext(ctx)) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, incomingMetadataCtxKey{}, md)
+}
+
+// buildOutgoingMetadata builds the metadata to send with a call.
+//
+// Merges the outgoing metadata with the encoded baggage.
+// Baggage values take precedence over outgoing metadata with the same key.
+func buildOutgoingMetadata(ctx context.Context) (map[string]string, error) {
+	md, err := EncodeBaggage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	outgoing := MetadataFromOutgoingContext(ctx)
+	if len(outgoing) == 0 {
+		return md, nil
+	}
+	if md == nil {
+		md = make(map[string]string, len(outgoing))
+	}
+	for k, v := range outgoing {
+		if _, ok := md[k]; !ok {
+			md[k] = v
+		}
+	}
+	return md, nil
+}
@@ -0,0 +1,62 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// metadataEchoServer returns the incoming metadata value in the Echo response.
+type metadataEchoServer struct {
+	*echo.EchoServer
+}
+
+// Echo returns the metadata value in the message body.
+func (s *metadataEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	md := srpc.MetadataFromIncomingContext(ctx)
+	return &echo.EchoMsg{Body: md.Get(msg.GetBody())}, nil
+}
+
+func TestMetadata(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &metadataEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	ctx := srpc.AppendToOutgoingContext(context.Background(), "x-request-id", "req-1")
+	ctx = srpc.AppendToOutgoingContext(ctx, "x-user", "user-1")
+	for key, expected := range map[string]string{"x-request-id": "req-1", "x-user": "user-1"} {
+		resp, err := client.Echo(ctx, &echo.EchoMsg{Body: key})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resp.GetBody() != expected {
+			t.Fatalf("expected metadata %s=%q got %q", key, expected, resp.GetBody())
+		}
+	}
+}
+
+func TestNewContextWithMetadata(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &metadataEchoServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	// expect the metadata to replace the existing metadata
+	ctx := srpc.AppendToOutgoingContext(context.Background(), "x-user", "user-1")
+	ctx = srpc.NewContextWithMetadata(ctx, srpc.Metadata{"authorization": "token-1"})
+	for key, expected := range map[string]string{"authorization": "token-1", "x-user": ""} {
+		resp, err := client.Echo(ctx, &echo.EchoMsg{Body: key})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resp.GetBody() != expected {
+			t.Fatalf("expected metadata %s=%q got %q", key, expected, resp.GetBody())
+		}
+	}
+}
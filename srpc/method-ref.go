@@ -0,0 +1,54 @@
+package srpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MethodRef references a method of a service.
+//
+// The generated code contains a MethodRef for each method, for example
+// echo.SRPCEchoerService.EchoMethod. Use them instead of string literals in
+// interceptors, policies and metrics labels.
+type MethodRef struct {
+	// ServiceID is the id of the service.
+	ServiceID string
+	// MethodID is the id of the method.
+	MethodID string
+}
+
+// ParseMethodRef parses a method reference in the service/method format.
+func ParseMethodRef(ref string) (MethodRef, error) {
+	idx := strings.LastIndexByte(ref, '/')
+	if idx <= 0 || idx == len(ref)-1 {
+		return MethodRef{}, errors.Errorf("invalid method reference: %q: expected service/method", ref)
+	}
+	return MethodRef{ServiceID: ref[:idx], MethodID: ref[idx+1:]}, nil
+}
+
+// String returns the reference in the service/method format.
+func (r MethodRef) String() string {
+	return r.ServiceID + "/" + r.MethodID
+}
+
+// Matches checks if the reference matches the service and method.
+func (r MethodRef) Matches(serviceID, methodID string) bool {
+	return r.ServiceID == serviceID && r.MethodID == methodID
+}
+
+// MatchMethods returns an interceptor which applies the interceptor only to
+// calls to one of the methods.
+func MatchMethods(interceptor ServerInterceptor, methods ...MethodRef) ServerInterceptor {
+	match := make(map[MethodRef]struct{}, len(methods))
+	for _, method := range methods {
+		match[method] = struct{}{}
+	}
+	return func(ctx context.Context, serviceID, methodID string, strm Stream, next Invoker) (bool, error) {
+		if _, ok := match[MethodRef{ServiceID: serviceID, MethodID: methodID}]; !ok {
+			return next(serviceID, methodID, strm)
+		}
+		return interceptor(ctx, serviceID, methodID, strm, next)
+	}
+}
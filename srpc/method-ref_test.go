@@ -0,0 +1,53 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+func TestMethodRef(t *testing.T) {
+	ref := echo.SRPCEchoerService.EchoMethod
+	if ref.String() != "echo.Echoer/Echo" || !ref.Matches(echo.SRPCEchoerServiceID, echo.SRPCEchoerEchoMethodID) {
+		t.Fatalf("unexpected method ref %v", ref)
+	}
+	parsed, err := srpc.ParseMethodRef(ref.String())
+	if err != nil || parsed != ref {
+		t.Fatalf("expected %v got %v: %v", ref, parsed, err)
+	}
+	for _, invalid := range []string{"", "echo.Echoer", "/Echo", "echo.Echoer/"} {
+		if _, err := srpc.ParseMethodRef(invalid); err == nil {
+			t.Fatalf("expected error parsing %q", invalid)
+		}
+	}
+}
+
+func TestMatchMethods(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	errRejected := errors.New("rejected")
+	reject := func(ctx context.Context, serviceID, methodID string, strm srpc.Stream, next srpc.Invoker) (bool, error) {
+		return true, errRejected
+	}
+	server := srpc.NewServer(mux, srpc.WithInterceptors(srpc.MatchMethods(reject, echo.SRPCEchoerService.EchoMethod)))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	ctx := context.Background()
+
+	// expect the interceptor to apply only to the matching method
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err == nil || err.Error() != errRejected.Error() {
+		t.Fatalf("expected %v got %v", errRejected, err)
+	}
+	strm, err := client.EchoServerStream(ctx, &echo.EchoMsg{Body: "hello"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if msg, err := strm.Recv(); err != nil || msg.GetBody() != "hello" {
+		t.Fatalf("expected response got %v: %v", msg, err)
+	}
+	_ = strm.Close()
+}
@@ -0,0 +1,87 @@
+package mqbridge
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNoResponders is returned if no server is subscribed to the subject.
+var ErrNoResponders = errors.New("no responders for subject")
+
+// RequestHandler handles a request received from the broker.
+//
+// Returns the reply to send to the requester.
+type RequestHandler func(ctx context.Context, data []byte) ([]byte, error)
+
+// Broker is a message queue with request/reply semantics.
+type Broker interface {
+	// Request sends the request to a subscriber of the subject and waits for the reply.
+	Request(ctx context.Context, subject string, data []byte) ([]byte, error)
+	// Subscribe calls the handler with the requests sent to the subject.
+	//
+	// The requests may be load balanced between the subscribers.
+	// Returns a function to unsubscribe.
+	Subscribe(subject string, handler RequestHandler) (func(), error)
+}
+
+// MemBroker is an in-memory Broker.
+//
+// Requests are handled by the most recent subscriber of the subject.
+type MemBroker struct {
+	// mtx guards below fields
+	mtx sync.Mutex
+	// subs contains the handlers for each subject
+	subs map[string][]*memSub
+}
+
+// memSub is a subscription to a MemBroker subject.
+type memSub struct {
+	handler RequestHandler
+}
+
+// NewMemBroker constructs a new in-memory broker.
+func NewMemBroker() *MemBroker {
+	return &MemBroker{subs: make(map[string][]*memSub)}
+}
+
+// Request sends the request to a subscriber of the subject and waits for the reply.
+func (b *MemBroker) Request(ctx context.Context, subject string, data []byte) ([]byte, error) {
+	b.mtx.Lock()
+	subs := b.subs[subject]
+	var sub *memSub
+	if len(subs) != 0 {
+		sub = subs[len(subs)-1]
+	}
+	b.mtx.Unlock()
+	if sub == nil {
+		return nil, ErrNoResponders
+	}
+	return sub.handler(ctx, append([]byte(nil), data...))
+}
+
+// Subscribe calls the handler with the requests sent to the subject.
+func (b *MemBroker) Subscribe(subject string, handler RequestHandler) (func(), error) {
+	sub := &memSub{handler: handler}
+	b.mtx.Lock()
+	b.subs[subject] = append(b.subs[subject], sub)
+	b.mtx.Unlock()
+	return func() {
+		b.mtx.Lock()
+		defer b.mtx.Unlock()
+		subs := b.subs[subject]
+		for i, s := range subs {
+			if s == sub {
+				b.subs[subject] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		if len(b.subs[subject]) == 0 {
+			delete(b.subs, subject)
+		}
+	}, nil
+}
+
+// _ is a type assertion
+var _ Broker = ((*MemBroker)(nil))
@@ -0,0 +1,86 @@
+package mqbridge
+
+import (
+	"context"
+	"time"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// Client is a srpc.Client which sends unary calls over a Broker.
+//
+// Streaming calls are not supported.
+type Client struct {
+	// broker is the message queue
+	broker Broker
+	// subject is the subject to send the calls to
+	subject string
+}
+
+// NewClient constructs a new Client sending calls to the subject.
+func NewClient(broker Broker, subject string) *Client {
+	return &Client{broker: broker, subject: subject}
+}
+
+// Invoke executes a unary RPC with the remote.
+func (c *Client) Invoke(ctx context.Context, service, method string, in, out srpc.Message) error {
+	var data []byte
+	if in != nil {
+		var err error
+		data, err = in.MarshalVT()
+		if err != nil {
+			return err
+		}
+	}
+	pkt := srpc.NewCallStartPacket(service, method, data, len(data) == 0)
+	pkt.GetCallStart().Metadata = srpc.MetadataFromOutgoingContext(ctx)
+	reqData, err := pkt.MarshalVT()
+	if err != nil {
+		return err
+	}
+
+	replyData, err := c.broker.Request(ctx, c.subject, reqData)
+	if err != nil {
+		return err
+	}
+	reply := &srpc.Packet{}
+	if err := reply.UnmarshalVT(replyData); err != nil {
+		return err
+	}
+	callData := reply.GetCallData()
+	if callData == nil {
+		return errors.New("mqbridge: expected call data reply")
+	}
+	if errStr := callData.GetError(); errStr != "" {
+		var callErr error
+		if st := callData.GetStatus(); st != nil {
+			callErr = &srpc.StatusError{Status: st}
+		} else {
+			callErr = errors.New(errStr)
+		}
+		if details := callData.GetErrorDetails(); len(details) != 0 {
+			callErr = srpc.NewDetailedError(callErr, details...)
+		}
+		return callErr
+	}
+	if out == nil {
+		return nil
+	}
+	return out.UnmarshalVT(callData.GetData())
+}
+
+// NewStream returns ErrUnimplemented: only unary calls are supported.
+func (c *Client) NewStream(ctx context.Context, service, method string, firstMsg srpc.Message) (srpc.Stream, error) {
+	return nil, srpc.ErrUnimplemented
+}
+
+// Ping calls the built-in ping service with the remote.
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	err := c.Invoke(ctx, srpc.PingServiceID, srpc.PingMethodID, srpc.NewRawMessage(nil), srpc.NewRawMessage(nil))
+	return time.Since(start), err
+}
+
+// _ is a type assertion
+var _ srpc.Client = ((*Client)(nil))
@@ -0,0 +1,70 @@
+package mqbridge_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/mqbridge"
+	"github.com/aperturerobotics/starpc/srpc/status"
+)
+
+// metadataEchoServer returns the incoming metadata value in the Echo response.
+type metadataEchoServer struct {
+	*echo.EchoServer
+}
+
+// Echo returns the metadata value in the message body.
+func (s *metadataEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	if msg.GetBody() == "missing" {
+		return nil, status.Error(status.NotFound, "no such key")
+	}
+	md := srpc.MetadataFromIncomingContext(ctx)
+	return &echo.EchoMsg{Body: md.Get(msg.GetBody())}, nil
+}
+
+func TestMQBridge(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &metadataEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	broker := mqbridge.NewMemBroker()
+	client := echo.NewSRPCEchoerClient(mqbridge.NewClient(broker, "starpc.echo"))
+	ctx := srpc.AppendToOutgoingContext(context.Background(), "x-request-id", "req-1")
+	msg := &echo.EchoMsg{Body: "x-request-id"}
+
+	// expect calls to fail without a server
+	if _, err := client.Echo(ctx, msg); err != mqbridge.ErrNoResponders {
+		t.Fatalf("expected %v got %v", mqbridge.ErrNoResponders, err)
+	}
+
+	// expect calls to be handled by the server subscribed to the subject
+	stop, err := mqbridge.Serve(broker, "starpc.echo", srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer stop()
+	resp, err := client.Echo(ctx, msg)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != "req-1" {
+		t.Fatalf("expected metadata to be forwarded got %q", resp.GetBody())
+	}
+
+	// expect errors to be returned to the client
+	if _, err := client.EchoClientStream(ctx); err != srpc.ErrUnimplemented {
+		t.Fatalf("expected %v got %v", srpc.ErrUnimplemented, err)
+	}
+	if err := client.SRPCClient().Invoke(ctx, "unknown.Service", "Method", nil, nil); err == nil || err.Error() != srpc.ErrUnimplemented.Error() {
+		t.Fatalf("expected %v got %v", srpc.ErrUnimplemented, err)
+	}
+
+	// expect the status of the error to be returned to the client
+	_, err = client.Echo(ctx, &echo.EchoMsg{Body: "missing"})
+	if st := status.Convert(err); st.Code() != status.NotFound || st.Message() != "no such key" {
+		t.Fatalf("expected not found status got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Package mqnats implements a mqbridge Broker with a NATS connection.
+//
+// This package is a separate module so that the NATS client is only a
+// dependency of the programs which use it.
+package mqnats
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aperturerobotics/starpc/srpc/mqbridge"
+	"github.com/nats-io/nats.go"
+)
+
+// Broker is a mqbridge.Broker using a NATS connection.
+type Broker struct {
+	// conn is the nats connection
+	conn *nats.Conn
+	// queue is the queue group of the subscriptions, if set
+	queue string
+	// ctx is canceled when Close is called
+	ctx context.Context
+	// ctxCancel cancels ctx
+	ctxCancel context.CancelFunc
+}
+
+// NewBroker constructs a Broker with a NATS connection.
+//
+// If queue is set, subscribers join the queue group: each request is handled
+// by one of the subscribers. Close the broker to cancel the requests being
+// handled. The connection is not closed by the broker.
+func NewBroker(conn *nats.Conn, queue string) *Broker {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	return &Broker{conn: conn, queue: queue, ctx: ctx, ctxCancel: ctxCancel}
+}
+
+// Request sends the request to a subscriber of the subject and waits for the reply.
+func (b *Broker) Request(ctx context.Context, subject string, data []byte) ([]byte, error) {
+	msg, err := b.conn.RequestWithContext(ctx, subject, data)
+	if err != nil {
+		if err == nats.ErrNoResponders {
+			return nil, mqbridge.ErrNoResponders
+		}
+		return nil, err
+	}
+	return msg.Data, nil
+}
+
+// Subscribe calls the handler with the requests sent to the subject.
+//
+// Each request is handled in a separate goroutine, so a slow call does not
+// delay the other requests of the subscription. The context passed to the
+// handler is canceled when the broker is closed or the subscription is
+// removed. Unsubscribing waits for the handlers to return.
+func (b *Broker) Subscribe(subject string, handler mqbridge.RequestHandler) (func(), error) {
+	ctx, ctxCancel := context.WithCancel(b.ctx)
+	// mtx guards unsubscribed and adding to wg
+	var mtx sync.Mutex
+	var unsubscribed bool
+	var wg sync.WaitGroup
+	cb := func(msg *nats.Msg) {
+		mtx.Lock()
+		if unsubscribed {
+			mtx.Unlock()
+			return
+		}
+		wg.Add(1)
+		mtx.Unlock()
+		go func() {
+			defer wg.Done()
+			reply, err := handler(ctx, msg.Data)
+			if err != nil {
+				return
+			}
+			_ = msg.Respond(reply)
+		}()
+	}
+	var sub *nats.Subscription
+	var err error
+	if b.queue != "" {
+		sub, err = b.conn.QueueSubscribe(subject, b.queue, cb)
+	} else {
+		sub, err = b.conn.Subscribe(subject, cb)
+	}
+	if err != nil {
+		ctxCancel()
+		return nil, err
+	}
+	var unsubOnce sync.Once
+	return func() {
+		unsubOnce.Do(func() {
+			_ = sub.Unsubscribe()
+			// the callback may still be running after Unsubscribe
+			mtx.Lock()
+			unsubscribed = true
+			mtx.Unlock()
+			ctxCancel()
+			wg.Wait()
+		})
+	}, nil
+}
+
+// Close cancels the requests being handled by the subscriptions.
+//
+// The subscriptions should still be removed with the returned functions.
+func (b *Broker) Close() {
+	b.ctxCancel()
+}
+
+// _ is a type assertion
+var _ mqbridge.Broker = ((*Broker)(nil))
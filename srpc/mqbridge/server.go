@@ -0,0 +1,68 @@
+package mqbridge
+
+import (
+	"context"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// Serve handles the calls sent to the subject with the client.
+//
+// Use a client constructed with srpc.NewServerPipe to handle the calls with a
+// local Server, or any other client to forward the calls. Returns a function
+// to stop handling calls.
+func Serve(broker Broker, subject string, client srpc.Client) (func(), error) {
+	return broker.Subscribe(subject, func(ctx context.Context, data []byte) ([]byte, error) {
+		var outData []byte
+		err := handleRequest(ctx, client, data, &outData)
+		return newReplyPacket(outData, err).MarshalVT()
+	})
+}
+
+// newReplyPacket constructs the final CallData packet with the result.
+//
+// Copies the status and details of the error, if any.
+func newReplyPacket(outData []byte, err error) *srpc.Packet {
+	pkt := srpc.NewCallDataPacket(outData, len(outData) == 0 && err == nil, true, err)
+	if err == nil {
+		return pkt
+	}
+	pkt.GetCallData().ErrorDetails = srpc.GetErrorDetails(err)
+	if st := srpc.StatusFromError(err); st != nil {
+		pkt.GetCallData().Status = &srpc.Status{
+			Code:    st.GetCode(),
+			Message: err.Error(),
+			Details: st.GetDetails(),
+		}
+	}
+	return pkt
+}
+
+// handleRequest decodes and invokes the call in the request.
+func handleRequest(ctx context.Context, client srpc.Client, data []byte, outData *[]byte) error {
+	pkt := &srpc.Packet{}
+	if err := pkt.UnmarshalVT(data); err != nil {
+		return err
+	}
+	callStart := pkt.GetCallStart()
+	if callStart == nil {
+		return errors.New("mqbridge: expected call start request")
+	}
+	for key, value := range callStart.GetMetadata() {
+		ctx = srpc.AppendToOutgoingContext(ctx, key, value)
+	}
+	out := srpc.NewRawMessage(nil)
+	err := client.Invoke(
+		ctx,
+		callStart.GetRpcService(),
+		callStart.GetRpcMethod(),
+		srpc.NewRawMessage(callStart.GetData()),
+		out,
+	)
+	if err != nil {
+		return err
+	}
+	*outData = out.GetData()
+	return nil
+}
@@ -3,6 +3,7 @@ package srpc
 import (
 	"context"
 	"io"
+	"time"
 )
 
 // MsgStream implements the stream interface passed to implementations.
@@ -13,6 +14,18 @@ type MsgStream struct {
 	writer Writer
 	// dataCh is the incoming data channel.
 	dataCh chan []byte
+	// peerCanceled is closed when the remote cancels the call.
+	// may be nil
+	peerCanceled <-chan struct{}
+	// closedErr returns the error to return after dataCh is closed.
+	// may be nil
+	closedErr func() error
+	// pendingSend is closed when the write of a timed out MsgSendCtx completes.
+	// may be nil
+	pendingSend chan struct{}
+	// codec encodes the messages.
+	// may be nil
+	codec Codec
 }
 
 // NewMsgStream constructs a new Stream with a ClientRPC.
@@ -30,47 +43,250 @@ func (r *MsgStream) Context() context.Context {
 	return r.ctx
 }
 
-// MsgSend sends the message to the remote.
-func (r *MsgStream) MsgSend(msg Message) error {
+// checkCanceled returns an error if the stream was canceled.
+func (r *MsgStream) checkCanceled() error {
+	select {
+	case <-r.peerCanceled:
+		return ErrCanceledByPeer
+	default:
+	}
 	select {
 	case <-r.ctx.Done():
 		return context.Canceled
 	default:
 	}
+	return nil
+}
 
-	msgData, err := msg.MarshalVT()
+// MsgSend sends the message to the remote.
+//
+// Returns ErrCanceledByPeer if the remote canceled the call.
+func (r *MsgStream) MsgSend(msg Message) error {
+	if err := r.checkCanceled(); err != nil {
+		return err
+	}
+
+	msgData, err := marshalWithCodec(r.codec, msg)
 	if err != nil {
 		return err
 	}
+	if err := r.waitPendingSend(r.ctx); err != nil {
+		return err
+	}
 	outPkt := NewCallDataPacket(msgData, len(msgData) == 0, false, nil)
 	return r.writer.WritePacket(outPkt)
 }
 
+// MsgSendCtx sends the message to the remote waiting at most until ctx is done.
+//
+// Returns ErrSendTimeout if the ctx deadline expired or context.Canceled if
+// ctx was canceled, while the stream remains usable. The write cannot be
+// interrupted: the message may still be delivered, and the next send waits
+// for the write to complete to keep the messages in order.
+func (r *MsgStream) MsgSendCtx(ctx context.Context, msg Message) error {
+	if err := r.checkCanceled(); err != nil {
+		return err
+	}
+
+	msgData, err := marshalWithCodec(r.codec, msg)
+	if err != nil {
+		return err
+	}
+	if err := r.waitPendingSend(ctx); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return sendCtxErr(ctx)
+	}
+	outPkt := NewCallDataPacket(msgData, len(msgData) == 0, false, nil)
+	errCh := make(chan error, 1)
+	doneCh := make(chan struct{})
+	go func() {
+		errCh <- r.writer.WritePacket(outPkt)
+		close(doneCh)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		r.pendingSend = doneCh
+		return sendCtxErr(ctx)
+	}
+}
+
+// MsgSendBatch sends the messages to the remote in order.
+//
+// Writes the messages with a single write if the writer is a BatchWriter.
+// Returns ErrCanceledByPeer if the remote canceled the call.
+func (r *MsgStream) MsgSendBatch(msgs []Message) error {
+	if err := r.checkCanceled(); err != nil {
+		return err
+	}
+
+	if err := r.waitPendingSend(r.ctx); err != nil {
+		return err
+	}
+	pkts := make([]*Packet, len(msgs))
+	for i, msg := range msgs {
+		msgData, err := marshalWithCodec(r.codec, msg)
+		if err != nil {
+			return err
+		}
+		pkts[i] = NewCallDataPacket(msgData, len(msgData) == 0, false, nil)
+	}
+	return writePackets(r.writer, pkts)
+}
+
 // MsgRecv receives an incoming message from the remote.
 // Parses the message into the object at msg.
+//
+// Returns ErrCanceledByPeer if the remote canceled the call.
 func (r *MsgStream) MsgRecv(msg Message) error {
+	return r.msgRecv(msg, nil)
+}
+
+// MsgRecvTimeout receives an incoming message waiting at most the duration.
+//
+// Returns ErrRecvTimeout if no message was received in time.
+func (r *MsgStream) MsgRecvTimeout(msg Message, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	return r.msgRecv(msg, timer.C)
+}
+
+// MsgTryRecv receives an incoming message if one is available without blocking.
+//
+// Returns false if no message was available.
+func (r *MsgStream) MsgTryRecv(msg Message) (bool, error) {
+	select {
+	case data, ok := <-r.dataCh:
+		if err := r.handleRecv(msg, data, ok); err != nil {
+			return false, err
+		}
+		return true, nil
+	default:
+	}
+	return false, r.checkCanceled()
+}
+
+// msgRecv receives an incoming message until the timeout channel fires.
+// timeoutCh may be nil to wait indefinitely.
+func (r *MsgStream) msgRecv(msg Message, timeoutCh <-chan time.Time) error {
 	select {
+	case <-r.peerCanceled:
+		return ErrCanceledByPeer
 	case <-r.Context().Done():
-		return context.Canceled
+		return r.checkCanceled()
+	case <-timeoutCh:
+		return ErrRecvTimeout
 	case data, ok := <-r.dataCh:
-		if !ok {
-			return io.EOF
+		return r.handleRecv(msg, data, ok)
+	}
+}
+
+// handleRecv parses data received from dataCh into msg.
+func (r *MsgStream) handleRecv(msg Message, data []byte, ok bool) error {
+	if !ok {
+		select {
+		case <-r.peerCanceled:
+			return ErrCanceledByPeer
+		default:
 		}
-		return msg.UnmarshalVT(data)
+		if r.closedErr != nil {
+			if err := r.closedErr(); err != nil {
+				return err
+			}
+		}
+		return io.EOF
 	}
+	return unmarshalWithCodec(r.codec, msg, data)
 }
 
 // CloseSend signals to the remote that we will no longer send any messages.
 func (r *MsgStream) CloseSend() error {
+	if err := r.waitPendingSend(r.ctx); err != nil {
+		return err
+	}
 	outPkt := NewCallDataPacket(nil, false, true, nil)
 	return r.writer.WritePacket(outPkt)
 }
 
+// waitPendingSend waits for the write of a timed out MsgSendCtx to complete.
+func (r *MsgStream) waitPendingSend(ctx context.Context) error {
+	if r.pendingSend == nil {
+		return nil
+	}
+	select {
+	case <-r.pendingSend:
+		r.pendingSend = nil
+		return nil
+	case <-r.peerCanceled:
+		return ErrCanceledByPeer
+	case <-ctx.Done():
+		return sendCtxErr(ctx)
+	}
+}
+
+// sendCtxErr returns the error of a send interrupted by ctx.
+func sendCtxErr(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return ErrSendTimeout
+	}
+	return context.Canceled
+}
+
 // Close closes the stream.
 func (r *MsgStream) Close() error {
 	_ = r.writer.Close()
 	return nil
 }
 
+// CloseWithTimeout cancels the call and waits at most d for the remote to
+// complete it.
+//
+// Sends CallCancel and waits for the stream to close, discarding any messages
+// received in the meantime. Waits for the write left by a timed out MsgSendCtx
+// first. Resets the stream if the remote did not complete the call in time, so
+// that teardown takes a bounded amount of time.
+func (r *MsgStream) CloseWithTimeout(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	// wait for the write of a timed out MsgSendCtx before writing CallCancel.
+	if r.pendingSend != nil {
+		select {
+		case <-r.pendingSend:
+			r.pendingSend = nil
+		case <-timer.C:
+			return r.Reset()
+		}
+	}
+	// the write fails if the call already completed.
+	_ = r.writer.WritePacket(NewCallCancelPacket())
+	for {
+		select {
+		case _, ok := <-r.dataCh:
+			if !ok {
+				return r.Close()
+			}
+		case <-timer.C:
+			return r.Reset()
+		}
+	}
+}
+
+// Reset aborts the stream without waiting for the remote.
+//
+// Resets the transport stream if supported: the remote sees a transport error
+// instead of a graceful close. Otherwise closes the stream.
+func (r *MsgStream) Reset() error {
+	return resetWriter(r.writer)
+}
+
 // _ is a type assertion
-var _ Stream = ((*MsgStream)(nil))
+var (
+	_ Stream         = ((*MsgStream)(nil))
+	_ RecvPoller     = ((*MsgStream)(nil))
+	_ BatchSender    = ((*MsgStream)(nil))
+	_ CtxSender      = ((*MsgStream)(nil))
+	_ StreamResetter = ((*MsgStream)(nil))
+)
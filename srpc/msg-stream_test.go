@@ -0,0 +1,194 @@
+package srpc_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// blockedWriter blocks writes until released.
+type blockedWriter struct {
+	release chan struct{}
+	// writing is the number of writes in progress.
+	writing int32
+	// concurrent is set if writes overlapped.
+	concurrent int32
+	mtx        sync.Mutex
+	written    []*srpc.Packet
+}
+
+func (w *blockedWriter) WritePacket(p *srpc.Packet) error {
+	if atomic.AddInt32(&w.writing, 1) != 1 {
+		atomic.StoreInt32(&w.concurrent, 1)
+	}
+	defer atomic.AddInt32(&w.writing, -1)
+	<-w.release
+	w.mtx.Lock()
+	w.written = append(w.written, p)
+	w.mtx.Unlock()
+	return nil
+}
+
+func (w *blockedWriter) Close() error {
+	return nil
+}
+
+func TestMsgStream_MsgSendCtx(t *testing.T) {
+	writer := &blockedWriter{release: make(chan struct{})}
+	strm := srpc.NewMsgStream(context.Background(), writer, make(chan []byte))
+
+	// expect the blocked send to time out
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer ctxCancel()
+	if err := srpc.MsgSendCtx(ctx, strm, &echo.EchoMsg{Body: "first"}); err != srpc.ErrSendTimeout {
+		t.Fatalf("expected %v got %v", srpc.ErrSendTimeout, err)
+	}
+
+	// expect the stream to remain usable and keep the messages in order
+	close(writer.release)
+	if err := strm.MsgSend(&echo.EchoMsg{Body: "second"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	writer.mtx.Lock()
+	defer writer.mtx.Unlock()
+	if len(writer.written) != 2 {
+		t.Fatalf("expected 2 packets got %d", len(writer.written))
+	}
+	for i, body := range []string{"first", "second"} {
+		msg := &echo.EchoMsg{}
+		if err := msg.UnmarshalVT(writer.written[i].GetCallData().GetData()); err != nil {
+			t.Fatal(err.Error())
+		}
+		if msg.GetBody() != body {
+			t.Fatalf("expected %q got %q", body, msg.GetBody())
+		}
+	}
+}
+
+func TestPipeStream_MsgSendCtx(t *testing.T) {
+	s1, s2 := srpc.NewPipeStream(context.Background())
+	defer s1.Close()
+
+	// fill the buffer of the other end
+	var sent int
+	for {
+		ctx, ctxCancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		err := srpc.MsgSendCtx(ctx, s1, &echo.EchoMsg{Body: "hello"})
+		ctxCancel()
+		if err == srpc.ErrSendTimeout {
+			break
+		}
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		sent++
+	}
+	if sent == 0 {
+		t.Fatal("expected messages to be sent before the timeout")
+	}
+
+	// expect the stream to remain usable
+	msg := &echo.EchoMsg{}
+	if err := s2.MsgRecv(msg); err != nil || msg.GetBody() != "hello" {
+		t.Fatalf("expected message got %v: %v", msg, err)
+	}
+	if err := s1.MsgSend(&echo.EchoMsg{Body: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+// resetRecordingWriter records Close and Reset calls.
+type resetRecordingWriter struct {
+	// onCancel is called when a CallCancel packet is written.
+	onCancel func()
+	mtx      sync.Mutex
+	closed   bool
+	reset    bool
+}
+
+func (w *resetRecordingWriter) WritePacket(p *srpc.Packet) error {
+	if p.GetCallCancel() && w.onCancel != nil {
+		w.onCancel()
+	}
+	return nil
+}
+
+func (w *resetRecordingWriter) Close() error {
+	w.mtx.Lock()
+	w.closed = true
+	w.mtx.Unlock()
+	return nil
+}
+
+func (w *resetRecordingWriter) Reset() error {
+	w.mtx.Lock()
+	w.reset = true
+	w.mtx.Unlock()
+	return nil
+}
+
+func TestMsgStream_CloseWithTimeout(t *testing.T) {
+	// expect a graceful close if the remote completes the call
+	dataCh := make(chan []byte, 1)
+	dataCh <- []byte("queued")
+	writer := &resetRecordingWriter{onCancel: func() { close(dataCh) }}
+	strm := srpc.NewMsgStream(context.Background(), writer, dataCh)
+	if err := srpc.CloseWithTimeout(strm, time.Second); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !writer.closed || writer.reset {
+		t.Fatalf("expected graceful close: closed=%v reset=%v", writer.closed, writer.reset)
+	}
+
+	// expect a reset if the remote does not complete the call in time
+	writer = &resetRecordingWriter{}
+	strm = srpc.NewMsgStream(context.Background(), writer, make(chan []byte))
+	start := time.Now()
+	if err := strm.CloseWithTimeout(time.Millisecond * 10); err != nil {
+		t.Fatal(err.Error())
+	}
+	if dur := time.Since(start); dur > time.Second {
+		t.Fatalf("expected bounded close, took %v", dur)
+	}
+	writer.mtx.Lock()
+	defer writer.mtx.Unlock()
+	if writer.closed || !writer.reset {
+		t.Fatalf("expected reset: closed=%v reset=%v", writer.closed, writer.reset)
+	}
+}
+
+func TestMsgStream_CloseWithTimeoutPendingSend(t *testing.T) {
+	writer := &blockedWriter{release: make(chan struct{})}
+	strm := srpc.NewMsgStream(context.Background(), writer, make(chan []byte))
+
+	// leave a pending write behind
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer ctxCancel()
+	if err := srpc.MsgSendCtx(ctx, strm, &echo.EchoMsg{Body: "first"}); err != srpc.ErrSendTimeout {
+		t.Fatalf("expected %v got %v", srpc.ErrSendTimeout, err)
+	}
+
+	// expect CallCancel to be written after the pending write
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- strm.CloseWithTimeout(time.Millisecond * 100)
+	}()
+	<-time.After(time.Millisecond * 10)
+	close(writer.release)
+	if err := <-errCh; err != nil {
+		t.Fatal(err.Error())
+	}
+	if atomic.LoadInt32(&writer.concurrent) != 0 {
+		t.Fatal("expected CallCancel to wait for the pending write")
+	}
+	writer.mtx.Lock()
+	defer writer.mtx.Unlock()
+	if len(writer.written) != 2 || !writer.written[1].GetCallCancel() {
+		t.Fatalf("expected data then CallCancel got %v", writer.written)
+	}
+}
@@ -0,0 +1,134 @@
+package srpc
+
+import "github.com/pkg/errors"
+
+// ItemCodeError is the code used for a failed item if none was given.
+const ItemCodeError = "error"
+
+// MultiStatusResponse is a batch method response with a MultiStatus.
+//
+// Implemented by response messages with a multi_status field.
+type MultiStatusResponse interface {
+	// GetMultiStatus returns the status of each item in the batch.
+	GetMultiStatus() *MultiStatus
+}
+
+// MultiStatusOutcome classifies the result of a batch call.
+type MultiStatusOutcome int
+
+const (
+	// MultiStatusNone indicates the response has no item statuses.
+	MultiStatusNone MultiStatusOutcome = iota
+	// MultiStatusOK indicates all items succeeded.
+	MultiStatusOK
+	// MultiStatusPartial indicates some of the items failed.
+	MultiStatusPartial
+	// MultiStatusFailed indicates all items failed.
+	MultiStatusFailed
+)
+
+// String returns the name of the outcome.
+func (o MultiStatusOutcome) String() string {
+	switch o {
+	case MultiStatusOK:
+		return "ok"
+	case MultiStatusPartial:
+		return "partial"
+	case MultiStatusFailed:
+		return "failed"
+	default:
+		return "none"
+	}
+}
+
+// AddOK adds the status of a successful item.
+func (m *MultiStatus) AddOK(index int) {
+	m.Items = append(m.Items, &ItemStatus{Index: uint32(index)})
+}
+
+// AddError adds the status of a failed item.
+//
+// If code is empty, uses ItemCodeError.
+func (m *MultiStatus) AddError(index int, code string, err error) {
+	if code == "" {
+		code = ItemCodeError
+	}
+	var errStr string
+	if err != nil {
+		errStr = err.Error()
+	}
+	m.Items = append(m.Items, &ItemStatus{
+		Index: uint32(index),
+		Code:  code,
+		Error: errStr,
+	})
+}
+
+// Add adds the status of an item with the result of processing it.
+//
+// Adds a successful item if err is nil.
+func (m *MultiStatus) Add(index int, err error) {
+	if err == nil {
+		m.AddOK(index)
+	} else {
+		m.AddError(index, "", err)
+	}
+}
+
+// CountFailed returns the number of failed items.
+func (m *MultiStatus) CountFailed() int {
+	var n int
+	for _, item := range m.GetItems() {
+		if !item.IsOK() {
+			n++
+		}
+	}
+	return n
+}
+
+// Outcome classifies the result of the batch.
+func (m *MultiStatus) Outcome() MultiStatusOutcome {
+	total := len(m.GetItems())
+	if total == 0 {
+		return MultiStatusNone
+	}
+	switch m.CountFailed() {
+	case 0:
+		return MultiStatusOK
+	case total:
+		return MultiStatusFailed
+	default:
+		return MultiStatusPartial
+	}
+}
+
+// IsOK checks if the item succeeded.
+func (i *ItemStatus) IsOK() bool {
+	return i.GetCode() == "" && i.GetError() == ""
+}
+
+// Err returns an error for the item or nil if it succeeded.
+func (i *ItemStatus) Err() error {
+	if i.IsOK() {
+		return nil
+	}
+	if i.GetError() == "" {
+		return errors.New(i.GetCode())
+	}
+	return errors.New(i.GetError())
+}
+
+// GetMultiStatusOutcome classifies the result of a batch call response.
+//
+// msg can be a MultiStatus or a MultiStatusResponse. Returns MultiStatusNone
+// for other messages.
+func GetMultiStatusOutcome(msg Message) MultiStatusOutcome {
+	switch m := msg.(type) {
+	case *MultiStatus:
+		return m.Outcome()
+	case MultiStatusResponse:
+		return m.GetMultiStatus().Outcome()
+	default:
+		return MultiStatusNone
+	}
+}
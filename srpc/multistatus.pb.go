@@ -0,0 +1,237 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1-devel
+// 	protoc        v3.19.3
+// source: github.com/aperturerobotics/starpc/srpc/multistatus.proto
+
+package srpc
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MultiStatus reports the result of each item of a batch method.
+//
+// Batch unary methods include it in the response as the multi_status field.
+// The call succeeds if the request was processed, even if some items failed.
+type MultiStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Items contains the status of each item in request order.
+	Items []*ItemStatus `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *MultiStatus) Reset() {
+	*x = MultiStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MultiStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultiStatus) ProtoMessage() {}
+
+func (x *MultiStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MultiStatus.ProtoReflect.Descriptor instead.
+func (*MultiStatus) Descriptor() ([]byte, []int) {
+	return file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MultiStatus) GetItems() []*ItemStatus {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// ItemStatus is the status of a single item of a batch method.
+type ItemStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Index is the index of the item in the request.
+	Index uint32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Code is a short machine-readable status code.
+	// Empty if the item succeeded.
+	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	// Error is the error message if the item failed.
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ItemStatus) Reset() {
+	*x = ItemStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ItemStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemStatus) ProtoMessage() {}
+
+func (x *ItemStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemStatus.ProtoReflect.Descriptor instead.
+func (*ItemStatus) Descriptor() ([]byte, []int) {
+	return file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ItemStatus) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ItemStatus) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ItemStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_github_com_aperturerobotics_starpc_srpc_multistatus_proto protoreflect.FileDescriptor
+
+var file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDesc = []byte{
+	0x0a, 0x39, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x70, 0x65,
+	0x72, 0x74, 0x75, 0x72, 0x65, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x69, 0x63, 0x73, 0x2f, 0x73, 0x74,
+	0x61, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x72, 0x70, 0x63, 0x2f, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x73, 0x72, 0x70,
+	0x63, 0x22, 0x35, 0x0a, 0x0b, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x26, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x4c, 0x0a, 0x0a, 0x49, 0x74, 0x65, 0x6d,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDescOnce sync.Once
+	file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDescData = file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDesc
+)
+
+func file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDescGZIP() []byte {
+	file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDescOnce.Do(func() {
+		file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDescData)
+	})
+	return file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDescData
+}
+
+var file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_goTypes = []interface{}{
+	(*MultiStatus)(nil), // 0: srpc.MultiStatus
+	(*ItemStatus)(nil),  // 1: srpc.ItemStatus
+}
+var file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_depIdxs = []int32{
+	1, // 0: srpc.MultiStatus.items:type_name -> srpc.ItemStatus
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_init() }
+func file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_init() {
+	if File_github_com_aperturerobotics_starpc_srpc_multistatus_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MultiStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ItemStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_goTypes,
+		DependencyIndexes: file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_depIdxs,
+		MessageInfos:      file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_msgTypes,
+	}.Build()
+	File_github_com_aperturerobotics_starpc_srpc_multistatus_proto = out.File
+	file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_rawDesc = nil
+	file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_goTypes = nil
+	file_github_com_aperturerobotics_starpc_srpc_multistatus_proto_depIdxs = nil
+}
@@ -0,0 +1,79 @@
+package srpc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// batchHandler stores a batch of comma-separated items reporting each status.
+type batchHandler struct{}
+
+func TestMultiStatus(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := mux.Register(batchHandler{}); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux)))
+
+	var outcomes []srpc.MultiStatusOutcome
+	ctx := srpc.WithClientTrace(context.Background(), &srpc.ClientTrace{
+		MultiStatus: func(outcome srpc.MultiStatusOutcome) {
+			outcomes = append(outcomes, outcome)
+		},
+	})
+	for _, body := range []string{"a,b", "a,,b", ","} {
+		resp := &srpc.MultiStatus{}
+		if err := client.Invoke(ctx, "test.Batch", "Put", &echo.EchoMsg{Body: body}, resp); err != nil {
+			t.Fatal(err.Error())
+		}
+		if body == "a,,b" {
+			if n := resp.CountFailed(); n != 1 {
+				t.Fatalf("expected 1 failed item got %d", n)
+			}
+			item := resp.GetItems()[1]
+			if item.GetIndex() != 1 || item.GetCode() != "empty" || item.Err() == nil {
+				t.Fatalf("unexpected item status: %v", item.String())
+			}
+		}
+	}
+	expected := []srpc.MultiStatusOutcome{srpc.MultiStatusOK, srpc.MultiStatusPartial, srpc.MultiStatusFailed}
+	if len(outcomes) != len(expected) {
+		t.Fatalf("expected outcomes %v got %v", expected, outcomes)
+	}
+	for i := range expected {
+		if outcomes[i] != expected[i] {
+			t.Fatalf("expected outcomes %v got %v", expected, outcomes)
+		}
+	}
+}
+
+// GetServiceID returns the ID of the service.
+func (batchHandler) GetServiceID() string { return "test.Batch" }
+
+// GetMethodIDs returns the list of methods for the service.
+func (batchHandler) GetMethodIDs() []string { return []string{"Put"} }
+
+// InvokeMethod fails the empty items and returns the MultiStatus.
+func (batchHandler) InvokeMethod(serviceID, methodID string, strm srpc.Stream) (bool, error) {
+	if methodID != "Put" {
+		return false, nil
+	}
+	req := &echo.EchoMsg{}
+	if err := strm.MsgRecv(req); err != nil {
+		return true, err
+	}
+	resp := &srpc.MultiStatus{}
+	for i, item := range strings.Split(req.GetBody(), ",") {
+		if item == "" {
+			resp.AddError(i, "empty", errors.New("item is empty"))
+		} else {
+			resp.AddOK(i)
+		}
+	}
+	return true, strm.MsgSend(resp)
+}
@@ -1,31 +1,162 @@
 package srpc
 
-import "sync"
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // Mux contains a set of <service, method> handlers.
 type Mux interface {
 	// Register registers a new RPC method handler (service).
+	// Returns a *RegisterConflictError if a method is already registered.
 	Register(handler Handler) error
 	// InvokeMethod invokes the method matching the service & method ID.
 	// Returns false, nil if not found.
 	// If service string is empty, ignore it.
 	InvokeMethod(serviceID, methodID string, strm Stream) (bool, error)
+	// Services returns the registered services sorted by service ID.
+	Services() []ServiceInfo
+}
+
+// ServiceInfo describes a service registered with a Mux.
+type ServiceInfo struct {
+	// ServiceID is the ID of the service.
+	ServiceID string
+	// Methods contains the methods of the service sorted by method ID.
+	Methods []MethodInfo
+}
+
+// MethodInfo describes a method registered with a Mux.
+type MethodInfo struct {
+	// MethodID is the ID of the method.
+	MethodID string
+	// HandlerType is the Go type of the handler, for example "*echo.EchoServer".
+	HandlerType string
+	// Deprecated indicates the method has the "deprecated" proto option.
+	Deprecated bool
+	// DeprecatedCalls is the number of calls to the deprecated method.
+	DeprecatedCalls uint64
+	// CacheTTL is the result cache duration of the method, zero if not cached.
+	CacheTTL time.Duration
+}
+
+// MuxOption is an option passed to NewMux.
+type MuxOption func(m *mux)
+
+// WithDeprecationHandler sets a callback called when a deprecated method is invoked.
+func WithDeprecationHandler(cb DeprecationHandler) MuxOption {
+	return func(m *mux) {
+		m.deprecationHandler = cb
+	}
+}
+
+// WithAllowOverride allows Register to replace existing method handlers.
+//
+// By default Register returns a *RegisterConflictError instead.
+func WithAllowOverride() MuxOption {
+	return func(m *mux) {
+		m.allowOverride = true
+	}
+}
+
+// WithResultCache caches the responses of methods with a cache duration.
+//
+// The cache durations are set with the cache_ttl_ms proto option, see
+// CachedMethodsHandler. Methods without a duration are not cached.
+func WithResultCache(cache *ResultCache) MuxOption {
+	return func(m *mux) {
+		m.resultCache = cache
+	}
+}
+
+// WithCoalescer coalesces identical concurrent calls to the Coalescer methods.
+func WithCoalescer(coalescer *Coalescer) MuxOption {
+	return func(m *mux) {
+		m.coalescer = coalescer
+	}
+}
+
+// muxMethod is a registered method handler.
+type muxMethod struct {
+	// handler is the method handler
+	handler Handler
+	// registrant describes the handler and where it was registered
+	registrant string
 }
 
 // muxMethods is a mapping from method id to handler.
-type muxMethods map[string]Handler
+type muxMethods map[string]*muxMethod
+
+// RegisterConflictError is returned if a method handler is already registered.
+type RegisterConflictError struct {
+	// ServiceID is the service ID.
+	ServiceID string
+	// MethodID is the method ID.
+	MethodID string
+	// Existing describes the registered handler and where it was registered.
+	Existing string
+	// Conflicting describes the rejected handler and where it was registered.
+	Conflicting string
+}
+
+// Error returns the error string listing both registrants.
+func (e *RegisterConflictError) Error() string {
+	return fmt.Sprintf(
+		"method %s/%s already registered by %s: conflicts with %s",
+		e.ServiceID,
+		e.MethodID,
+		e.Existing,
+		e.Conflicting,
+	)
+}
+
+// Unwrap returns ErrRegisterConflict.
+func (e *RegisterConflictError) Unwrap() error {
+	return ErrRegisterConflict
+}
 
 // mux is the default implementation of Mux.
 type mux struct {
+	// deprecationHandler is called when a deprecated method is invoked.
+	// may be nil
+	deprecationHandler DeprecationHandler
+	// resultCache caches method results.
+	// may be nil
+	resultCache *ResultCache
+	// coalescer coalesces identical concurrent calls.
+	// may be nil
+	coalescer *Coalescer
+	// allowOverride allows replacing existing method handlers.
+	allowOverride bool
 	// rmtx guards below fields
 	rmtx sync.RWMutex
 	// services contains a mapping from services to handlers.
 	services map[string]muxMethods
+	// deprecated contains a mapping from services to deprecated method call counters.
+	// the counters are accessed with atomic
+	deprecated map[string]map[string]*uint64
+	// cacheTTLs contains a mapping from services to method cache durations.
+	cacheTTLs map[string]map[string]time.Duration
 }
 
 // NewMux constructs a new Mux.
-func NewMux() Mux {
-	return &mux{services: make(map[string]muxMethods)}
+func NewMux(opts ...MuxOption) Mux {
+	m := &mux{
+		services:   make(map[string]muxMethods),
+		deprecated: make(map[string]map[string]*uint64),
+		cacheTTLs:  make(map[string]map[string]time.Duration),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	return m
 }
 
 // Register registers a new RPC method handler (service).
@@ -36,17 +167,64 @@ func (m *mux) Register(handler Handler) error {
 		return ErrEmptyServiceID
 	}
 
+	registrant := describeRegistrant(handler)
+
 	m.rmtx.Lock()
 	defer m.rmtx.Unlock()
 
 	serviceMethods := m.services[serviceID]
+	if !m.allowOverride {
+		for _, methodID := range methodIDs {
+			if existing := serviceMethods[methodID]; existing != nil {
+				return &RegisterConflictError{
+					ServiceID:   serviceID,
+					MethodID:    methodID,
+					Existing:    existing.registrant,
+					Conflicting: registrant,
+				}
+			}
+		}
+	}
 	if serviceMethods == nil {
 		serviceMethods = make(muxMethods)
 		m.services[serviceID] = serviceMethods
 	}
 	for _, methodID := range methodIDs {
 		if methodID != "" {
-			serviceMethods[methodID] = handler
+			serviceMethods[methodID] = &muxMethod{
+				handler:    handler,
+				registrant: registrant,
+			}
+			// clear the options of the replaced handler, if any.
+			delete(m.deprecated[serviceID], methodID)
+			delete(m.cacheTTLs[serviceID], methodID)
+		}
+	}
+
+	if dh, ok := handler.(DeprecatedMethodsHandler); ok {
+		deprecatedIDs := dh.GetDeprecatedMethodIDs()
+		if len(deprecatedIDs) != 0 {
+			deprecatedMethods := m.deprecated[serviceID]
+			if deprecatedMethods == nil {
+				deprecatedMethods = make(map[string]*uint64, len(deprecatedIDs))
+				m.deprecated[serviceID] = deprecatedMethods
+			}
+			for _, methodID := range deprecatedIDs {
+				deprecatedMethods[methodID] = new(uint64)
+			}
+		}
+	}
+
+	if ch, ok := handler.(CachedMethodsHandler); ok {
+		if ttls := ch.GetMethodCacheTTLs(); len(ttls) != 0 {
+			cachedMethods := m.cacheTTLs[serviceID]
+			if cachedMethods == nil {
+				cachedMethods = make(map[string]time.Duration, len(ttls))
+				m.cacheTTLs[serviceID] = cachedMethods
+			}
+			for methodID, ttl := range ttls {
+				cachedMethods[methodID] = ttl
+			}
 		}
 	}
 
@@ -58,10 +236,15 @@ func (m *mux) Register(handler Handler) error {
 // If service string is empty, ignore it.
 func (m *mux) InvokeMethod(serviceID, methodID string, strm Stream) (bool, error) {
 	var handler Handler
+	var deprecatedCalls *uint64
+	var cacheTTL time.Duration
 	m.rmtx.RLock()
-	svcMethods := m.services[serviceID]
-	if svcMethods != nil {
-		handler = svcMethods[methodID]
+	if method := m.services[serviceID][methodID]; method != nil {
+		handler = method.handler
+	}
+	if handler != nil {
+		deprecatedCalls = m.deprecated[serviceID][methodID]
+		cacheTTL = m.cacheTTLs[serviceID][methodID]
 	}
 	m.rmtx.RUnlock()
 
@@ -69,8 +252,81 @@ func (m *mux) InvokeMethod(serviceID, methodID string, strm Stream) (bool, error
 		return false, nil
 	}
 
+	if deprecatedCalls != nil {
+		atomic.AddUint64(deprecatedCalls, 1)
+		_ = SetResponseMetadata(strm.Context(), DeprecatedMetadataKey, serviceID+"/"+methodID+" is deprecated")
+		if m.deprecationHandler != nil {
+			m.deprecationHandler(serviceID, methodID)
+		}
+	}
+
+	if cacheTTL > 0 && m.resultCache != nil {
+		return true, m.resultCache.invokeMethod(handler, serviceID, methodID, cacheTTL, strm)
+	}
+
+	if m.coalescer != nil && m.coalescer.isCoalesced(serviceID, methodID) {
+		return true, m.coalescer.invokeMethod(handler, serviceID, methodID, strm)
+	}
+
 	return handler.InvokeMethod(serviceID, methodID, strm)
 }
 
+// Services returns the registered services sorted by service ID.
+func (m *mux) Services() []ServiceInfo {
+	m.rmtx.RLock()
+	defer m.rmtx.RUnlock()
+
+	services := make([]ServiceInfo, 0, len(m.services))
+	for serviceID, svcMethods := range m.services {
+		info := ServiceInfo{
+			ServiceID: serviceID,
+			Methods:   make([]MethodInfo, 0, len(svcMethods)),
+		}
+		for methodID, method := range svcMethods {
+			info.Methods = append(info.Methods, MethodInfo{
+				MethodID:    methodID,
+				HandlerType: fmt.Sprintf("%T", method.handler),
+				CacheTTL:    m.cacheTTLs[serviceID][methodID],
+			})
+			if calls := m.deprecated[serviceID][methodID]; calls != nil {
+				info.Methods[len(info.Methods)-1].Deprecated = true
+				info.Methods[len(info.Methods)-1].DeprecatedCalls = atomic.LoadUint64(calls)
+			}
+		}
+		sort.Slice(info.Methods, func(i, j int) bool {
+			return info.Methods[i].MethodID < info.Methods[j].MethodID
+		})
+		services = append(services, info)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].ServiceID < services[j].ServiceID
+	})
+	return services
+}
+
+// describeRegistrant describes the handler and the caller registering it.
+//
+// Skips the frames in the srpc package and the generated register functions.
+func describeRegistrant(handler Handler) string {
+	desc := fmt.Sprintf("%T", handler)
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, srpcPkgPrefix) && !strings.HasSuffix(frame.File, "_srpc.pb.go") {
+			if frame.File != "" {
+				desc += fmt.Sprintf(" (registered at %s:%d)", frame.File, frame.Line)
+			}
+			return desc
+		}
+		if !more {
+			return desc
+		}
+	}
+}
+
+// srpcPkgPrefix is the function name prefix of the srpc package.
+const srpcPkgPrefix = "github.com/aperturerobotics/starpc/srpc."
+
 // _ is a type assertion
 var _ Mux = ((*mux)(nil))
@@ -0,0 +1,148 @@
+package srpc_test
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// optionsHandler sets the deprecated methods and cache durations of a Handler.
+type optionsHandler struct {
+	srpc.Handler
+	// deprecated contains the deprecated method ids.
+	deprecated []string
+	// ttls contains the cache durations by method id.
+	ttls map[string]time.Duration
+}
+
+// GetDeprecatedMethodIDs returns the list of deprecated methods for the service.
+func (h *optionsHandler) GetDeprecatedMethodIDs() []string {
+	return h.deprecated
+}
+
+// GetMethodCacheTTLs returns the cache duration for each cached method.
+func (h *optionsHandler) GetMethodCacheTTLs() map[string]time.Duration {
+	return h.ttls
+}
+
+// getMethodInfo returns the info of the registered method.
+func getMethodInfo(t *testing.T, mux srpc.Mux, serviceID, methodID string) srpc.MethodInfo {
+	for _, svc := range mux.Services() {
+		if svc.ServiceID != serviceID {
+			continue
+		}
+		for _, method := range svc.Methods {
+			if method.MethodID == methodID {
+				return method
+			}
+		}
+	}
+	t.Fatalf("method not registered: %s/%s", serviceID, methodID)
+	return srpc.MethodInfo{}
+}
+
+func TestMux_RegisterOverride(t *testing.T) {
+	mux := srpc.NewMux(srpc.WithAllowOverride())
+	echoHandler := &echo.SRPCEchoerHandler{}
+	if err := mux.Register(&optionsHandler{
+		Handler:    echoHandler,
+		deprecated: []string{"Echo", "EchoServerStream"},
+		ttls:       map[string]time.Duration{"Echo": time.Minute},
+	}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if info := getMethodInfo(t, mux, echo.SRPCEchoerServiceID, "Echo"); !info.Deprecated || info.CacheTTL != time.Minute {
+		t.Fatalf("unexpected method info: %+v", info)
+	}
+
+	// replacing the handler clears the options of the replaced handler
+	if err := mux.Register(echoHandler); err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, methodID := range []string{"Echo", "EchoServerStream"} {
+		info := getMethodInfo(t, mux, echo.SRPCEchoerServiceID, methodID)
+		if info.Deprecated || info.CacheTTL != 0 {
+			t.Fatalf("expected the options of %s to be cleared: %+v", methodID, info)
+		}
+	}
+}
+
+func TestMuxServices(t *testing.T) {
+	mux := srpc.NewMux()
+	cmux := &cachedMux{Mux: mux, ttls: map[string]time.Duration{"Echo": time.Minute}}
+	if err := echo.SRPCRegisterEchoer(cmux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := mux.Register(batchHandler{}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	services := mux.Services()
+	if len(services) != 2 {
+		t.Fatalf("expected 2 services got %d", len(services))
+	}
+	if services[0].ServiceID != echo.SRPCEchoerServiceID || services[1].ServiceID != "test.Batch" {
+		t.Fatalf("unexpected services: %v", services)
+	}
+	if methods := services[1].Methods; len(methods) != 1 || methods[0].MethodID != "Put" || methods[0].HandlerType != "srpc_test.batchHandler" {
+		t.Fatalf("unexpected methods: %v", methods)
+	}
+	var methodIDs []string
+	for _, method := range services[0].Methods {
+		methodIDs = append(methodIDs, method.MethodID)
+		expectedTTL := time.Duration(0)
+		if method.MethodID == "Echo" {
+			expectedTTL = time.Minute
+		}
+		if method.CacheTTL != expectedTTL {
+			t.Fatalf("expected %s cache ttl %v got %v", method.MethodID, expectedTTL, method.CacheTTL)
+		}
+	}
+	if strings.Join(methodIDs, ",") != "Echo,EchoBidiStream,EchoClientStream,EchoServerStream,RpcStream" {
+		t.Fatalf("unexpected method ids: %v", methodIDs)
+	}
+}
+
+func TestMuxRegisterConflict(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	err := echo.SRPCRegisterEchoer(mux, &countingEchoServer{EchoServer: echo.NewEchoServer(mux)})
+	var conflictErr *srpc.RegisterConflictError
+	if !errors.As(err, &conflictErr) || !errors.Is(err, srpc.ErrRegisterConflict) {
+		t.Fatalf("expected register conflict error got %v", err)
+	}
+	if conflictErr.ServiceID != echo.SRPCEchoerServiceID || conflictErr.MethodID != "Echo" {
+		t.Fatalf("unexpected conflict: %v", err)
+	}
+	// the diagnostic lists where both handlers were registered
+	for _, desc := range []string{conflictErr.Existing, conflictErr.Conflicting} {
+		if !strings.Contains(desc, "*echo.SRPCEchoerHandler") || !strings.Contains(desc, "mux_test.go:") {
+			t.Fatalf("unexpected registrant: %q", desc)
+		}
+	}
+
+	// WithAllowOverride replaces the handler
+	mux = srpc.NewMux(srpc.WithAllowOverride())
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	echoServer := &countingEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	if _, err := client.Echo(context.Background(), &echo.EchoMsg{Body: "hello world"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if calls := atomic.LoadInt32(&echoServer.calls); calls != 1 {
+		t.Fatalf("expected 1 call got %d", calls)
+	}
+}
@@ -0,0 +1,160 @@
+//go:build !starpc_core
+
+package srpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+)
+
+// MuxedConnTrace contains hooks called at the phases of the streams of a
+// MuxedConn, see NewTracedMuxedConn.
+//
+// Any of the hooks may be nil. Hooks may be called from any goroutine.
+type MuxedConnTrace struct {
+	// StreamOpened is called after opening an outbound stream with the time
+	// spent opening the stream and any error.
+	StreamOpened func(latency time.Duration, err error)
+	// StreamAccepted is called after accepting an inbound stream with the
+	// number of active streams including the accepted stream, or any error.
+	StreamAccepted func(active int, err error)
+	// StreamReset is called when a stream is reset.
+	// remote is set if the remote reset the stream.
+	StreamReset func(remote bool)
+	// StreamClosed is called once when a stream is closed or reset with the
+	// time since the stream was opened or accepted.
+	StreamClosed func(lifetime time.Duration)
+}
+
+// TracedMuxedConn is a MuxedConn calling the MuxedConnTrace hooks.
+type TracedMuxedConn struct {
+	network.MuxedConn
+	// trace contains the hooks
+	trace *MuxedConnTrace
+	// active is the number of active streams
+	active int32
+}
+
+// NewTracedMuxedConn wraps a MuxedConn to call the trace hooks.
+//
+// Makes the stream lifecycle observable, for example to record stream open
+// latency, active streams and resets with a metrics library.
+func NewTracedMuxedConn(conn network.MuxedConn, trace *MuxedConnTrace) *TracedMuxedConn {
+	return &TracedMuxedConn{MuxedConn: conn, trace: trace}
+}
+
+// ActiveStreams returns the number of streams which are not closed or reset.
+func (c *TracedMuxedConn) ActiveStreams() int {
+	return int(atomic.LoadInt32(&c.active))
+}
+
+// OpenStream opens a new outbound stream.
+func (c *TracedMuxedConn) OpenStream(ctx context.Context) (network.MuxedStream, error) {
+	start := time.Now()
+	strm, err := c.MuxedConn.OpenStream(ctx)
+	if c.trace.StreamOpened != nil {
+		c.trace.StreamOpened(time.Since(start), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c.newTracedStream(strm), nil
+}
+
+// AcceptStream accepts an inbound stream.
+func (c *TracedMuxedConn) AcceptStream() (network.MuxedStream, error) {
+	strm, err := c.MuxedConn.AcceptStream()
+	if err != nil {
+		if c.trace.StreamAccepted != nil {
+			c.trace.StreamAccepted(c.ActiveStreams(), err)
+		}
+		return nil, err
+	}
+	tstrm := c.newTracedStream(strm)
+	if c.trace.StreamAccepted != nil {
+		c.trace.StreamAccepted(c.ActiveStreams(), nil)
+	}
+	return tstrm, nil
+}
+
+// newTracedStream wraps the stream adding it to the active streams.
+func (c *TracedMuxedConn) newTracedStream(strm network.MuxedStream) *tracedMuxedStream {
+	atomic.AddInt32(&c.active, 1)
+	return &tracedMuxedStream{MuxedStream: strm, conn: c, start: time.Now()}
+}
+
+// tracedMuxedStream is a MuxedStream calling the MuxedConnTrace hooks.
+type tracedMuxedStream struct {
+	network.MuxedStream
+	// conn is the traced conn
+	conn *TracedMuxedConn
+	// start is when the stream was opened or accepted
+	start time.Time
+	// resetOnce guards calling the StreamReset hook
+	resetOnce sync.Once
+	// closeOnce guards calling the StreamClosed hook
+	closeOnce sync.Once
+}
+
+// Read reads from the stream.
+func (s *tracedMuxedStream) Read(p []byte) (int, error) {
+	n, err := s.MuxedStream.Read(p)
+	if err != nil && errors.Is(err, network.ErrReset) {
+		s.reset(true)
+	}
+	return n, err
+}
+
+// Write writes to the stream.
+func (s *tracedMuxedStream) Write(p []byte) (int, error) {
+	n, err := s.MuxedStream.Write(p)
+	if err != nil && errors.Is(err, network.ErrReset) {
+		s.reset(true)
+	}
+	return n, err
+}
+
+// Close closes the stream.
+func (s *tracedMuxedStream) Close() error {
+	err := s.MuxedStream.Close()
+	s.closed()
+	return err
+}
+
+// Reset resets the stream.
+func (s *tracedMuxedStream) Reset() error {
+	err := s.MuxedStream.Reset()
+	s.reset(false)
+	return err
+}
+
+// reset calls the StreamReset hook once and marks the stream closed.
+func (s *tracedMuxedStream) reset(remote bool) {
+	s.resetOnce.Do(func() {
+		if s.conn.trace.StreamReset != nil {
+			s.conn.trace.StreamReset(remote)
+		}
+	})
+	s.closed()
+}
+
+// closed calls the StreamClosed hook once.
+func (s *tracedMuxedStream) closed() {
+	s.closeOnce.Do(func() {
+		atomic.AddInt32(&s.conn.active, -1)
+		if s.conn.trace.StreamClosed != nil {
+			s.conn.trace.StreamClosed(time.Since(s.start))
+		}
+	})
+}
+
+// _ is a type assertion
+var (
+	_ network.MuxedConn   = ((*TracedMuxedConn)(nil))
+	_ network.MuxedStream = ((*tracedMuxedStream)(nil))
+)
@@ -1,3 +1,5 @@
+//go:build !starpc_core
+
 package srpc
 
 import (
@@ -27,7 +29,8 @@ func NewClientWithConn(conn net.Conn, outbound bool) (Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewClientWithMuxedConn(mconn), nil
+	openStreamFn := NewOpenStreamWithMuxedConn(mconn)
+	return NewClientWithConnInfo(openStreamFn, NewConnInfo(conn, MuxerMplex)), nil
 }
 
 // NewClientWithMuxedConn constructs a new client with a MuxedConn.
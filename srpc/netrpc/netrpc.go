@@ -0,0 +1,228 @@
+package netrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// Codec encodes the args and replies of the calls.
+type Codec interface {
+	// Marshal encodes the value.
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal decodes the data into the value.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec encodes values with encoding/json.
+type JSONCodec struct{}
+
+// Marshal encodes the value.
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the data into the value.
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// GobCodec encodes values with encoding/gob.
+//
+// The type information is sent with every value.
+type GobCodec struct{}
+
+// Marshal encodes the value.
+func (GobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the data into the value.
+func (GobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// typeOfError is the reflect type of error.
+var typeOfError = reflect.TypeOf((*error)(nil)).Elem()
+
+// method is a method of the receiver.
+type method struct {
+	// fn is the method value bound to the receiver
+	fn reflect.Value
+	// argType is the type of the args
+	argType reflect.Type
+	// replyType is the type of the reply, a pointer
+	replyType reflect.Type
+}
+
+// Handler exposes the methods of a net/rpc style receiver as a srpc.Handler.
+//
+// Methods must have the net/rpc signature:
+//
+//	func (t *T) MethodName(args T1, reply *T2) error
+//
+// where T1 and T2 are exported or builtin types. Other methods are ignored.
+type Handler struct {
+	// serviceID is the service ID
+	serviceID string
+	// codec encodes the args and replies
+	codec Codec
+	// methods contains the methods by name
+	methods map[string]*method
+	// methodIDs contains the sorted method names
+	methodIDs []string
+}
+
+// NewHandler constructs a Handler for the receiver.
+//
+// If serviceID is empty, uses the type name of the receiver like net/rpc.
+// Returns an error if the receiver has no suitable methods.
+func NewHandler(serviceID string, rcvr interface{}, codec Codec) (*Handler, error) {
+	rval := reflect.ValueOf(rcvr)
+	rtype := rval.Type()
+	if serviceID == "" {
+		serviceID = reflect.Indirect(rval).Type().Name()
+	}
+	if serviceID == "" {
+		return nil, errors.Errorf("netrpc: no service name for type %s", rtype.String())
+	}
+
+	h := &Handler{
+		serviceID: serviceID,
+		codec:     codec,
+		methods:   make(map[string]*method),
+	}
+	for i := 0; i < rtype.NumMethod(); i++ {
+		m := rtype.Method(i)
+		if !m.IsExported() {
+			continue
+		}
+		mtype := m.Type
+		// receiver, args, reply
+		if mtype.NumIn() != 3 || mtype.NumOut() != 1 || mtype.Out(0) != typeOfError {
+			continue
+		}
+		argType, replyType := mtype.In(1), mtype.In(2)
+		if replyType.Kind() != reflect.Pointer || !isExportedOrBuiltinType(argType) || !isExportedOrBuiltinType(replyType) {
+			continue
+		}
+		h.methods[m.Name] = &method{
+			fn:        rval.Method(i),
+			argType:   argType,
+			replyType: replyType,
+		}
+		h.methodIDs = append(h.methodIDs, m.Name)
+	}
+	if len(h.methods) == 0 {
+		return nil, errors.Errorf("netrpc: type %s has no exported methods of suitable type", rtype.String())
+	}
+	sort.Strings(h.methodIDs)
+	return h, nil
+}
+
+// isExportedOrBuiltinType checks if the type is exported or a builtin.
+func isExportedOrBuiltinType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return token.IsExported(t.Name()) || t.PkgPath() == ""
+}
+
+// GetServiceID returns the ID of the service.
+func (h *Handler) GetServiceID() string {
+	return h.serviceID
+}
+
+// GetMethodIDs returns the list of methods for the service.
+func (h *Handler) GetMethodIDs() []string {
+	return h.methodIDs
+}
+
+// InvokeMethod invokes the method matching the service & method ID.
+func (h *Handler) InvokeMethod(serviceID, methodID string, strm srpc.Stream) (bool, error) {
+	if serviceID != "" && serviceID != h.serviceID {
+		return false, nil
+	}
+	m := h.methods[methodID]
+	if m == nil {
+		return false, nil
+	}
+
+	req := srpc.NewRawMessage(nil)
+	if err := strm.MsgRecv(req); err != nil {
+		return true, err
+	}
+	argv := reflect.New(m.argType)
+	if err := h.codec.Unmarshal(req.GetData(), argv.Interface()); err != nil {
+		return true, err
+	}
+	replyv := reflect.New(m.replyType.Elem())
+	out := m.fn.Call([]reflect.Value{argv.Elem(), replyv})
+	if errv := out[0].Interface(); errv != nil {
+		return true, errv.(error)
+	}
+	data, err := h.codec.Marshal(replyv.Interface())
+	if err != nil {
+		return true, err
+	}
+	return true, strm.MsgSend(srpc.NewRawMessage(data))
+}
+
+// Register registers the handler with the mux.
+func (h *Handler) Register(mux srpc.Mux) error {
+	return mux.Register(h)
+}
+
+// Client calls net/rpc style services with a srpc.Client.
+type Client struct {
+	// client is the srpc client
+	client srpc.Client
+	// codec encodes the args and replies
+	codec Codec
+}
+
+// NewClient constructs a new Client.
+//
+// The codec must match the codec of the Handler.
+func NewClient(client srpc.Client, codec Codec) *Client {
+	return &Client{client: client, codec: codec}
+}
+
+// Call calls the named method and waits for it to complete.
+//
+// serviceMethod has the net/rpc format "Service.Method".
+// reply must be a pointer.
+func (c *Client) Call(ctx context.Context, serviceMethod string, args, reply interface{}) error {
+	dot := strings.LastIndex(serviceMethod, ".")
+	if dot < 0 {
+		return errors.Errorf("netrpc: service/method request ill-formed: %s", serviceMethod)
+	}
+	data, err := c.codec.Marshal(args)
+	if err != nil {
+		return err
+	}
+	out := srpc.NewRawMessage(nil)
+	if err := c.client.Invoke(ctx, serviceMethod[:dot], serviceMethod[dot+1:], srpc.NewRawMessage(data), out); err != nil {
+		return err
+	}
+	return c.codec.Unmarshal(out.GetData(), reply)
+}
+
+// _ is a type assertion
+var (
+	_ srpc.Handler = ((*Handler)(nil))
+	_ Codec        = JSONCodec{}
+	_ Codec        = GobCodec{}
+)
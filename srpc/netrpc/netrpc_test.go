@@ -0,0 +1,62 @@
+package netrpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/netrpc"
+	"github.com/pkg/errors"
+)
+
+// NetRPCArgs are the args of the net/rpc style test service.
+type NetRPCArgs struct {
+	A, B int
+}
+
+// NetRPCArith is a net/rpc style test service.
+type NetRPCArith struct{}
+
+func TestNetRPC(t *testing.T) {
+	ctx := context.Background()
+	for _, codec := range []netrpc.Codec{netrpc.JSONCodec{}, netrpc.GobCodec{}} {
+		mux := srpc.NewMux()
+		handler, err := netrpc.NewHandler("", &NetRPCArith{}, codec)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if err := handler.Register(mux); err != nil {
+			t.Fatal(err.Error())
+		}
+		if ids := handler.GetMethodIDs(); len(ids) != 2 || ids[0] != "Divide" || ids[1] != "Multiply" {
+			t.Fatalf("unexpected methods: %v", ids)
+		}
+		client := netrpc.NewClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))), codec)
+
+		var reply int
+		if err := client.Call(ctx, "NetRPCArith.Multiply", &NetRPCArgs{A: 7, B: 8}, &reply); err != nil {
+			t.Fatal(err.Error())
+		}
+		if reply != 56 {
+			t.Fatalf("expected 56 got %d", reply)
+		}
+		if err := client.Call(ctx, "NetRPCArith.Divide", &NetRPCArgs{A: 7}, &reply); err == nil || err.Error() != "divide by zero" {
+			t.Fatalf("expected divide by zero error got %v", err)
+		}
+	}
+}
+
+// Multiply multiplies the args.
+func (t *NetRPCArith) Multiply(args *NetRPCArgs, reply *int) error {
+	*reply = args.A * args.B
+	return nil
+}
+
+// Divide divides the args.
+func (t *NetRPCArith) Divide(args NetRPCArgs, reply *int) error {
+	if args.B == 0 {
+		return errors.New("divide by zero")
+	}
+	*reply = args.A / args.B
+	return nil
+}
@@ -0,0 +1,66 @@
+package operations
+
+import (
+	"context"
+	"io"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/longrunning"
+	"github.com/pkg/errors"
+)
+
+// GetResultTo decodes the result of a done operation into out.
+//
+// Returns the operation error if the operation failed.
+func (o *Operation) GetResultTo(out srpc.Message) error {
+	if !o.GetDone() {
+		return errors.New("operation is not done")
+	}
+	if errStr := o.GetError(); errStr != "" {
+		return errors.New(errStr)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := out.UnmarshalVT(o.GetResult()); err != nil {
+		return errors.Wrap(srpc.ErrInvalidMessage, err.Error())
+	}
+	return nil
+}
+
+// Wait watches the operation until it is done and decodes the result into out.
+//
+// onProgress is called with each progress update and may be nil.
+// out may be nil to skip decoding the result.
+// Returns the operation error if the operation failed.
+func Wait(
+	ctx context.Context,
+	client SRPCOperationsClient,
+	id string,
+	out srpc.Message,
+	onProgress func(p *longrunning.Progress),
+) error {
+	strm, err := client.WatchOperation(ctx, &GetOperationRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	defer strm.Close()
+
+	var lastProgress *longrunning.Progress
+	for {
+		op, err := strm.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return longrunning.ErrNoResult
+			}
+			return err
+		}
+		if progress := op.GetProgress(); progress != nil && onProgress != nil && !progress.EqualVT(lastProgress) {
+			onProgress(progress)
+			lastProgress = progress
+		}
+		if op.GetDone() {
+			return op.GetResultTo(out)
+		}
+	}
+}
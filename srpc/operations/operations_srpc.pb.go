@@ -0,0 +1,265 @@
+// Code generated by protoc-gen-srpc. DO NOT EDIT.
+// protoc-gen-srpc version: v0.0.0-20220611014014-aa9dc5523865
+// source: github.com/aperturerobotics/starpc/srpc/operations/operations.proto
+
+package operations
+
+import (
+	context "context"
+	time "time"
+
+	srpc "github.com/aperturerobotics/starpc/srpc"
+)
+
+type SRPCOperationsClient interface {
+	SRPCClient() srpc.Client
+
+	GetOperation(ctx context.Context, in *GetOperationRequest, opts ...srpc.CallOption) (*Operation, error)
+	WatchOperation(ctx context.Context, in *GetOperationRequest, opts ...srpc.CallOption) (SRPCOperations_WatchOperationClient, error)
+	CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...srpc.CallOption) (*CancelOperationResponse, error)
+}
+
+type srpcOperationsClient struct {
+	cc srpc.Client
+}
+
+func NewSRPCOperationsClient(cc srpc.Client) SRPCOperationsClient {
+	return &srpcOperationsClient{cc}
+}
+
+func (c *srpcOperationsClient) SRPCClient() srpc.Client { return c.cc }
+
+func (c *srpcOperationsClient) GetOperation(ctx context.Context, in *GetOperationRequest, opts ...srpc.CallOption) (*Operation, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, SRPCOperationsServiceID, SRPCOperationsGetOperationMethodID, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *srpcOperationsClient) WatchOperation(ctx context.Context, in *GetOperationRequest, opts ...srpc.CallOption) (SRPCOperations_WatchOperationClient, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	stream, err := c.cc.NewStream(ctx, SRPCOperationsServiceID, SRPCOperationsWatchOperationMethodID, in)
+	if err != nil {
+		return nil, err
+	}
+	strm := &srpcOperations_WatchOperationClient{stream}
+	if err := strm.CloseSend(); err != nil {
+		return nil, err
+	}
+	return strm, nil
+}
+
+type SRPCOperations_WatchOperationClient interface {
+	srpc.Stream
+	srpc.StreamRecvIter[*Operation]
+	Recv() (*Operation, error)
+	RecvTo(*Operation) error
+	RecvTimeout(time.Duration) (*Operation, error)
+	TryRecv() (*Operation, bool, error)
+	RecvBatch(max int) ([]*Operation, error)
+}
+
+type srpcOperations_WatchOperationClient struct {
+	srpc.Stream
+}
+
+func (x *srpcOperations_WatchOperationClient) Recv() (*Operation, error) {
+	m := new(Operation)
+	if err := x.MsgRecv(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcOperations_WatchOperationClient) RecvTo(m *Operation) error {
+	return x.MsgRecv(m)
+}
+
+func (x *srpcOperations_WatchOperationClient) RecvTimeout(d time.Duration) (*Operation, error) {
+	m := new(Operation)
+	if err := srpc.MsgRecvTimeout(x.Stream, m, d); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *srpcOperations_WatchOperationClient) TryRecv() (*Operation, bool, error) {
+	m := new(Operation)
+	ok, err := srpc.MsgTryRecv(x.Stream, m)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return m, true, nil
+}
+
+func (x *srpcOperations_WatchOperationClient) RecvBatch(max int) ([]*Operation, error) {
+	return srpc.RecvBatch(x.Stream, max, func() *Operation { return new(Operation) })
+}
+
+func (c *srpcOperationsClient) CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...srpc.CallOption) (*CancelOperationResponse, error) {
+	ctx = srpc.WithCallOptions(ctx, opts...)
+	out := new(CancelOperationResponse)
+	err := c.cc.Invoke(ctx, SRPCOperationsServiceID, SRPCOperationsCancelOperationMethodID, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type SRPCOperationsServer interface {
+	GetOperation(context.Context, *GetOperationRequest) (*Operation, error)
+	WatchOperation(*GetOperationRequest, SRPCOperations_WatchOperationStream) error
+	CancelOperation(context.Context, *CancelOperationRequest) (*CancelOperationResponse, error)
+}
+
+type SRPCOperationsUnimplementedServer struct{}
+
+func (s *SRPCOperationsUnimplementedServer) GetOperation(context.Context, *GetOperationRequest) (*Operation, error) {
+	return nil, srpc.ErrUnimplemented
+}
+
+func (s *SRPCOperationsUnimplementedServer) WatchOperation(*GetOperationRequest, SRPCOperations_WatchOperationStream) error {
+	return srpc.ErrUnimplemented
+}
+
+func (s *SRPCOperationsUnimplementedServer) CancelOperation(context.Context, *CancelOperationRequest) (*CancelOperationResponse, error) {
+	return nil, srpc.ErrUnimplemented
+}
+
+const SRPCOperationsServiceID = "operations.Operations"
+
+const (
+	SRPCOperationsGetOperationMethodID    = "GetOperation"
+	SRPCOperationsWatchOperationMethodID  = "WatchOperation"
+	SRPCOperationsCancelOperationMethodID = "CancelOperation"
+)
+
+// SRPCOperationsService contains the references to the methods of the service.
+var SRPCOperationsService = struct {
+	GetOperationMethod    srpc.MethodRef
+	WatchOperationMethod  srpc.MethodRef
+	CancelOperationMethod srpc.MethodRef
+}{
+	GetOperationMethod:    srpc.MethodRef{ServiceID: SRPCOperationsServiceID, MethodID: SRPCOperationsGetOperationMethodID},
+	WatchOperationMethod:  srpc.MethodRef{ServiceID: SRPCOperationsServiceID, MethodID: SRPCOperationsWatchOperationMethodID},
+	CancelOperationMethod: srpc.MethodRef{ServiceID: SRPCOperationsServiceID, MethodID: SRPCOperationsCancelOperationMethodID},
+}
+
+type SRPCOperationsHandler struct {
+	impl SRPCOperationsServer
+}
+
+func (SRPCOperationsHandler) GetServiceID() string { return SRPCOperationsServiceID }
+
+func (SRPCOperationsHandler) GetMethodIDs() []string {
+	return []string{
+		"GetOperation",
+		"WatchOperation",
+		"CancelOperation",
+	}
+}
+
+func (d *SRPCOperationsHandler) InvokeMethod(
+	serviceID, methodID string,
+	strm srpc.Stream,
+) (bool, error) {
+	if serviceID != "" && serviceID != d.GetServiceID() {
+		return false, nil
+	}
+
+	switch methodID {
+	case "GetOperation":
+		return true, d.InvokeMethod_GetOperation(d.impl, strm)
+	case "WatchOperation":
+		return true, d.InvokeMethod_WatchOperation(d.impl, strm)
+	case "CancelOperation":
+		return true, d.InvokeMethod_CancelOperation(d.impl, strm)
+	default:
+		return false, nil
+	}
+}
+
+func (SRPCOperationsHandler) InvokeMethod_GetOperation(impl SRPCOperationsServer, strm srpc.Stream) error {
+	req := new(GetOperationRequest)
+	if err := strm.MsgRecv(req); err != nil {
+		return err
+	}
+	out, err := impl.GetOperation(strm.Context(), req)
+	if err != nil {
+		return err
+	}
+	return strm.MsgSend(out)
+}
+
+func (SRPCOperationsHandler) InvokeMethod_WatchOperation(impl SRPCOperationsServer, strm srpc.Stream) error {
+	req := new(GetOperationRequest)
+	if err := strm.MsgRecv(req); err != nil {
+		return err
+	}
+	serverStrm := &srpcOperations_WatchOperationStream{strm}
+	return impl.WatchOperation(req, serverStrm)
+}
+
+func (SRPCOperationsHandler) InvokeMethod_CancelOperation(impl SRPCOperationsServer, strm srpc.Stream) error {
+	req := new(CancelOperationRequest)
+	if err := strm.MsgRecv(req); err != nil {
+		return err
+	}
+	out, err := impl.CancelOperation(strm.Context(), req)
+	if err != nil {
+		return err
+	}
+	return strm.MsgSend(out)
+}
+
+func SRPCRegisterOperations(mux srpc.Mux, impl SRPCOperationsServer) error {
+	return mux.Register(&SRPCOperationsHandler{impl: impl})
+}
+
+type SRPCOperations_GetOperationStream interface {
+	srpc.Stream
+	SendAndClose(*Operation) error
+}
+
+type srpcOperations_GetOperationStream struct {
+	srpc.Stream
+}
+
+func (x *srpcOperations_GetOperationStream) SendAndClose(m *Operation) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type SRPCOperations_WatchOperationStream interface {
+	srpc.Stream
+	Send(*Operation) error
+}
+
+type srpcOperations_WatchOperationStream struct {
+	srpc.Stream
+}
+
+func (x *srpcOperations_WatchOperationStream) Send(m *Operation) error {
+	return x.MsgSend(m)
+}
+
+type SRPCOperations_CancelOperationStream interface {
+	srpc.Stream
+	SendAndClose(*CancelOperationResponse) error
+}
+
+type srpcOperations_CancelOperationStream struct {
+	srpc.Stream
+}
+
+func (x *srpcOperations_CancelOperationStream) SendAndClose(m *CancelOperationResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
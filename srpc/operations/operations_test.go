@@ -0,0 +1,93 @@
+package operations_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/longrunning"
+	"github.com/aperturerobotics/starpc/srpc/operations"
+)
+
+func TestOperations(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	mux := srpc.NewMux()
+	opsServer := operations.NewServer(ctx, operations.NewMemStore())
+	if err := opsServer.Register(mux); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := operations.NewSRPCOperationsClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	// run an operation to completion
+	releaseCh := make(chan struct{})
+	op, err := opsServer.Start(func(ctx context.Context, report func(percent float32, note string) error) (srpc.Message, error) {
+		if err := report(50, "halfway"); err != nil {
+			return nil, err
+		}
+		<-releaseCh
+		return &echo.EchoMsg{Body: "hello world"}, nil
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var progressCount int
+	progressCh := make(chan struct{})
+	waitErrCh := make(chan error, 1)
+	result := &echo.EchoMsg{}
+	go func() {
+		waitErrCh <- operations.Wait(ctx, client, op.GetId(), result, func(p *longrunning.Progress) {
+			progressCount++
+			if progressCount == 1 {
+				close(progressCh)
+			}
+		})
+	}()
+	select {
+	case <-progressCh:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for progress")
+	}
+	polled, err := client.GetOperation(ctx, &operations.GetOperationRequest{Id: op.GetId()})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if polled.GetDone() || polled.GetProgress().GetPercent() != 50 {
+		t.Fatalf("unexpected polled operation state: %v", polled.String())
+	}
+	close(releaseCh)
+	if err := <-waitErrCh; err != nil {
+		t.Fatal(err.Error())
+	}
+	if result.GetBody() != "hello world" {
+		t.Fatalf("unexpected result: %q", result.GetBody())
+	}
+
+	// cancel an operation
+	op, err = opsServer.Start(func(ctx context.Context, report func(percent float32, note string) error) (srpc.Message, error) {
+		<-ctx.Done()
+		return nil, context.Canceled
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	resp, err := client.CancelOperation(ctx, &operations.CancelOperationRequest{Id: op.GetId()})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !resp.GetCanceled() {
+		t.Fatal("expected the operation to be canceled")
+	}
+	err = operations.Wait(ctx, client, op.GetId(), nil, nil)
+	if err == nil || err.Error() != context.Canceled.Error() {
+		t.Fatalf("expected %v got %v", context.Canceled, err)
+	}
+
+	// unknown operation
+	if _, err := client.GetOperation(ctx, &operations.GetOperationRequest{Id: "unknown"}); err == nil || err.Error() != operations.ErrNotFound.Error() {
+		t.Fatalf("expected %v got %v", operations.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,196 @@
+package operations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/aperturerobotics/starpc/internal/broadcast"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/longrunning"
+	"github.com/pkg/errors"
+)
+
+// ErrNotFound is returned if the operation was not found.
+var ErrNotFound = errors.New("operation not found")
+
+// OperationFunc runs an operation reporting progress.
+//
+// ctx is canceled when the operation is canceled.
+type OperationFunc func(ctx context.Context, report func(percent float32, note string) error) (srpc.Message, error)
+
+// Server runs operations and implements SRPCOperationsServer.
+type Server struct {
+	// ctx is the server lifetime context
+	ctx context.Context
+	// store stores the operation state
+	store Store
+	// bcast guards running and is broadcast when an operation changes
+	bcast broadcast.Broadcast
+	// running contains the cancel funcs of running operations
+	// guarded by bcast
+	running map[string]context.CancelFunc
+}
+
+// NewServer constructs a new operations Server.
+//
+// Operations are canceled when ctx is canceled.
+func NewServer(ctx context.Context, store Store) *Server {
+	return &Server{
+		ctx:     ctx,
+		store:   store,
+		running: make(map[string]context.CancelFunc),
+	}
+}
+
+// Register registers the Operations service with the Mux.
+func (s *Server) Register(mux srpc.Mux) error {
+	return SRPCRegisterOperations(mux, s)
+}
+
+// Start starts running fn as an operation in a separate goroutine.
+//
+// The operation outlives the call which started it and is bound by the
+// lifetime of the Server instead. Returns the initial operation handle which
+// can be returned to the client.
+func (s *Server) Start(fn OperationFunc) (*Operation, error) {
+	id, err := newOperationID()
+	if err != nil {
+		return nil, err
+	}
+	op := &Operation{Id: id}
+	opCtx, opCtxCancel := context.WithCancel(s.ctx)
+	s.bcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		err = s.store.PutOperation(s.ctx, op)
+		if err == nil {
+			s.running[id] = opCtxCancel
+			broadcast()
+		}
+	})
+	if err != nil {
+		opCtxCancel()
+		return nil, err
+	}
+	go s.runOperation(opCtx, opCtxCancel, id, fn)
+	return &Operation{Id: id}, nil
+}
+
+// runOperation runs the operation and stores the result.
+func (s *Server) runOperation(ctx context.Context, ctxCancel context.CancelFunc, id string, fn OperationFunc) {
+	defer ctxCancel()
+	report := func(percent float32, note string) error {
+		if percent < 0 || percent > 100 {
+			return errors.Errorf("progress percent out of range: %v", percent)
+		}
+		return s.putOperation(&Operation{
+			Id:       id,
+			Progress: &longrunning.Progress{Percent: percent, Note: note},
+		}, false)
+	}
+	result, err := fn(ctx, report)
+	final := &Operation{Id: id, Done: true}
+	if err == nil && result != nil {
+		final.Result, err = result.MarshalVT()
+	}
+	if err != nil {
+		final.Result = nil
+		final.Error = err.Error()
+	}
+	if prev, _ := s.store.GetOperation(s.ctx, id); prev != nil {
+		final.Progress = prev.GetProgress()
+	}
+	_ = s.putOperation(final, true)
+}
+
+// putOperation stores the operation and wakes any watchers.
+// if done is set, removes the operation from the running set.
+func (s *Server) putOperation(op *Operation, done bool) error {
+	var err error
+	s.bcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		err = s.store.PutOperation(s.ctx, op)
+		if done {
+			delete(s.running, op.GetId())
+		}
+		broadcast()
+	})
+	return err
+}
+
+// GetOperation returns the current state of an operation.
+func (s *Server) GetOperation(ctx context.Context, req *GetOperationRequest) (*Operation, error) {
+	op, err := s.store.GetOperation(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if op == nil {
+		return nil, ErrNotFound
+	}
+	return op, nil
+}
+
+// WatchOperation streams the state of an operation until it is done.
+func (s *Server) WatchOperation(req *GetOperationRequest, strm SRPCOperations_WatchOperationStream) error {
+	ctx := strm.Context()
+	var last *Operation
+	for {
+		var op *Operation
+		var err error
+		var waitCh <-chan struct{}
+		s.bcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+			op, err = s.store.GetOperation(ctx, req.GetId())
+			waitCh = getWaitCh()
+		})
+		if err != nil {
+			return err
+		}
+		if op == nil {
+			return ErrNotFound
+		}
+		if last == nil || !op.EqualVT(last) {
+			if err := strm.Send(op); err != nil {
+				return err
+			}
+			last = op
+		}
+		if op.GetDone() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		case <-waitCh:
+		}
+	}
+}
+
+// CancelOperation cancels a running operation.
+func (s *Server) CancelOperation(ctx context.Context, req *CancelOperationRequest) (*CancelOperationResponse, error) {
+	var cancel context.CancelFunc
+	s.bcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		cancel = s.running[req.GetId()]
+	})
+	if cancel == nil {
+		op, err := s.store.GetOperation(ctx, req.GetId())
+		if err != nil {
+			return nil, err
+		}
+		if op == nil {
+			return nil, ErrNotFound
+		}
+		return &CancelOperationResponse{}, nil
+	}
+	cancel()
+	return &CancelOperationResponse{Canceled: true}, nil
+}
+
+// newOperationID generates a new random operation id.
+func newOperationID() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
+
+// _ is a type assertion
+var _ SRPCOperationsServer = ((*Server)(nil))
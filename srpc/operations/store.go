@@ -0,0 +1,53 @@
+package operations
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Store stores the state of operations.
+//
+// Implementations must be concurrency safe.
+type Store interface {
+	// GetOperation returns the operation with the id or nil if not found.
+	GetOperation(ctx context.Context, id string) (*Operation, error)
+	// PutOperation stores the operation overwriting any existing state.
+	PutOperation(ctx context.Context, op *Operation) error
+}
+
+// MemStore is an in-memory Store.
+type MemStore struct {
+	// mtx guards ops
+	mtx sync.Mutex
+	// ops contains the operations by id
+	ops map[string]*Operation
+}
+
+// NewMemStore constructs a new in-memory Store.
+func NewMemStore() *MemStore {
+	return &MemStore{ops: make(map[string]*Operation)}
+}
+
+// GetOperation returns the operation with the id or nil if not found.
+func (s *MemStore) GetOperation(ctx context.Context, id string) (*Operation, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	op := s.ops[id]
+	if op == nil {
+		return nil, nil
+	}
+	return proto.Clone(op).(*Operation), nil
+}
+
+// PutOperation stores the operation overwriting any existing state.
+func (s *MemStore) PutOperation(ctx context.Context, op *Operation) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.ops[op.GetId()] = proto.Clone(op).(*Operation)
+	return nil
+}
+
+// _ is a type assertion
+var _ Store = ((*MemStore)(nil))
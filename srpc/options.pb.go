@@ -0,0 +1,117 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1-devel
+// 	protoc        v3.19.3
+// source: github.com/aperturerobotics/starpc/srpc/options.proto
+
+package srpc
+
+import (
+	reflect "reflect"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var file_github_com_aperturerobotics_starpc_srpc_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         52720,
+		Name:          "srpc.raw_request",
+		Tag:           "varint,52720,opt,name=raw_request",
+		Filename:      "github.com/aperturerobotics/starpc/srpc/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*uint32)(nil),
+		Field:         52721,
+		Name:          "srpc.cache_ttl_ms",
+		Tag:           "varint,52721,opt,name=cache_ttl_ms",
+		Filename:      "github.com/aperturerobotics/starpc/srpc/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// raw_request delivers the request payloads to the handler as RawMessage.
+	//
+	// The handler receives the payload bytes without decoding them.
+	// The client still sends the request type.
+	//
+	// optional bool raw_request = 52720;
+	E_RawRequest = &file_github_com_aperturerobotics_starpc_srpc_options_proto_extTypes[0]
+	// cache_ttl_ms caches the responses of an idempotent unary method.
+	//
+	// Responses are cached by the request bytes for the duration in milliseconds.
+	// Enabled on the server with the WithResultCache mux option.
+	//
+	// optional uint32 cache_ttl_ms = 52721;
+	E_CacheTtlMs = &file_github_com_aperturerobotics_starpc_srpc_options_proto_extTypes[1]
+)
+
+var File_github_com_aperturerobotics_starpc_srpc_options_proto protoreflect.FileDescriptor
+
+var file_github_com_aperturerobotics_starpc_srpc_options_proto_rawDesc = []byte{
+	0x0a, 0x35, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x70, 0x65,
+	0x72, 0x74, 0x75, 0x72, 0x65, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x69, 0x63, 0x73, 0x2f, 0x73, 0x74,
+	0x61, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x72, 0x70, 0x63, 0x2f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x73, 0x72, 0x70, 0x63, 0x1a, 0x20, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x3a,
+	0x41, 0x0a, 0x0b, 0x72, 0x61, 0x77, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xf0,
+	0x9b, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x72, 0x61, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x3a, 0x42, 0x0a, 0x0c, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x74, 0x74, 0x6c, 0x5f,
+	0x6d, 0x73, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0xf1, 0x9b, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x63, 0x61, 0x63, 0x68,
+	0x65, 0x54, 0x74, 0x6c, 0x4d, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var file_github_com_aperturerobotics_starpc_srpc_options_proto_goTypes = []interface{}{
+	(*descriptorpb.MethodOptions)(nil), // 0: google.protobuf.MethodOptions
+}
+var file_github_com_aperturerobotics_starpc_srpc_options_proto_depIdxs = []int32{
+	0, // 0: srpc.raw_request:extendee -> google.protobuf.MethodOptions
+	0, // 1: srpc.cache_ttl_ms:extendee -> google.protobuf.MethodOptions
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	0, // [0:2] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_github_com_aperturerobotics_starpc_srpc_options_proto_init() }
+func file_github_com_aperturerobotics_starpc_srpc_options_proto_init() {
+	if File_github_com_aperturerobotics_starpc_srpc_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_aperturerobotics_starpc_srpc_options_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_aperturerobotics_starpc_srpc_options_proto_goTypes,
+		DependencyIndexes: file_github_com_aperturerobotics_starpc_srpc_options_proto_depIdxs,
+		ExtensionInfos:    file_github_com_aperturerobotics_starpc_srpc_options_proto_extTypes,
+	}.Build()
+	File_github_com_aperturerobotics_starpc_srpc_options_proto = out.File
+	file_github_com_aperturerobotics_starpc_srpc_options_proto_rawDesc = nil
+	file_github_com_aperturerobotics_starpc_srpc_options_proto_goTypes = nil
+	file_github_com_aperturerobotics_starpc_srpc_options_proto_depIdxs = nil
+}
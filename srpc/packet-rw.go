@@ -1,50 +1,63 @@
 package srpc
 
 import (
-	"bytes"
-	"context"
-	"encoding/binary"
 	"io"
 
+	"github.com/aperturerobotics/starpc/srpc/frame"
 	"github.com/pkg/errors"
 )
 
 // maxMessageSize is the max message size in bytes
-var maxMessageSize = 1e7
+var maxMessageSize = frame.DefaultMaxSize
 
 // PacketReaderWriter reads and writes packets from a io.ReadWriter.
-// Uses a LittleEndian uint32 length prefix.
+// Uses a LittleEndian uint32 length prefix, see the frame package.
 type PacketReaderWriter struct {
 	// rw is the io.ReadWriterCloser
 	rw io.ReadWriteCloser
-	// buf is the buffered data
-	buf bytes.Buffer
+	// fr reads frames from rw
+	fr *frame.Reader
+	// fw writes frames to rw
+	fw *frame.Writer
 }
 
 // NewPacketReadWriter constructs a new read/writer.
 func NewPacketReadWriter(rw io.ReadWriteCloser) *PacketReaderWriter {
-	return &PacketReaderWriter{rw: rw}
+	return &PacketReaderWriter{
+		rw: rw,
+		fr: frame.NewReader(rw, frame.FormatUint32LE, maxMessageSize),
+		fw: frame.NewWriter(rw, frame.FormatUint32LE),
+	}
 }
 
 // WritePacket writes a packet to the writer.
 func (r *PacketReaderWriter) WritePacket(p *Packet) error {
 	msgSize := p.SizeVT()
-	data := make([]byte, 4+msgSize)
-	binary.LittleEndian.PutUint32(data, uint32(msgSize))
-	_, err := p.MarshalToVT(data[4:])
-	if err != nil {
+	data := frame.FormatUint32LE.AppendPrefix(make([]byte, 0, 4+msgSize), msgSize)
+	data = data[:4+msgSize]
+	if _, err := p.MarshalToVT(data[4:]); err != nil {
 		return err
 	}
-	var n int
-	written := 0
-	for written < len(data) {
-		n, err = r.rw.Write(data)
-		if err != nil {
+	return r.fw.WriteRaw(data)
+}
+
+// WritePackets writes the packets to the writer with a single write.
+func (r *PacketReaderWriter) WritePackets(pkts []*Packet) error {
+	var size int
+	for _, p := range pkts {
+		size += 4 + p.SizeVT()
+	}
+	data := make([]byte, 0, size)
+	for _, p := range pkts {
+		msgSize := p.SizeVT()
+		data = frame.FormatUint32LE.AppendPrefix(data, msgSize)
+		start := len(data)
+		data = data[:start+msgSize]
+		if _, err := p.MarshalToVT(data[start:]); err != nil {
 			return err
 		}
-		written += n
 	}
-	return err
+	return r.fw.WriteRaw(data)
 }
 
 // ReadPump executes the read pump in a goroutine.
@@ -61,59 +74,16 @@ func (r *PacketReaderWriter) ReadPump(cb PacketHandler, closed CloseHandler) {
 // ReadToHandler reads data to the given handler.
 // Does not handle closing the stream, use ReadPump instead.
 func (r *PacketReaderWriter) ReadToHandler(cb PacketHandler) error {
-	var currLen uint32
-	buf := make([]byte, 2048)
-	isOpen := true
-	for isOpen {
-		// read some data into the buffer
-		n, err := r.rw.Read(buf)
-		if err != nil {
-			if err == io.EOF || err == context.Canceled {
-				isOpen = false
-			} else {
-				return err
-			}
+	return r.fr.ReadToHandler(func(data []byte) error {
+		if len(data) == 0 {
+			return errors.New("unexpected zero len prefix")
 		}
-
-		// push the data to r.buf
-		_, err = r.buf.Write(buf[:n])
-		if err != nil {
+		npkt := &Packet{}
+		if err := npkt.UnmarshalVT(data); err != nil {
 			return err
 		}
-
-		// check if we have enough data for a length prefix
-		bufLen := r.buf.Len()
-		if bufLen < 4 {
-			continue
-		}
-
-		// parse the length prefix if not done already
-		if currLen == 0 {
-			currLen = r.readLengthPrefix(r.buf.Bytes())
-			if currLen == 0 {
-				return errors.New("unexpected zero len prefix")
-			}
-			if currLen > uint32(maxMessageSize) {
-				return errors.Errorf("message size %v greater than maximum %v", currLen, maxMessageSize)
-			}
-		}
-
-		// emit the packet if fully buffered
-		if currLen != 0 && bufLen >= int(currLen)+4 {
-			pkt := r.buf.Next(int(currLen + 4))[4:]
-			currLen = 0
-			npkt := &Packet{}
-			if err := npkt.UnmarshalVT(pkt); err != nil {
-				return err
-			}
-			if err := cb(npkt); err != nil {
-				return err
-			}
-		}
-	}
-
-	// closed
-	return nil
+		return cb(npkt)
+	})
 }
 
 // Close closes the packet rw.
@@ -121,13 +91,18 @@ func (r *PacketReaderWriter) Close() error {
 	return r.rw.Close()
 }
 
-// readLengthPrefix reads the length prefix.
-func (r *PacketReaderWriter) readLengthPrefix(b []byte) uint32 {
-	if len(b) < 4 {
-		return 0
+// Reset resets the underlying stream if supported, otherwise closes it.
+//
+// Muxed streams are reset: the remote sees a transport error.
+func (r *PacketReaderWriter) Reset() error {
+	if rs, ok := r.rw.(interface{ Reset() error }); ok {
+		return rs.Reset()
 	}
-	return binary.LittleEndian.Uint32(b)
+	return r.rw.Close()
 }
 
 // _ is a type assertion
-var _ Writer = (*PacketReaderWriter)(nil)
+var (
+	_ BatchWriter = (*PacketReaderWriter)(nil)
+	_ ResetWriter = (*PacketReaderWriter)(nil)
+)
@@ -1,5 +1,7 @@
 package srpc
 
+import "time"
+
 // PacketHandler handles a packet.
 //
 // pkt is optional (can be nil)
@@ -16,6 +18,20 @@ func (p *Packet) Validate() error {
 		return b.CallStart.Validate()
 	case *Packet_CallData:
 		return b.CallData.Validate()
+	case *Packet_CallCancel:
+		if !b.CallCancel {
+			return ErrEmptyPacket
+		}
+		return nil
+	case *Packet_Drain:
+		if b.Drain == nil {
+			return ErrEmptyPacket
+		}
+		return nil
+	case *Packet_Ping, *Packet_Pong:
+		return nil
+	case *Packet_Signal:
+		return b.Signal.Validate()
 	default:
 		return ErrUnrecognizedPacket
 	}
@@ -62,9 +78,84 @@ func NewCallDataPacket(data []byte, dataIsZero bool, complete bool, err error) *
 	}}
 }
 
+// NewCallProgressPacket constructs a new CallData packet with progress metadata.
+func NewCallProgressPacket(progress map[string]string) *Packet {
+	return &Packet{Body: &Packet_CallData{
+		CallData: &CallData{
+			Progress: progress,
+		},
+	}}
+}
+
+// NewCallErrorDetailsPacket constructs a new CallData packet with error details.
+func NewCallErrorDetailsPacket(details []string) *Packet {
+	return &Packet{Body: &Packet_CallData{
+		CallData: &CallData{
+			ErrorDetails: details,
+		},
+	}}
+}
+
+// NewCallCancelPacket constructs a new CallCancel packet.
+func NewCallCancelPacket() *Packet {
+	return &Packet{Body: &Packet_CallCancel{CallCancel: true}}
+}
+
+// NewPingPacket constructs a new Ping packet.
+func NewPingPacket(id uint64) *Packet {
+	return &Packet{Body: &Packet_Ping{Ping: id}}
+}
+
+// NewPongPacket constructs a new Pong packet responding to the Ping with the id.
+func NewPongPacket(id uint64) *Packet {
+	return &Packet{Body: &Packet_Pong{Pong: id}}
+}
+
+// NewSignalPacket constructs a new Signal packet.
+func NewSignalPacket(kind string, data []byte) *Packet {
+	return &Packet{Body: &Packet_Signal{
+		Signal: &Signal{
+			Kind: kind,
+			Data: data,
+		},
+	}}
+}
+
+// Validate performs cursory validation of the packet.
+func (p *Signal) Validate() error {
+	if len(p.GetKind()) == 0 {
+		return ErrEmptyPacket
+	}
+	return nil
+}
+
+// NewDrainPacket constructs a new Drain packet.
+//
+// deadline is the time until open calls are canceled, zero if none.
+func NewDrainPacket(deadline time.Duration, callRejected bool) *Packet {
+	var deadlineMs uint64
+	if deadline > 0 {
+		deadlineMs = uint64(deadline.Milliseconds())
+		if deadlineMs == 0 {
+			deadlineMs = 1
+		}
+	}
+	return &Packet{Body: &Packet_Drain{
+		Drain: &Drain{
+			DeadlineMs:   deadlineMs,
+			CallRejected: callRejected,
+		},
+	}}
+}
+
+// GetDeadline returns the time until open calls are canceled, zero if none.
+func (d *Drain) GetDeadline() time.Duration {
+	return time.Duration(d.GetDeadlineMs()) * time.Millisecond
+}
+
 // Validate performs cursory validation of the packet.
 func (p *CallData) Validate() error {
-	if len(p.GetData()) == 0 && !p.GetComplete() && len(p.GetError()) == 0 && !p.GetDataIsZero() {
+	if len(p.GetData()) == 0 && !p.GetComplete() && len(p.GetError()) == 0 && !p.GetDataIsZero() && len(p.GetProgress()) == 0 && len(p.GetErrorDetails()) == 0 {
 		return ErrEmptyPacket
 	}
 	return nil
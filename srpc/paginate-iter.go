@@ -0,0 +1,22 @@
+//go:build go1.23
+
+package srpc
+
+import "iter"
+
+// All returns an iterator over the remaining items.
+//
+// Yields the error which stopped the iteration as the last value, if any.
+func (p *Paginator[Item]) All() iter.Seq2[Item, error] {
+	return func(yield func(Item, error) bool) {
+		for p.Next() {
+			if !yield(p.Item(), nil) {
+				return
+			}
+		}
+		if err := p.Err(); err != nil {
+			var empty Item
+			yield(empty, err)
+		}
+	}
+}
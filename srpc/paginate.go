@@ -0,0 +1,90 @@
+package srpc
+
+import "context"
+
+// PageResponse is a response message of a paged list method.
+//
+// Follows the page_token and next_page_token field conventions: the response
+// contains a next_page_token which is empty on the last page.
+type PageResponse interface {
+	// GetNextPageToken returns the token for the next page.
+	GetNextPageToken() string
+}
+
+// PageFetcher fetches the page with the token.
+//
+// The page token is empty for the first page.
+// Returns the items and the token for the next page, empty on the last page.
+type PageFetcher[Item any] func(ctx context.Context, pageToken string) ([]Item, string, error)
+
+// Paginator iterates over the items of a paged list method.
+//
+// Fetches the next page when the items of the current page are exhausted.
+type Paginator[Item any] struct {
+	// ctx is the context for fetching pages
+	ctx context.Context
+	// fetch fetches a page
+	fetch PageFetcher[Item]
+	// items contains the remaining items of the current page
+	items []Item
+	// item is the current item
+	item Item
+	// nextPageToken is the token for the next page
+	nextPageToken string
+	// started is set after the first page was fetched
+	started bool
+	// err is the error which stopped the iteration
+	err error
+}
+
+// NewPaginator constructs a new Paginator with a PageFetcher.
+func NewPaginator[Item any](ctx context.Context, fetch PageFetcher[Item]) *Paginator[Item] {
+	return &Paginator[Item]{ctx: ctx, fetch: fetch}
+}
+
+// Paginate constructs a new Paginator calling a paged list method.
+//
+// setPageToken sets the page_token field on the request.
+// getItems returns the items of a response page.
+// Note: the page token is set on req before each call.
+func Paginate[Req any, Resp PageResponse, Item any](
+	ctx context.Context,
+	req Req,
+	setPageToken func(req Req, pageToken string),
+	list func(ctx context.Context, req Req) (Resp, error),
+	getItems func(resp Resp) []Item,
+) *Paginator[Item] {
+	return NewPaginator(ctx, func(ctx context.Context, pageToken string) ([]Item, string, error) {
+		setPageToken(req, pageToken)
+		resp, err := list(ctx, req)
+		if err != nil {
+			return nil, "", err
+		}
+		return getItems(resp), resp.GetNextPageToken(), nil
+	})
+}
+
+// Next advances to the next item, fetching the next page if necessary.
+//
+// Returns false if there are no more items or an error occurred.
+func (p *Paginator[Item]) Next() bool {
+	for len(p.items) == 0 {
+		if p.err != nil || (p.started && p.nextPageToken == "") {
+			return false
+		}
+		p.items, p.nextPageToken, p.err = p.fetch(p.ctx, p.nextPageToken)
+		p.started = true
+	}
+	p.item, p.items = p.items[0], p.items[1:]
+	return true
+}
+
+// Item returns the current item.
+func (p *Paginator[Item]) Item() Item {
+	return p.item
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (p *Paginator[Item]) Err() error {
+	return p.err
+}
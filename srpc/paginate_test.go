@@ -0,0 +1,104 @@
+package srpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// listRequest is a paged list request.
+type listRequest struct {
+	// pageToken is the token of the page to list.
+	pageToken string
+}
+
+// listResponse is a page of a paged list response.
+type listResponse struct {
+	// items contains the items of the page.
+	items []string
+	// nextPageToken is the token of the next page.
+	nextPageToken string
+}
+
+// GetNextPageToken returns the token for the next page.
+func (r *listResponse) GetNextPageToken() string {
+	return r.nextPageToken
+}
+
+// listPages serves the pages keyed by the page token.
+func listPages(pages map[string]*listResponse, calls *[]string) func(ctx context.Context, req *listRequest) (*listResponse, error) {
+	return func(ctx context.Context, req *listRequest) (*listResponse, error) {
+		*calls = append(*calls, req.pageToken)
+		page, ok := pages[req.pageToken]
+		if !ok {
+			return nil, errors.New("invalid page token")
+		}
+		return page, nil
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	// the second page and the last page are empty
+	pages := map[string]*listResponse{
+		"":   {items: []string{"a", "b"}, nextPageToken: "p2"},
+		"p2": {nextPageToken: "p3"},
+		"p3": {items: []string{"c"}, nextPageToken: "p4"},
+		"p4": {},
+	}
+	var calls []string
+	pgr := srpc.Paginate(
+		context.Background(),
+		&listRequest{},
+		func(req *listRequest, pageToken string) { req.pageToken = pageToken },
+		listPages(pages, &calls),
+		func(resp *listResponse) []string { return resp.items },
+	)
+
+	var items []string
+	for pgr.Next() {
+		items = append(items, pgr.Item())
+	}
+	if err := pgr.Err(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(items) != 3 || items[0] != "a" || items[1] != "b" || items[2] != "c" {
+		t.Fatalf("unexpected items: %v", items)
+	}
+	if len(calls) != 4 || calls[1] != "p2" || calls[2] != "p3" || calls[3] != "p4" {
+		t.Fatalf("unexpected page requests: %q", calls)
+	}
+	if pgr.Next() {
+		t.Fatal("expected no items after the last page")
+	}
+	if len(calls) != 4 {
+		t.Fatalf("expected no page requests after the last page: %q", calls)
+	}
+}
+
+func TestPaginate_Error(t *testing.T) {
+	pages := map[string]*listResponse{
+		"": {items: []string{"a"}, nextPageToken: "invalid"},
+	}
+	var calls []string
+	pgr := srpc.Paginate(
+		context.Background(),
+		&listRequest{},
+		func(req *listRequest, pageToken string) { req.pageToken = pageToken },
+		listPages(pages, &calls),
+		func(resp *listResponse) []string { return resp.items },
+	)
+	if !pgr.Next() || pgr.Item() != "a" {
+		t.Fatal("expected the first item")
+	}
+	if pgr.Next() {
+		t.Fatal("expected the iteration to stop at the error")
+	}
+	if err := pgr.Err(); err == nil || err.Error() != "invalid page token" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pgr.Next() || len(calls) != 2 {
+		t.Fatalf("expected no page requests after the error: %q", calls)
+	}
+}
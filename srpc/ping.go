@@ -0,0 +1,47 @@
+package srpc
+
+import (
+	"context"
+	"time"
+)
+
+// PingServiceID is the service ID of the built-in ping service.
+const PingServiceID = "starpc.ping"
+
+// PingMethodID is the method ID of the ping method.
+const PingMethodID = "Ping"
+
+// WithoutPing disables the built-in ping service.
+//
+// The ping service is registered on every Server by default.
+func WithoutPing() ServerOption {
+	return func(s *Server) {
+		s.noPing = true
+	}
+}
+
+// pingClient calls the built-in ping service with the client.
+//
+// Returns the round trip time of the call.
+func pingClient(ctx context.Context, client Client) (time.Duration, error) {
+	start := time.Now()
+	err := client.Invoke(ctx, PingServiceID, PingMethodID, NewRawMessage(nil), NewRawMessage(nil))
+	return time.Since(start), err
+}
+
+// servePing handles a call to the built-in ping service.
+func servePing(serviceID, methodID string, strm Stream) (bool, error) {
+	if methodID != PingMethodID {
+		return false, nil
+	}
+	return true, handlePing(strm)
+}
+
+// handlePing handles a call to the ping method by echoing the message.
+func handlePing(strm Stream) error {
+	msg := NewRawMessage(nil)
+	if err := strm.MsgRecv(msg); err != nil {
+		return err
+	}
+	return strm.MsgSend(msg)
+}
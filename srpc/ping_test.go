@@ -0,0 +1,58 @@
+package srpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestPing(t *testing.T) {
+	ctx := context.Background()
+
+	// expect the ping service to be handled by every server, even if not ready
+	server := srpc.NewServer(srpc.NewMux(), srpc.WithReadinessGate(0))
+	rtt, err := srpc.NewClient(srpc.NewServerPipe(server)).Ping(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if rtt <= 0 {
+		t.Fatalf("expected round trip time got %v", rtt)
+	}
+}
+
+func TestPing_Interceptors(t *testing.T) {
+	ctx := context.Background()
+	errDenied := errors.New("denied")
+	var calls []string
+	server := srpc.NewServer(srpc.NewMux(), srpc.WithInterceptors(
+		func(ctx context.Context, serviceID, methodID string, strm srpc.Stream, next srpc.Invoker) (bool, error) {
+			calls = append(calls, serviceID+"/"+methodID)
+			if srpc.MetadataFromIncomingContext(ctx).Get("auth") == "" {
+				return true, errDenied
+			}
+			return next(serviceID, methodID, strm)
+		},
+	))
+	client := srpc.NewClient(srpc.NewServerPipe(server))
+
+	// expect the interceptors to handle ping calls
+	if _, err := client.Ping(ctx); err == nil || err.Error() != errDenied.Error() {
+		t.Fatalf("expected %v got %v", errDenied, err)
+	}
+	if _, err := client.Ping(srpc.AppendToOutgoingContext(ctx, "auth", "token")); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(calls) != 2 || calls[0] != srpc.PingServiceID+"/"+srpc.PingMethodID {
+		t.Fatalf("unexpected intercepted calls: %v", calls)
+	}
+}
+
+func TestPing_Disabled(t *testing.T) {
+	// expect the ping service to be disabled by the option
+	server := srpc.NewServer(srpc.NewMux(), srpc.WithoutPing())
+	if _, err := srpc.NewClient(srpc.NewServerPipe(server)).Ping(context.Background()); err == nil || err.Error() != srpc.ErrUnimplemented.Error() {
+		t.Fatalf("expected %v got %v", srpc.ErrUnimplemented, err)
+	}
+}
@@ -0,0 +1,50 @@
+package srpc
+
+import "google.golang.org/protobuf/proto"
+
+// ProtoMessage adapts a proto.Message without the vtprotobuf methods to Message.
+//
+// The generated code wraps the well-known types such as google.protobuf.Empty
+// and google.protobuf.Timestamp which do not implement Message.
+type ProtoMessage[T proto.Message] struct {
+	// Msg is the wrapped message.
+	Msg T
+}
+
+// NewProtoMessage wraps the message.
+func NewProtoMessage[T proto.Message](msg T) *ProtoMessage[T] {
+	return &ProtoMessage[T]{Msg: msg}
+}
+
+// MarshalVT marshals the message with the proto package.
+func (m *ProtoMessage[T]) MarshalVT() ([]byte, error) {
+	return proto.Marshal(m.Msg)
+}
+
+// UnmarshalVT unmarshals the message with the proto package.
+func (m *ProtoMessage[T]) UnmarshalVT(data []byte) error {
+	return proto.Unmarshal(data, m.Msg)
+}
+
+// SizeVT returns the encoded size of the message.
+func (m *ProtoMessage[T]) SizeVT() int {
+	return proto.Size(m.Msg)
+}
+
+// RecvProtoBatch is RecvBatch for messages which do not implement Message.
+func RecvProtoBatch[T proto.Message](strm Stream, max int, newMsg func() T) ([]T, error) {
+	wrapped, err := RecvBatch(strm, max, func() *ProtoMessage[T] {
+		return NewProtoMessage(newMsg())
+	})
+	var batch []T
+	if len(wrapped) != 0 {
+		batch = make([]T, len(wrapped))
+		for i, msg := range wrapped {
+			batch[i] = msg.Msg
+		}
+	}
+	return batch, err
+}
+
+// _ is a type assertion
+var _ Message = ((*ProtoMessage[proto.Message])(nil))
@@ -0,0 +1,42 @@
+package srpc
+
+import "context"
+
+// ProtocolError is a packet from the remote which violated the protocol and
+// failed the call, passed to HandleProtocolError.
+//
+// For example a data packet received after the call completed, which usually
+// indicates a protocol race or a misbehaving peer.
+type ProtocolError struct {
+	// Client is set for calls made by a Client.
+	Client bool
+	// ServiceID is the service of the call, empty if no call was started.
+	ServiceID string
+	// MethodID is the method of the call, empty if no call was started.
+	MethodID string
+	// Err is the error returned handling the packet, for example ErrCompleted.
+	Err error
+}
+
+// ProtocolErrorHandler is a StatsHandler which receives the protocol errors.
+//
+// The packet handler returns the error, which closes the stream: use this to
+// count the errors in metrics instead of losing them in the individual calls.
+type ProtocolErrorHandler interface {
+	// HandleProtocolError is called when a packet from the remote failed the call.
+	HandleProtocolError(ctx context.Context, ev *ProtocolError)
+}
+
+// isProtocolError checks if an error returned by a packet handler was caused
+// by the remote rather than canceling the call locally.
+func isProtocolError(err error) bool {
+	return err != nil && err != context.Canceled
+}
+
+// handleProtocolError passes the protocol error to the StatsHandler, if it
+// implements ProtocolErrorHandler.
+func handleProtocolError(ctx context.Context, h StatsHandler, ev *ProtocolError) {
+	if peh, ok := h.(ProtocolErrorHandler); ok {
+		peh.HandleProtocolError(ctx, ev)
+	}
+}
@@ -0,0 +1,106 @@
+package srpc_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// waitingEchoServer blocks EchoBidiStream until the call is canceled.
+type waitingEchoServer struct {
+	*echo.EchoServer
+}
+
+// EchoBidiStream waits for the call to be canceled.
+func (s *waitingEchoServer) EchoBidiStream(strm echo.SRPCEchoer_EchoBidiStreamStream) error {
+	<-strm.Context().Done()
+	return context.Canceled
+}
+
+// protocolErrorStatsHandler records the protocol errors.
+type protocolErrorStatsHandler struct {
+	recordingStatsHandler
+	errs chan *srpc.ProtocolError
+}
+
+func (h *protocolErrorStatsHandler) HandleProtocolError(ctx context.Context, ev *srpc.ProtocolError) {
+	h.errs <- ev
+}
+
+func TestProtocolError_Server(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &waitingEchoServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	handler := &protocolErrorStatsHandler{errs: make(chan *srpc.ProtocolError, 1)}
+	channelz := srpc.NewChannelz()
+	server := srpc.NewServer(mux, srpc.WithStatsHandler(handler), srpc.WithChannelz(channelz))
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = server.HandleStream(ctx, serverConn)
+	}()
+	go func() {
+		_, _ = io.Copy(io.Discard, clientConn)
+	}()
+
+	// send a message after completing the call
+	prw := srpc.NewPacketReadWriter(clientConn)
+	for _, pkt := range []*srpc.Packet{
+		srpc.NewCallStartPacket(echo.SRPCEchoerServiceID, "EchoBidiStream", nil, false),
+		srpc.NewCallDataPacket(nil, false, true, nil),
+		srpc.NewCallDataPacket([]byte("late"), false, false, nil),
+	} {
+		if err := prw.WritePacket(pkt); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	select {
+	case ev := <-handler.errs:
+		if ev.Client || ev.ServiceID != echo.SRPCEchoerServiceID || ev.MethodID != "EchoBidiStream" || ev.Err != srpc.ErrCompleted {
+			t.Fatalf("unexpected protocol error %v", ev)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected protocol error")
+	}
+	if n := channelz.GetProtocolErrors(); n != 1 {
+		t.Fatalf("expected 1 protocol error got %d", n)
+	}
+	ctxCancel()
+	wg.Wait()
+}
+
+func TestProtocolError_Client(t *testing.T) {
+	handler := &protocolErrorStatsHandler{errs: make(chan *srpc.ProtocolError, 1)}
+	ctx := srpc.WithClientStatsHandler(context.Background(), handler)
+	rpc := srpc.NewClientRPC(ctx, echo.SRPCEchoerServiceID, "Echo")
+	defer rpc.Close()
+
+	if err := rpc.HandlePacket(srpc.NewCallDataPacket([]byte("hello"), false, true, nil)); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := rpc.HandlePacket(srpc.NewCallDataPacket([]byte("late"), false, false, nil)); err != srpc.ErrCompleted {
+		t.Fatalf("expected %v got %v", srpc.ErrCompleted, err)
+	}
+	select {
+	case ev := <-handler.errs:
+		if !ev.Client || ev.MethodID != "Echo" || ev.Err != srpc.ErrCompleted {
+			t.Fatalf("unexpected protocol error %v", ev)
+		}
+	default:
+		t.Fatal("expected protocol error")
+	}
+}
@@ -0,0 +1,42 @@
+package srpc
+
+// RawMessage is a Message containing the raw payload bytes.
+//
+// Marshal and unmarshal pass the bytes through without decoding.
+// Used by handlers of methods with the raw_request option.
+type RawMessage struct {
+	// Data is the raw payload.
+	Data []byte
+}
+
+// NewRawMessage constructs a new RawMessage with the payload.
+func NewRawMessage(data []byte) *RawMessage {
+	return &RawMessage{Data: data}
+}
+
+// GetData returns the raw payload.
+func (m *RawMessage) GetData() []byte {
+	if m == nil {
+		return nil
+	}
+	return m.Data
+}
+
+// MarshalVT returns the raw payload.
+func (m *RawMessage) MarshalVT() ([]byte, error) {
+	return m.GetData(), nil
+}
+
+// UnmarshalVT copies the raw payload into the message.
+func (m *RawMessage) UnmarshalVT(data []byte) error {
+	m.Data = append(m.Data[:0], data...)
+	return nil
+}
+
+// SizeVT returns the size of the raw payload.
+func (m *RawMessage) SizeVT() int {
+	return len(m.GetData())
+}
+
+// _ is a type assertion
+var _ Message = ((*RawMessage)(nil))
@@ -0,0 +1,83 @@
+package srpc_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// rawEchoHandler implements echo.Echoer/Echo with the raw_request option.
+//
+// Mirrors the generated handler of a method with the raw_request option.
+type rawEchoHandler struct {
+	// reqCh receives the raw request payloads.
+	reqCh chan []byte
+}
+
+// GetServiceID returns the ID of the service.
+func (h *rawEchoHandler) GetServiceID() string { return echo.SRPCEchoerServiceID }
+
+// GetMethodIDs returns the list of methods for the service.
+func (h *rawEchoHandler) GetMethodIDs() []string { return []string{"Echo"} }
+
+// InvokeMethod receives the request as a RawMessage and echoes the payload.
+func (h *rawEchoHandler) InvokeMethod(serviceID, methodID string, strm srpc.Stream) (bool, error) {
+	if methodID != "Echo" {
+		return false, nil
+	}
+	req := new(srpc.RawMessage)
+	if err := strm.MsgRecv(req); err != nil {
+		return true, err
+	}
+	h.reqCh <- req.GetData()
+	if err := strm.MsgSend(req); err != nil {
+		return true, err
+	}
+	return true, strm.CloseSend()
+}
+
+func TestRawMessage_Request(t *testing.T) {
+	mux := srpc.NewMux()
+	handler := &rawEchoHandler{reqCh: make(chan []byte, 1)}
+	if err := mux.Register(handler); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	req := &echo.EchoMsg{Body: "hello world"}
+	expected, err := req.MarshalVT()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	out, err := client.Echo(context.Background(), req)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	// the handler receives the encoded request without decoding it
+	if data := <-handler.reqCh; !bytes.Equal(data, expected) {
+		t.Fatalf("expected raw request %x got %x", expected, data)
+	}
+	if out.GetBody() != req.GetBody() {
+		t.Fatalf("unexpected response: %q", out.GetBody())
+	}
+}
+
+func TestRawMessage_RoundTrip(t *testing.T) {
+	msg := &srpc.RawMessage{}
+	if err := msg.UnmarshalVT([]byte("hello")); err != nil {
+		t.Fatal(err.Error())
+	}
+	data, err := msg.MarshalVT()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(data) != "hello" || msg.SizeVT() != 5 {
+		t.Fatalf("unexpected payload: %q", data)
+	}
+	if (*srpc.RawMessage)(nil).GetData() != nil {
+		t.Fatal("expected nil payload from nil message")
+	}
+}
@@ -0,0 +1,204 @@
+package srpc
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultDNSRefreshInterval is the default interval between DNS re-resolutions.
+const DefaultDNSRefreshInterval = time.Second * 30
+
+// ErrUnknownResolver is returned if no resolver is registered for the scheme of a target.
+var ErrUnknownResolver = errors.New("unknown resolver scheme")
+
+// Address is a resolved address of a target.
+type Address struct {
+	// Addr is the network address, e.g. host:port.
+	Addr string
+}
+
+// Resolver resolves the endpoint of a target to addresses.
+//
+// Must be concurrency safe.
+type Resolver interface {
+	// Resolve resolves the endpoint to the current addresses.
+	Resolve(ctx context.Context, endpoint string) ([]Address, error)
+	// Watch calls cb with the current addresses and each time they change.
+	//
+	// Blocks until ctx is canceled. Resolution errors are not reported: the
+	// last addresses remain valid until the next successful resolution.
+	Watch(ctx context.Context, endpoint string, cb func(addrs []Address)) error
+}
+
+// resolvers contains the registered resolvers by scheme.
+var resolvers = struct {
+	sync.RWMutex
+	m map[string]Resolver
+}{m: map[string]Resolver{
+	"dns":         &DNSResolver{},
+	"passthrough": PassthroughResolver{},
+}}
+
+// RegisterResolver registers the resolver for targets with the scheme.
+//
+// Replaces any resolver with the same scheme. The dns and passthrough
+// resolvers are registered by default.
+func RegisterResolver(scheme string, r Resolver) {
+	resolvers.Lock()
+	resolvers.m[scheme] = r
+	resolvers.Unlock()
+}
+
+// GetResolver returns the registered resolver for the scheme or nil.
+func GetResolver(scheme string) Resolver {
+	resolvers.RLock()
+	defer resolvers.RUnlock()
+	return resolvers.m[scheme]
+}
+
+// ParseTarget splits a target into the scheme and the endpoint.
+//
+// Targets have the scheme://authority/endpoint format, for example
+// dns:///example.com:443. The authority is ignored. Targets without a scheme,
+// such as example.com:443, use the passthrough scheme.
+func ParseTarget(target string) (scheme, endpoint string) {
+	idx := strings.Index(target, "://")
+	if idx <= 0 {
+		return "passthrough", target
+	}
+	scheme, rest := target[:idx], target[idx+3:]
+	if slash := strings.IndexByte(rest, '/'); slash >= 0 {
+		rest = rest[slash+1:]
+	}
+	return scheme, rest
+}
+
+// ResolveTarget resolves the target with the resolver registered for its scheme.
+func ResolveTarget(ctx context.Context, target string) (Resolver, string, []Address, error) {
+	scheme, endpoint := ParseTarget(target)
+	resolver := GetResolver(scheme)
+	if resolver == nil {
+		return nil, "", nil, errors.Wrap(ErrUnknownResolver, scheme)
+	}
+	addrs, err := resolver.Resolve(ctx, endpoint)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, "", nil, errors.Errorf("no addresses for target: %s", target)
+	}
+	return resolver, endpoint, addrs, nil
+}
+
+// PassthroughResolver resolves the endpoint to itself.
+type PassthroughResolver struct{}
+
+// Resolve returns the endpoint as the only address.
+func (PassthroughResolver) Resolve(ctx context.Context, endpoint string) ([]Address, error) {
+	return []Address{{Addr: endpoint}}, nil
+}
+
+// Watch calls cb with the endpoint and blocks until ctx is canceled.
+func (PassthroughResolver) Watch(ctx context.Context, endpoint string, cb func(addrs []Address)) error {
+	cb([]Address{{Addr: endpoint}})
+	<-ctx.Done()
+	return context.Canceled
+}
+
+// DNSResolver resolves host:port endpoints with DNS.
+//
+// The host is resolved to its IP addresses, each with the port.
+type DNSResolver struct {
+	// Resolver is the resolver to use.
+	// If nil, uses net.DefaultResolver.
+	Resolver *net.Resolver
+	// RefreshInterval is the interval between re-resolutions in Watch.
+	// If zero, uses DefaultDNSRefreshInterval.
+	RefreshInterval time.Duration
+}
+
+// Resolve looks up the addresses of the host of the endpoint.
+//
+// Returns the addresses sorted for a stable order.
+func (r *DNSResolver) Resolve(ctx context.Context, endpoint string) ([]Address, error) {
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	resolver := r.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	hosts, err := resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(hosts)
+	addrs := make([]Address, len(hosts))
+	for i, h := range hosts {
+		addrs[i] = Address{Addr: net.JoinHostPort(h, port)}
+	}
+	return addrs, nil
+}
+
+// Watch re-resolves the endpoint at the refresh interval.
+func (r *DNSResolver) Watch(ctx context.Context, endpoint string, cb func(addrs []Address)) error {
+	return PollResolver(ctx, endpoint, r.RefreshInterval, r.Resolve, cb)
+}
+
+// PollResolver implements Watch by calling resolve at the interval.
+//
+// Calls cb with the first successful resolution and when the addresses differ
+// from the previous one. Empty results are ignored. If interval is zero, uses
+// DefaultDNSRefreshInterval.
+func PollResolver(
+	ctx context.Context,
+	endpoint string,
+	interval time.Duration,
+	resolve func(ctx context.Context, endpoint string) ([]Address, error),
+	cb func(addrs []Address),
+) error {
+	if interval <= 0 {
+		interval = DefaultDNSRefreshInterval
+	}
+	tkr := time.NewTicker(interval)
+	defer tkr.Stop()
+	var prev []Address
+	for {
+		addrs, err := resolve(ctx, endpoint)
+		if err == nil && len(addrs) != 0 && !addressesEqual(prev, addrs) {
+			prev = addrs
+			cb(addrs)
+		}
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		case <-tkr.C:
+		}
+	}
+}
+
+// addressesEqual checks if the address lists are equal.
+func addressesEqual(a, b []Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// _ is a type assertion
+var (
+	_ Resolver = ((*DNSResolver)(nil))
+	_ Resolver = PassthroughResolver{}
+)
@@ -0,0 +1,47 @@
+package srpc
+
+import (
+	"context"
+	"fmt"
+)
+
+// responseMetadataSetterCtxKey is the context key for the responseMetadataSetter.
+type responseMetadataSetterCtxKey struct{}
+
+// responseMetadataSetter sets the response metadata of a server call.
+type responseMetadataSetter interface {
+	// setResponseMetadata merges metadata into the response metadata.
+	setResponseMetadata(md Metadata) error
+}
+
+// withResponseMetadataSetter attaches the responseMetadataSetter to the context.
+func withResponseMetadataSetter(ctx context.Context, setter responseMetadataSetter) context.Context {
+	return context.WithValue(ctx, responseMetadataSetterCtxKey{}, setter)
+}
+
+// SetResponseMetadata sets metadata sent to the client with the result of a call.
+//
+// ctx must be the handler context of a server call. kv must contain an even
+// number of strings: key1, value1, key2, value2, ... Overwrites any values
+// set previously with the same keys. The client receives the metadata via
+// WithResponseMetadata.
+//
+// Returns ErrNoServerCall if ctx does not belong to a server call, or
+// ErrCompleted if the call already completed.
+func SetResponseMetadata(ctx context.Context, kv ...string) error {
+	if len(kv)%2 == 1 {
+		panic(fmt.Sprintf("srpc: SetResponseMetadata got an odd number of input pairs for metadata: %d", len(kv)))
+	}
+	setter, _ := ctx.Value(responseMetadataSetterCtxKey{}).(responseMetadataSetter)
+	if setter == nil {
+		return ErrNoServerCall
+	}
+	if len(kv) == 0 {
+		return nil
+	}
+	md := make(Metadata, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		md[kv[i]] = kv[i+1]
+	}
+	return setter.setResponseMetadata(md)
+}
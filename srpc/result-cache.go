@@ -0,0 +1,93 @@
+package srpc
+
+import (
+	"sync"
+	"time"
+)
+
+// ResultCache caches the responses of idempotent unary methods.
+//
+// Responses are keyed on the service, method and request bytes. Concurrent
+// calls with the same key wait for a single call to the handler instead of
+// each calling it (stampede protection). Errors are not cached or shared with
+// the waiting calls: if the call fails each waiting call invokes the handler.
+type ResultCache struct {
+	// maxEntries is the maximum number of cached responses.
+	maxEntries int
+	// group coalesces concurrent calls with the same key.
+	group callGroup
+	// mtx guards entries
+	mtx sync.Mutex
+	// entries contains the cached responses by key.
+	entries map[string]*resultCacheEntry
+}
+
+// resultCacheEntry is a cached response.
+type resultCacheEntry struct {
+	// data is the response payload.
+	data []byte
+	// expires is when the entry expires.
+	expires time.Time
+}
+
+// NewResultCache constructs a new ResultCache.
+//
+// maxEntries limits the number of cached responses, zero for no limit.
+func NewResultCache(maxEntries int) *ResultCache {
+	return &ResultCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*resultCacheEntry),
+	}
+}
+
+// Purge removes all cached responses.
+func (c *ResultCache) Purge() {
+	c.mtx.Lock()
+	c.entries = make(map[string]*resultCacheEntry)
+	c.mtx.Unlock()
+}
+
+// invokeMethod invokes a unary method with the handler using the cache.
+func (c *ResultCache) invokeMethod(handler Handler, serviceID, methodID string, ttl time.Duration, strm Stream) error {
+	req := &RawMessage{}
+	if err := strm.MsgRecv(req); err != nil {
+		return err
+	}
+	key := codecKey(strm.Context(), DefaultCoalesceKey(serviceID, methodID, req.GetData()))
+
+	lookup := func() ([]byte, bool) {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		entry := c.entries[key]
+		if entry == nil {
+			return nil, false
+		}
+		if !time.Now().Before(entry.expires) {
+			delete(c.entries, key)
+			return nil, false
+		}
+		return entry.data, true
+	}
+	return c.group.invoke(handler, serviceID, methodID, key, req.GetData(), strm, lookup, func(data []byte) {
+		c.mtx.Lock()
+		c.storeLocked(key, data, time.Now().Add(ttl))
+		c.mtx.Unlock()
+	})
+}
+
+// storeLocked stores a response evicting expired entries if full.
+// expects mtx to be locked.
+func (c *ResultCache) storeLocked(key string, data []byte, expires time.Time) {
+	if c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		now := time.Now()
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			return
+		}
+	}
+	c.entries[key] = &resultCacheEntry{data: data, expires: expires}
+}
@@ -0,0 +1,167 @@
+package srpc_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+)
+
+// countingEchoHandler implements echo.Echoer/Echo counting the calls.
+type countingEchoHandler struct {
+	// calls is the number of calls to Echo.
+	calls int32
+}
+
+// GetServiceID returns the ID of the service.
+func (h *countingEchoHandler) GetServiceID() string { return echo.SRPCEchoerServiceID }
+
+// GetMethodIDs returns the list of methods for the service.
+func (h *countingEchoHandler) GetMethodIDs() []string { return []string{"Echo"} }
+
+// GetMethodCacheTTLs returns the cache duration for each cached method.
+func (h *countingEchoHandler) GetMethodCacheTTLs() map[string]time.Duration {
+	return map[string]time.Duration{"Echo": time.Minute}
+}
+
+// InvokeMethod counts the call and echoes the request after a delay.
+func (h *countingEchoHandler) InvokeMethod(serviceID, methodID string, strm srpc.Stream) (bool, error) {
+	if methodID != "Echo" {
+		return false, nil
+	}
+	atomic.AddInt32(&h.calls, 1)
+	req := new(echo.EchoMsg)
+	if err := strm.MsgRecv(req); err != nil {
+		return true, err
+	}
+	<-time.After(time.Millisecond * 5)
+	return true, strm.MsgSend(req)
+}
+
+func TestResultCache_SingleInvocation(t *testing.T) {
+	mux := srpc.NewMux(srpc.WithResultCache(srpc.NewResultCache(0)))
+	handler := &countingEchoHandler{}
+	if err := mux.Register(handler); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	// calls arrive before, during and after the first call completes.
+	const concurrency = 50
+	var wg sync.WaitGroup
+	errCh := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Echo(context.Background(), &echo.EchoMsg{Body: "hello"})
+			errCh <- err
+		}()
+		<-time.After(time.Millisecond / 5)
+	}
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if calls := atomic.LoadInt32(&handler.calls); calls != 1 {
+		t.Fatalf("expected 1 call got %d", calls)
+	}
+}
+
+// cachedHandler sets the cache durations of a Handler.
+type cachedHandler struct {
+	srpc.Handler
+	// ttls contains the cache durations by method id.
+	ttls map[string]time.Duration
+}
+
+// cachedMux registers handlers with cache durations.
+type cachedMux struct {
+	srpc.Mux
+	// ttls contains the cache durations by method id.
+	ttls map[string]time.Duration
+}
+
+// countingEchoServer counts the calls to Echo.
+type countingEchoServer struct {
+	*echo.EchoServer
+	// calls is the number of calls to Echo.
+	calls int32
+}
+
+func TestResultCache(t *testing.T) {
+	mux := srpc.NewMux(srpc.WithResultCache(srpc.NewResultCache(0)))
+	echoServer := &countingEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	cmux := &cachedMux{Mux: mux, ttls: map[string]time.Duration{"Echo": time.Minute}}
+	if err := echo.SRPCRegisterEchoer(cmux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	ctx := context.Background()
+
+	// concurrent identical calls invoke the handler once
+	const concurrency = 10
+	errCh := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			out, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello world"})
+			if err == nil && out.GetBody() != "hello world" {
+				err = errors.Errorf("unexpected response: %q", out.GetBody())
+			}
+			errCh <- err
+		}()
+	}
+	for i := 0; i < concurrency; i++ {
+		if err := <-errCh; err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if calls := atomic.LoadInt32(&echoServer.calls); calls != 1 {
+		t.Fatalf("expected 1 call got %d", calls)
+	}
+
+	// cached response
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello world"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if calls := atomic.LoadInt32(&echoServer.calls); calls != 1 {
+		t.Fatalf("expected 1 call got %d", calls)
+	}
+
+	// different request
+	out, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello again"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if out.GetBody() != "hello again" {
+		t.Fatalf("unexpected response: %q", out.GetBody())
+	}
+	if calls := atomic.LoadInt32(&echoServer.calls); calls != 2 {
+		t.Fatalf("expected 2 calls got %d", calls)
+	}
+}
+
+// GetMethodCacheTTLs returns the cache duration for each cached method.
+func (h *cachedHandler) GetMethodCacheTTLs() map[string]time.Duration {
+	return h.ttls
+}
+
+// Register registers the handler with the cache durations.
+func (m *cachedMux) Register(handler srpc.Handler) error {
+	return m.Mux.Register(&cachedHandler{Handler: handler, ttls: m.ttls})
+}
+
+// Echo counts the call and echoes the message after a delay.
+func (s *countingEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	atomic.AddInt32(&s.calls, 1)
+	<-time.After(time.Millisecond * 50)
+	return &echo.EchoMsg{Body: msg.GetBody()}, nil
+}
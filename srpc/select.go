@@ -0,0 +1,50 @@
+package srpc
+
+import (
+	"context"
+	"sync"
+)
+
+// SelectResult is a message or error received by Select.
+type SelectResult[T any] struct {
+	// Index is the index of the stream in the list passed to Select.
+	Index int
+	// Msg is the received message, if Err is nil.
+	Msg T
+	// Err is the error returned by the stream, io.EOF if the stream ended.
+	// No more results are returned for the stream after Err is set.
+	Err error
+}
+
+// Select receives messages from the streams and returns them on a channel in
+// the order they arrive, from whichever stream produces a message first.
+//
+// Starts a goroutine for each stream which exits after the stream returns an
+// error or ctx is canceled. A stream blocked in Recv exits when it is closed.
+// The returned channel is closed after all of the goroutines exit.
+func Select[T any](ctx context.Context, streams ...StreamRecv[T]) <-chan SelectResult[T] {
+	resultCh := make(chan SelectResult[T])
+	var wg sync.WaitGroup
+	wg.Add(len(streams))
+	for i, strm := range streams {
+		go func(idx int, strm StreamRecv[T]) {
+			defer wg.Done()
+			for {
+				msg, err := strm.Recv()
+				select {
+				case <-ctx.Done():
+					return
+				case resultCh <- SelectResult[T]{Index: idx, Msg: msg, Err: err}:
+				}
+				if err != nil {
+					return
+				}
+			}
+		}(i, strm)
+	}
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+	return resultCh
+}
@@ -0,0 +1,89 @@
+package srpc
+
+import "time"
+
+// ServerConfig contains the Server options which can be changed at runtime.
+//
+// A ServerConfig must not be modified after it is passed to the Server.
+type ServerConfig struct {
+	// ReadyTimeout is how long calls wait for readiness before failing.
+	// See WithReadinessGate.
+	ReadyTimeout time.Duration
+	// CallTimeout limits the duration of each call.
+	// Zero for no limit.
+	CallTimeout time.Duration
+	// MaxConcurrentCalls limits the number of concurrent calls.
+	// Calls over the limit fail with ErrTooManyCalls.
+	// Zero for no limit.
+	MaxConcurrentCalls int
+	// MaxConcurrentStreamsPerConn limits the number of concurrent streams of
+	// each connection. Streams over the limit fail with ErrTooManyStreams.
+	// See WithConnStreamCounter. Zero for no limit.
+	MaxConcurrentStreamsPerConn int
+	// Heartbeat configures the pings sent to idle clients.
+	// See WithServerHeartbeat.
+	Heartbeat HeartbeatParams
+}
+
+// Clone returns a copy of the config.
+func (c *ServerConfig) Clone() *ServerConfig {
+	if c == nil {
+		return &ServerConfig{}
+	}
+	conf := *c
+	return &conf
+}
+
+// WithServerConfig sets the initial ServerConfig.
+//
+// Use Server.SetConfig to change the config at runtime.
+func WithServerConfig(conf *ServerConfig) ServerOption {
+	return func(s *Server) {
+		s.conf.Store(conf.Clone())
+	}
+}
+
+// GetConfig returns the current ServerConfig.
+//
+// The returned config must not be modified: use Clone and SetConfig.
+func (s *Server) GetConfig() *ServerConfig {
+	conf, _ := s.conf.Load().(*ServerConfig)
+	return conf
+}
+
+// SetConfig atomically replaces the ServerConfig.
+//
+// The new config applies to calls started after SetConfig returns.
+// Calls any callbacks registered with OnConfigChange.
+func (s *Server) SetConfig(conf *ServerConfig) {
+	conf = conf.Clone()
+	s.confMtx.Lock()
+	s.conf.Store(conf)
+	cbs := make([]func(conf *ServerConfig), 0, len(s.confCbs))
+	for _, cb := range s.confCbs {
+		cbs = append(cbs, cb)
+	}
+	s.confMtx.Unlock()
+	for _, cb := range cbs {
+		cb(conf)
+	}
+}
+
+// OnConfigChange registers a callback called when the ServerConfig changes.
+//
+// The callback must not block. Returns a func to remove the callback.
+func (s *Server) OnConfigChange(cb func(conf *ServerConfig)) func() {
+	s.confMtx.Lock()
+	defer s.confMtx.Unlock()
+	if s.confCbs == nil {
+		s.confCbs = make(map[uint64]func(conf *ServerConfig))
+	}
+	id := s.confCbID
+	s.confCbID++
+	s.confCbs[id] = cb
+	return func() {
+		s.confMtx.Lock()
+		delete(s.confCbs, id)
+		s.confMtx.Unlock()
+	}
+}
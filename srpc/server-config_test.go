@@ -0,0 +1,71 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestServerConfig(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := echo.NewEchoServer(mux)
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux)
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	ctx := context.Background()
+	msg := &echo.EchoMsg{Body: "hello world"}
+
+	confCh := make(chan *srpc.ServerConfig, 2)
+	release := server.OnConfigChange(func(conf *srpc.ServerConfig) {
+		confCh <- conf
+	})
+	defer release()
+
+	// limit concurrent calls
+	server.SetConfig(&srpc.ServerConfig{MaxConcurrentCalls: 1})
+	if conf := <-confCh; conf.MaxConcurrentCalls != 1 {
+		t.Fatalf("unexpected config: %v", conf)
+	}
+	strmCtx, strmCtxCancel := context.WithCancel(ctx)
+	defer strmCtxCancel()
+	strm, err := client.EchoServerStream(strmCtx, msg)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := client.Echo(ctx, msg); err == nil || err.Error() != srpc.ErrTooManyCalls.Error() {
+		t.Fatalf("expected %v got %v", srpc.ErrTooManyCalls, err)
+	}
+	strmCtxCancel()
+
+	// limit call duration
+	server.SetConfig(&srpc.ServerConfig{CallTimeout: time.Millisecond * 100})
+	if conf := <-confCh; conf.CallTimeout != time.Millisecond*100 {
+		t.Fatalf("unexpected config: %v", conf)
+	}
+	strm, err = client.EchoServerStream(ctx, msg)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var received int
+	for {
+		if _, err := strm.Recv(); err != nil {
+			break
+		}
+		received++
+	}
+	// the handler sends 5 messages 200ms apart
+	if received >= 5 {
+		t.Fatalf("expected the call to time out, received %d messages", received)
+	}
+	if _, err := client.Echo(ctx, msg); err != nil {
+		t.Fatal(err.Error())
+	}
+}
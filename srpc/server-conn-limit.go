@@ -0,0 +1,52 @@
+package srpc
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// connStreamsCtxKey is the context key for the connStreams.
+type connStreamsCtxKey struct{}
+
+// connStreams counts the active streams of a connection.
+type connStreams struct {
+	// active is the number of active streams
+	active int32
+}
+
+// WithConnStreamCounter marks ctx as the context of a single connection.
+//
+// The streams handled with HandleStream with the returned context count
+// towards ServerConfig.MaxConcurrentStreamsPerConn. AcceptMuxedConn calls
+// WithConnStreamCounter for each connection.
+func WithConnStreamCounter(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(connStreamsCtxKey{}).(*connStreams); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, connStreamsCtxKey{}, &connStreams{})
+}
+
+// acquireConnStream counts a stream of the connection of ctx.
+//
+// Returns false if the limit is exceeded. Call release if true is returned.
+func acquireConnStream(ctx context.Context, limit int) (release func(), ok bool) {
+	cs, _ := ctx.Value(connStreamsCtxKey{}).(*connStreams)
+	if cs == nil || limit <= 0 {
+		return func() {}, true
+	}
+	if atomic.AddInt32(&cs.active, 1) > int32(limit) {
+		atomic.AddInt32(&cs.active, -1)
+		return nil, false
+	}
+	return func() { atomic.AddInt32(&cs.active, -1) }, true
+}
+
+// rejectCall rejects the call on prw with err after reading the first packet.
+func rejectCall(prw *PacketReaderWriter, err error) error {
+	_ = prw.ReadToHandler(func(pkt *Packet) error {
+		_ = prw.WritePacket(NewCallDataPacket(nil, false, true, err))
+		return err
+	})
+	_ = prw.Close()
+	return err
+}
@@ -0,0 +1,66 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestMaxConcurrentStreamsPerConn(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &blockingEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		started:    make(chan struct{}, 1),
+		release:    make(chan struct{}),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux, srpc.WithServerConfig(&srpc.ServerConfig{MaxConcurrentStreamsPerConn: 1}))
+	// the server pipe handles the streams with the client context
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	connCtx := srpc.WithConnStreamCounter(context.Background())
+	msg := &echo.EchoMsg{Body: "hello"}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.Echo(connCtx, msg)
+		errCh <- err
+	}()
+	<-echoServer.started
+
+	// expect streams over the limit to be rejected
+	if _, err := client.Echo(connCtx, msg); err == nil || err.Error() != srpc.ErrTooManyStreams.Error() {
+		t.Fatalf("expected %v got %v", srpc.ErrTooManyStreams, err)
+	}
+
+	// expect streams of other connections to be accepted
+	strm, err := client.EchoBidiStream(srpc.WithConnStreamCounter(context.Background()))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != nil {
+		t.Fatal(err.Error())
+	}
+	_ = strm.Close()
+
+	// expect the stream to be accepted after the active stream completes
+	close(echoServer.release)
+	if err := <-errCh; err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 0; ; i++ {
+		// the server releases the stream shortly after sending the result
+		_, err := client.Echo(connCtx, msg)
+		if err == nil {
+			break
+		}
+		if i == 50 || err.Error() != srpc.ErrTooManyStreams.Error() {
+			t.Fatal(err.Error())
+		}
+		<-time.After(time.Millisecond * 10)
+	}
+}
@@ -0,0 +1,113 @@
+package srpc
+
+import (
+	"context"
+	"time"
+)
+
+// Shutdown stops accepting new calls and waits for open calls to complete.
+//
+// Sends a Drain packet to the clients of open calls. New calls are rejected
+// with a Drain packet and fail with ErrDraining on the client. The deadline of
+// ctx, if any, is sent to the clients. If ctx is canceled before the open calls
+// complete, cancels the remaining calls and returns context.Canceled.
+//
+// Afterwards closes the server: Run closes the listeners and connections and
+// returns nil.
+func (s *Server) Shutdown(ctx context.Context) error {
+	var rpcs []*ServerRPC
+	var deadline time.Time
+	s.drainBcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		if !s.draining {
+			s.draining = true
+			s.drainDeadline, _ = ctx.Deadline()
+		}
+		deadline = s.drainDeadline
+		for rpc := range s.rpcs {
+			rpcs = append(rpcs, rpc)
+		}
+	})
+
+	// notify the open calls
+	pkt := newDrainPacket(deadline, false)
+	for _, rpc := range rpcs {
+		_ = rpc.writeControlPacket(pkt)
+	}
+
+	// wait for the open calls to complete
+	err := s.drainBcast.Wait(ctx, func(broadcast func(), getWaitCh func() <-chan struct{}) (bool, error) {
+		return len(s.rpcs) == 0, nil
+	})
+	s.drainBcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		if err != nil {
+			for rpc := range s.rpcs {
+				rpc.ctxCancel()
+			}
+		}
+		s.closed = true
+		broadcast()
+	})
+	return err
+}
+
+// waitClosed waits for Shutdown to complete.
+func (s *Server) waitClosed(ctx context.Context) error {
+	return s.drainBcast.Wait(ctx, func(broadcast func(), getWaitCh func() <-chan struct{}) (bool, error) {
+		return s.closed, nil
+	})
+}
+
+// IsDraining checks if Shutdown was called on the server.
+func (s *Server) IsDraining() bool {
+	var draining bool
+	s.drainBcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		draining = s.draining
+	})
+	return draining
+}
+
+// addRPC tracks an open call for Shutdown.
+//
+// Returns false and the drain deadline if the server is draining.
+func (s *Server) addRPC(rpc *ServerRPC) (bool, time.Time) {
+	added := true
+	var deadline time.Time
+	s.drainBcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		if s.draining {
+			added, deadline = false, s.drainDeadline
+			return
+		}
+		if s.rpcs == nil {
+			s.rpcs = make(map[*ServerRPC]struct{})
+		}
+		s.rpcs[rpc] = struct{}{}
+	})
+	return added, deadline
+}
+
+// removeRPC removes an open call added with addRPC.
+func (s *Server) removeRPC(rpc *ServerRPC) {
+	s.drainBcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		delete(s.rpcs, rpc)
+		broadcast()
+	})
+}
+
+// rejectDraining rejects the call on prw after reading the first packet.
+func rejectDraining(prw *PacketReaderWriter, deadline time.Time) error {
+	_ = prw.ReadToHandler(func(pkt *Packet) error {
+		_ = prw.WritePacket(newDrainPacket(deadline, true))
+		return ErrDraining
+	})
+	_ = prw.Close()
+	return ErrDraining
+}
+
+// newDrainPacket constructs a Drain packet with a deadline.
+func newDrainPacket(deadline time.Time, callRejected bool) *Packet {
+	var remaining time.Duration
+	if !deadline.IsZero() {
+		remaining = time.Until(deadline)
+	}
+	return NewDrainPacket(remaining, callRejected)
+}
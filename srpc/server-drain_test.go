@@ -0,0 +1,156 @@
+package srpc_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// blockingEchoServer blocks Echo until released.
+type blockingEchoServer struct {
+	*echo.EchoServer
+	started chan struct{}
+	release chan struct{}
+}
+
+func TestShutdown(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &blockingEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		started:    make(chan struct{}, 1),
+		release:    make(chan struct{}),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx := context.Background()
+	msg := &echo.EchoMsg{Body: "hello world"}
+	server := srpc.NewServer(mux)
+	srpcClient := srpc.NewClient(srpc.NewServerPipe(server))
+	client := echo.NewSRPCEchoerClient(srpcClient)
+
+	// start a call which stays open during shutdown
+	callErrCh := make(chan error, 1)
+	go func() {
+		_, err := client.Echo(ctx, msg)
+		callErrCh <- err
+	}()
+	<-echoServer.started
+
+	shutdownCtx, shutdownCtxCancel := context.WithTimeout(ctx, time.Second*5)
+	defer shutdownCtxCancel()
+	shutdownErrCh := make(chan error, 1)
+	go func() {
+		shutdownErrCh <- server.Shutdown(shutdownCtx)
+	}()
+	for !srpc.IsClientDraining(srpcClient) {
+		<-time.After(time.Millisecond * 10)
+	}
+
+	// expect new calls to fail without contacting the server
+	if _, err := client.Echo(ctx, msg); err != srpc.ErrDraining {
+		t.Fatalf("expected %v got %v", srpc.ErrDraining, err)
+	}
+
+	// expect the server to reject calls from other clients
+	otherClient := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	if _, err := otherClient.Echo(ctx, msg); err != srpc.ErrDraining {
+		t.Fatalf("expected %v got %v", srpc.ErrDraining, err)
+	}
+
+	// expect the open call to complete
+	select {
+	case err := <-shutdownErrCh:
+		t.Fatalf("expected shutdown to wait for open calls, got %v", err)
+	default:
+	}
+	close(echoServer.release)
+	if err := <-callErrCh; err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := <-shutdownErrCh; err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+// Echo waits for release and echoes the message.
+func (s *blockingEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	s.started <- struct{}{}
+	select {
+	case <-ctx.Done():
+		return nil, context.Canceled
+	case <-s.release:
+	}
+	return s.EchoServer.Echo(ctx, msg)
+}
+
+// drainStreamServer sends the messages when the shutdown starts.
+type drainStreamServer struct {
+	*echo.EchoServer
+	// shutdownCh is closed when the shutdown starts
+	shutdownCh chan struct{}
+}
+
+func TestShutdown_ConcurrentSend(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &drainStreamServer{
+		EchoServer: echo.NewEchoServer(mux),
+		shutdownCh: make(chan struct{}),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux)
+	openStream, concurrent := newWriteCheckPipe(server)
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(openStream))
+
+	strm, err := client.EchoServerStream(context.Background(), &echo.EchoMsg{Body: "hello"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// shut down while the handler is sending the messages
+	shutdownErrCh := make(chan error, 1)
+	close(echoServer.shutdownCh)
+	go func() {
+		shutdownErrCh <- server.Shutdown(context.Background())
+	}()
+	for {
+		if _, err := strm.Recv(); err != nil {
+			if err != io.EOF {
+				t.Fatal(err.Error())
+			}
+			break
+		}
+	}
+	if err := <-shutdownErrCh; err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// expect the Drain packet to be serialized with the messages
+	if concurrent() {
+		t.Fatal("expected the writes to the stream to be serialized")
+	}
+}
+
+// EchoServerStream sends the message, then sends it repeatedly after the
+// shutdown started.
+func (s *drainStreamServer) EchoServerStream(msg *echo.EchoMsg, strm echo.SRPCEchoer_EchoServerStreamStream) error {
+	if err := strm.Send(msg); err != nil {
+		return err
+	}
+	<-s.shutdownCh
+	for i := 0; i < 200; i++ {
+		if err := strm.Send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,3 +1,5 @@
+//go:build !starpc_core
+
 package srpc
 
 import (
@@ -38,7 +40,13 @@ func (s *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	defer c.Close(websocket.StatusInternalError, "closed")
 
-	ctx := r.Context()
+	ctx := WithConnInfo(r.Context(), &ConnInfo{
+		Transport:       TransportWebSocket,
+		Muxer:           MuxerMplex,
+		ProtocolVersion: ProtocolVersion,
+		RemoteAddr:      r.RemoteAddr,
+		TLS:             r.TLS,
+	})
 	wsConn, err := NewWebSocketConn(ctx, c, true)
 	if err != nil {
 		// TODO: handle / log error?
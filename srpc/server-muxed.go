@@ -0,0 +1,92 @@
+//go:build !starpc_core
+
+package srpc
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	mp "github.com/libp2p/go-mplex"
+	"github.com/pkg/errors"
+)
+
+// AcceptMuxedConn runs a loop which calls Accept on a muxer to handle streams.
+//
+// Starts HandleStream in a separate goroutine to handle the stream.
+// Returns context.Canceled or io.EOF when the loop is complete / closed after
+// waiting for the HandleStream goroutines to exit.
+// The streams count towards ServerConfig.MaxConcurrentStreamsPerConn.
+// Reports the conn and the CloseReason to the StatsHandler and the Channelz, if
+// set.
+func (s *Server) AcceptMuxedConn(ctx context.Context, mplex network.MuxedConn) (rerr error) {
+	ctx = WithConnStreamCounter(ctx)
+	// closeErr is the error which closed the muxer, if known
+	var closeErr error
+	if s.channelz != nil {
+		var removeConn func(reason CloseReason)
+		ctx, removeConn = s.channelz.addConn(ctx)
+		defer func() {
+			removeConn(s.connCloseReason(ctx, rerr, closeErr))
+		}()
+	}
+	if s.statsHandler != nil {
+		info := ConnInfoFromContext(ctx)
+		s.statsHandler.HandleConn(ctx, &ConnEvent{Info: info})
+		defer func() {
+			reason := s.connCloseReason(ctx, rerr, closeErr)
+			s.statsHandler.HandleConn(ctx, &ConnEvent{Info: info, Closed: true, Err: rerr, Reason: reason})
+		}()
+	}
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return context.Canceled
+		default:
+			if mplex.IsClosed() {
+				// the closed muxer returns the error which closed it
+				// after resetting the streams which were not accepted
+				for closeErr == nil {
+					strm, err := mplex.AcceptStream()
+					if err != nil {
+						closeErr = err
+					} else {
+						_ = strm.Reset()
+					}
+				}
+				return io.EOF
+			}
+		}
+
+		muxedStream, err := mplex.AcceptStream()
+		if err != nil {
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = s.HandleStream(ctx, muxedStream)
+		}()
+	}
+}
+
+// connCloseReason classifies the error which closed a muxed conn.
+//
+// closeErr is the error which closed the muxer, if known.
+func (s *Server) connCloseReason(ctx context.Context, err, closeErr error) CloseReason {
+	if closeErr != nil {
+		err = closeErr
+	}
+	switch {
+	case s.IsDraining():
+		return CloseReasonServerDrain
+	case ctx.Err() != nil || errors.Is(err, mp.ErrShutdown):
+		return CloseReasonServerClose
+	case errors.Is(err, mp.ErrInvalidState) || errors.Is(err, mp.ErrTwoInitiators):
+		return CloseReasonProtocolError
+	}
+	return ClassifyCloseReason(err)
+}
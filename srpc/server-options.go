@@ -0,0 +1,62 @@
+package srpc
+
+import (
+	"context"
+	"time"
+)
+
+// ServerOption is an option passed to NewServer.
+type ServerOption func(s *Server)
+
+// WithServerContext sets the lifetime context of the server.
+//
+// Contexts returned by DetachContext in handlers are canceled when this
+// context is canceled. Defaults to context.Background.
+func WithServerContext(ctx context.Context) ServerOption {
+	return func(s *Server) {
+		s.ctx = ctx
+	}
+}
+
+// WithReadinessGate gates calls until the server is marked ready.
+//
+// The server starts as not ready: see SetReady. Calls received while not
+// ready wait up to queueTimeout for readiness and then fail with
+// ErrUnavailable. If queueTimeout is zero calls fail immediately.
+func WithReadinessGate(queueTimeout time.Duration) ServerOption {
+	return func(s *Server) {
+		conf := s.GetConfig().Clone()
+		conf.ReadyTimeout = queueTimeout
+		s.notReady = true
+		s.conf.Store(conf)
+	}
+}
+
+// WithServerHeartbeat pings clients while a call is idle.
+//
+// Calls with clients which do not respond in time fail with
+// ErrHeartbeatTimeout. Clients which do not support Ping packets are not
+// pinged. See HeartbeatParams.
+func WithServerHeartbeat(params HeartbeatParams) ServerOption {
+	return func(s *Server) {
+		conf := s.GetConfig().Clone()
+		conf.Heartbeat = params
+		s.conf.Store(conf)
+	}
+}
+
+// ContextValuesFunc returns the context for a stream from the connection.
+//
+// info is nil if the connection is unknown.
+type ContextValuesFunc func(ctx context.Context, info *ConnInfo) context.Context
+
+// WithContextValues applies the function to the context of every stream.
+//
+// Use to attach connection-level dependencies to the handler contexts, such as
+// a database session or tenant configuration for the peer. Functions from
+// multiple options are applied in order.
+func WithContextValues(fn ContextValuesFunc) ServerOption {
+	return func(s *Server) {
+		s.ctxValues = append(s.ctxValues, fn)
+	}
+}
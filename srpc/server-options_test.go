@@ -0,0 +1,44 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// tenantCtxKey is the context key for the tenant of the connection.
+type tenantCtxKey struct{}
+
+// tenantEchoServer returns the tenant of the connection in the Echo response.
+type tenantEchoServer struct {
+	*echo.EchoServer
+}
+
+func TestContextValues(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &tenantEchoServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux, srpc.WithContextValues(func(ctx context.Context, info *srpc.ConnInfo) context.Context {
+		if info == nil {
+			return ctx
+		}
+		return context.WithValue(ctx, tenantCtxKey{}, "tenant-"+info.Transport)
+	}))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	resp, err := client.Echo(context.Background(), &echo.EchoMsg{Body: "hello"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp.GetBody() != "tenant-pipe" {
+		t.Fatalf("expected tenant from connection got %q", resp.GetBody())
+	}
+}
+
+// Echo returns the tenant in the message body.
+func (s *tenantEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	tenant, _ := ctx.Value(tenantCtxKey{}).(string)
+	return &echo.EchoMsg{Body: tenant}, nil
+}
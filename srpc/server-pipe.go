@@ -8,11 +8,15 @@ import (
 // NewServerPipe constructs a open stream func which creates an in-memory Pipe
 // Stream with the given Server. Starts read pumps for both. Starts the
 // HandleStream function on the server in a separate goroutine.
+//
+// The server side uses the values of the call context but not its
+// cancellation: like a remote server, it observes the cancellation of the call
+// as a CallCancel packet.
 func NewServerPipe(server *Server) OpenStreamFunc {
 	return func(ctx context.Context, msgHandler PacketHandler, closeHandler CloseHandler) (Writer, error) {
 		srvPipe, clientPipe := net.Pipe()
 		go func() {
-			_ = server.HandleStream(ctx, srvPipe)
+			_ = server.HandleStream(DetachContext(ctx), srvPipe)
 		}()
 		clientPrw := NewPacketReadWriter(clientPipe)
 		go clientPrw.ReadPump(msgHandler, closeHandler)
@@ -0,0 +1,107 @@
+package srpc
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// SetReady marks the server as ready or not ready to handle calls.
+//
+// While not ready, calls are gated as configured with WithReadinessGate.
+// Servers without the option are ready unless SetReady(false) is called.
+func (s *Server) SetReady(ready bool) {
+	s.readyBcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		if s.notReady != ready {
+			return
+		}
+		s.notReady = !ready
+		broadcast()
+	})
+}
+
+// IsReady checks if the server is ready to handle calls.
+func (s *Server) IsReady() bool {
+	var ready bool
+	s.readyBcast.HoldLock(func(broadcast func(), getWaitCh func() <-chan struct{}) {
+		ready = !s.notReady
+	})
+	return ready
+}
+
+// WaitReady waits for the server to be ready to handle calls.
+//
+// Returns context.Canceled if ctx is canceled.
+func (s *Server) WaitReady(ctx context.Context) error {
+	return s.readyBcast.Wait(ctx, func(broadcast func(), getWaitCh func() <-chan struct{}) (bool, error) {
+		return !s.notReady, nil
+	})
+}
+
+// waitReadyGate waits for readiness up to the queue timeout.
+//
+// Returns ErrUnavailable if the server did not become ready in time.
+func (s *Server) waitReadyGate(ctx context.Context) error {
+	if s.IsReady() {
+		return nil
+	}
+	readyTimeout := s.GetConfig().ReadyTimeout
+	if readyTimeout <= 0 {
+		return ErrUnavailable
+	}
+	waitCtx, waitCtxCancel := context.WithTimeout(ctx, readyTimeout)
+	defer waitCtxCancel()
+	if err := s.WaitReady(waitCtx); err != nil {
+		if ctx.Err() != nil {
+			return context.Canceled
+		}
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// serverMux gates calls to the Mux on the readiness of the server.
+type serverMux struct {
+	Mux
+	// s is the server
+	s *Server
+}
+
+// InvokeMethod waits for readiness and invokes the method.
+//
+// Calls to the built-in ping service skip the readiness gate, the concurrency
+// limit and the admission controller, but not the interceptors.
+// Returns ErrTooManyCalls if MaxConcurrentCalls is exceeded.
+// Admits the call with the AdmissionController, if set.
+func (m *serverMux) InvokeMethod(serviceID, methodID string, strm Stream) (bool, error) {
+	if serviceID == PingServiceID && !m.s.noPing {
+		return m.intercept(servePing)(serviceID, methodID, strm)
+	}
+	if err := m.s.waitReadyGate(strm.Context()); err != nil {
+		return true, err
+	}
+	active := atomic.AddInt32(&m.s.activeCalls, 1)
+	defer atomic.AddInt32(&m.s.activeCalls, -1)
+	if maxCalls := m.s.GetConfig().MaxConcurrentCalls; maxCalls > 0 && int(active) > maxCalls {
+		return true, ErrTooManyCalls
+	}
+	if m.s.admission != nil {
+		req := newAdmissionRequest(strm.Context(), serviceID, methodID, int(active))
+		done, err := m.s.admission.Admit(strm.Context(), req)
+		if err != nil {
+			return true, err
+		}
+		defer done()
+	}
+	return m.intercept(m.Mux.InvokeMethod)(serviceID, methodID, strm)
+}
+
+// intercept wraps the invoker with the server interceptors, if any.
+func (m *serverMux) intercept(invoker Invoker) Invoker {
+	if len(m.s.interceptors) == 0 {
+		return invoker
+	}
+	return ChainInterceptors(invoker, m.s.interceptors...)
+}
+
+// _ is a type assertion
+var _ Mux = ((*serverMux)(nil))
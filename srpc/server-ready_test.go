@@ -0,0 +1,50 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestReadinessGate(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := echo.NewEchoServer(mux)
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx := context.Background()
+	msg := &echo.EchoMsg{Body: "hello world"}
+
+	// reject while not ready
+	server := srpc.NewServer(mux, srpc.WithReadinessGate(0))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	if _, err := client.Echo(ctx, msg); err == nil || err.Error() != srpc.ErrUnavailable.Error() {
+		t.Fatalf("expected %v got %v", srpc.ErrUnavailable, err)
+	}
+	server.SetReady(true)
+	if _, err := client.Echo(ctx, msg); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// queue until ready
+	server = srpc.NewServer(mux, srpc.WithReadinessGate(time.Second*5))
+	client = echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.Echo(ctx, msg)
+		errCh <- err
+	}()
+	<-time.After(time.Millisecond * 50)
+	select {
+	case err := <-errCh:
+		t.Fatalf("expected call to wait for readiness, got %v", err)
+	default:
+	}
+	server.SetReady(true)
+	if err := <-errCh; err != nil {
+		t.Fatal(err.Error())
+	}
+}
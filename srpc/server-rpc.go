@@ -3,7 +3,10 @@ package srpc
 import (
 	"context"
 	"io"
+	"sync"
+	"sync/atomic"
 
+	"github.com/aperturerobotics/starpc/internal/taskgroup"
 	"github.com/pkg/errors"
 )
 
@@ -22,6 +25,8 @@ type ServerRPC struct {
 	service string
 	// method is the rpc method
 	method string
+	// metadata is the call metadata
+	metadata map[string]string
 	// dataCh contains queued data packets.
 	// closed when the client closes the channel.
 	dataCh chan []byte
@@ -32,14 +37,63 @@ type ServerRPC struct {
 	// before dataCh is closed, managed by HandlePacket.
 	// immutable after dataCh is closed or ctxCancel
 	clientErr error
+	// peerCanceled is closed when the client cancels the call.
+	// controlled by HandlePacket.
+	peerCanceled chan struct{}
+	// detached is set to 1 if the stream was detached from the handler.
+	detached uint32
+	// invoked is set to 1 before the handler is started.
+	invoked uint32
+	// handlerDone is closed when the handler returns.
+	handlerDone chan struct{}
+	// finishOnce guards finish.
+	finishOnce sync.Once
+	// writeMtx guards finished, writer and writing packets.
+	writeMtx sync.Mutex
+	// finished is set after the result was written.
+	// guarded by writeMtx
+	finished bool
+	// responseMetadata is sent with the result of the call.
+	// guarded by writeMtx
+	responseMetadata Metadata
+	// result is the result of the call.
+	// set by finish
+	result error
+	// loadReporter returns the load attached to the result, if set.
+	loadReporter LoadReporter
+	// errorPolicy is applied to the error sent with the result, if set.
+	errorPolicy *ErrorPolicy
+	// compressor compresses the messages, if set.
+	// set by HandleCallStart
+	compressor Compressor
+	// codec encodes the messages, if set.
+	// set by HandleCallStart
+	codec Codec
+	// signalsSupported indicates the client accepts signals.
+	// set by HandleCallStart
+	signalsSupported bool
+	// signalMtx guards signalHandler.
+	signalMtx sync.Mutex
+	// signalHandler is called with signals from the client.
+	// may be nil
+	signalHandler func(sig *Signal)
+	// heartbeat pings the client, if set.
+	heartbeat *heartbeat
+	// tasks tracks the invokeRPC goroutine.
+	tasks taskgroup.Group
+	// callStart is called after CallStart was received.
+	// may be nil
+	callStart func(serviceID, methodID string)
 }
 
 // NewServerRPC constructs a new ServerRPC session.
 // note: call SetWriter before handling any incoming messages.
 func NewServerRPC(ctx context.Context, mux Mux) *ServerRPC {
 	rpc := &ServerRPC{
-		dataCh: make(chan []byte, 5),
-		mux:    mux,
+		dataCh:       make(chan []byte, 5),
+		mux:          mux,
+		peerCanceled: make(chan struct{}),
+		handlerDone:  make(chan struct{}),
 	}
 	rpc.ctx, rpc.ctxCancel = context.WithCancel(ctx)
 	return rpc
@@ -65,11 +119,26 @@ func (r *ServerRPC) Wait(ctx context.Context) error {
 	return r.clientErr
 }
 
+// Join waits for the goroutines started by the ServerRPC to exit.
+//
+// Returns after the handler returns. Call after the read pump has exited so
+// that no further goroutines can be started.
+func (r *ServerRPC) Join() {
+	r.tasks.Wait()
+}
+
 // HandleStreamClose handles the incoming stream closing w/ optional error.
 func (r *ServerRPC) HandleStreamClose(closeErr error) {
 	if r.dataChClosed {
+		if r.heartbeat.timedOut() {
+			// the client is unresponsive: cancel the handler.
+			r.ctxCancel()
+		}
 		return
 	}
+	if r.heartbeat.timedOut() {
+		closeErr = ErrHeartbeatTimeout
+	}
 	if closeErr != nil {
 		if r.clientErr == nil {
 			r.clientErr = closeErr
@@ -91,12 +160,24 @@ func (r *ServerRPC) HandlePacket(msg *Packet) error {
 	if err := msg.Validate(); err != nil {
 		return err
 	}
+	r.heartbeat.touch()
 
 	switch b := msg.GetBody().(type) {
 	case *Packet_CallStart:
 		return r.HandleCallStart(b.CallStart)
 	case *Packet_CallData:
 		return r.HandleCallData(b.CallData)
+	case *Packet_CallCancel:
+		return r.HandleCallCancel()
+	case *Packet_Ping:
+		// write from a goroutine: the remote may be blocked writing to us.
+		r.tasks.Go(func() {
+			_ = r.writeControlPacket(NewPongPacket(b.Ping))
+		})
+		return nil
+	case *Packet_Signal:
+		r.handleSignal(b.Signal)
+		return nil
 	default:
 		return nil
 	}
@@ -112,9 +193,45 @@ func (r *ServerRPC) HandleCallStart(pkt *CallStart) error {
 		return ErrCompleted
 	}
 	r.method, r.service = pkt.GetRpcMethod(), pkt.GetRpcService()
+	r.metadata = pkt.GetMetadata()
+	if r.callStart != nil {
+		r.callStart(r.service, r.method)
+	}
+
+	comp, err := lookupCompressor(pkt.GetCompression())
+	if err != nil {
+		r.finish(err)
+		return nil
+	}
+	codec, err := lookupCodec(pkt.GetCodec())
+	if err != nil {
+		r.finish(err)
+		return nil
+	}
+	r.codec = codec
+	if comp != nil {
+		r.compressor = comp
+		r.writeMtx.Lock()
+		r.writer = newCompressWriter(r.writer, comp)
+		r.writeMtx.Unlock()
+	}
+	if pkt.GetPingSupported() {
+		r.heartbeat.enable()
+	}
+	r.signalsSupported = pkt.GetSignalsSupported()
+	if pkt.GetHeartbeat() {
+		// acknowledge that Ping packets are supported
+		if err := r.writeControlPacket(NewPongPacket(0)); err != nil {
+			return err
+		}
+	}
 
 	// process first data packet, if included
 	if data := pkt.GetData(); len(data) != 0 || pkt.GetDataIsZero() {
+		data, err := decompressData(r.compressor, data, pkt.GetDataCompressed())
+		if err != nil {
+			return err
+		}
 		if data == nil {
 			data = []byte{}
 		}
@@ -127,7 +244,8 @@ func (r *ServerRPC) HandleCallStart(pkt *CallStart) error {
 	}
 
 	// invoke the rpc
-	go r.invokeRPC()
+	atomic.StoreUint32(&r.invoked, 1)
+	r.tasks.Go(r.invokeRPC)
 
 	return nil
 }
@@ -139,6 +257,10 @@ func (r *ServerRPC) HandleCallData(pkt *CallData) error {
 	}
 
 	if data := pkt.GetData(); len(data) != 0 || pkt.GetDataIsZero() {
+		data, err := decompressData(r.compressor, data, pkt.GetDataCompressed())
+		if err != nil {
+			return err
+		}
 		select {
 		case <-r.ctx.Done():
 			return context.Canceled
@@ -160,19 +282,210 @@ func (r *ServerRPC) HandleCallData(pkt *CallData) error {
 	return nil
 }
 
+// HandleCallCancel handles the call cancel packet.
+//
+// Cancels the call context: any further MsgSend or MsgRecv calls by the
+// handler return ErrCanceledByPeer.
+func (r *ServerRPC) HandleCallCancel() error {
+	select {
+	case <-r.peerCanceled:
+		return nil
+	default:
+	}
+	if r.clientErr == nil {
+		r.clientErr = ErrCanceledByPeer
+	}
+	close(r.peerCanceled)
+	if r.service == "" {
+		// invokeRPC has not been called, otherwise it would close the writer
+		_ = r.writer.Close()
+	}
+	r.ctxCancel()
+	return nil
+}
+
 // invoke invokes the RPC after CallStart is received.
 func (r *ServerRPC) invokeRPC() {
-	// ctx := r.ctx
+	defer close(r.handlerDone)
 	serviceID, methodID := r.service, r.method
-	strm := NewMsgStream(r.ctx, r.writer, r.dataCh)
-	ok, err := r.mux.InvokeMethod(serviceID, methodID, strm)
-	if err == nil && !ok {
-		err = ErrUnimplemented
-	}
-	outPkt := NewCallDataPacket(nil, false, true, err)
-	_ = r.writer.WritePacket(outPkt)
-	_ = r.writer.Close()
+	ctx, err := DecodeBaggage(newIncomingContext(r.ctx, r.metadata), r.metadata)
+	if err == nil {
+		ctx = withProgressSender(withStreamDetacher(ctx, r), r)
+		ctx = withErrorDetailsSender(ctx, r)
+		ctx = withResponseMetadataSetter(ctx, r)
+		ctx = withSignalEndpoint(ctx, r)
+		if r.codec != nil {
+			ctx = withCallCodec(ctx, r.codec)
+		}
+		strm := NewMsgStream(ctx, &serverRPCWriter{r: r}, r.dataCh)
+		strm.peerCanceled = r.peerCanceled
+		strm.codec = r.codec
+		var ok bool
+		ok, err = r.mux.InvokeMethod(serviceID, methodID, strm)
+		if err == nil && !ok {
+			err = ErrUnimplemented
+		}
+	}
+	if err == nil && atomic.LoadUint32(&r.detached) != 0 {
+		// the owner of the DetachedStream completes the call.
+		return
+	}
+	r.finish(err)
+}
+
+// detach marks the stream as detached from the handler.
+func (r *ServerRPC) detach() (func(err error), bool) {
+	if !atomic.CompareAndSwapUint32(&r.detached, 0, 1) {
+		return nil, false
+	}
+	return r.finish, true
+}
+
+// cancel cancels the call and waits for the handler to return, if started.
+//
+// Does not wait if the client canceled the call: the result is not written.
+func (r *ServerRPC) cancel() {
 	r.ctxCancel()
+	if atomic.LoadUint32(&r.invoked) == 0 {
+		return
+	}
+	select {
+	case <-r.handlerDone:
+	case <-r.peerCanceled:
+	}
+}
+
+// finish writes the result of the call and closes the writer.
+//
+// Only the first call has any effect. Packets written afterwards fail with
+// ErrCompleted.
+func (r *ServerRPC) finish(err error) {
+	r.finishOnce.Do(func() {
+		r.result = err
+		var outPkt *Packet
+		select {
+		case <-r.peerCanceled:
+			// the client canceled the call: skip writing the result.
+		default:
+			err = r.errorPolicy.apply(r.ctx, r.service, r.method, err)
+			outPkt = NewCallDataPacket(nil, false, true, err)
+			if r.loadReporter != nil {
+				outPkt.GetCallData().LoadReport = r.loadReporter()
+			}
+			outPkt.GetCallData().ErrorDetails = r.errorPolicy.truncateDetails(GetErrorDetails(err))
+			outPkt.GetCallData().Status = newErrorStatus(err)
+		}
+		r.writeMtx.Lock()
+		r.finished = true
+		if outPkt != nil {
+			outPkt.GetCallData().Metadata = r.responseMetadata
+			_ = r.writer.WritePacket(outPkt)
+		}
+		r.writeMtx.Unlock()
+		_ = r.writer.Close()
+		r.ctxCancel()
+	})
+}
+
+// sendProgress writes a keepalive progress packet.
+func (r *ServerRPC) sendProgress(progress Metadata) error {
+	return r.writeControlPacket(NewCallProgressPacket(progress))
+}
+
+// sendErrorDetails writes an error details packet.
+//
+// Applies the error policy to the details.
+func (r *ServerRPC) sendErrorDetails(details []string) error {
+	details = r.errorPolicy.applyDetails(r.ctx, details)
+	if len(details) == 0 {
+		return nil
+	}
+	return r.writeControlPacket(NewCallErrorDetailsPacket(details))
+}
+
+// setResponseMetadata merges metadata into the response metadata.
+func (r *ServerRPC) setResponseMetadata(md Metadata) error {
+	r.writeMtx.Lock()
+	defer r.writeMtx.Unlock()
+	if r.finished {
+		return ErrCompleted
+	}
+	if r.responseMetadata == nil {
+		r.responseMetadata = make(Metadata, len(md))
+	}
+	for k, v := range md {
+		r.responseMetadata[k] = v
+	}
+	return nil
+}
+
+// handleSignal calls the signal handler, if set.
+func (r *ServerRPC) handleSignal(sig *Signal) {
+	r.signalMtx.Lock()
+	cb := r.signalHandler
+	r.signalMtx.Unlock()
+	if cb != nil {
+		cb(sig)
+	}
+}
+
+// sendSignal writes a signal packet if the client accepts signals.
+func (r *ServerRPC) sendSignal(sig *Signal) error {
+	if !r.signalsSupported {
+		return ErrSignalsUnsupported
+	}
+	return r.writeControlPacket(&Packet{Body: &Packet_Signal{Signal: sig}})
+}
+
+// setSignalHandler sets the callback for signals from the client.
+func (r *ServerRPC) setSignalHandler(cb func(sig *Signal)) {
+	r.signalMtx.Lock()
+	r.signalHandler = cb
+	r.signalMtx.Unlock()
+}
+
+// writeControlPacket writes a packet if the result was not written yet.
+func (r *ServerRPC) writeControlPacket(pkt *Packet) error {
+	r.writeMtx.Lock()
+	defer r.writeMtx.Unlock()
+	if r.finished {
+		return ErrCompleted
+	}
+	return r.writer.WritePacket(pkt)
+}
+
+// serverRPCWriter writes the packets of the handler with the write lock held.
+//
+// The packets are not interleaved with the packets written by other
+// goroutines, and fail with ErrCompleted after the result was written.
+type serverRPCWriter struct {
+	// r is the server rpc
+	r *ServerRPC
+}
+
+// WritePacket writes a packet if the result was not written yet.
+func (w *serverRPCWriter) WritePacket(p *Packet) error {
+	return w.r.writeControlPacket(p)
+}
+
+// WritePackets writes the packets if the result was not written yet.
+func (w *serverRPCWriter) WritePackets(pkts []*Packet) error {
+	w.r.writeMtx.Lock()
+	defer w.r.writeMtx.Unlock()
+	if w.r.finished {
+		return ErrCompleted
+	}
+	return writePackets(w.r.writer, pkts)
+}
+
+// Close closes the writer.
+func (w *serverRPCWriter) Close() error {
+	return w.r.writer.Close()
+}
+
+// Reset resets the writer if supported, otherwise closes it.
+func (w *serverRPCWriter) Reset() error {
+	return resetWriter(w.r.writer)
 }
 
 // Close releases any resources held by the ServerRPC.
@@ -187,3 +500,14 @@ func (r *ServerRPC) Close() {
 	}
 	r.ctxCancel()
 }
+
+// _ is a type assertion
+var (
+	_ streamDetacher         = ((*ServerRPC)(nil))
+	_ progressSender         = ((*ServerRPC)(nil))
+	_ errorDetailsSender     = ((*ServerRPC)(nil))
+	_ responseMetadataSetter = ((*ServerRPC)(nil))
+	_ signalEndpoint         = ((*ServerRPC)(nil))
+	_ BatchWriter            = ((*serverRPCWriter)(nil))
+	_ ResetWriter            = ((*serverRPCWriter)(nil))
+)
@@ -0,0 +1,66 @@
+package srpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// cancelEchoServer reports the result of sending after the client canceled.
+type cancelEchoServer struct {
+	*echo.EchoServer
+	// sendErrCh receives the error from sending after the cancel.
+	sendErrCh chan error
+}
+
+func TestServerSendAfterCancel(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &cancelEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		sendErrCh:  make(chan error, 1),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	strm, err := client.EchoServerStream(ctx, &echo.EchoMsg{Body: "hello world"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != nil {
+		t.Fatal(err.Error())
+	}
+	ctxCancel()
+
+	// expect the client to fail fast
+	if err := strm.MsgSend(&echo.EchoMsg{}); err != context.Canceled {
+		t.Fatalf("expected client send to return %v got %v", context.Canceled, err)
+	}
+
+	// expect the handler to fail fast with ErrCanceledByPeer
+	select {
+	case err := <-echoServer.sendErrCh:
+		if err != srpc.ErrCanceledByPeer {
+			t.Fatalf("expected server send to return %v got %v", srpc.ErrCanceledByPeer, err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the server to observe the cancel")
+	}
+}
+
+// EchoServerStream sends one message, waits for cancel, then sends again.
+func (s *cancelEchoServer) EchoServerStream(msg *echo.EchoMsg, strm echo.SRPCEchoer_EchoServerStreamStream) error {
+	if err := strm.Send(msg); err != nil {
+		return err
+	}
+	<-strm.Context().Done()
+	err := strm.Send(msg)
+	s.sendErrCh <- err
+	return err
+}
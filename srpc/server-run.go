@@ -0,0 +1,134 @@
+//go:build !starpc_core
+
+package srpc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// runShutdownLinger is the max duration Run waits for the clients to close the
+// connections after Shutdown.
+//
+// The muxer sends the queued packets in the background: closing the connection
+// right away could drop the results of the calls completed by Shutdown.
+const runShutdownLinger = time.Millisecond * 500
+
+// Run accepts connections from the listeners until ctx is canceled.
+//
+// Serves each connection with the default muxer. When ctx is canceled or a
+// listener fails, closes the listeners and the connections and waits for all
+// of the goroutines started for them to exit. Returns nil if ctx was canceled,
+// otherwise the first error accepting a connection, so Run can be composed
+// with errgroup.
+//
+// Shutdown the server to stop Run after the open calls complete. The
+// connections are then closed once the clients close them, or after a short
+// linger so that the clients can read the results of the completed calls.
+func (s *Server) Run(ctx context.Context, listeners ...net.Listener) error {
+	parentCtx := ctx
+	ctx, ctxCancel := context.WithCancel(ctx)
+	defer ctxCancel()
+
+	// shutdownCh is closed if Run stops because of Shutdown.
+	shutdownCh := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if s.waitClosed(ctx) == nil {
+			close(shutdownCh)
+			ctxCancel()
+		}
+	}()
+	errCh := make(chan error, len(listeners))
+	for _, lis := range listeners {
+		lis := lis
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errCh <- s.runListener(ctx, parentCtx, shutdownCh, lis, &wg)
+			ctxCancel()
+		}()
+		go func() {
+			defer wg.Done()
+			<-ctx.Done()
+			_ = lis.Close()
+		}()
+	}
+	wg.Wait()
+
+	close(errCh)
+	for err := range errCh {
+		if err != nil && err != context.Canceled {
+			return err
+		}
+	}
+	return nil
+}
+
+// runListener accepts connections from the listener until it is closed.
+//
+// Serves each connection in a goroutine tracked with wg. If shutdownCh is
+// closed, lingers before closing the connections unless parentCtx is canceled.
+func (s *Server) runListener(
+	ctx, parentCtx context.Context,
+	shutdownCh <-chan struct{},
+	lis net.Listener,
+	wg *sync.WaitGroup,
+) error {
+	for {
+		nc, err := lis.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return context.Canceled
+			}
+			return err
+		}
+
+		mc, err := NewMuxedConn(nc, false)
+		if err != nil {
+			_ = nc.Close()
+			continue
+		}
+
+		connCtx := WithConnInfo(ctx, NewConnInfo(nc, MuxerMplex))
+		connDone := make(chan struct{})
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			defer close(connDone)
+			_ = s.AcceptMuxedConn(connCtx, mc)
+		}()
+		go func() {
+			defer wg.Done()
+			select {
+			case <-ctx.Done():
+				lingerClose(parentCtx, shutdownCh, connDone)
+			case <-connDone:
+			}
+			_ = mc.Close()
+		}()
+	}
+}
+
+// lingerClose waits for the client to close the connection after Shutdown.
+//
+// Returns immediately if shutdownCh is not closed. Otherwise waits until
+// connDone is closed, the linger expires or parentCtx is canceled.
+func lingerClose(parentCtx context.Context, shutdownCh, connDone <-chan struct{}) {
+	select {
+	case <-shutdownCh:
+	default:
+		return
+	}
+	timer := time.NewTimer(runShutdownLinger)
+	defer timer.Stop()
+	select {
+	case <-connDone:
+	case <-timer.C:
+	case <-parentCtx.Done():
+	}
+}
@@ -0,0 +1,67 @@
+package srpc
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// StreamStats contains the outcome of a stream handled by the Server.
+//
+// Also passed to StatsHandler for the calls made by a Client.
+type StreamStats struct {
+	// Client is set for calls made by a Client.
+	Client bool
+	// ServiceID is the service of the call, empty if no call was started.
+	ServiceID string
+	// MethodID is the method of the call, empty if no call was started.
+	MethodID string
+	// Start is the time the stream was accepted.
+	Start time.Time
+	// Duration is the time from Start until the stream was closed.
+	Duration time.Duration
+	// BytesRead is the number of bytes read from the stream.
+	BytesRead uint64
+	// BytesWritten is the number of bytes written to the stream.
+	BytesWritten uint64
+	// Err is the result of the call sent to the client, nil if successful.
+	Err error
+}
+
+// HandleStreamWithStats handles an incoming ReadWriteCloser stream and
+// returns the stats of the stream and the result of the call.
+//
+// The error is the same as HandleStream: the result of the call is in the
+// Err field of the stats.
+func (s *Server) HandleStreamWithStats(ctx context.Context, rwc io.ReadWriteCloser) (*StreamStats, error) {
+	stats := &StreamStats{}
+	err := s.handleStream(ctx, rwc, stats)
+	return stats, err
+}
+
+// countingReadWriteCloser counts the bytes read and written.
+type countingReadWriteCloser struct {
+	io.ReadWriteCloser
+	// read is the number of bytes read
+	read uint64
+	// written is the number of bytes written
+	written uint64
+}
+
+// Read reads data from the stream.
+func (c *countingReadWriteCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Read(p)
+	atomic.AddUint64(&c.read, uint64(n))
+	return n, err
+}
+
+// Write writes data to the stream.
+func (c *countingReadWriteCloser) Write(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Write(p)
+	atomic.AddUint64(&c.written, uint64(n))
+	return n, err
+}
+
+// _ is a type assertion
+var _ io.ReadWriteCloser = ((*countingReadWriteCloser)(nil))
@@ -0,0 +1,56 @@
+package srpc_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+func TestHandleStreamWithStats(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &failingEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	server := srpc.NewServer(mux)
+
+	// open each stream with a pipe, reporting the stats of the stream
+	statsCh := make(chan *srpc.StreamStats, 1)
+	openStream := func(ctx context.Context, msgHandler srpc.PacketHandler, closeHandler srpc.CloseHandler) (srpc.Writer, error) {
+		srvPipe, clientPipe := net.Pipe()
+		go func() {
+			stats, _ := server.HandleStreamWithStats(ctx, srvPipe)
+			statsCh <- stats
+		}()
+		clientPrw := srpc.NewPacketReadWriter(clientPipe)
+		go clientPrw.ReadPump(msgHandler, closeHandler)
+		return clientPrw, nil
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(openStream))
+
+	ctx := context.Background()
+	msg := &echo.EchoMsg{Body: "hello world"}
+	if _, err := client.Echo(ctx, msg); err != nil {
+		t.Fatal(err.Error())
+	}
+	stats := <-statsCh
+	if stats.ServiceID != echo.SRPCEchoerServiceID || stats.MethodID != "Echo" || stats.Err != nil {
+		t.Fatalf("unexpected stats: %#v", stats)
+	}
+	if stats.BytesRead == 0 || stats.BytesWritten == 0 || stats.Start.IsZero() || stats.Duration <= 0 {
+		t.Fatalf("expected stream to be measured: %#v", stats)
+	}
+
+	// expect the stats to contain the error returned by the handler
+	atomic.StoreInt32(&echoServer.failing, 1)
+	if _, err := client.Echo(ctx, msg); err == nil {
+		t.Fatal("expected call to fail")
+	}
+	if stats := <-statsCh; stats.Err == nil || stats.Err.Error() != "backend unavailable" {
+		t.Fatalf("expected handler error got %v", stats.Err)
+	}
+}
@@ -3,21 +3,79 @@ package srpc
 import (
 	"context"
 	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/aperturerobotics/starpc/internal/broadcast"
+	"github.com/aperturerobotics/starpc/internal/taskgroup"
 )
 
 // Server handles incoming RPC streams with a mux.
 type Server struct {
 	// mux is the srpc mux
 	mux Mux
+	// ctx is the server lifetime context
+	ctx context.Context
+	// readyBcast guards notReady and is broadcast when it changes
+	readyBcast broadcast.Broadcast
+	// notReady indicates calls are gated until SetReady(true)
+	// guarded by readyBcast
+	notReady bool
+	// conf contains the current *ServerConfig
+	conf atomic.Value
+	// confMtx guards SetConfig and the fields below
+	confMtx sync.Mutex
+	// confCbs contains the config change callbacks
+	confCbs map[uint64]func(conf *ServerConfig)
+	// confCbID is the next config change callback id
+	confCbID uint64
+	// activeCalls is the number of active calls
+	activeCalls int32
+	// drainBcast guards the fields below and is broadcast when rpcs changes
+	drainBcast broadcast.Broadcast
+	// draining indicates Shutdown was called
+	// guarded by drainBcast
+	draining bool
+	// drainDeadline is the deadline passed to Shutdown, if any.
+	// guarded by drainBcast
+	drainDeadline time.Time
+	// rpcs contains the open calls
+	// guarded by drainBcast
+	rpcs map[*ServerRPC]struct{}
+	// closed indicates Shutdown completed
+	// guarded by drainBcast
+	closed bool
+	// loadReporter returns the load attached to the call results, if set.
+	loadReporter LoadReporter
+	// admission admits calls, if set.
+	admission AdmissionController
+	// ctxValues are applied to the context of each stream.
+	ctxValues []ContextValuesFunc
+	// noPing disables the built-in ping service.
+	noPing bool
+	// errorPolicy is applied to errors returned by handlers, if set.
+	errorPolicy *ErrorPolicy
+	// interceptors wrap the calls to the mux.
+	interceptors []ServerInterceptor
+	// statsHandler receives the stats of the calls, if set.
+	statsHandler StatsHandler
+	// channelz tracks the live connections and streams, if set.
+	channelz *Channelz
 }
 
 // NewServer constructs a new SRPC server.
-func NewServer(mux Mux) *Server {
-	return &Server{
+func NewServer(mux Mux, opts ...ServerOption) *Server {
+	s := &Server{
 		mux: mux,
+		ctx: context.Background(),
 	}
+	s.conf.Store(&ServerConfig{})
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // GetMux returns the mux.
@@ -26,37 +84,137 @@ func (s *Server) GetMux() Mux {
 }
 
 // HandleStream handles an incoming ReadWriteCloser stream.
+//
+// If rwc is a net.Conn, attaches the ConnInfo to the handler context.
+// Applies the WithContextValues functions to the handler context.
+// Returns ErrDraining if the call was rejected because of Shutdown, or
+// ErrTooManyStreams if the connection exceeded MaxConcurrentStreamsPerConn.
+// Completes the call, closes rwc and waits for the read pump and the handler
+// to exit before returning.
 func (s *Server) HandleStream(ctx context.Context, rwc io.ReadWriteCloser) error {
-	subCtx, subCtxCancel := context.WithCancel(ctx)
-	defer subCtxCancel()
-	serverRPC := NewServerRPC(subCtx, s.mux)
-	prw := NewPacketReadWriter(rwc)
-	serverRPC.SetWriter(prw)
-	go prw.ReadPump(serverRPC.HandlePacket, serverRPC.HandleStreamClose)
-	return serverRPC.Wait(ctx)
+	return s.handleStream(ctx, rwc, nil)
 }
 
-// AcceptMuxedConn runs a loop which calls Accept on a muxer to handle streams.
-//
-// Starts HandleStream in a separate goroutine to handle the stream.
-// Returns context.Canceled or io.EOF when the loop is complete / closed.
-func (s *Server) AcceptMuxedConn(ctx context.Context, mplex network.MuxedConn) error {
-	for {
-		select {
-		case <-ctx.Done():
-			return context.Canceled
-		default:
-			if mplex.IsClosed() {
-				return io.EOF
+// handleStream handles an incoming stream filling stats if set.
+func (s *Server) handleStream(ctx context.Context, rwc io.ReadWriteCloser, stats *StreamStats) error {
+	// the options of the client call do not apply to the handler calls.
+	ctx = withoutCallOptions(ctx)
+	if nc, ok := rwc.(net.Conn); ok && ConnInfoFromContext(ctx) == nil {
+		ctx = WithConnInfo(ctx, NewConnInfo(nc, ""))
+	}
+	if len(s.ctxValues) != 0 {
+		info := ConnInfoFromContext(ctx)
+		for _, fn := range s.ctxValues {
+			ctx = fn(ctx, info)
+		}
+	}
+	if s.statsHandler != nil {
+		if stats == nil {
+			stats = &StreamStats{}
+		}
+		defer func() {
+			if stats.ServiceID != "" {
+				s.statsHandler.HandleRPCEnd(ctx, stats)
 			}
+		}()
+	}
+	if stats != nil {
+		crwc := &countingReadWriteCloser{ReadWriteCloser: rwc}
+		rwc = crwc
+		stats.Start = time.Now()
+		defer func() {
+			stats.Duration = time.Since(stats.Start)
+			stats.BytesRead = atomic.LoadUint64(&crwc.read)
+			stats.BytesWritten = atomic.LoadUint64(&crwc.written)
+		}()
+	}
+	conf := s.GetConfig()
+	var subCtx context.Context
+	var subCtxCancel context.CancelFunc
+	if callTimeout := conf.CallTimeout; callTimeout > 0 {
+		subCtx, subCtxCancel = context.WithTimeout(withServerContext(ctx, s.ctx), callTimeout)
+	} else {
+		subCtx, subCtxCancel = context.WithCancel(withServerContext(ctx, s.ctx))
+	}
+	defer subCtxCancel()
+	serverRPC := NewServerRPC(subCtx, &serverMux{Mux: s.mux, s: s})
+	prw := NewPacketReadWriter(rwc)
+	handlePacket := serverRPC.HandlePacket
+	if s.channelz != nil {
+		czStrm := s.channelz.addStream(ctx)
+		defer s.channelz.removeStream(czStrm)
+		serverRPC.SetWriter(&channelzWriter{Writer: prw, strm: czStrm})
+		handlePacket = czStrm.handlePacket(handlePacket)
+	} else {
+		serverRPC.SetWriter(prw)
+	}
+	serverRPC.loadReporter = s.loadReporter
+	serverRPC.errorPolicy = s.errorPolicy
+	serverRPC.heartbeat = newHeartbeat(conf.Heartbeat)
+	if s.statsHandler != nil {
+		serverRPC.callStart = func(serviceID, methodID string) {
+			s.statsHandler.HandleRPCBegin(ctx, &RPCBegin{ServiceID: serviceID, MethodID: methodID, Start: stats.Start})
 		}
-
-		muxedStream, err := mplex.AcceptStream()
-		if err != nil {
+		statsHandlePacket := handlePacket
+		handlePacket = func(pkt *Packet) error {
+			err := statsHandlePacket(pkt)
+			if isProtocolError(err) {
+				// called from the read pump which sets service and method
+				handleProtocolError(ctx, s.statsHandler, &ProtocolError{
+					ServiceID: serverRPC.service,
+					MethodID:  serverRPC.method,
+					Err:       err,
+				})
+			}
 			return err
 		}
-		go func() {
-			_ = s.HandleStream(ctx, muxedStream)
-		}()
 	}
+	release, ok := acquireConnStream(ctx, conf.MaxConcurrentStreamsPerConn)
+	if !ok {
+		err := rejectCall(prw, ErrTooManyStreams)
+		if stats != nil {
+			stats.Err = err
+		}
+		return err
+	}
+	defer release()
+	if added, deadline := s.addRPC(serverRPC); !added {
+		err := rejectDraining(prw, deadline)
+		if stats != nil {
+			stats.Err = err
+		}
+		return err
+	}
+	defer s.removeRPC(serverRPC)
+	var tasks taskgroup.Group
+	tasks.Go(func() {
+		prw.ReadPump(handlePacket, serverRPC.HandleStreamClose)
+	})
+	if serverRPC.heartbeat != nil {
+		tasks.Go(func() {
+			serverRPC.heartbeat.run(serverRPC.writeControlPacket, func() { _ = prw.Close() }, serverRPC.ctx.Done())
+		})
+	}
+	var err error
+	select {
+	case <-ctx.Done():
+		err = context.Canceled
+	case <-serverRPC.ctx.Done():
+	}
+	// complete the call if it was canceled before the handler returned.
+	// wait for the handler first: it may still be writing to the stream.
+	serverRPC.cancel()
+	serverRPC.finish(context.Canceled)
+	// closes the stream: wait for the read pump and the other goroutines.
+	tasks.Wait()
+	serverRPC.Join()
+	// the read pump sets the client error until it exits.
+	if err == nil {
+		err = serverRPC.clientErr
+	}
+	if stats != nil {
+		stats.ServiceID, stats.MethodID = serverRPC.service, serverRPC.method
+		stats.Err = serverRPC.result
+	}
+	return err
 }
@@ -0,0 +1,60 @@
+package srpc
+
+import "context"
+
+// signalEndpointCtxKey is the context key for the signalEndpoint.
+type signalEndpointCtxKey struct{}
+
+// signalEndpoint sends and receives the signals of a call.
+type signalEndpoint interface {
+	// sendSignal writes a signal packet.
+	sendSignal(sig *Signal) error
+	// setSignalHandler sets the callback for incoming signals.
+	setSignalHandler(cb func(sig *Signal))
+}
+
+// withSignalEndpoint attaches the signalEndpoint to the context.
+func withSignalEndpoint(ctx context.Context, ep signalEndpoint) context.Context {
+	return context.WithValue(ctx, signalEndpointCtxKey{}, ep)
+}
+
+// SendSignal sends an out-of-band signal to the remote of a call.
+//
+// ctx must be the handler context of a server call or the context of a client
+// stream, see Stream.Context. Signals are not part of the message sequence:
+// the remote receives them with the callback set by SetSignalHandler or
+// WithSignalHandler. kind must be set.
+//
+// Servers send signals only to clients which accept them. Servers which do
+// not support signals fail the call: clients may check for the signals feature
+// with the capabilities service.
+//
+// Returns ErrNoCall if ctx does not belong to a call, ErrSignalsUnsupported if
+// the client does not accept signals, or ErrCompleted if the call completed.
+func SendSignal(ctx context.Context, kind string, data []byte) error {
+	ep, _ := ctx.Value(signalEndpointCtxKey{}).(signalEndpoint)
+	if ep == nil {
+		return ErrNoCall
+	}
+	sig := &Signal{Kind: kind, Data: data}
+	if err := sig.Validate(); err != nil {
+		return err
+	}
+	return ep.sendSignal(sig)
+}
+
+// SetSignalHandler sets the callback for signals sent by the remote of a call.
+//
+// ctx is the context of the call as for SendSignal. cb is called from the
+// packet read loop and must not block. Signals received while no callback is
+// set are dropped. Pass nil to clear the callback.
+//
+// Returns ErrNoCall if ctx does not belong to a call.
+func SetSignalHandler(ctx context.Context, cb func(sig *Signal)) error {
+	ep, _ := ctx.Value(signalEndpointCtxKey{}).(signalEndpoint)
+	if ep == nil {
+		return ErrNoCall
+	}
+	ep.setSignalHandler(cb)
+	return nil
+}
@@ -0,0 +1,168 @@
+package srpc_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// signalEchoServer acknowledges the signals of the client with a signal.
+type signalEchoServer struct {
+	*echo.EchoServer
+}
+
+// EchoBidiStream echoes the messages and acknowledges the signals.
+func (s *signalEchoServer) EchoBidiStream(strm echo.SRPCEchoer_EchoBidiStreamStream) error {
+	ctx := strm.Context()
+	if err := srpc.SetSignalHandler(ctx, func(sig *srpc.Signal) {
+		// must not block the read loop
+		go func() {
+			_ = srpc.SendSignal(ctx, "ack-"+sig.GetKind(), sig.GetData())
+		}()
+	}); err != nil {
+		return err
+	}
+	for {
+		msg, err := strm.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := strm.Send(msg); err != nil {
+			return err
+		}
+	}
+}
+
+func TestSignals(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &signalEchoServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	signals := make(chan *srpc.Signal, 1)
+	ctx := srpc.WithCallOptions(context.Background(), srpc.WithSignalHandler(func(sig *srpc.Signal) {
+		signals <- sig
+	}))
+	strm, err := client.EchoBidiStream(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer strm.Close()
+	msg := &echo.EchoMsg{Body: "hello"}
+	if err := strm.Send(msg); err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp, err := strm.Recv(); err != nil || resp.GetBody() != "hello" {
+		t.Fatalf("expected response got %v: %v", resp, err)
+	}
+
+	// expect the signal to be delivered to the callback, not the stream
+	if err := srpc.SendSignal(strm.Context(), "slow-down", []byte("10")); err != nil {
+		t.Fatal(err.Error())
+	}
+	select {
+	case sig := <-signals:
+		if sig.GetKind() != "ack-slow-down" || string(sig.GetData()) != "10" {
+			t.Fatalf("unexpected signal: %v", sig)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected signal from the server")
+	}
+	if err := strm.Send(msg); err != nil {
+		t.Fatal(err.Error())
+	}
+	if resp, err := strm.Recv(); err != nil || resp.GetBody() != "hello" {
+		t.Fatalf("expected response got %v: %v", resp, err)
+	}
+
+	if err := srpc.SendSignal(strm.Context(), "", nil); err != srpc.ErrEmptyPacket {
+		t.Fatalf("expected %v got %v", srpc.ErrEmptyPacket, err)
+	}
+	if err := srpc.SendSignal(context.Background(), "slow-down", nil); err != srpc.ErrNoCall {
+		t.Fatalf("expected %v got %v", srpc.ErrNoCall, err)
+	}
+}
+
+// signalStreamServer sends signals while echoing the messages.
+type signalStreamServer struct {
+	*echo.EchoServer
+}
+
+func TestSignals_ConcurrentSend(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &signalStreamServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	openStream, concurrent := newWriteCheckPipe(srpc.NewServer(mux))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(openStream))
+
+	strm, err := client.EchoBidiStream(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	doneCh := make(chan struct{})
+	go sendSignals(strm.Context(), "client", doneCh)
+	msg := &echo.EchoMsg{Body: "hello"}
+	for i := 0; i < 50; i++ {
+		if err := strm.Send(msg); err != nil {
+			t.Fatal(err.Error())
+		}
+		if _, err := strm.Recv(); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	close(doneCh)
+	if err := strm.CloseSend(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := strm.Recv(); err != io.EOF {
+		t.Fatalf("expected %v got %v", io.EOF, err)
+	}
+	_ = strm.Close()
+
+	// expect the signals to be serialized with the messages on both sides
+	if concurrent() {
+		t.Fatal("expected the writes to the stream to be serialized")
+	}
+}
+
+// EchoBidiStream echoes the messages while sending signals.
+func (s *signalStreamServer) EchoBidiStream(strm echo.SRPCEchoer_EchoBidiStreamStream) error {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	go sendSignals(strm.Context(), "server", doneCh)
+	for {
+		msg, err := strm.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := strm.Send(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// sendSignals sends signals with the kind until doneCh is closed or sending fails.
+func sendSignals(ctx context.Context, kind string, doneCh <-chan struct{}) {
+	for {
+		select {
+		case <-doneCh:
+			return
+		default:
+		}
+		if err := srpc.SendSignal(ctx, kind, nil); err != nil {
+			return
+		}
+	}
+}
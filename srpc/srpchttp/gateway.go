@@ -0,0 +1,308 @@
+package srpchttp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"sync"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/status"
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxBodySize is the default max size of a request body.
+const DefaultMaxBodySize = 4 * 1024 * 1024
+
+// DefaultMetadataHeaderPrefix is the default prefix of the headers forwarded
+// as call metadata.
+const DefaultMetadataHeaderPrefix = "Srpc-Metadata-"
+
+// Config configures a Gateway.
+type Config struct {
+	// Prefix is the path prefix stripped before matching, for example "/api".
+	Prefix string
+	// MaxBodySize is the max size of a request body.
+	// If zero, uses DefaultMaxBodySize.
+	MaxBodySize int64
+	// MetadataHeaderPrefix is the prefix of the headers forwarded as metadata.
+	// The prefix is stripped and the key is lowercased.
+	// If empty, uses DefaultMetadataHeaderPrefix.
+	MetadataHeaderPrefix string
+	// DisableDefaultRoutes disables the POST /{service}/{method} convention.
+	// Only the routes added with AddRoute are served.
+	DisableDefaultRoutes bool
+}
+
+// Gateway is a http.Handler which transcodes HTTP/JSON requests to unary calls.
+//
+// By default POST /{service}/{method} calls the method with the JSON body as
+// the request. Routes added with AddRoute map other paths and verbs in the
+// style of the google.api.http annotations. The messages are encoded with the
+// json codec, so the server must support it.
+type Gateway struct {
+	// client is the client making the calls
+	client srpc.Client
+	// conf is the gateway config
+	conf Config
+	// mtx guards routes
+	mtx sync.RWMutex
+	// routes contains the routes added with AddRoute
+	routes []*route
+}
+
+// NewGateway constructs a new Gateway calling the client.
+//
+// If conf is nil, uses the defaults.
+func NewGateway(client srpc.Client, conf *Config) *Gateway {
+	g := &Gateway{client: client}
+	if conf != nil {
+		g.conf = *conf
+	}
+	if g.conf.MaxBodySize <= 0 {
+		g.conf.MaxBodySize = DefaultMaxBodySize
+	}
+	if g.conf.MetadataHeaderPrefix == "" {
+		g.conf.MetadataHeaderPrefix = DefaultMetadataHeaderPrefix
+	}
+	g.conf.MetadataHeaderPrefix = textproto.CanonicalMIMEHeaderKey(g.conf.MetadataHeaderPrefix)
+	return g
+}
+
+// AddRoute maps a HTTP verb and path template to a method.
+//
+// The template matches the path segments literally except for variables in
+// braces, for example "/v1/messages/{id}", which set the request field with
+// the same name to the segment. "{name=**}" matches the rest of the path. The
+// query parameters also set request fields, and for verbs other than GET and
+// DELETE the JSON body is the request. Fields are set as JSON strings, which
+// protojson accepts for the string, bytes and number fields.
+//
+// Routes are matched in the order they were added, before the default routes.
+func (g *Gateway) AddRoute(verb, template, service, method string) error {
+	rt, err := parseRoute(verb, template)
+	if err != nil {
+		return err
+	}
+	rt.service, rt.method = service, method
+	g.mtx.Lock()
+	g.routes = append(g.routes, rt)
+	g.mtx.Unlock()
+	return nil
+}
+
+// ServeHTTP transcodes the request to a unary call.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := req.URL.Path
+	if g.conf.Prefix != "" {
+		if !strings.HasPrefix(path, g.conf.Prefix) {
+			writeStatus(w, status.New(status.NotFound, http.StatusText(http.StatusNotFound)))
+			return
+		}
+		path = path[len(g.conf.Prefix):]
+	}
+
+	c, httpStatus := g.match(req.Method, path)
+	if c == nil {
+		code := status.NotFound
+		if httpStatus == http.StatusMethodNotAllowed {
+			code = status.Unimplemented
+		}
+		writeError(w, httpStatus, status.New(code, http.StatusText(httpStatus)))
+		return
+	}
+
+	var body []byte
+	if c.useBody {
+		var err error
+		body, err = io.ReadAll(io.LimitReader(req.Body, g.conf.MaxBodySize+1))
+		if err != nil {
+			writeStatus(w, status.New(status.InvalidArgument, err.Error()))
+			return
+		}
+		if int64(len(body)) > g.conf.MaxBodySize {
+			writeError(w, http.StatusRequestEntityTooLarge, status.New(status.ResourceExhausted, "request body too large"))
+			return
+		}
+		if len(body) != 0 && !json.Valid(body) {
+			writeStatus(w, status.New(status.InvalidArgument, "request body is not valid json"))
+			return
+		}
+	}
+	for key, vals := range req.URL.Query() {
+		if _, ok := c.vars[key]; !ok && len(vals) != 0 {
+			c.vars[key] = vals[0]
+		}
+	}
+	body, err := mergeFields(body, c.vars)
+	if err != nil {
+		writeStatus(w, status.New(status.InvalidArgument, err.Error()))
+		return
+	}
+
+	ctx := srpc.WithCallOptions(
+		req.Context(),
+		srpc.WithCodec(srpc.JSONCodecName),
+		srpc.WithMetadata(g.headerMetadata(req.Header)),
+	)
+	out := srpc.NewRawMessage(nil)
+	if err := g.client.Invoke(ctx, c.service, c.method, srpc.NewRawMessage(body), out); err != nil {
+		writeStatus(w, status.Convert(err))
+		return
+	}
+	data := out.GetData()
+	if len(data) == 0 {
+		data = []byte("{}")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// call is a call matched by a request.
+type call struct {
+	// service is the service ID
+	service string
+	// method is the method ID
+	method string
+	// vars contains the fields set by the path
+	vars map[string]string
+	// useBody indicates the body is the request
+	useBody bool
+}
+
+// match returns the call for the request.
+//
+// Returns nil and the HTTP status if no route matches.
+func (g *Gateway) match(verb, path string) (*call, int) {
+	segs := splitPath(path)
+	g.mtx.RLock()
+	routes := g.routes
+	g.mtx.RUnlock()
+	var verbMismatch bool
+	for _, rt := range routes {
+		vars, ok := rt.match(segs)
+		if !ok {
+			continue
+		}
+		if rt.verb != verb {
+			verbMismatch = true
+			continue
+		}
+		return &call{
+			service: rt.service,
+			method:  rt.method,
+			vars:    vars,
+			useBody: verb != http.MethodGet && verb != http.MethodDelete,
+		}, 0
+	}
+
+	if !g.conf.DisableDefaultRoutes && len(segs) == 2 && segs[0] != "" && segs[1] != "" {
+		if verb != http.MethodPost {
+			return nil, http.StatusMethodNotAllowed
+		}
+		return &call{service: segs[0], method: segs[1], vars: make(map[string]string), useBody: true}, 0
+	}
+	if verbMismatch {
+		return nil, http.StatusMethodNotAllowed
+	}
+	return nil, http.StatusNotFound
+}
+
+// headerMetadata returns the metadata from the headers with the prefix.
+func (g *Gateway) headerMetadata(header http.Header) srpc.Metadata {
+	var md srpc.Metadata
+	for key, vals := range header {
+		if len(vals) == 0 || !strings.HasPrefix(key, g.conf.MetadataHeaderPrefix) {
+			continue
+		}
+		name := strings.ToLower(key[len(g.conf.MetadataHeaderPrefix):])
+		if name == "" {
+			continue
+		}
+		if md == nil {
+			md = make(srpc.Metadata)
+		}
+		md[name] = vals[0]
+	}
+	return md
+}
+
+// mergeFields sets the fields in the JSON object body.
+func mergeFields(body []byte, fields map[string]string) ([]byte, error) {
+	if len(fields) == 0 {
+		return body, nil
+	}
+	obj := make(map[string]json.RawMessage, len(fields))
+	if len(strings.TrimSpace(string(body))) != 0 {
+		if err := json.Unmarshal(body, &obj); err != nil {
+			return nil, errors.Wrap(err, "request body")
+		}
+	}
+	for key, val := range fields {
+		enc, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = enc
+	}
+	return json.Marshal(obj)
+}
+
+// errorBody is the JSON body of an error response.
+type errorBody struct {
+	// Code is the status code.
+	Code uint32 `json:"code"`
+	// Message is the error message.
+	Message string `json:"message"`
+}
+
+// writeStatus writes the status as a JSON error response.
+func writeStatus(w http.ResponseWriter, st *status.Status) {
+	writeError(w, HTTPStatusFromCode(st.Code()), st)
+}
+
+// writeError writes the status as a JSON error response with the HTTP status.
+func writeError(w http.ResponseWriter, httpStatus int, st *status.Status) {
+	data, _ := json.Marshal(&errorBody{Code: uint32(st.Code()), Message: st.Message()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	_, _ = w.Write(data)
+}
+
+// HTTPStatusFromCode returns the HTTP status code for the status code.
+//
+// Uses the same mapping as grpc-gateway.
+func HTTPStatusFromCode(code status.Code) int {
+	switch code {
+	case status.OK:
+		return http.StatusOK
+	case status.Canceled:
+		return 499
+	case status.InvalidArgument, status.OutOfRange, status.FailedPrecondition:
+		return http.StatusBadRequest
+	case status.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case status.NotFound:
+		return http.StatusNotFound
+	case status.AlreadyExists, status.Aborted:
+		return http.StatusConflict
+	case status.PermissionDenied:
+		return http.StatusForbidden
+	case status.Unauthenticated:
+		return http.StatusUnauthorized
+	case status.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case status.Unimplemented:
+		return http.StatusNotImplemented
+	case status.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// _ is a type assertion
+var _ http.Handler = ((*Gateway)(nil))
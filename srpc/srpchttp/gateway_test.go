@@ -0,0 +1,100 @@
+package srpchttp_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/srpchttp"
+	"github.com/aperturerobotics/starpc/srpc/status"
+)
+
+// metadataEchoServer echoes the "x-user" metadata if set.
+type metadataEchoServer struct {
+	*echo.EchoServer
+}
+
+// Echo echoes the message or the x-user metadata.
+func (s *metadataEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	if user := srpc.MetadataFromIncomingContext(ctx).Get("x-user"); user != "" {
+		return &echo.EchoMsg{Body: user}, nil
+	}
+	if msg.GetBody() == "missing" {
+		return nil, status.Error(status.NotFound, "no such body")
+	}
+	return msg, nil
+}
+
+func TestGateway(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &metadataEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux)))
+
+	gw := srpchttp.NewGateway(client, &srpchttp.Config{Prefix: "/api"})
+	if err := gw.AddRoute(http.MethodGet, "/v1/echo/{body}", echo.SRPCEchoerServiceID, "Echo"); err != nil {
+		t.Fatal(err.Error())
+	}
+	srv := httptest.NewServer(gw)
+	defer srv.Close()
+
+	do := func(method, path, body string, header http.Header) (int, string) {
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return resp.StatusCode, strings.Join(strings.Fields(string(data)), "")
+	}
+
+	// default route
+	if code, body := do(http.MethodPost, "/api/echo.Echoer/Echo", `{"body":"hello"}`, nil); code != 200 || body != `{"body":"hello"}` {
+		t.Fatalf("unexpected response %d: %s", code, body)
+	}
+	// templated route with path variable
+	if code, body := do(http.MethodGet, "/api/v1/echo/world", "", nil); code != 200 || body != `{"body":"world"}` {
+		t.Fatalf("unexpected response %d: %s", code, body)
+	}
+	// metadata header
+	hdr := http.Header{"Srpc-Metadata-X-User": {"alice"}}
+	if code, body := do(http.MethodPost, "/api/echo.Echoer/Echo", `{}`, hdr); code != 200 || body != `{"body":"alice"}` {
+		t.Fatalf("unexpected response %d: %s", code, body)
+	}
+	// status error
+	if code, body := do(http.MethodPost, "/api/echo.Echoer/Echo", `{"body":"missing"}`, nil); code != 404 || body != `{"code":5,"message":"nosuchbody"}` {
+		t.Fatalf("unexpected response %d: %s", code, body)
+	}
+	// unknown method
+	if code, _ := do(http.MethodPost, "/api/echo.Echoer/Other", `{}`, nil); code != http.StatusNotImplemented {
+		t.Fatalf("expected 501 got %d", code)
+	}
+	// wrong verb and unknown path
+	if code, _ := do(http.MethodGet, "/api/echo.Echoer/Echo", "", nil); code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 got %d", code)
+	}
+	if code, _ := do(http.MethodPost, "/other/echo.Echoer/Echo", `{}`, nil); code != http.StatusNotFound {
+		t.Fatalf("expected 404 got %d", code)
+	}
+	// invalid json
+	if code, _ := do(http.MethodPost, "/api/echo.Echoer/Echo", `{"body":`, nil); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 got %d", code)
+	}
+}
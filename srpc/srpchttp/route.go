@@ -0,0 +1,105 @@
+package srpchttp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// route maps a HTTP verb and path template to a method.
+type route struct {
+	// verb is the HTTP verb
+	verb string
+	// segs contains the template segments
+	segs []routeSeg
+	// service is the service ID
+	service string
+	// method is the method ID
+	method string
+}
+
+// routeSeg is a segment of a path template.
+type routeSeg struct {
+	// literal is the literal segment if field is empty
+	literal string
+	// field is the request field set by the variable
+	field string
+	// rest indicates the variable matches the rest of the path
+	rest bool
+}
+
+// parseRoute parses the path template of a route.
+func parseRoute(verb, template string) (*route, error) {
+	switch verb {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return nil, errors.Errorf("unsupported http verb: %q", verb)
+	}
+	if !strings.HasPrefix(template, "/") {
+		return nil, errors.Errorf("path template must start with /: %q", template)
+	}
+	rt := &route{verb: verb}
+	segs := splitPath(template)
+	for i, seg := range segs {
+		if !strings.HasPrefix(seg, "{") {
+			if strings.ContainsAny(seg, "{}") {
+				return nil, errors.Errorf("invalid path template segment: %q", seg)
+			}
+			rt.segs = append(rt.segs, routeSeg{literal: seg})
+			continue
+		}
+		if !strings.HasSuffix(seg, "}") {
+			return nil, errors.Errorf("invalid path template variable: %q", seg)
+		}
+		field, pattern := seg[1:len(seg)-1], "*"
+		if idx := strings.IndexByte(field, '='); idx != -1 {
+			field, pattern = field[:idx], field[idx+1:]
+		}
+		if field == "" || (pattern != "*" && pattern != "**") {
+			return nil, errors.Errorf("invalid path template variable: %q", seg)
+		}
+		rest := pattern == "**"
+		if rest && i != len(segs)-1 {
+			return nil, errors.Errorf("path template variable %q must be last", seg)
+		}
+		rt.segs = append(rt.segs, routeSeg{field: field, rest: rest})
+	}
+	return rt, nil
+}
+
+// match matches the path segments returning the fields set by the variables.
+func (r *route) match(segs []string) (map[string]string, bool) {
+	vars := make(map[string]string)
+	for i, seg := range r.segs {
+		if seg.rest {
+			if i >= len(segs) {
+				return nil, false
+			}
+			vars[seg.field] = strings.Join(segs[i:], "/")
+			return vars, true
+		}
+		if i >= len(segs) {
+			return nil, false
+		}
+		if seg.field == "" {
+			if segs[i] != seg.literal {
+				return nil, false
+			}
+			continue
+		}
+		if segs[i] == "" {
+			return nil, false
+		}
+		vars[seg.field] = segs[i]
+	}
+	if len(segs) != len(r.segs) {
+		return nil, false
+	}
+	return vars, true
+}
+
+// splitPath splits the path into segments without the leading slash.
+func splitPath(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "/"), "/")
+}
@@ -0,0 +1,101 @@
+package srpc
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// StatsHandler receives the stats of calls and connections.
+//
+// Use to build metrics and tracing for the Server and Client. Methods are
+// called from any goroutine and must not block.
+type StatsHandler interface {
+	// HandleRPCBegin is called when a call starts.
+	HandleRPCBegin(ctx context.Context, begin *RPCBegin)
+	// HandleRPCEnd is called once when a call started with HandleRPCBegin ends.
+	HandleRPCEnd(ctx context.Context, stats *StreamStats)
+	// HandleConn is called when the Server accepts and closes a muxed conn.
+	HandleConn(ctx context.Context, ev *ConnEvent)
+}
+
+// RPCBegin contains the info of a call passed to HandleRPCBegin.
+type RPCBegin struct {
+	// Client is set for calls made by a Client.
+	Client bool
+	// ServiceID is the service of the call.
+	ServiceID string
+	// MethodID is the method of the call.
+	MethodID string
+	// Start is the time the call started.
+	Start time.Time
+}
+
+// ConnEvent is a change of the state of a connection passed to HandleConn.
+type ConnEvent struct {
+	// Info is the connection info, nil if unknown.
+	Info *ConnInfo
+	// Closed is set when the connection was closed.
+	Closed bool
+	// Err is the error which closed the connection, if any.
+	Err error
+	// Reason classifies why the connection was closed.
+	// Set when Closed is set.
+	Reason CloseReason
+}
+
+// WithStatsHandler reports the stats of the calls to the StatsHandler.
+func WithStatsHandler(h StatsHandler) ServerOption {
+	return func(s *Server) {
+		s.statsHandler = h
+	}
+}
+
+// clientStatsCtxKey is the context key for the client StatsHandler.
+type clientStatsCtxKey struct{}
+
+// WithClientStatsHandler attaches a StatsHandler to the context.
+//
+// The stats of all calls made with the returned context are reported to the
+// handler. The bytes are the sizes of the encoded packets.
+func WithClientStatsHandler(ctx context.Context, h StatsHandler) context.Context {
+	return context.WithValue(ctx, clientStatsCtxKey{}, h)
+}
+
+// ContextClientStatsHandler returns the StatsHandler attached to the context or nil.
+func ContextClientStatsHandler(ctx context.Context) StatsHandler {
+	h, _ := ctx.Value(clientStatsCtxKey{}).(StatsHandler)
+	return h
+}
+
+// statsWriter counts the size of the packets written.
+type statsWriter struct {
+	Writer
+	// written is the number of bytes written
+	written *uint64
+}
+
+// WritePacket writes a packet to the remote.
+func (w *statsWriter) WritePacket(p *Packet) error {
+	atomic.AddUint64(w.written, uint64(p.SizeVT()))
+	return w.Writer.WritePacket(p)
+}
+
+// WritePackets writes the packets to the remote in order.
+func (w *statsWriter) WritePackets(pkts []*Packet) error {
+	for _, p := range pkts {
+		atomic.AddUint64(w.written, uint64(p.SizeVT()))
+	}
+	return writePackets(w.Writer, pkts)
+}
+
+// Reset aborts the stream.
+func (w *statsWriter) Reset() error {
+	return resetWriter(w.Writer)
+}
+
+// _ is a type assertion
+var (
+	_ BatchWriter = ((*statsWriter)(nil))
+	_ ResetWriter = ((*statsWriter)(nil))
+)
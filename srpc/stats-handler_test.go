@@ -0,0 +1,100 @@
+package srpc_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// recordingStatsHandler records the stats of the calls.
+type recordingStatsHandler struct {
+	mtx    sync.Mutex
+	begins []*srpc.RPCBegin
+	ends   []*srpc.StreamStats
+}
+
+func (h *recordingStatsHandler) HandleRPCBegin(ctx context.Context, begin *srpc.RPCBegin) {
+	h.mtx.Lock()
+	h.begins = append(h.begins, begin)
+	h.mtx.Unlock()
+}
+
+func (h *recordingStatsHandler) HandleRPCEnd(ctx context.Context, stats *srpc.StreamStats) {
+	h.mtx.Lock()
+	h.ends = append(h.ends, stats)
+	h.mtx.Unlock()
+}
+
+func (h *recordingStatsHandler) HandleConn(ctx context.Context, ev *srpc.ConnEvent) {}
+
+// get returns the recorded stats.
+func (h *recordingStatsHandler) get() ([]*srpc.RPCBegin, []*srpc.StreamStats) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.begins, h.ends
+}
+
+func TestStatsHandler_Server(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, &errorEchoServer{EchoServer: echo.NewEchoServer(mux)}); err != nil {
+		t.Fatal(err.Error())
+	}
+	handler := &recordingStatsHandler{}
+	server := srpc.NewServer(mux, srpc.WithStatsHandler(handler))
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(server)))
+	if _, err := client.Echo(context.Background(), &echo.EchoMsg{Body: "not found"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// the server reports the end after the result is sent.
+	var begins []*srpc.RPCBegin
+	var ends []*srpc.StreamStats
+	for i := 0; i < 100; i++ {
+		if begins, ends = handler.get(); len(ends) != 0 {
+			break
+		}
+		<-time.After(10 * time.Millisecond)
+	}
+	if len(begins) != 1 || begins[0].Client || begins[0].MethodID != "Echo" || begins[0].Start.IsZero() {
+		t.Fatalf("unexpected begin %v", begins)
+	}
+	if len(ends) != 1 {
+		t.Fatalf("expected 1 end got %v", ends)
+	}
+	end := ends[0]
+	if end.ServiceID != echo.SRPCEchoerServiceID || end.MethodID != "Echo" || end.Err == nil || end.Err.Error() != "not found" {
+		t.Fatalf("unexpected end %v", end)
+	}
+	if end.BytesRead == 0 || end.BytesWritten == 0 {
+		t.Fatalf("expected byte counts got %v", end)
+	}
+}
+
+func TestStatsHandler_Client(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	handler := &recordingStatsHandler{}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+	ctx := srpc.WithClientStatsHandler(context.Background(), handler)
+	if _, err := client.Echo(ctx, &echo.EchoMsg{Body: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	begins, ends := handler.get()
+	if len(begins) != 1 || !begins[0].Client || begins[0].MethodID != "Echo" {
+		t.Fatalf("unexpected begin %v", begins)
+	}
+	if len(ends) != 1 {
+		t.Fatalf("expected 1 end got %v", ends)
+	}
+	end := ends[0]
+	if !end.Client || end.MethodID != "Echo" || end.Err != nil || end.BytesRead == 0 || end.BytesWritten == 0 {
+		t.Fatalf("unexpected end %v", end)
+	}
+}
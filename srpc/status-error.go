@@ -0,0 +1,57 @@
+package srpc
+
+import "github.com/pkg/errors"
+
+// StatusError is an error with a structured Status.
+//
+// Returned by the client if the server sent a Status with the error.
+// Use the status package to construct and inspect status errors.
+type StatusError struct {
+	// Status is the status of the error.
+	Status *Status
+}
+
+// Error returns the status message.
+func (e *StatusError) Error() string {
+	return e.Status.GetMessage()
+}
+
+// SRPCStatus returns the status of the error.
+func (e *StatusError) SRPCStatus() *Status {
+	return e.Status
+}
+
+// StatusProvider is an error with a structured Status.
+type StatusProvider interface {
+	// SRPCStatus returns the status of the error.
+	SRPCStatus() *Status
+}
+
+// StatusFromError returns the Status of the error or any wrapped error.
+//
+// Returns nil if the error does not have a Status.
+func StatusFromError(err error) *Status {
+	var sp StatusProvider
+	if err == nil || !errors.As(err, &sp) {
+		return nil
+	}
+	return sp.SRPCStatus()
+}
+
+// newErrorStatus returns the Status to send with the error, if any.
+//
+// The message is set to the full error string.
+func newErrorStatus(err error) *Status {
+	st := StatusFromError(err)
+	if st == nil {
+		return nil
+	}
+	return &Status{
+		Code:    st.GetCode(),
+		Message: err.Error(),
+		Details: st.GetDetails(),
+	}
+}
+
+// _ is a type assertion
+var _ StatusProvider = ((*StatusError)(nil))
@@ -0,0 +1,74 @@
+package status
+
+import "strconv"
+
+// Code is a status code.
+//
+// The codes match the gRPC status codes.
+type Code uint32
+
+const (
+	// OK indicates the call succeeded.
+	OK Code = 0
+	// Canceled indicates the call was canceled.
+	Canceled Code = 1
+	// Unknown indicates an unknown error.
+	Unknown Code = 2
+	// InvalidArgument indicates the request was invalid.
+	InvalidArgument Code = 3
+	// DeadlineExceeded indicates the deadline expired before the call completed.
+	DeadlineExceeded Code = 4
+	// NotFound indicates the requested entity was not found.
+	NotFound Code = 5
+	// AlreadyExists indicates the entity to create already exists.
+	AlreadyExists Code = 6
+	// PermissionDenied indicates the caller is not allowed to make the call.
+	PermissionDenied Code = 7
+	// ResourceExhausted indicates a resource or quota was exhausted.
+	ResourceExhausted Code = 8
+	// FailedPrecondition indicates the system is not in the required state.
+	FailedPrecondition Code = 9
+	// Aborted indicates the call was aborted, usually due to a conflict.
+	Aborted Code = 10
+	// OutOfRange indicates the request was past the valid range.
+	OutOfRange Code = 11
+	// Unimplemented indicates the method is not implemented.
+	Unimplemented Code = 12
+	// Internal indicates an internal error.
+	Internal Code = 13
+	// Unavailable indicates the service is unavailable, the call can be retried.
+	Unavailable Code = 14
+	// DataLoss indicates unrecoverable data loss or corruption.
+	DataLoss Code = 15
+	// Unauthenticated indicates the caller does not have valid credentials.
+	Unauthenticated Code = 16
+)
+
+// codeNames contains the names of the codes.
+var codeNames = [...]string{
+	OK:                 "OK",
+	Canceled:           "Canceled",
+	Unknown:            "Unknown",
+	InvalidArgument:    "InvalidArgument",
+	DeadlineExceeded:   "DeadlineExceeded",
+	NotFound:           "NotFound",
+	AlreadyExists:      "AlreadyExists",
+	PermissionDenied:   "PermissionDenied",
+	ResourceExhausted:  "ResourceExhausted",
+	FailedPrecondition: "FailedPrecondition",
+	Aborted:            "Aborted",
+	OutOfRange:         "OutOfRange",
+	Unimplemented:      "Unimplemented",
+	Internal:           "Internal",
+	Unavailable:        "Unavailable",
+	DataLoss:           "DataLoss",
+	Unauthenticated:    "Unauthenticated",
+}
+
+// String returns the name of the code.
+func (c Code) String() string {
+	if int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
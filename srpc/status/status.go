@@ -0,0 +1,185 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// Status is the structured status of a call.
+type Status struct {
+	// s is the status message
+	s *srpc.Status
+}
+
+// New constructs a Status with the code and message.
+func New(code Code, msg string) *Status {
+	return &Status{s: &srpc.Status{Code: uint32(code), Message: msg}}
+}
+
+// Newf constructs a Status with the code and formatted message.
+func Newf(code Code, format string, args ...interface{}) *Status {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// FromProto constructs a Status from the status message.
+func FromProto(s *srpc.Status) *Status {
+	return &Status{s: s}
+}
+
+// Error returns an error with the code and message.
+func Error(code Code, msg string) error {
+	return New(code, msg).Err()
+}
+
+// Errorf returns an error with the code and formatted message.
+func Errorf(code Code, format string, args ...interface{}) error {
+	return Newf(code, format, args...).Err()
+}
+
+// FromError returns the Status of the error.
+//
+// Returns true if the error has a Status or is a known srpc error, which is
+// mapped to the matching code. Otherwise returns a Status with the Unknown
+// code and the error message. Returns nil, true if err is nil.
+func FromError(err error) (*Status, bool) {
+	if err == nil {
+		return nil, true
+	}
+	if st := srpc.StatusFromError(err); st != nil {
+		return FromProto(st), true
+	}
+	if code, ok := codeFromError(err); ok {
+		return New(code, err.Error()), true
+	}
+	return New(Unknown, err.Error()), false
+}
+
+// Convert returns the Status of the error, see FromError.
+func Convert(err error) *Status {
+	st, _ := FromError(err)
+	return st
+}
+
+// codeFromError returns the code for known errors.
+//
+// Errors received from the remote are compared by the error string.
+func codeFromError(err error) (Code, bool) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Canceled, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded, true
+	}
+	errStr := err.Error()
+	for code, knownErrs := range knownErrors {
+		for _, knownErr := range knownErrs {
+			if errors.Is(err, knownErr) || errStr == knownErr.Error() {
+				return code, true
+			}
+		}
+	}
+	return Unknown, false
+}
+
+// knownErrors maps codes to the srpc errors with the code.
+var knownErrors = map[Code][]error{
+	Canceled:          {srpc.ErrCanceledByPeer},
+	Unimplemented:     {srpc.ErrUnimplemented},
+	ResourceExhausted: {srpc.ErrTooManyCalls, srpc.ErrOverloaded},
+	Unavailable:       {srpc.ErrUnavailable, srpc.ErrDraining},
+}
+
+// Code returns the status code.
+//
+// Returns OK if the Status is nil.
+func (s *Status) Code() Code {
+	if s == nil {
+		return OK
+	}
+	return Code(s.s.GetCode())
+}
+
+// Message returns the status message.
+func (s *Status) Message() string {
+	if s == nil {
+		return ""
+	}
+	return s.s.GetMessage()
+}
+
+// Details returns the serialized detail messages.
+func (s *Status) Details() []*srpc.StatusDetail {
+	if s == nil {
+		return nil
+	}
+	return s.s.GetDetails()
+}
+
+// Proto returns the status message.
+func (s *Status) Proto() *srpc.Status {
+	if s == nil {
+		return nil
+	}
+	return s.s
+}
+
+// Err returns an error with the Status.
+//
+// Returns nil if the code is OK.
+func (s *Status) Err() error {
+	if s.Code() == OK {
+		return nil
+	}
+	return &srpc.StatusError{Status: s.s}
+}
+
+// WithDetails returns a copy of the Status with the detail messages appended.
+func (s *Status) WithDetails(details ...srpc.Message) (*Status, error) {
+	if s.Code() == OK {
+		return nil, errors.New("no error details for status with code OK")
+	}
+	out := &srpc.Status{
+		Code:    s.s.GetCode(),
+		Message: s.s.GetMessage(),
+		Details: append([]*srpc.StatusDetail(nil), s.s.GetDetails()...),
+	}
+	for _, detail := range details {
+		data, err := detail.MarshalVT()
+		if err != nil {
+			return nil, err
+		}
+		out.Details = append(out.Details, &srpc.StatusDetail{
+			TypeUrl: TypeURL(detail),
+			Value:   data,
+		})
+	}
+	return FromProto(out), nil
+}
+
+// typeURLPrefix is the prefix of the type url of proto messages.
+const typeURLPrefix = "type.googleapis.com/"
+
+// TypeURL returns the type url identifying the message type.
+//
+// Uses the full name of proto messages and the Go type name otherwise.
+func TypeURL(msg srpc.Message) string {
+	if pmsg, ok := msg.(proto.Message); ok {
+		return typeURLPrefix + string(pmsg.ProtoReflect().Descriptor().FullName())
+	}
+	return reflect.TypeOf(msg).String()
+}
+
+// UnmarshalDetail unmarshals the detail into msg if the types match.
+//
+// Returns false if the detail contains a different message type.
+func UnmarshalDetail(detail *srpc.StatusDetail, msg srpc.Message) (bool, error) {
+	if detail.GetTypeUrl() != TypeURL(msg) {
+		return false, nil
+	}
+	return true, msg.UnmarshalVT(detail.GetValue())
+}
@@ -0,0 +1,61 @@
+package status_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+	"github.com/aperturerobotics/starpc/srpc/status"
+	"github.com/pkg/errors"
+)
+
+// notFoundEchoServer returns a NotFound status error with a detail.
+type notFoundEchoServer struct {
+	*echo.EchoServer
+}
+
+func TestStatus(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &notFoundEchoServer{EchoServer: echo.NewEchoServer(mux)}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	_, err := client.Echo(context.Background(), &echo.EchoMsg{Body: "key-1"})
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("expected status error got %v", err)
+	}
+	if st.Code() != status.NotFound || st.Message() != "lookup: no such key" {
+		t.Fatalf("unexpected status %v: %s", st.Code(), st.Message())
+	}
+	if len(st.Details()) != 1 {
+		t.Fatalf("expected 1 detail got %d", len(st.Details()))
+	}
+	detail := &echo.EchoMsg{}
+	if ok, err := status.UnmarshalDetail(st.Details()[0], detail); err != nil || !ok {
+		t.Fatalf("unmarshal detail: %v %v", ok, err)
+	}
+	if detail.GetBody() != "key-1" {
+		t.Fatalf("unexpected detail body %q", detail.GetBody())
+	}
+
+	// built-in errors map to codes
+	if code := status.Convert(srpc.ErrUnimplemented).Code(); code != status.Unimplemented {
+		t.Fatalf("expected Unimplemented got %v", code)
+	}
+	if st, ok := status.FromError(errors.New("other")); ok || st.Code() != status.Unknown {
+		t.Fatalf("expected Unknown got %v", st.Code())
+	}
+}
+
+// Echo returns the NotFound status with the message as a detail.
+func (s *notFoundEchoServer) Echo(ctx context.Context, msg *echo.EchoMsg) (*echo.EchoMsg, error) {
+	st, err := status.New(status.NotFound, "no such key").WithDetails(msg)
+	if err != nil {
+		return nil, err
+	}
+	return nil, errors.Wrap(st.Err(), "lookup")
+}
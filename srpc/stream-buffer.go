@@ -0,0 +1,121 @@
+package srpc
+
+import (
+	"sync"
+	"time"
+)
+
+// BufferedStream is a Stream which buffers the sent messages.
+//
+// The buffered messages are sent together with Flush, once they reach the max
+// size, or after the max delay. Reduces the number of writes for handlers
+// which send many small messages in a tight loop. Flush before returning
+// from the handler: messages buffered after the call completed are dropped.
+type BufferedStream struct {
+	Stream
+	// maxSize is the buffered size in bytes which triggers a flush.
+	maxSize int
+	// maxDelay is the max duration messages are buffered, if set.
+	maxDelay time.Duration
+	// mtx guards below fields
+	mtx sync.Mutex
+	// buf contains the buffered messages
+	buf []Message
+	// size is the size of the buffered messages
+	size int
+	// timer flushes the messages after maxDelay, if set.
+	timer *time.Timer
+	// err is the error from a delayed flush, if any.
+	err error
+}
+
+// NewBufferedStream constructs a new BufferedStream.
+//
+// maxSize is the buffered size in bytes which triggers a flush. maxDelay is
+// the max duration messages are buffered, zero to wait for Flush.
+func NewBufferedStream(strm Stream, maxSize int, maxDelay time.Duration) *BufferedStream {
+	return &BufferedStream{Stream: strm, maxSize: maxSize, maxDelay: maxDelay}
+}
+
+// MsgSend buffers the message.
+//
+// Returns the error of a previous delayed flush, if any.
+func (s *BufferedStream) MsgSend(msg Message) error {
+	data, err := MarshalMessage(msg)
+	if err != nil {
+		return err
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.buf = append(s.buf, NewRawMessage(data))
+	s.size += len(data)
+	if s.size >= s.maxSize {
+		return s.flushLocked()
+	}
+	if s.maxDelay > 0 && s.timer == nil {
+		s.timer = time.AfterFunc(s.maxDelay, s.flushDelayed)
+	}
+	return nil
+}
+
+// Flush sends the buffered messages.
+func (s *BufferedStream) Flush() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	return s.flushLocked()
+}
+
+// CloseSend flushes the buffered messages and signals to the remote that we
+// will no longer send any messages.
+func (s *BufferedStream) CloseSend() error {
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	return s.Stream.CloseSend()
+}
+
+// Close flushes the buffered messages and closes the stream.
+func (s *BufferedStream) Close() error {
+	_ = s.Flush()
+	return s.Stream.Close()
+}
+
+// flushDelayed flushes the buffered messages after maxDelay.
+func (s *BufferedStream) flushDelayed() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if err := s.flushLocked(); err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+// flushLocked sends the buffered messages while mtx is locked.
+func (s *BufferedStream) flushLocked() error {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if len(s.buf) == 0 {
+		return nil
+	}
+	msgs := s.buf
+	s.buf, s.size = nil, 0
+	if bs, ok := s.Stream.(BatchSender); ok {
+		return bs.MsgSendBatch(msgs)
+	}
+	for _, msg := range msgs {
+		if err := s.Stream.MsgSend(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// _ is a type assertion
+var _ Stream = ((*BufferedStream)(nil))
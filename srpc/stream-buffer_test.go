@@ -0,0 +1,110 @@
+package srpc_test
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// countingBuffer is a ReadWriteCloser counting the writes.
+type countingBuffer struct {
+	mtx    sync.Mutex
+	buf    bytes.Buffer
+	writes int
+}
+
+func TestBufferedStream(t *testing.T) {
+	rwc := &countingBuffer{}
+	strm := srpc.NewMsgStream(context.Background(), srpc.NewPacketReadWriter(rwc), nil)
+	bs := srpc.NewBufferedStream(strm, 1024, 0)
+
+	// expect the messages to be written with a single write on Flush
+	for i := 0; i < 10; i++ {
+		if err := bs.MsgSend(&echo.EchoMsg{Body: strconv.Itoa(i)}); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if n := rwc.getWrites(); n != 0 {
+		t.Fatalf("expected no writes before flush got %d", n)
+	}
+	if err := bs.Flush(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if n := rwc.getWrites(); n != 1 {
+		t.Fatalf("expected 1 write got %d", n)
+	}
+	var bodies []string
+	err := srpc.NewPacketReadWriter(rwc).ReadToHandler(func(pkt *srpc.Packet) error {
+		msg := &echo.EchoMsg{}
+		if err := msg.UnmarshalVT(pkt.GetCallData().GetData()); err != nil {
+			return err
+		}
+		bodies = append(bodies, msg.GetBody())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(bodies) != 10 || bodies[0] != "0" || bodies[9] != "9" {
+		t.Fatalf("unexpected messages: %v", bodies)
+	}
+
+	// expect the messages to be flushed once they reach the max size
+	body := string(make([]byte, 600))
+	for i := 0; i < 2; i++ {
+		if err := bs.MsgSend(&echo.EchoMsg{Body: body}); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if n := rwc.getWrites(); n != 2 {
+		t.Fatalf("expected 2 writes got %d", n)
+	}
+}
+
+func TestBufferedStream_MaxDelay(t *testing.T) {
+	rwc := &countingBuffer{}
+	strm := srpc.NewMsgStream(context.Background(), srpc.NewPacketReadWriter(rwc), nil)
+	bs := srpc.NewBufferedStream(strm, 1024, time.Millisecond*10)
+	if err := bs.MsgSend(&echo.EchoMsg{Body: "hello"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 0; i < 100 && rwc.getWrites() == 0; i++ {
+		<-time.After(time.Millisecond * 5)
+	}
+	if n := rwc.getWrites(); n != 1 {
+		t.Fatalf("expected 1 write after the max delay got %d", n)
+	}
+}
+
+// Read reads from the buffer.
+func (b *countingBuffer) Read(p []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.Read(p)
+}
+
+// Write writes to the buffer.
+func (b *countingBuffer) Write(p []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.writes++
+	return b.buf.Write(p)
+}
+
+// Close does nothing.
+func (b *countingBuffer) Close() error {
+	return nil
+}
+
+// getWrites returns the number of writes.
+func (b *countingBuffer) getWrites() int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.writes
+}
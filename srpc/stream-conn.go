@@ -0,0 +1,296 @@
+package srpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// streamConnMaxWrite is the max size of the message sent by a StreamConn write.
+const streamConnMaxWrite = 64 * 1024
+
+// StreamConnNetwork is the network name of the default StreamConn addresses.
+const StreamConnNetwork = "srpc"
+
+// StreamConn is a net.Conn over a Stream.
+//
+// The data is sent as RawMessage payloads: the remote must also use a
+// StreamConn or read the raw payloads. The read deadline interrupts a blocked
+// Read. The write deadline is mapped to the context of MsgSendCtx, if the
+// stream implements CtxSender.
+type StreamConn struct {
+	// strm is the stream
+	strm Stream
+	// local is the local address
+	local net.Addr
+	// remote is the remote address
+	remote net.Addr
+	// closed is closed when Close is called
+	closed chan struct{}
+	// closeOnce guards closing closed
+	closeOnce sync.Once
+	// recvOnce starts the receive pump
+	recvOnce sync.Once
+	// recvCh receives the payloads from the receive pump.
+	// closed after recvErr is set
+	recvCh chan []byte
+	// recvErr is the error which ended the receive pump
+	recvErr error
+	// readMtx guards buf
+	readMtx sync.Mutex
+	// buf contains the unread data of the last payload
+	buf []byte
+	// writeMtx serializes the writes
+	writeMtx sync.Mutex
+	// mtx guards below fields
+	mtx sync.Mutex
+	// readDeadline is the read deadline, zero if unset
+	readDeadline time.Time
+	// readDeadlineCh is closed and replaced when the read deadline changes
+	readDeadlineCh chan struct{}
+	// writeDeadline is the write deadline, zero if unset
+	writeDeadline time.Time
+}
+
+// NewStreamConn constructs a net.Conn over a Stream.
+//
+// local and remote are returned by LocalAddr and RemoteAddr. If nil, uses an
+// address with the StreamConnNetwork network.
+func NewStreamConn(strm Stream, local, remote net.Addr) *StreamConn {
+	if local == nil {
+		local = streamConnAddr{}
+	}
+	if remote == nil {
+		remote = streamConnAddr{}
+	}
+	return &StreamConn{
+		strm:           strm,
+		local:          local,
+		remote:         remote,
+		closed:         make(chan struct{}),
+		recvCh:         make(chan []byte),
+		readDeadlineCh: make(chan struct{}),
+	}
+}
+
+// Read reads data from the stream.
+//
+// Returns os.ErrDeadlineExceeded if the read deadline expired.
+func (c *StreamConn) Read(p []byte) (int, error) {
+	c.readMtx.Lock()
+	defer c.readMtx.Unlock()
+	for len(c.buf) == 0 {
+		data, err := c.recv()
+		if err != nil {
+			return 0, err
+		}
+		c.buf = data
+	}
+	n := copy(p, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
+
+// recv waits for the next payload until the read deadline.
+func (c *StreamConn) recv() ([]byte, error) {
+	c.recvOnce.Do(func() {
+		go c.recvPump()
+	})
+	for {
+		select {
+		case <-c.closed:
+			return nil, net.ErrClosed
+		default:
+		}
+
+		c.mtx.Lock()
+		deadline, deadlineCh := c.readDeadline, c.readDeadlineCh
+		c.mtx.Unlock()
+
+		var timer *time.Timer
+		var timerCh <-chan time.Time
+		if !deadline.IsZero() {
+			d := time.Until(deadline)
+			if d <= 0 {
+				return nil, os.ErrDeadlineExceeded
+			}
+			timer = time.NewTimer(d)
+			timerCh = timer.C
+		}
+
+		var data []byte
+		var err error
+		var done bool
+		select {
+		case <-c.closed:
+			data, err, done = nil, net.ErrClosed, true
+		case recv, ok := <-c.recvCh:
+			data, done = recv, true
+			if !ok {
+				err = c.recvErr
+			}
+		case <-timerCh:
+			err, done = os.ErrDeadlineExceeded, true
+		case <-deadlineCh:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if done {
+			return data, err
+		}
+	}
+}
+
+// recvPump receives the payloads from the stream until it ends.
+func (c *StreamConn) recvPump() {
+	for {
+		msg := NewRawMessage(nil)
+		if err := c.strm.MsgRecv(msg); err != nil {
+			c.recvErr = err
+			close(c.recvCh)
+			return
+		}
+		if len(msg.GetData()) == 0 {
+			continue
+		}
+		select {
+		case c.recvCh <- msg.GetData():
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// Write writes data to the stream.
+//
+// Returns os.ErrDeadlineExceeded if the write deadline expired.
+func (c *StreamConn) Write(p []byte) (int, error) {
+	select {
+	case <-c.closed:
+		return 0, net.ErrClosed
+	default:
+	}
+
+	c.mtx.Lock()
+	deadline := c.writeDeadline
+	c.mtx.Unlock()
+	ctx := c.strm.Context()
+	if !deadline.IsZero() {
+		if !time.Now().Before(deadline) {
+			return 0, os.ErrDeadlineExceeded
+		}
+		var ctxCancel context.CancelFunc
+		ctx, ctxCancel = context.WithDeadline(ctx, deadline)
+		defer ctxCancel()
+	}
+
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+	var n int
+	for len(p) != 0 {
+		chunk := p
+		if len(chunk) > streamConnMaxWrite {
+			chunk = chunk[:streamConnMaxWrite]
+		}
+		if err := c.send(ctx, chunk, !deadline.IsZero()); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
+
+// send sends the chunk with the write deadline of ctx, if any.
+func (c *StreamConn) send(ctx context.Context, chunk []byte, hasDeadline bool) error {
+	sender, ok := c.strm.(CtxSender)
+	if !hasDeadline || !ok {
+		return c.strm.MsgSend(NewRawMessage(chunk))
+	}
+	// the write may complete after a timeout: do not retain the caller buffer
+	data := make([]byte, len(chunk))
+	copy(data, chunk)
+	err := sender.MsgSendCtx(ctx, NewRawMessage(data))
+	if err == ErrSendTimeout {
+		return os.ErrDeadlineExceeded
+	}
+	return err
+}
+
+// CloseWrite signals to the remote that we will no longer write any data.
+//
+// The remote reads io.EOF while data can still be read.
+func (c *StreamConn) CloseWrite() error {
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+	return c.strm.CloseSend()
+}
+
+// Close closes the stream.
+//
+// Any blocked Read or Write fails with net.ErrClosed.
+func (c *StreamConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = c.strm.Close()
+	})
+	return err
+}
+
+// LocalAddr returns the local address.
+func (c *StreamConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+// RemoteAddr returns the remote address.
+func (c *StreamConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// SetDeadline sets the read and write deadlines.
+func (c *StreamConn) SetDeadline(t time.Time) error {
+	_ = c.SetReadDeadline(t)
+	return c.SetWriteDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline.
+//
+// A zero value disables the deadline. Applies to a blocked Read.
+func (c *StreamConn) SetReadDeadline(t time.Time) error {
+	c.mtx.Lock()
+	c.readDeadline = t
+	close(c.readDeadlineCh)
+	c.readDeadlineCh = make(chan struct{})
+	c.mtx.Unlock()
+	return nil
+}
+
+// SetWriteDeadline sets the write deadline.
+//
+// A zero value disables the deadline. Applies to the following writes.
+func (c *StreamConn) SetWriteDeadline(t time.Time) error {
+	c.mtx.Lock()
+	c.writeDeadline = t
+	c.mtx.Unlock()
+	return nil
+}
+
+// streamConnAddr is the default address of a StreamConn.
+type streamConnAddr struct{}
+
+// Network returns the network name.
+func (streamConnAddr) Network() string {
+	return StreamConnNetwork
+}
+
+// String returns the address.
+func (streamConnAddr) String() string {
+	return StreamConnNetwork
+}
+
+// _ is a type assertion
+var _ net.Conn = ((*StreamConn)(nil))
@@ -0,0 +1,119 @@
+package srpc_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// lineConnHandler serves a line protocol over a StreamConn.
+//
+// Responds to each line with the line in upper case.
+type lineConnHandler struct{}
+
+// GetServiceID returns the ID of the service.
+func (h *lineConnHandler) GetServiceID() string { return "test.Line" }
+
+// GetMethodIDs returns the list of methods for the service.
+func (h *lineConnHandler) GetMethodIDs() []string { return []string{"Conn"} }
+
+// InvokeMethod serves the line protocol until the client closes the write side.
+func (h *lineConnHandler) InvokeMethod(serviceID, methodID string, strm srpc.Stream) (bool, error) {
+	if methodID != "Conn" {
+		return false, nil
+	}
+	conn := srpc.NewStreamConn(strm, nil, nil)
+	rd := bufio.NewReader(conn)
+	for {
+		line, err := rd.ReadString('\n')
+		if err == io.EOF {
+			return true, conn.CloseWrite()
+		}
+		if err != nil {
+			return true, err
+		}
+		if _, err := conn.Write([]byte(strings.ToUpper(line))); err != nil {
+			return true, err
+		}
+	}
+}
+
+func TestStreamConn(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := mux.Register(&lineConnHandler{}); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux)))
+	strm, err := client.NewStream(context.Background(), "test.Line", "Conn", nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	remote := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 25}
+	var conn net.Conn = srpc.NewStreamConn(strm, nil, remote)
+	defer conn.Close()
+	if conn.RemoteAddr() != remote || conn.LocalAddr().Network() != srpc.StreamConnNetwork {
+		t.Fatalf("unexpected addrs %v %v", conn.LocalAddr(), conn.RemoteAddr())
+	}
+
+	// expect a blocked read to fail with a timeout at the read deadline
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(make([]byte, 1))
+		errCh <- err
+	}()
+	<-time.After(time.Millisecond * 10)
+	if err := conn.SetReadDeadline(time.Now().Add(time.Millisecond * 10)); err != nil {
+		t.Fatal(err.Error())
+	}
+	err = <-errCh
+	var netErr net.Error
+	if !errors.Is(err, os.ErrDeadlineExceeded) || !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expected timeout got %v", err)
+	}
+
+	// expect the conn to remain usable after clearing the deadline
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		t.Fatal(err.Error())
+	}
+	rd := bufio.NewReader(conn)
+	for _, line := range []string{"helo example.com\n", strings.Repeat("x", 100*1024) + "\n"} {
+		if _, err := conn.Write([]byte(line)); err != nil {
+			t.Fatal(err.Error())
+		}
+		resp, err := rd.ReadString('\n')
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resp != strings.ToUpper(line) {
+			t.Fatalf("unexpected response of len %d", len(resp))
+		}
+	}
+
+	// expect the remote to close after the write side is closed
+	if err := conn.(*srpc.StreamConn).CloseWrite(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := rd.ReadString('\n'); err != io.EOF {
+		t.Fatalf("expected EOF got %v", err)
+	}
+
+	// expect writes with an expired deadline to fail
+	_ = conn.SetWriteDeadline(time.Now().Add(-time.Second))
+	if _, err := conn.Write([]byte("late\n")); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded got %v", err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected closed got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package srpc
+
+import "context"
+
+// streamDetacherCtxKey is the context key for the streamDetacher.
+type streamDetacherCtxKey struct{}
+
+// streamDetacher detaches a server-side stream from its handler.
+type streamDetacher interface {
+	// detach marks the stream as detached.
+	// returns the func to complete the call or false if already detached.
+	detach() (func(err error), bool)
+}
+
+// DetachedStream is a server-side Stream detached from its handler.
+//
+// Returning from the handler does not complete the call: Finish must be
+// called to complete it instead.
+type DetachedStream struct {
+	Stream
+	// finish completes the call
+	finish func(err error)
+}
+
+// DetachStream detaches a server-side stream from the handler.
+//
+// After this call the stream can be handed to a long-lived manager and the
+// handler can return nil without completing the call. If the handler returns
+// an error the call is completed with that error as usual.
+//
+// Returns ErrStreamNotDetachable if strm is not a server-side stream or was
+// already detached.
+func DetachStream(strm Stream) (*DetachedStream, error) {
+	detacher, _ := strm.Context().Value(streamDetacherCtxKey{}).(streamDetacher)
+	if detacher == nil {
+		return nil, ErrStreamNotDetachable
+	}
+	finish, ok := detacher.detach()
+	if !ok {
+		return nil, ErrStreamNotDetachable
+	}
+	return &DetachedStream{Stream: strm, finish: finish}, nil
+}
+
+// withStreamDetacher attaches the streamDetacher to the context.
+func withStreamDetacher(ctx context.Context, detacher streamDetacher) context.Context {
+	return context.WithValue(ctx, streamDetacherCtxKey{}, detacher)
+}
+
+// Finish completes the call with an optional error.
+//
+// Only the first call has any effect.
+func (s *DetachedStream) Finish(err error) {
+	s.finish(err)
+}
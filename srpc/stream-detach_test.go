@@ -0,0 +1,83 @@
+package srpc_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// detachStreamEchoServer detaches the bidi stream and hands it to the test.
+type detachStreamEchoServer struct {
+	*echo.EchoServer
+	// streamCh receives the detached stream.
+	streamCh chan *srpc.DetachedStream
+}
+
+func TestDetachStream(t *testing.T) {
+	mux := srpc.NewMux()
+	echoServer := &detachStreamEchoServer{
+		EchoServer: echo.NewEchoServer(mux),
+		streamCh:   make(chan *srpc.DetachedStream, 1),
+	}
+	if err := echo.SRPCRegisterEchoer(mux, echoServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := echo.NewSRPCEchoerClient(srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux))))
+
+	ctx := context.Background()
+	strm, err := client.EchoBidiStream(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	msg := &echo.EchoMsg{Body: "hello world"}
+	if err := strm.Send(msg); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var detached *srpc.DetachedStream
+	select {
+	case detached = <-echoServer.streamCh:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the detached stream")
+	}
+	if _, err := srpc.DetachStream(detached); err != srpc.ErrStreamNotDetachable {
+		t.Fatalf("expected second detach to return %v got %v", srpc.ErrStreamNotDetachable, err)
+	}
+
+	// expect the stream to remain usable after the handler returned
+	<-time.After(time.Millisecond * 50)
+	recvMsg := &echo.EchoMsg{}
+	if err := detached.MsgRecv(recvMsg); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := detached.MsgSend(recvMsg); err != nil {
+		t.Fatal(err.Error())
+	}
+	out, err := strm.Recv()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if out.GetBody() != msg.GetBody() {
+		t.Fatalf("expected %q got %q", msg.GetBody(), out.GetBody())
+	}
+
+	// expect Finish to complete the call
+	detached.Finish(nil)
+	if _, err := strm.Recv(); err != io.EOF {
+		t.Fatalf("expected %v got %v", io.EOF, err)
+	}
+}
+
+// EchoBidiStream detaches the stream and returns immediately.
+func (s *detachStreamEchoServer) EchoBidiStream(strm echo.SRPCEchoer_EchoBidiStreamStream) error {
+	detached, err := srpc.DetachStream(strm)
+	if err != nil {
+		return err
+	}
+	s.streamCh <- detached
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !go1.23
+
+package srpc
+
+// StreamRecvIter is a stream which can be received from with range-over-func.
+//
+// Requires Go 1.23 or later: empty with older versions.
+type StreamRecvIter[T any] interface{}
+
+// StreamSendIter is a stream which can send the messages of an iterator.
+//
+// Requires Go 1.23 or later: empty with older versions.
+type StreamSendIter[T any] interface{}
@@ -0,0 +1,58 @@
+//go:build go1.23
+
+package srpc
+
+import (
+	"io"
+	"iter"
+)
+
+// StreamRecvIter is a stream which can be received from with range-over-func.
+//
+// Implemented by the generated stream clients.
+type StreamRecvIter[T any] interface {
+	// All returns an iterator over the received messages.
+	All() iter.Seq2[T, error]
+}
+
+// StreamSendIter is a stream which can send the messages of an iterator.
+//
+// Implemented by the generated stream clients.
+type StreamSendIter[T any] interface {
+	// SendAll sends all messages from the iterator.
+	SendAll(seq iter.Seq[T]) error
+}
+
+// RecvAll returns an iterator over the messages received from the stream.
+//
+// Stops when the stream ends. Yields the error which ended the stream as the
+// last value unless it was io.EOF.
+func RecvAll[T any](strm StreamRecv[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			msg, err := strm.Recv()
+			if err != nil {
+				if err != io.EOF {
+					var empty T
+					yield(empty, err)
+				}
+				return
+			}
+			if !yield(msg, nil) {
+				return
+			}
+		}
+	}
+}
+
+// SendAll sends all messages from the iterator to the stream.
+//
+// Stops and returns the error if a send fails.
+func SendAll[T any](strm StreamSend[T], seq iter.Seq[T]) error {
+	for msg := range seq {
+		if err := strm.Send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
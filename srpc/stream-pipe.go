@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"sync"
+	"time"
 )
 
 // pipeStream implements an in-memory stream.
@@ -36,7 +37,7 @@ func (p *pipeStream) Context() context.Context {
 
 // MsgSend sends the message to the remote.
 func (p *pipeStream) MsgSend(msg Message) error {
-	data, err := msg.MarshalVT()
+	data, err := MarshalMessage(msg)
 	if err != nil {
 		return err
 	}
@@ -48,17 +49,72 @@ func (p *pipeStream) MsgSend(msg Message) error {
 	}
 }
 
+// MsgSendCtx sends the message to the remote waiting at most until ctx is done.
+//
+// Returns ErrSendTimeout if the ctx deadline expired before the message was queued.
+func (p *pipeStream) MsgSendCtx(ctx context.Context, msg Message) error {
+	data, err := MarshalMessage(msg)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-p.ctx.Done():
+		return context.Canceled
+	case <-ctx.Done():
+		return sendCtxErr(ctx)
+	case p.other.dataCh <- data:
+		return nil
+	}
+}
+
 // MsgRecv receives an incoming message from the remote.
 // Parses the message into the object at msg.
 func (p *pipeStream) MsgRecv(msg Message) error {
+	return p.msgRecv(msg, nil)
+}
+
+// MsgRecvTimeout receives an incoming message waiting at most the duration.
+//
+// Returns ErrRecvTimeout if no message was received in time.
+func (p *pipeStream) MsgRecvTimeout(msg Message, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	return p.msgRecv(msg, timer.C)
+}
+
+// MsgTryRecv receives an incoming message if one is available without blocking.
+//
+// Returns false if no message was available.
+func (p *pipeStream) MsgTryRecv(msg Message) (bool, error) {
+	select {
+	case data, ok := <-p.dataCh:
+		if !ok {
+			return false, io.EOF
+		}
+		if err := UnmarshalMessage(msg, data); err != nil {
+			return false, err
+		}
+		return true, nil
+	case <-p.ctx.Done():
+		return false, context.Canceled
+	default:
+		return false, nil
+	}
+}
+
+// msgRecv receives an incoming message until the timeout channel fires.
+// timeoutCh may be nil to wait indefinitely.
+func (p *pipeStream) msgRecv(msg Message, timeoutCh <-chan time.Time) error {
 	select {
 	case <-p.ctx.Done():
 		return context.Canceled
+	case <-timeoutCh:
+		return ErrRecvTimeout
 	case data, ok := <-p.dataCh:
 		if !ok {
 			return io.EOF
 		}
-		return msg.UnmarshalVT(data)
+		return UnmarshalMessage(msg, data)
 	}
 }
 
@@ -83,4 +139,8 @@ func (p *pipeStream) closeRemote() {
 }
 
 // _ is a type assertion
-var _ Stream = ((*pipeStream)(nil))
+var (
+	_ Stream     = ((*pipeStream)(nil))
+	_ RecvPoller = ((*pipeStream)(nil))
+	_ CtxSender  = ((*pipeStream)(nil))
+)
@@ -0,0 +1,105 @@
+package srpc
+
+import "context"
+
+// SyncSendStream is a Stream which is safe to send with from many goroutines.
+//
+// The sends and CloseSend are serialized with a per-stream lock, so concurrent
+// producers can share one stream without their own lock. Each message is sent
+// whole: messages from different goroutines are never interleaved, but their
+// order is the order in which the goroutines acquired the lock. MsgRecv is not
+// serialized and should be called from a single goroutine.
+type SyncSendStream struct {
+	Stream
+	// sendCh is a semaphore held while sending.
+	sendCh chan struct{}
+}
+
+// NewSyncSendStream constructs a new SyncSendStream.
+func NewSyncSendStream(strm Stream) *SyncSendStream {
+	return &SyncSendStream{Stream: strm, sendCh: make(chan struct{}, 1)}
+}
+
+// MsgSend sends the message to the remote.
+//
+// Waits for the sends of other goroutines to complete first.
+func (s *SyncSendStream) MsgSend(msg Message) error {
+	if err := s.lockSend(s.Context()); err != nil {
+		return err
+	}
+	defer s.unlockSend()
+	return s.Stream.MsgSend(msg)
+}
+
+// MsgSendCtx sends the message to the remote waiting at most until ctx is done.
+//
+// ctx also bounds the wait for the sends of other goroutines. Returns
+// ErrUnimplemented if the stream does not implement CtxSender.
+func (s *SyncSendStream) MsgSendCtx(ctx context.Context, msg Message) error {
+	sender, ok := s.Stream.(CtxSender)
+	if !ok {
+		return ErrUnimplemented
+	}
+	if err := s.lockSend(ctx); err != nil {
+		return err
+	}
+	defer s.unlockSend()
+	return sender.MsgSendCtx(ctx, msg)
+}
+
+// MsgSendBatch sends the messages to the remote in order.
+//
+// The messages are not interleaved with the sends of other goroutines.
+func (s *SyncSendStream) MsgSendBatch(msgs []Message) error {
+	if err := s.lockSend(s.Context()); err != nil {
+		return err
+	}
+	defer s.unlockSend()
+	if bs, ok := s.Stream.(BatchSender); ok {
+		return bs.MsgSendBatch(msgs)
+	}
+	for _, msg := range msgs {
+		if err := s.Stream.MsgSend(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CloseSend signals to the remote that we will no longer send any messages.
+//
+// Waits for the sends of other goroutines to complete first.
+func (s *SyncSendStream) CloseSend() error {
+	if err := s.lockSend(s.Context()); err != nil {
+		return err
+	}
+	defer s.unlockSend()
+	return s.Stream.CloseSend()
+}
+
+// lockSend acquires the send lock waiting at most until ctx is done.
+func (s *SyncSendStream) lockSend(ctx context.Context) error {
+	select {
+	case s.sendCh <- struct{}{}:
+		return nil
+	default:
+	}
+	select {
+	case s.sendCh <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return sendCtxErr(ctx)
+	}
+}
+
+// unlockSend releases the send lock.
+func (s *SyncSendStream) unlockSend() {
+	<-s.sendCh
+}
+
+// _ is a type assertion
+var (
+	_ Stream      = ((*SyncSendStream)(nil))
+	_ CtxSender   = ((*SyncSendStream)(nil))
+	_ BatchSender = ((*SyncSendStream)(nil))
+)
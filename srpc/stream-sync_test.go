@@ -0,0 +1,89 @@
+package srpc_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aperturerobotics/starpc/echo"
+	"github.com/aperturerobotics/starpc/srpc"
+)
+
+// TestSyncSendStream sends from many goroutines on one stream.
+//
+// Run with -race to check the sends are serialized.
+func TestSyncSendStream(t *testing.T) {
+	mux := srpc.NewMux()
+	if err := echo.SRPCRegisterEchoer(mux, echo.NewEchoServer(mux)); err != nil {
+		t.Fatal(err.Error())
+	}
+	client := srpc.NewClient(srpc.NewServerPipe(srpc.NewServer(mux)))
+	ctx := context.Background()
+
+	strm, err := client.NewStream(ctx, echo.SRPCEchoerServiceID, "EchoBidiStream", nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer strm.Close()
+	syncStrm := srpc.NewSyncSendStream(strm)
+
+	greeting := &echo.EchoMsg{}
+	if err := syncStrm.MsgRecv(greeting); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	const producers, count = 8, 50
+	var wg sync.WaitGroup
+	errCh := make(chan error, producers)
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				body := strconv.Itoa(p) + "-" + strconv.Itoa(i)
+				var err error
+				switch i % 3 {
+				case 0:
+					err = syncStrm.MsgSend(&echo.EchoMsg{Body: body})
+				case 1:
+					err = syncStrm.MsgSendCtx(ctx, &echo.EchoMsg{Body: body})
+				default:
+					err = syncStrm.MsgSendBatch([]srpc.Message{&echo.EchoMsg{Body: body}})
+				}
+				if err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}(p)
+	}
+	go func() {
+		wg.Wait()
+		errCh <- syncStrm.CloseSend()
+	}()
+
+	// expect every message to be echoed whole and in order per producer
+	next := make([]int, producers)
+	for n := 0; n < producers*count; n++ {
+		msg := &echo.EchoMsg{}
+		if err := syncStrm.MsgRecv(msg); err != nil {
+			t.Fatal(err.Error())
+		}
+		pStr, iStr, _ := strings.Cut(msg.GetBody(), "-")
+		p, _ := strconv.Atoi(pStr)
+		i, _ := strconv.Atoi(iStr)
+		if p < 0 || p >= producers || next[p] != i {
+			t.Fatalf("unexpected message %q", msg.GetBody())
+		}
+		next[p]++
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := syncStrm.MsgRecv(&echo.EchoMsg{}); err != io.EOF {
+		t.Fatalf("expected EOF got %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package srpc
 
 import (
 	"context"
+	"io"
+	"time"
 )
 
 // Stream is a handle to an on-going bi-directional or one-directional stream RPC handle.
@@ -10,6 +12,9 @@ type Stream interface {
 	Context() context.Context
 
 	// MsgSend sends the message to the remote.
+	//
+	// Not safe to call concurrently with other sends or CloseSend: wrap the
+	// stream with NewSyncSendStream to send from many goroutines.
 	MsgSend(msg Message) error
 
 	// MsgRecv receives an incoming message from the remote.
@@ -22,3 +27,133 @@ type Stream interface {
 	// Close closes the stream.
 	Close() error
 }
+
+// StreamRecv is a stream which receives messages of type T.
+//
+// Implemented by the generated stream clients.
+type StreamRecv[T any] interface {
+	// Recv receives the next message from the stream.
+	Recv() (T, error)
+}
+
+// StreamSend is a stream which sends messages of type T.
+//
+// Implemented by the generated stream clients.
+type StreamSend[T any] interface {
+	// Send sends the message to the remote.
+	Send(T) error
+}
+
+// RecvPoller is a Stream which supports receiving without blocking.
+//
+// Intended for event-loop style consumers which poll multiple streams.
+type RecvPoller interface {
+	// MsgRecvTimeout receives an incoming message waiting at most the duration.
+	// Returns ErrRecvTimeout if no message was received in time.
+	MsgRecvTimeout(msg Message, d time.Duration) error
+	// MsgTryRecv receives an incoming message if one is available without blocking.
+	// Returns false if no message was available.
+	MsgTryRecv(msg Message) (bool, error)
+}
+
+// CtxSender is a Stream which can send a message with its own context.
+//
+// Intended for sends which may block on backpressure.
+type CtxSender interface {
+	// MsgSendCtx sends the message to the remote waiting at most until ctx is done.
+	// Returns ErrSendTimeout if the ctx deadline expired.
+	MsgSendCtx(ctx context.Context, msg Message) error
+}
+
+// StreamResetter is a Stream which can bound the time taken to close it.
+type StreamResetter interface {
+	// CloseWithTimeout cancels the call and waits at most d for the remote to
+	// complete it, then resets the stream.
+	CloseWithTimeout(d time.Duration) error
+	// Reset aborts the stream without waiting for the remote.
+	Reset() error
+}
+
+// BatchSender is a Stream which can send many messages at once.
+type BatchSender interface {
+	// MsgSendBatch sends the messages to the remote in order.
+	MsgSendBatch(msgs []Message) error
+}
+
+// RecvBatch receives the messages queued on the stream, up to max.
+//
+// Waits for the first message, then drains the messages already received
+// without blocking, to amortize the per-message overhead of bursts. Returns a
+// single message if the stream does not implement RecvPoller. An io.EOF after
+// the first message ends the batch and is returned by the next call. Other
+// errors are returned with the messages received before them.
+func RecvBatch[T Message](strm Stream, max int, newMsg func() T) ([]T, error) {
+	first := newMsg()
+	if err := strm.MsgRecv(first); err != nil {
+		return nil, err
+	}
+	msgs := []T{first}
+	poller, ok := strm.(RecvPoller)
+	if !ok {
+		return msgs, nil
+	}
+	for len(msgs) < max {
+		msg := newMsg()
+		ok, err := poller.MsgTryRecv(msg)
+		if err == io.EOF || (err == nil && !ok) {
+			break
+		}
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// MsgRecvTimeout receives a message from the stream waiting at most the duration.
+//
+// Returns ErrRecvTimeout if no message was received in time.
+// Returns ErrUnimplemented if the stream does not implement RecvPoller.
+func MsgRecvTimeout(strm Stream, msg Message, d time.Duration) error {
+	poller, ok := strm.(RecvPoller)
+	if !ok {
+		return ErrUnimplemented
+	}
+	return poller.MsgRecvTimeout(msg, d)
+}
+
+// MsgSendCtx sends a message to the stream waiting at most until ctx is done.
+//
+// The stream remains usable if the send fails with ErrSendTimeout.
+// Returns ErrUnimplemented if the stream does not implement CtxSender.
+func MsgSendCtx(ctx context.Context, strm Stream, msg Message) error {
+	sender, ok := strm.(CtxSender)
+	if !ok {
+		return ErrUnimplemented
+	}
+	return sender.MsgSendCtx(ctx, msg)
+}
+
+// CloseWithTimeout closes the stream waiting at most d for the remote.
+//
+// Calls Close if the stream does not implement StreamResetter.
+func CloseWithTimeout(strm Stream, d time.Duration) error {
+	resetter, ok := strm.(StreamResetter)
+	if !ok {
+		return strm.Close()
+	}
+	return resetter.CloseWithTimeout(d)
+}
+
+// MsgTryRecv receives a message from the stream if one is available without blocking.
+//
+// Returns false if no message was available.
+// Returns ErrUnimplemented if the stream does not implement RecvPoller.
+func MsgTryRecv(strm Stream, msg Message) (bool, error) {
+	poller, ok := strm.(RecvPoller)
+	if !ok {
+		return false, ErrUnimplemented
+	}
+	return poller.MsgTryRecv(msg)
+}
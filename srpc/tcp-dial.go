@@ -0,0 +1,61 @@
+//go:build !starpc_core
+
+package srpc
+
+import (
+	"context"
+	"io"
+	"net"
+)
+
+// Dial dials a TCP target and constructs a Client with the default muxer.
+//
+// The target is resolved with the Resolver registered for its scheme, for
+// example dns:///example.com:443, or dialed directly if it has no scheme. The
+// addresses are tried in order. Applies the TCP options to the connection.
+// The connection should be closed when the client is no longer needed.
+func Dial(ctx context.Context, target string, opts ...TCPOption) (Client, net.Conn, error) {
+	_, _, addrs, err := ResolveTarget(ctx, target)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, addr := range addrs {
+		var client Client
+		var nc net.Conn
+		client, nc, err = dialTCP(ctx, addr.Addr, opts...)
+		if err == nil {
+			return client, nc, nil
+		}
+	}
+	return nil, nil, err
+}
+
+// DialResolved dials each of the addresses of a TCP target and balances the
+// calls across them.
+//
+// The target is re-resolved to follow changes to the addresses. If balancer
+// is nil, uses NewRoundRobinClient. See NewResolvedClient.
+func DialResolved(ctx context.Context, target string, balancer BalancerFunc, opts ...TCPOption) (*ResolvedClient, error) {
+	return NewResolvedClient(ctx, target, func(ctx context.Context, addr string) (Client, io.Closer, error) {
+		return dialTCP(ctx, addr, opts...)
+	}, balancer)
+}
+
+// dialTCP dials a TCP address and constructs a Client with the default muxer.
+func dialTCP(ctx context.Context, addr string, opts ...TCPOption) (Client, net.Conn, error) {
+	var dialer net.Dialer
+	nc, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ApplyTCPOptions(nc, opts...); err != nil {
+		_ = nc.Close()
+		return nil, nil, err
+	}
+	client, err := NewClientWithConn(nc, true)
+	if err != nil {
+		_ = nc.Close()
+		return nil, nil, err
+	}
+	return client, nc, nil
+}
@@ -0,0 +1,141 @@
+package srpc
+
+import (
+	"net"
+	"time"
+)
+
+// TCPOption configures TCP connections dialed or accepted by the helpers.
+type TCPOption func(o *tcpOptions)
+
+// tcpOptions contains the TCP connection options.
+type tcpOptions struct {
+	// noDelay sets TCP_NODELAY, if set.
+	noDelay *bool
+	// keepAlive sets SO_KEEPALIVE, if set.
+	keepAlive *bool
+	// keepAlivePeriod is the keep-alive interval, if > 0.
+	keepAlivePeriod time.Duration
+	// readBufferSize is the SO_RCVBUF size, if > 0.
+	readBufferSize int
+	// writeBufferSize is the SO_SNDBUF size, if > 0.
+	writeBufferSize int
+}
+
+// WithTCPNoDelay sets TCP_NODELAY on the connection.
+//
+// Go enables TCP_NODELAY by default.
+func WithTCPNoDelay(enable bool) TCPOption {
+	return func(o *tcpOptions) {
+		o.noDelay = &enable
+	}
+}
+
+// WithTCPKeepAlive enables SO_KEEPALIVE with the keep-alive interval.
+//
+// If period is zero, uses the default interval.
+// If period is negative, disables keep-alives.
+func WithTCPKeepAlive(period time.Duration) TCPOption {
+	return func(o *tcpOptions) {
+		enable := period >= 0
+		o.keepAlive = &enable
+		o.keepAlivePeriod = period
+	}
+}
+
+// WithTCPReadBufferSize sets the SO_RCVBUF socket buffer size in bytes.
+func WithTCPReadBufferSize(size int) TCPOption {
+	return func(o *tcpOptions) {
+		o.readBufferSize = size
+	}
+}
+
+// WithTCPWriteBufferSize sets the SO_SNDBUF socket buffer size in bytes.
+func WithTCPWriteBufferSize(size int) TCPOption {
+	return func(o *tcpOptions) {
+		o.writeBufferSize = size
+	}
+}
+
+// ApplyTCPOptions applies the TCP options to the connection.
+//
+// Does nothing if the connection is not a *net.TCPConn.
+func ApplyTCPOptions(conn net.Conn, opts ...TCPOption) error {
+	tc, ok := conn.(*net.TCPConn)
+	if !ok || len(opts) == 0 {
+		return nil
+	}
+
+	o := &tcpOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	if o.noDelay != nil {
+		if err := tc.SetNoDelay(*o.noDelay); err != nil {
+			return err
+		}
+	}
+	if o.keepAlive != nil {
+		if err := tc.SetKeepAlive(*o.keepAlive); err != nil {
+			return err
+		}
+		if *o.keepAlive && o.keepAlivePeriod > 0 {
+			if err := tc.SetKeepAlivePeriod(o.keepAlivePeriod); err != nil {
+				return err
+			}
+		}
+	}
+	if o.readBufferSize > 0 {
+		if err := tc.SetReadBuffer(o.readBufferSize); err != nil {
+			return err
+		}
+	}
+	if o.writeBufferSize > 0 {
+		if err := tc.SetWriteBuffer(o.writeBufferSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Listen listens on a TCP address.
+//
+// Applies the TCP options to accepted connections.
+func Listen(addr string, opts ...TCPOption) (net.Listener, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts) == 0 {
+		return lis, nil
+	}
+	return &tcpListener{Listener: lis, opts: opts}, nil
+}
+
+// tcpListener applies TCP options to accepted connections.
+type tcpListener struct {
+	net.Listener
+	// opts are the TCP options
+	opts []TCPOption
+}
+
+// Accept waits for and returns the next connection to the listener.
+func (l *tcpListener) Accept() (net.Conn, error) {
+	for {
+		nc, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if err := ApplyTCPOptions(nc, l.opts...); err != nil {
+			_ = nc.Close()
+			continue
+		}
+		return nc, nil
+	}
+}
+
+// _ is a type assertion
+var _ net.Listener = ((*tcpListener)(nil))
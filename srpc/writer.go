@@ -7,3 +7,38 @@ type Writer interface {
 	// Close closes the writer.
 	Close() error
 }
+
+// BatchWriter is a Writer which can write many packets at once.
+type BatchWriter interface {
+	Writer
+	// WritePackets writes the packets to the remote in order.
+	WritePackets(pkts []*Packet) error
+}
+
+// ResetWriter is a Writer which can abort the transport stream.
+type ResetWriter interface {
+	Writer
+	// Reset aborts the stream without closing it gracefully.
+	Reset() error
+}
+
+// resetWriter resets the writer if it is a ResetWriter, otherwise closes it.
+func resetWriter(w Writer) error {
+	if rw, ok := w.(ResetWriter); ok {
+		return rw.Reset()
+	}
+	return w.Close()
+}
+
+// writePackets writes the packets with WritePackets if w is a BatchWriter.
+func writePackets(w Writer, pkts []*Packet) error {
+	if bw, ok := w.(BatchWriter); ok {
+		return bw.WritePackets(pkts)
+	}
+	for _, pkt := range pkts {
+		if err := w.WritePacket(pkt); err != nil {
+			return err
+		}
+	}
+	return nil
+}